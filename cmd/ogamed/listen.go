@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const unixListenPrefix = "unix:"
+
+// unixSocketPerm permissions of the REST API unix socket, the owner and its group can connect
+const unixSocketPerm = 0660
+
+// parseListen returns the unix socket path of a --listen value (eg: unix:/run/ogamed.sock)
+func parseListen(listen string) (string, error) {
+	if !strings.HasPrefix(listen, unixListenPrefix) || len(listen) == len(unixListenPrefix) {
+		return "", errors.New("invalid listen address, expected unix:/path/to/ogamed.sock")
+	}
+	return strings.TrimPrefix(listen, unixListenPrefix), nil
+}
+
+// listenUnix creates the unix socket, replacing a socket left by a previous run that was not shut down cleanly
+func listenUnix(path string) (*net.UnixListener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New(path + " already exists and is not a socket")
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, unixSocketPerm); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// startUnix serves e on a unix socket until the process is interrupted,
+// the socket is removed when the server shuts down
+func startUnix(e *echo.Echo, path string) error {
+	l, err := listenUnix(path)
+	if err != nil {
+		return err
+	}
+	e.Listener = l
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		<-sigs
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = e.Shutdown(ctx)
+	}()
+	if err := e.Start(""); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
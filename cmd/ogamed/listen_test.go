@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseListen(t *testing.T) {
+	path, err := parseListen("unix:/run/ogamed.sock")
+	assert.NoError(t, err)
+	assert.Equal(t, "/run/ogamed.sock", path)
+	_, err = parseListen("unix:")
+	assert.Error(t, err)
+	_, err = parseListen("127.0.0.1:8080")
+	assert.Error(t, err)
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ogamed.sock")
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.GET("/bot/planets", func(c echo.Context) error {
+		return c.String(http.StatusOK, "planets")
+	})
+	l, err := listenUnix(path)
+	assert.NoError(t, err)
+	e.Listener = l
+	done := make(chan error, 1)
+	go func() { done <- e.Start("") }()
+
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(unixSocketPerm), fi.Mode().Perm())
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://ogamed/bot/planets")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "planets", string(body))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, e.Shutdown(ctx))
+	assert.Equal(t, http.ErrServerClosed, <-done)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// A socket left by a previous run is replaced, any other file is not
+	assert.NoError(t, os.WriteFile(path, []byte("x"), 0600))
+	_, err = listenUnix(path)
+	assert.Error(t, err)
+}
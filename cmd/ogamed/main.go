@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypto/subtle"
+	"errors"
 	"github.com/alaingilbert/ogame/pkg/wrapper"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -9,6 +10,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var version = "0.0.0"
@@ -61,6 +64,11 @@ func main() {
 			Value:   8080,
 			EnvVars: []string{"OGAMED_PORT"},
 		},
+		&cli.StringFlag{
+			Name:    "listen",
+			Usage:   "Serve the HTTP API on a unix socket instead of host/port (eg: unix:/run/ogamed.sock)",
+			EnvVars: []string{"OGAMED_LISTEN"},
+		},
 		&cli.BoolFlag{
 			Name:    "auto-login",
 			Usage:   "Login when process starts",
@@ -157,6 +165,50 @@ func main() {
 			Value:   "",
 			EnvVars: []string{"NJA_API_KEY"},
 		},
+		&cli.StringFlag{
+			Name:    "critical-routes",
+			Usage:   "Comma separated list of routes allowed to use the critical task priority",
+			Value:   "/bot/fleets/:fleetID/cancel,/bot/planets/:planetID/send-fleet",
+			EnvVars: []string{"OGAMED_CRITICAL_ROUTES"},
+		},
+		&cli.IntFlag{
+			Name:    "chat-max-backoff",
+			Usage:   "Maximum delay in seconds between two reconnections to the game websocket",
+			Value:   60,
+			EnvVars: []string{"OGAMED_CHAT_MAX_BACKOFF"},
+		},
+		&cli.StringFlag{
+			Name:    "storage-webhook-url",
+			Usage:   "Url that receives a POST when a planet storage is projected to be full",
+			EnvVars: []string{"OGAMED_STORAGE_WEBHOOK_URL"},
+		},
+		&cli.IntFlag{
+			Name:    "storage-lead-time",
+			Usage:   "Alert when a storage is projected to be full within this many minutes",
+			Value:   120,
+			EnvVars: []string{"OGAMED_STORAGE_LEAD_TIME"},
+		},
+		&cli.StringFlag{
+			Name:    "lobby-locale",
+			Usage:   "Locale sent to the gameforge lobby (eg: pt_BR), derived from the language by default",
+			EnvVars: []string{"OGAMED_LOBBY_LOCALE"},
+		},
+		&cli.IntFlag{
+			Name:    "max-response-size",
+			Usage:   "Maximum size of a game response in megabytes, bigger responses are rejected",
+			Value:   64,
+			EnvVars: []string{"OGAMED_MAX_RESPONSE_SIZE"},
+		},
+		&cli.StringFlag{
+			Name:    "self-test-schedule",
+			Usage:   "Time of day (eg: 04:30) of the daily extraction self-test, disabled by default",
+			EnvVars: []string{"OGAMED_SELF_TEST_SCHEDULE"},
+		},
+		&cli.IntFlag{
+			Name:    "throttle-critical-wait",
+			Usage:   "Seconds after which critical tasks are let through while the game server throttles the bot, 0 to wait for the whole cool-down",
+			EnvVars: []string{"OGAMED_THROTTLE_CRITICAL_WAIT"},
+		},
 	}
 	app.Action = start
 	if err := app.Run(os.Args); err != nil {
@@ -172,6 +224,7 @@ func start(c *cli.Context) error {
 	autoLogin := c.Bool("auto-login")
 	host := c.String("host")
 	port := c.Int("port")
+	listen := c.String("listen")
 	proxyAddr := c.String("proxy")
 	proxyUsername := c.String("proxy-username")
 	proxyPassword := c.String("proxy-password")
@@ -187,6 +240,28 @@ func start(c *cli.Context) error {
 	cookiesFilename := c.String("cookies-filename")
 	corsEnabled := c.Bool("cors-enabled")
 	njaApiKey := c.String("nja-api-key")
+	criticalRoutes := strings.Split(c.String("critical-routes"), ",")
+	chatMaxBackoff := c.Int("chat-max-backoff")
+	storageWebhookURL := c.String("storage-webhook-url")
+	storageLeadTime := c.Int("storage-lead-time")
+	lobbyLocale := c.String("lobby-locale")
+	maxResponseSize := c.Int("max-response-size")
+	selfTestSchedule := c.String("self-test-schedule")
+	throttleCriticalWait := c.Int("throttle-critical-wait")
+
+	var socketPath string
+	if listen != "" {
+		if c.IsSet("host") || c.IsSet("port") {
+			return errors.New("--listen cannot be used with --host/--port")
+		}
+		if enableTLS {
+			return errors.New("--listen cannot be used with --enable-tls")
+		}
+		var err error
+		if socketPath, err = parseListen(listen); err != nil {
+			return err
+		}
+	}
 
 	params := wrapper.Params{
 		Universe:        universe,
@@ -202,6 +277,15 @@ func start(c *cli.Context) error {
 		Lobby:           lobby,
 		APINewHostname:  apiNewHostname,
 		CookiesFilename: cookiesFilename,
+		ChatMaxBackoff:  time.Duration(chatMaxBackoff) * time.Second,
+
+		StorageWebhookURL: storageWebhookURL,
+		StorageLeadTime:   time.Duration(storageLeadTime) * time.Minute,
+		LobbyLocale:       lobbyLocale,
+		MaxResponseBytes:  int64(maxResponseSize) << 20,
+		SelfTestSchedule:  selfTestSchedule,
+
+		ThrottleCriticalWait: time.Duration(throttleCriticalWait) * time.Second,
 	}
 	if njaApiKey != "" {
 		params.CaptchaCallback = wrapper.NinjaSolver(njaApiKey)
@@ -225,6 +309,7 @@ func start(c *cli.Context) error {
 			return next(ctx)
 		}
 	})
+	e.Use(wrapper.TaskPriorityMiddleware(criticalRoutes))
 	if len(basicAuthUsername) > 0 && len(basicAuthPassword) > 0 {
 		log.Println("Enable Basic Auth")
 		e.Use(middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
@@ -236,21 +321,44 @@ func start(c *cli.Context) error {
 			return false, nil
 		}))
 	}
+	e.Use(wrapper.MaintenanceMiddleware)
 	e.HideBanner = true
 	e.HidePort = true
 	e.Debug = false
+	registerRoutes(e)
+
+	if socketPath != "" {
+		log.Println("Listen on unix socket " + socketPath)
+		return startUnix(e, socketPath)
+	}
+	if enableTLS {
+		log.Println("Enable TLS Support")
+		return e.StartTLS(host+":"+strconv.Itoa(port), tlsCertFile, tlsKeyFile)
+	}
+	log.Println("Disable TLS Support")
+	return e.Start(host + ":" + strconv.Itoa(port))
+}
+
+// registerRoutes registers the routes of the API
+func registerRoutes(e *echo.Echo) {
 	e.GET("/", wrapper.HomeHandler)
 	e.GET("/tasks", wrapper.TasksHandler)
+	e.GET("/debug/vars", wrapper.DebugVarsHandler)
+	e.GET("/bot/ws", wrapper.WSHandler)
+	e.GET("/bot/info", wrapper.BotInfoHandler)
 
 	// CAPTCHA Handler
 	e.GET("/bot/captcha", wrapper.GetCaptchaHandler)
 	e.POST("/bot/captcha/solve", wrapper.GetCaptchaSolverHandler)
 	e.GET("/bot/captcha/challenge", wrapper.GetCaptchaChallengeHandler)
+	e.GET("/bot/captcha/question/:challengeID", wrapper.GetCaptchaQuestionHandler)
+	e.GET("/bot/captcha/icons/:challengeID", wrapper.GetCaptchaIconsHandler)
 
 	e.GET("/bot/ip", wrapper.GetPublicIPHandler)
 	e.GET("/bot/server", wrapper.GetServerHandler)
 	e.GET("/bot/server-data", wrapper.GetServerDataHandler)
 	e.POST("/bot/set-user-agent", wrapper.SetUserAgentHandler)
+	e.POST("/bot/set-api-new-hostname", wrapper.SetAPINewHostnameHandler)
 	e.GET("/bot/server-url", wrapper.ServerURLHandler)
 	e.GET("/bot/language", wrapper.GetLanguageHandler)
 	e.GET("/bot/empire/type/:typeID", wrapper.GetEmpireHandler)
@@ -259,12 +367,23 @@ func start(c *cli.Context) error {
 	e.GET("/bot/logout", wrapper.LogoutHandler)
 	e.GET("/bot/username", wrapper.GetUsernameHandler)
 	e.GET("/bot/universe-name", wrapper.GetUniverseNameHandler)
+	e.GET("/bot/server/effective-speeds", wrapper.GetEffectiveSpeedsHandler)
+	e.GET("/bot/server/settings", wrapper.GetServerSettingsHandler)
 	e.GET("/bot/server/speed", wrapper.GetUniverseSpeedHandler)
 	e.GET("/bot/server/speed-fleet", wrapper.GetUniverseSpeedFleetHandler)
 	e.GET("/bot/server/version", wrapper.ServerVersionHandler)
 	e.GET("/bot/server/time", wrapper.ServerTimeHandler)
+	e.GET("/bot/server/time/offset", wrapper.ServerTimeOffsetHandler)
+	e.POST("/bot/simulate-combat", wrapper.SimulateCombatHandler)
+	e.GET("/bot/last-activity", wrapper.GetLastActivityHandler)
+	e.GET("/bot/maintenance", wrapper.GetMaintenanceHandler)
+	e.GET("/bot/health", wrapper.HealthHandler)
+	e.GET("/bot/extractor/supported", wrapper.GetSupportedExtractorsHandler)
 	e.GET("/bot/is-under-attack", wrapper.IsUnderAttackHandler)
 	e.GET("/bot/is-vacation-mode", wrapper.IsVacationModeHandler)
+	e.GET("/bot/settings/spy-report", wrapper.GetSpyReportSettingsHandler)
+	e.GET("/bot/self-test/results", wrapper.GetSelfTestResultsHandler)
+	e.POST("/bot/settings/spy-report", wrapper.SetSpyReportSettingsHandler)
 	e.GET("/bot/user-infos", wrapper.GetUserInfosHandler)
 	e.GET("/bot/character-class", wrapper.GetCharacterClassHandler)
 	e.GET("/bot/has-commander", wrapper.HasCommanderHandler)
@@ -272,40 +391,93 @@ func start(c *cli.Context) error {
 	e.GET("/bot/has-engineer", wrapper.HasEngineerHandler)
 	e.GET("/bot/has-geologist", wrapper.HasGeologistHandler)
 	e.GET("/bot/has-technocrat", wrapper.HasTechnocratHandler)
+	e.GET("/bot/dark-matter", wrapper.GetDarkMatterHandler)
 	e.POST("/bot/send-message", wrapper.SendMessageHandler)
+	e.POST("/bot/send-messages", wrapper.SendMessagesHandler)
 	e.GET("/bot/fleets", wrapper.GetFleetsHandler)
+	e.GET("/bot/fleets/schedule", wrapper.GetFleetsScheduleHandler)
+	e.GET("/bot/fleets/incoming-friendly", wrapper.GetFriendlyArrivalsHandler)
 	e.GET("/bot/fleets/slots", wrapper.GetSlotsHandler)
+	e.POST("/bot/fleets/slots/reservations", wrapper.ReserveSlotsHandler)
+	e.DELETE("/bot/fleets/slots/reservations/:token", wrapper.ReleaseSlotsHandler)
 	e.POST("/bot/fleets/:fleetID/cancel", wrapper.CancelFleetHandler)
+	e.POST("/bot/acs/unions", wrapper.CreateUnionHandler)
+	e.GET("/bot/acs/unions/:unionID", wrapper.GetACSUnionDetailsHandler)
+	e.GET("/bot/espionage-report/diff", wrapper.GetEspionageReportDiffHandler)
 	e.GET("/bot/espionage-report/:msgid", wrapper.GetEspionageReportHandler)
 	e.GET("/bot/espionage-report/:galaxy/:system/:position", wrapper.GetEspionageReportForHandler)
+	e.GET("/bot/espionage-report/by-coord", wrapper.GetEspionageReportForHandler)
+	e.GET("/bot/bashing/:galaxy/:system/:position", wrapper.AttacksRemainingAgainstHandler)
 	e.GET("/bot/espionage-report", wrapper.GetEspionageReportMessagesHandler)
+	e.POST("/bot/espionage-report/prune", wrapper.DeleteEspionageReportsOlderThanHandler)
+	e.GET("/bot/espionage/incoming", wrapper.GetSpiedEventsHandler)
+	e.GET("/bot/capabilities", wrapper.GetCapabilitiesHandler)
+	e.PUT("/bot/crawler-policy", wrapper.SetCrawlerPolicyHandler)
+	e.GET("/bot/crawler-policy/status", wrapper.GetCrawlerPolicyStatusHandler)
+	e.POST("/bot/auto-sats", wrapper.SetAutoSatsPolicyHandler)
+	e.GET("/bot/auto-sats", wrapper.GetAutoSatsStatusHandler)
+	e.GET("/bot/recalls", wrapper.GetRecallJobsHandler)
+	e.DELETE("/bot/recalls/:jobID", wrapper.CancelRecallJobHandler)
+	e.GET("/bot/exposure", wrapper.GetExposureHandler)
+	e.GET("/bot/exposure/alert", wrapper.GetExposureAlertHandler)
+	e.PUT("/bot/exposure/alert", wrapper.SetExposureAlertHandler)
 	e.POST("/bot/delete-report/:messageID", wrapper.DeleteMessageHandler)
 	e.POST("/bot/delete-all-espionage-reports", wrapper.DeleteEspionageMessagesHandler)
 	e.POST("/bot/delete-all-reports/:tabIndex", wrapper.DeleteMessagesFromTabHandler)
+	e.POST("/bot/messages/:tabIndex/delete-filtered", wrapper.DeleteMessagesWhereHandler)
 	e.GET("/bot/attacks", wrapper.GetAttacksHandler)
+	e.GET("/bot/events", wrapper.GetEventListHandler)
 	e.GET("/bot/get-auction", wrapper.GetAuctionHandler)
 	e.POST("/bot/do-auction", wrapper.DoAuctionHandler)
 	e.GET("/bot/galaxy-infos/:galaxy/:system", wrapper.GalaxyInfosHandler)
+	e.GET("/bot/galaxy-infos", wrapper.GalaxyInfosHandler)
+	e.GET("/bot/validate-fleet-target", wrapper.ValidateFleetTargetHandler)
+	e.POST("/bot/find-colony-slots", wrapper.FindColonizationSlotsHandler)
 	e.GET("/bot/get-research", wrapper.GetResearchHandler)
 	e.GET("/bot/buy-offer-of-the-day", wrapper.BuyOfferOfTheDayHandler)
 	e.GET("/bot/price/:ogameID/:nbr", wrapper.GetPriceHandler)
 	e.GET("/bot/requirements/:ogameID", wrapper.GetRequirementsHandler)
+	e.GET("/bot/planets/:planetID/tech-details/:ogameID", wrapper.TechnologyDetailsHandler)
+	e.GET("/bot/planets/:planetID/research/effective-lab", wrapper.GetEffectiveLabLevelHandler)
+	e.GET("/bot/objects/:ogameID/rapidfire", wrapper.GetRapidfireHandler)
+	e.GET("/bot/ipm-needed", wrapper.IPMNeededHandler)
+	e.GET("/bot/expedition-odds", wrapper.ExpeditionOddsHandler)
+	e.GET("/bot/expeditions/cap", wrapper.GetExpeditionCapHandler)
+	e.GET("/bot/my-points", wrapper.GetMyPointsHandler)
+	e.GET("/bot/missile-defense", wrapper.MissileDefenseStatusHandler)
 	e.GET("/bot/moons", wrapper.GetMoonsHandler)
 	e.GET("/bot/moons/:moonID", wrapper.GetMoonHandler)
 	e.GET("/bot/moons/:galaxy/:system/:position", wrapper.GetMoonByCoordHandler)
 	e.GET("/bot/celestials/:celestialID/items", wrapper.GetCelestialItemsHandler)
+	e.GET("/bot/celestials/:celestialID/image", wrapper.GetCelestialImageHandler)
 	e.GET("/bot/celestials/:celestialID/items/:itemRef/activate", wrapper.ActivateCelestialItemHandler)
 	e.GET("/bot/celestials/:celestialID/techs", wrapper.TechsHandler)
+	e.GET("/bot/notifications", wrapper.GetNotificationsHandler)
 	e.GET("/bot/planets", wrapper.GetPlanetsHandler)
 	e.GET("/bot/planets/:planetID", wrapper.GetPlanetHandler)
 	e.GET("/bot/planets/:galaxy/:system/:position", wrapper.GetPlanetByCoordHandler)
+	e.GET("/bot/planets/by-coord", wrapper.GetPlanetByCoordHandler)
+	e.GET("/bot/planets/by-coord/:galaxy/:system/:position/all", wrapper.GetPlanetsByCoordHandler)
 	e.GET("/bot/planets/:planetID/resources-details", wrapper.GetResourcesDetailsHandler)
+	e.GET("/bot/planets/:planetID/wasted", wrapper.GetWastedResourcesHandler)
+	e.GET("/bot/planets/:planetID/ship-build-time/:ogameID/:count", wrapper.ShipBuildTimeHandler)
+	e.GET("/bot/planets/:planetID/mine-roi/:ogameID", wrapper.MineUpgradeROIHandler)
+	e.GET("/bot/planets/:planetID/temperature-effects", wrapper.GetTemperatureEffectsHandler)
+	e.GET("/bot/allowed-speeds", wrapper.GetAllowedSpeedsHandler)
 	e.GET("/bot/planets/:planetID/resource-settings", wrapper.GetResourceSettingsHandler)
 	e.POST("/bot/planets/:planetID/resource-settings", wrapper.SetResourceSettingsHandler)
+	e.POST("/bot/planets/:planetID/resource-settings/revert-last", wrapper.RevertResourceProfileHandler)
+	e.POST("/bot/planets/:planetID/apply-profile/:name", wrapper.ApplyResourceProfileHandler)
+	e.GET("/bot/resource-profiles", wrapper.GetResourceProfilesHandler)
+	e.PUT("/bot/resource-profiles/:name", wrapper.SetResourceProfileHandler)
+	e.GET("/bot/resource-profiles/changes", wrapper.GetResourceProfileChangesHandler)
+	e.POST("/bot/apply-profile-all/:name", wrapper.ApplyResourceProfileAllHandler)
 	e.GET("/bot/planets/:planetID/resources-buildings", wrapper.GetResourcesBuildingsHandler)
+	e.GET("/bot/planets/:planetID/plan-to-match", wrapper.PlanToMatchHandler)
 	e.GET("/bot/planets/:planetID/lifeform-buildings", wrapper.GetLfBuildingsHandler)
 	e.GET("/bot/planets/:planetID/lifeform-techs", wrapper.GetLfResearchHandler)
 	e.GET("/bot/planets/:planetID/defence", wrapper.GetDefenseHandler)
+	e.GET("/bot/planets/:planetID/missiles", wrapper.GetMissilesHandler)
 	e.GET("/bot/planets/:planetID/ships", wrapper.GetShipsHandler)
 	e.GET("/bot/planets/:planetID/facilities", wrapper.GetFacilitiesHandler)
 	e.POST("/bot/planets/:planetID/build/:ogameID/:nbr", wrapper.BuildHandler)
@@ -318,13 +490,20 @@ func start(c *cli.Context) error {
 	e.POST("/bot/planets/:planetID/teardown/:ogameID", wrapper.TeardownHandler)
 	e.GET("/bot/planets/:planetID/production", wrapper.GetProductionHandler)
 	e.GET("/bot/planets/:planetID/constructions", wrapper.ConstructionsBeingBuiltHandler)
+	e.GET("/bot/planets/:planetID/build-queue", wrapper.GetBuildQueueHandler)
 	e.POST("/bot/planets/:planetID/cancel-building", wrapper.CancelBuildingHandler)
+	e.POST("/bot/planets/:planetID/cancel-queued/:index", wrapper.CancelQueuedBuildHandler)
 	e.POST("/bot/planets/:planetID/cancel-research", wrapper.CancelResearchHandler)
 	e.GET("/bot/planets/:planetID/resources", wrapper.GetResourcesHandler)
 	e.POST("/bot/planets/:planetID/send-fleet", wrapper.SendFleetHandler)
+	e.GET("/bot/planets/:planetID/flight-time", wrapper.FlightTimeHandler)
+	e.POST("/bot/planets/:planetID/send-and-recall", wrapper.SendFleetAndRecallHandler)
 	e.POST("/bot/planets/:planetID/send-ipm", wrapper.SendIPMHandler)
+	e.POST("/bot/quick-spy", wrapper.QuickSpyHandler)
 	e.GET("/bot/moons/:moonID/phalanx/:galaxy/:system/:position", wrapper.PhalanxHandler)
+	e.GET("/bot/moons/:moonID/phalanx", wrapper.PhalanxHandler)
 	e.POST("/bot/moons/:moonID/jump-gate", wrapper.JumpGateHandler)
+	e.GET("/bot/moons/:moonID/facilities/detail", wrapper.GetMoonFacilitiesHandler)
 	e.GET("/game/allianceInfo.php", wrapper.GetAlliancePageContentHandler) // Example: //game/allianceInfo.php?allianceId=500127
 
 	// Get/Post Page Content
@@ -348,11 +527,4 @@ func start(c *cli.Context) error {
 	*/
 	e.GET("/api/*", wrapper.GetStaticHandler)
 	e.HEAD("/api/*", wrapper.GetStaticHEADHandler) // AntiGame uses this to check if the cached XML files need to be refreshed
-
-	if enableTLS {
-		log.Println("Enable TLS Support")
-		return e.StartTLS(host+":"+strconv.Itoa(port), tlsCertFile, tlsKeyFile)
-	}
-	log.Println("Disable TLS Support")
-	return e.Start(host + ":" + strconv.Itoa(port))
 }
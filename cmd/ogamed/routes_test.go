@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// routedHandler returns the name of the handler the request is routed to
+func routedHandler(e *echo.Echo, method, target string) string {
+	c := e.NewContext(httptest.NewRequest(method, target, nil), httptest.NewRecorder())
+	e.Router().Find(method, strings.Split(target, "?")[0], c)
+	for _, r := range e.Routes() {
+		if r.Method == method && r.Path == c.Path() {
+			return r.Name[strings.LastIndex(r.Name, ".")+1:]
+		}
+	}
+	return ""
+}
+
+func TestRegisterRoutes_espionageReport(t *testing.T) {
+	e := echo.New()
+	registerRoutes(e)
+	assert.Equal(t, "GetEspionageReportMessagesHandler", routedHandler(e, http.MethodGet, "/bot/espionage-report"))
+	assert.Equal(t, "GetEspionageReportForHandler", routedHandler(e, http.MethodGet, "/bot/espionage-report/by-coord?coord=M:1:2:3"))
+	assert.Equal(t, "GetEspionageReportForHandler", routedHandler(e, http.MethodGet, "/bot/espionage-report/1/2/3"))
+	assert.Equal(t, "GetEspionageReportDiffHandler", routedHandler(e, http.MethodGet, "/bot/espionage-report/diff?coord=1:2:3"))
+	assert.Equal(t, "GetEspionageReportHandler", routedHandler(e, http.MethodGet, "/bot/espionage-report/123"))
+}
@@ -14,6 +14,7 @@ import (
 type FullPageExtractorBytes interface {
 	ExtractAdmiral(pageHTML []byte) bool
 	ExtractAjaxChatToken(pageHTML []byte) (string, error)
+	ExtractAllianceID(pageHTML []byte) int64
 	ExtractCelestial(pageHTML []byte, v any) (ogame.Celestial, error)
 	ExtractCelestials(pageHTML []byte) ([]ogame.Celestial, error)
 	ExtractCharacterClass(pageHTML []byte) (ogame.CharacterClass, error)
@@ -23,8 +24,10 @@ type FullPageExtractorBytes interface {
 	ExtractIsInVacation(pageHTML []byte) bool
 	ExtractIsMobile(pageHTML []byte) bool
 	ExtractLifeformEnabled(pageHTML []byte) bool
+	ExtractMiniFleetToken(pageHTML []byte) (string, error)
 	ExtractMoon(pageHTML []byte, v any) (ogame.Moon, error)
 	ExtractMoons(pageHTML []byte) []ogame.Moon
+	ExtractNotifications(pageHTML []byte) []ogame.Notification
 	ExtractOGameTimestampFromBytes(pageHTML []byte) int64
 	ExtractOgameTimestamp(pageHTML []byte) int64
 	ExtractPlanet(pageHTML []byte, v any) (ogame.Planet, error)
@@ -36,6 +39,7 @@ type FullPageExtractorBytes interface {
 	ExtractResourcesDetailsFromFullPage(pageHTML []byte) ogame.ResourcesDetails
 	ExtractServerTime(pageHTML []byte) (time.Time, error)
 	ExtractTechnocrat(pageHTML []byte) bool
+	ExtractUniverseSpeeds(pageHTML []byte) ogame.EffectiveSpeeds
 }
 
 type FullPageExtractorDoc interface {
@@ -51,6 +55,7 @@ type FullPageExtractorDoc interface {
 	ExtractIsMobileFromDoc(doc *goquery.Document) bool
 	ExtractMoonFromDoc(doc *goquery.Document, v any) (ogame.Moon, error)
 	ExtractMoonsFromDoc(doc *goquery.Document) []ogame.Moon
+	ExtractNotificationsFromDoc(doc *goquery.Document) []ogame.Notification
 	ExtractOGameSessionFromDoc(doc *goquery.Document) string
 	ExtractOgameTimestampFromDoc(doc *goquery.Document) int64
 	ExtractPlanetFromDoc(doc *goquery.Document, v any) (ogame.Planet, error)
@@ -61,6 +66,7 @@ type FullPageExtractorDoc interface {
 	ExtractResourcesFromDoc(doc *goquery.Document) ogame.Resources
 	ExtractServerTimeFromDoc(doc *goquery.Document) (time.Time, error)
 	ExtractTechnocratFromDoc(doc *goquery.Document) bool
+	ExtractUniverseSpeedsFromDoc(doc *goquery.Document) ogame.EffectiveSpeeds
 }
 
 type FullPageExtractorBytesDoc interface {
@@ -70,6 +76,7 @@ type FullPageExtractorBytesDoc interface {
 
 type OverviewExtractorBytes interface {
 	ExtractActiveItems(pageHTML []byte) ([]ogame.ActiveItem, error)
+	ExtractBuildQueue(pageHTML []byte) []ogame.QueuedBuild
 	ExtractCancelBuildingInfos(pageHTML []byte) (token string, techID, listID int64, err error)
 	ExtractCancelFleetToken(pageHTML []byte, fleetID ogame.FleetID) (string, error)
 	ExtractCancelLfBuildingInfos(pageHTML []byte) (token string, id, listID int64, err error)
@@ -125,6 +132,7 @@ type FleetDispatchExtractorDoc interface {
 	FleetsExtractorBytes
 	ExtractFleet1ShipsFromDoc(doc *goquery.Document) (s ogame.ShipsInfos)
 	ExtractFleetDispatchACSFromDoc(doc *goquery.Document) []ogame.ACSValues
+	ExtractAllowedSpeedsFromDoc(doc *goquery.Document) []ogame.Speed
 }
 
 type FleetDispatchExtractorBytesDoc interface {
@@ -143,6 +151,7 @@ type ShipyardExtractorBytes interface {
 type ShipyardExtractorDoc interface {
 	ExtractProductionFromDoc(doc *goquery.Document) ([]ogame.Quantifiable, error)
 	ExtractShipsFromDoc(doc *goquery.Document) (ogame.ShipsInfos, error)
+	ExtractShipsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning
 }
 
 type ShipyardExtractorBytesDoc interface {
@@ -171,6 +180,7 @@ type FacilitiesExtractorBytes interface {
 
 type FacilitiesExtractorDoc interface {
 	ExtractFacilitiesFromDoc(doc *goquery.Document) (ogame.Facilities, error)
+	ExtractFacilitiesWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning
 }
 
 type FacilitiesExtractorBytesDoc interface {
@@ -185,6 +195,7 @@ type PhalanxExtractorBytes interface {
 type PreferencesExtractorBytes interface {
 	ExtractPopopsCombatreportFromDoc(doc *goquery.Document) bool
 	ExtractPreferences(pageHTML []byte) ogame.Preferences
+	ExtractPreferencesForm(pageHTML []byte) url.Values
 	ExtractPreferencesShowActivityMinutes(pageHTML []byte) bool
 	ExtractSpioAnz(pageHTML []byte) int64
 }
@@ -232,6 +243,7 @@ type DefensesExtractorBytes interface {
 
 type DefensesExtractorDoc interface {
 	ExtractDefenseFromDoc(doc *goquery.Document) (ogame.DefensesInfos, error)
+	ExtractDefenseWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning
 }
 
 type DefensesExtractorBytesDoc interface {
@@ -241,12 +253,16 @@ type DefensesExtractorBytesDoc interface {
 
 type EventListExtractorBytes interface {
 	ExtractAttacks(pageHTML []byte, ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error)
+	ExtractEvents(pageHTML []byte) ([]ogame.Event, error)
 	ExtractFleetsFromEventList(pageHTML []byte) []ogame.Fleet
+	ExtractUnionEvents(pageHTML []byte) []ogame.ACSUnionDetails
 }
 
 type EventListExtractorDoc interface {
 	ExtractAttacksFromDoc(doc *goquery.Document, ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error)
+	ExtractEventsFromDoc(doc *goquery.Document) ([]ogame.Event, error)
 	ExtractFleetsFromEventListFromDoc(doc *goquery.Document) []ogame.Fleet
+	ExtractUnionEventsFromDoc(doc *goquery.Document) []ogame.ACSUnionDetails
 }
 
 type EventListExtractorBytesDoc interface {
@@ -446,6 +462,7 @@ type ResourcesBuildingsExtractorBytes interface {
 
 type ResourcesBuildingsExtractorDoc interface {
 	ExtractResourcesBuildingsFromDoc(doc *goquery.Document) (ogame.ResourcesBuildings, error)
+	ExtractResourcesBuildingsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning
 }
 
 type ResourcesBuildingsExtractorBytesDoc interface {
@@ -113,6 +113,11 @@ func (e *Extractor) ExtractLifeformEnabled(pageHTML []byte) bool {
 	return bytes.Contains(pageHTML, []byte(`lifeformEnabled":true`))
 }
 
+// ExtractAllianceID returns the alliance id of the player, 0 when not in an alliance
+func (e *Extractor) ExtractAllianceID(pageHTML []byte) int64 {
+	return extractAllianceID(pageHTML)
+}
+
 // ExtractIsInVacation ...
 func (e *Extractor) ExtractIsInVacation(pageHTML []byte) bool {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -155,18 +160,46 @@ func (e *Extractor) ExtractCelestial(pageHTML []byte, v any) (ogame.Celestial, e
 	return e.ExtractCelestialFromDoc(doc, v)
 }
 
+// ExtractNotifications ...
+func (e *Extractor) ExtractNotifications(pageHTML []byte) []ogame.Notification {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractNotificationsFromDoc(doc)
+}
+
+// ExtractUniverseSpeeds ...
+func (e *Extractor) ExtractUniverseSpeeds(pageHTML []byte) ogame.EffectiveSpeeds {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractUniverseSpeedsFromDoc(doc)
+}
+
 // ExtractServerTime ...
 func (e *Extractor) ExtractServerTime(pageHTML []byte) (time.Time, error) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
 	return e.ExtractServerTimeFromDoc(doc)
 }
 
+// ExtractEvents ...
+func (e *Extractor) ExtractEvents(pageHTML []byte) ([]ogame.Event, error) {
+	return e.extractEvents(pageHTML, clockwork.NewRealClock())
+}
+
+func (e *Extractor) extractEvents(pageHTML []byte, clock clockwork.Clock) ([]ogame.Event, error) {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractEventsFromDoc(doc, clock)
+}
+
 // ExtractFleetsFromEventList ...
 func (e *Extractor) ExtractFleetsFromEventList(pageHTML []byte) []ogame.Fleet {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
 	return e.ExtractFleetsFromEventListFromDoc(doc)
 }
 
+// ExtractUnionEvents ...
+func (e *Extractor) ExtractUnionEvents(pageHTML []byte) []ogame.ACSUnionDetails {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return e.ExtractUnionEventsFromDoc(doc)
+}
+
 // ExtractIPM ...
 func (e *Extractor) ExtractIPM(pageHTML []byte) (duration, max int64, token string) {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -316,6 +349,12 @@ func (e *Extractor) ExtractPreferences(pageHTML []byte) ogame.Preferences {
 	return e.ExtractPreferencesFromDoc(doc)
 }
 
+// ExtractPreferencesForm returns the values the preferences form would post as is, token included
+func (e *Extractor) ExtractPreferencesForm(pageHTML []byte) url.Values {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractFormValuesFromDoc(doc.Find("form#prefs"))
+}
+
 // ExtractSpioAnz ...
 func (e *Extractor) ExtractSpioAnz(pageHTML []byte) int64 {
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -467,6 +506,26 @@ func (e *Extractor) ExtractFacilitiesFromDoc(doc *goquery.Document) (ogame.Facil
 	return extractFacilitiesFromDoc(doc)
 }
 
+// ExtractResourcesBuildingsWarningsFromDoc v6 pages do not list the technologies ids, no check is done
+func (e *Extractor) ExtractResourcesBuildingsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return []ogame.ParseWarning{}
+}
+
+// ExtractDefenseWarningsFromDoc v6 pages do not list the technologies ids, no check is done
+func (e *Extractor) ExtractDefenseWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return []ogame.ParseWarning{}
+}
+
+// ExtractShipsWarningsFromDoc v6 pages do not list the technologies ids, no check is done
+func (e *Extractor) ExtractShipsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return []ogame.ParseWarning{}
+}
+
+// ExtractFacilitiesWarningsFromDoc v6 pages do not list the technologies ids, no check is done
+func (e *Extractor) ExtractFacilitiesWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return []ogame.ParseWarning{}
+}
+
 // ExtractResearchFromDoc ...
 func (e *Extractor) ExtractResearchFromDoc(doc *goquery.Document) ogame.Researches {
 	return extractResearchFromDoc(doc)
@@ -506,6 +565,11 @@ func (e *Extractor) ExtractFleet1ShipsFromDoc(doc *goquery.Document) (s ogame.Sh
 	return extractFleet1ShipsFromDoc(doc)
 }
 
+// ExtractAllowedSpeedsFromDoc the fleet speeds of the fleet dispatch page
+func (e *Extractor) ExtractAllowedSpeedsFromDoc(doc *goquery.Document) []ogame.Speed {
+	return extractAllowedSpeedsFromDoc(doc)
+}
+
 // ExtractFleetDispatchACSFromDoc ...
 func (e *Extractor) ExtractFleetDispatchACSFromDoc(doc *goquery.Document) []ogame.ACSValues {
 	return extractFleetDispatchACSFromDoc(doc)
@@ -513,7 +577,7 @@ func (e *Extractor) ExtractFleetDispatchACSFromDoc(doc *goquery.Document) []ogam
 
 // ExtractEspionageReportMessageIDsFromDoc ...
 func (e *Extractor) ExtractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.EspionageReportSummary, int64) {
-	return extractEspionageReportMessageIDsFromDoc(doc)
+	return extractEspionageReportMessageIDsFromDoc(doc, e.GetLocation())
 }
 
 // ExtractCombatReportMessagesFromDoc ...
@@ -541,11 +605,21 @@ func (e *Extractor) ExtractResourceSettingsFromDoc(doc *goquery.Document) (ogame
 	return extractResourceSettingsFromDoc(doc)
 }
 
+// ExtractEventsFromDoc ...
+func (e *Extractor) ExtractEventsFromDoc(doc *goquery.Document) ([]ogame.Event, error) {
+	return extractEventsFromDoc(doc, clockwork.NewRealClock())
+}
+
 // ExtractFleetsFromEventListFromDoc ...
 func (e *Extractor) ExtractFleetsFromEventListFromDoc(doc *goquery.Document) []ogame.Fleet {
 	return extractFleetsFromEventListFromDoc(doc)
 }
 
+// ExtractUnionEventsFromDoc ...
+func (e *Extractor) ExtractUnionEventsFromDoc(doc *goquery.Document) []ogame.ACSUnionDetails {
+	return extractUnionEventsFromDoc(doc)
+}
+
 // ExtractIPMFromDoc ...
 func (e *Extractor) ExtractIPMFromDoc(doc *goquery.Document) (duration, max int64, token string) {
 	return extractIPMFromDoc(doc)
@@ -566,6 +640,16 @@ func (e *Extractor) ExtractSlotsFromDoc(doc *goquery.Document) ogame.Slots {
 	return extractSlotsFromDoc(doc)
 }
 
+// ExtractNotificationsFromDoc ...
+func (e *Extractor) ExtractNotificationsFromDoc(doc *goquery.Document) []ogame.Notification {
+	return extractNotificationsFromDoc(doc)
+}
+
+// ExtractUniverseSpeedsFromDoc ...
+func (e *Extractor) ExtractUniverseSpeedsFromDoc(doc *goquery.Document) ogame.EffectiveSpeeds {
+	return extractUniverseSpeedsFromDoc(doc)
+}
+
 // ExtractServerTimeFromDoc ...
 func (e *Extractor) ExtractServerTimeFromDoc(doc *goquery.Document) (time.Time, error) {
 	return extractServerTimeFromDoc(doc)
@@ -780,6 +864,11 @@ func (e *Extractor) ExtractAjaxChatToken(pageHTML []byte) (string, error) {
 	return extractAjaxChatToken(pageHTML)
 }
 
+// ExtractMiniFleetToken ...
+func (e *Extractor) ExtractMiniFleetToken(pageHTML []byte) (string, error) {
+	return extractMiniFleetToken(pageHTML)
+}
+
 // ExtractUserInfos ...
 func (e *Extractor) ExtractUserInfos(pageHTML []byte) (ogame.UserInfos, error) {
 	return extractUserInfos(pageHTML, e.GetLanguage())
@@ -828,6 +917,12 @@ func (e *Extractor) ExtractFleetDeutSaveFactor(pageHTML []byte) float64 {
 	return extractFleetDeutSaveFactor(pageHTML)
 }
 
+// ExtractBuildQueue extracts the building queue from the overview page
+func (e *Extractor) ExtractBuildQueue(pageHTML []byte) []ogame.QueuedBuild {
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	return extractBuildQueueFromDoc(doc)
+}
+
 // ExtractCancelBuildingInfos ...
 func (e *Extractor) ExtractCancelBuildingInfos(pageHTML []byte) (token string, techID, listID int64, err error) {
 	return extractCancelBuildingInfos(pageHTML)
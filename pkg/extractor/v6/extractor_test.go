@@ -1,6 +1,10 @@
 package v6
 
 import (
+	"bytes"
+	"errors"
+
+	"github.com/PuerkitoBio/goquery"
 	"github.com/alaingilbert/clockwork"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/stretchr/testify/assert"
@@ -142,6 +146,42 @@ func TestExtractAttacksACSMany(t *testing.T) {
 	assert.Equal(t, int64(7), attacks[2].Ships.Battlecruiser)
 }
 
+func TestExtractEvents(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/eventlist_friendly_from_moon.html")
+	events, err := NewExtractor().extractEvents(pageHTMLBytes, clockwork.NewFakeClock())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, int64(14708015), events[0].ID)
+	assert.Equal(t, ogame.OwnEvent, events[0].Type)
+	assert.Equal(t, ogame.Transport, events[0].MissionType)
+	assert.True(t, events[0].ReturnFlight)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 126, Position: 8, Type: ogame.PlanetType}, events[0].Origin)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 4, System: 116, Position: 12, Type: ogame.PlanetType}, events[0].Destination)
+	assert.Equal(t, "Homeworld", events[0].DestinationName)
+	assert.Equal(t, int64(210), events[0].Ships.LargeCargo)
+	assert.Equal(t, time.Unix(1539932619, 0), events[0].ArrivalTime)
+	assert.Equal(t, ogame.Resources{}, *events[0].Resources)
+	assert.Equal(t, ogame.Attack, events[1].MissionType)
+	assert.Equal(t, ogame.Resources{Metal: 235000, Crystal: 127500, Deuterium: 37500}, *events[1].Resources)
+	assert.Equal(t, ogame.MoonType, events[2].Origin.Type)
+	assert.Nil(t, events[2].Resources)
+	assert.Equal(t, ogame.NeutralEvent, events[2].Type)
+	assert.Equal(t, int64(1200), events[2].ShipsCount)
+}
+
+func TestExtractEventsACS(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/eventlist_acs.html")
+	events, err := NewExtractor().extractEvents(pageHTMLBytes, clockwork.NewFakeClock())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, ogame.HostileEvent, events[0].Type)
+	assert.Equal(t, ogame.GroupedAttack, events[0].MissionType)
+	assert.Equal(t, int64(19205235), events[0].UnionID)
+	assert.Equal(t, int64(2186), events[0].ShipsCount)
+	assert.Equal(t, int64(10), events[0].Ships.LightFighter)
+	assert.Equal(t, int64(2176), events[0].Ships.Battlecruiser)
+}
+
 func TestExtractAttacksACS2(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/eventlist_acs2.html")
 	attacks, _ := NewExtractor().extractAttacks(pageHTMLBytes, clockwork.NewFakeClock(), nil)
@@ -175,6 +215,13 @@ func TestExtractAttacks1(t *testing.T) {
 	assert.Nil(t, attacks[0].Ships)
 }
 
+func TestExtractAllianceID(t *testing.T) {
+	pageHTML, _ := ioutil.ReadFile("../../../samples/unversioned/overview_boosters.html")
+	assert.Equal(t, int64(205), NewExtractor().ExtractAllianceID(pageHTML))
+	pageHTML, _ = ioutil.ReadFile("../../../samples/unversioned/overview_active.html")
+	assert.Equal(t, int64(0), NewExtractor().ExtractAllianceID(pageHTML))
+}
+
 func TestExtractLifeformEnabled(t *testing.T) {
 	pageHTML, _ := ioutil.ReadFile("../../../samples/unversioned/overview_active.html")
 	assert.False(t, NewExtractor().ExtractLifeformEnabled(pageHTML))
@@ -915,9 +962,26 @@ func TestExtractEspionageReportMessageIDs(t *testing.T) {
 	assert.Equal(t, ogame.Coordinate{4, 117, 6, ogame.PlanetType}, msgs[0].Target)
 	assert.Equal(t, 0.5, msgs[0].LootPercentage)
 	assert.Equal(t, "Fleet Command", msgs[0].From)
+	assert.Equal(t, "08.07.2018 02:16:16", msgs[0].CreatedAt.Format("02.01.2006 15:04:05"))
 	assert.Equal(t, ogame.Action, msgs[1].Type)
 	assert.Equal(t, "Space Monitoring", msgs[1].From)
 	assert.Equal(t, ogame.Coordinate{4, 117, 9, ogame.PlanetType}, msgs[1].Target)
+	assert.Equal(t, "Origin", msgs[1].Attacker)
+	assert.Equal(t, ogame.Coordinate{4, 116, 8, ogame.PlanetType}, msgs[1].AttackerOrigin)
+	assert.Equal(t, int64(10), msgs[1].CounterEspionage)
+	assert.Equal(t, "", msgs[0].Attacker)
+}
+
+func TestExtractEspionageReportMessageIDsActions(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7.1/en/messages.html")
+	msgs, _ := NewExtractor().ExtractEspionageReportMessageIDs(pageHTMLBytes)
+	assert.Equal(t, ogame.Report, msgs[0].Type)
+	assert.Equal(t, ogame.Action, msgs[1].Type)
+	assert.Equal(t, int64(2678093), msgs[1].ID)
+	assert.Equal(t, "Jalmag", msgs[1].Attacker)
+	assert.Equal(t, ogame.Coordinate{1, 442, 9, ogame.PlanetType}, msgs[1].AttackerOrigin)
+	assert.Equal(t, ogame.Coordinate{1, 432, 14, ogame.PlanetType}, msgs[1].Target)
+	assert.Equal(t, int64(0), msgs[1].CounterEspionage)
 }
 
 func TestExtractEspionageReportMessageIDsLootPercentage(t *testing.T) {
@@ -994,6 +1058,21 @@ func TestExtractPreferencesShowActivityMinutes(t *testing.T) {
 	assert.False(t, checked)
 }
 
+func TestExtractPreferencesForm(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/preferences.html")
+	vals := NewExtractor().ExtractPreferencesForm(pageHTMLBytes)
+	assert.Equal(t, "6f85365287bbf7110bfc8cf5c9c6ef3a", vals.Get("token"))
+	assert.Equal(t, "save", vals.Get("mode"))
+	assert.Equal(t, "10", vals.Get("spio_anz"))
+	assert.Equal(t, "1", vals.Get("eventsShow"))
+	assert.Equal(t, "on", vals.Get("animatedSliders"))
+	assert.Equal(t, "1", vals.Get("showActivityMinutes"))
+	_, spioReportPictures := vals["spioReportPictures"]
+	assert.False(t, spioReportPictures)
+	_, urlaubsModus := vals["urlaubs_modus"]
+	assert.False(t, urlaubsModus)
+}
+
 func TestExtractPreferences(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/preferences.html")
 	prefs := NewExtractor().ExtractPreferences(pageHTMLBytes)
@@ -1168,6 +1247,21 @@ func TestExtractGalaxyInfos_vacationMode(t *testing.T) {
 	assert.EqualError(t, err, "account in vacation mode")
 }
 
+func TestExtractGalaxyInfos_skeleton(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/galaxy_skeleton.html")
+	infos, err := NewExtractor().ExtractGalaxyInfos(pageHTMLBytes, "Commodore Nomade", 123, 456)
+	assert.Equal(t, ogame.ErrGalaxySkeleton, err)
+	assert.Equal(t, int64(4), infos.Tmpgalaxy)
+	assert.Equal(t, int64(116), infos.Tmpsystem)
+}
+
+func TestExtractGalaxyInfos_mobile(t *testing.T) {
+	// The mobile view has neither the filters nor the rows, it must not be mistaken for a skeleton
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/galaxy_mobile.html")
+	_, err := NewExtractor().ExtractGalaxyInfos(pageHTMLBytes, "Commodore Nomade", 123, 456)
+	assert.Equal(t, ogame.ErrMobileView, err)
+}
+
 func TestExtractGalaxyInfos_bandit(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/galaxy_inactive_bandit_lord.html")
 	infos, _ := NewExtractor().ExtractGalaxyInfos(pageHTMLBytes, "Commodore Nomade", 123, 456)
@@ -1915,6 +2009,7 @@ func TestExtractFleetV71(t *testing.T) {
 	assert.Equal(t, int64(250), fleets[0].Ships.SmallCargo)
 	assert.Equal(t, int64(2), fleets[0].Ships.Pathfinder)
 	assert.Equal(t, ogame.Resources{}, fleets[0].Resources)
+	assert.False(t, fleets[0].CarriesResources)
 }
 
 func TestExtractFleetV72(t *testing.T) {
@@ -2008,6 +2103,7 @@ func TestExtractFleet(t *testing.T) {
 	assert.Equal(t, int64(1), fleets[0].Ships.ColonyShip)
 	assert.Equal(t, int64(1), fleets[0].Ships.EspionageProbe)
 	assert.Equal(t, ogame.Resources{Metal: 123, Crystal: 456, Deuterium: 789}, fleets[0].Resources)
+	assert.True(t, fleets[0].CarriesResources)
 }
 
 func TestExtractFleet_expedition(t *testing.T) {
@@ -2118,6 +2214,23 @@ func TestExtractFleet_unionID(t *testing.T) {
 	assert.Equal(t, int64(13558), fleets[0].UnionID)
 }
 
+func TestExtractUnionEvents(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/fleets_union_two.html")
+	unions := NewExtractor().ExtractUnionEvents(pageHTMLBytes)
+	assert.Equal(t, 1, len(unions))
+	assert.Equal(t, int64(19021280), unions[0].EventID)
+	assert.Equal(t, ogame.Coordinate{4, 208, 10, ogame.PlanetType}, unions[0].Destination)
+	assert.Equal(t, int64(1567486132), unions[0].ArrivalTime.Unix())
+	assert.Equal(t, int64(2), unions[0].TotalShips)
+	assert.Equal(t, 2, len(unions[0].Members))
+	assert.Equal(t, ogame.FleetID(19021280), unions[0].Members[0].FleetID)
+	assert.Equal(t, "Own fleet", unions[0].Members[0].Name)
+	assert.Equal(t, ogame.Coordinate{4, 208, 8, ogame.PlanetType}, unions[0].Members[0].Origin)
+	assert.Equal(t, int64(1), unions[0].Members[0].Ships.LargeCargo)
+	assert.Equal(t, ogame.FleetID(19021282), unions[0].Members[1].FleetID)
+	assert.Equal(t, int64(1), unions[0].Members[1].Ships.SmallCargo)
+}
+
 func TestExtractOverviewProduction(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/overview_shipyard_queue_full.html")
 	prods, countdown, _ := NewExtractor().ExtractOverviewProduction(pageHTMLBytes)
@@ -2528,3 +2641,105 @@ func TestExtractOGameSession(t *testing.T) {
 	session = NewExtractor().ExtractOGameSession(pageHTMLBytes)
 	assert.Equal(t, "c1626ce8228ac5986e3808a7d42d4afc764c1b68", session)
 }
+
+func TestIsLogged(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7/overview_mobile.html")
+	assert.True(t, IsLogged(pageHTMLBytes))
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.2/pl/defence.html")
+	assert.True(t, IsLogged(pageHTMLBytes))
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/eventlist_loggedout.html")
+	assert.False(t, IsLogged(pageHTMLBytes))
+}
+
+func TestIsLobbyPage(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/eventlist_loggedout.html")
+	assert.True(t, IsLobbyPage(pageHTMLBytes))
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/eventList.html")
+	assert.False(t, IsLobbyPage(pageHTMLBytes))
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.2/pl/defence.html")
+	assert.False(t, IsLobbyPage(pageHTMLBytes))
+}
+
+func TestExtractNotifications(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/fleets_no_union.html")
+	notifs := NewExtractor().ExtractNotifications(pageHTMLBytes)
+	assert.Equal(t, 2, len(notifs))
+	assert.Equal(t, ogame.NotificationMessages, notifs[0].Type)
+	assert.Equal(t, int64(63), notifs[0].Count)
+	assert.Equal(t, "63 unread message(s)", notifs[0].Title)
+	assert.Equal(t, ogame.NotificationWreckField, notifs[1].Type)
+	assert.Equal(t, int64(77), notifs[1].Count)
+	assert.Equal(t, int64(32646), notifs[1].Countdown)
+	assert.Equal(t, "Wreckages: Small Cargo: 77", notifs[1].Title)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/overview_always_events.html")
+	notifs = NewExtractor().ExtractNotifications(pageHTMLBytes)
+	assert.Equal(t, 2, len(notifs))
+	assert.Equal(t, int64(372), notifs[0].Count)
+	assert.Equal(t, ogame.NotificationAttack, notifs[1].Type)
+	assert.Equal(t, "Attack!", notifs[1].Title)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.2/en/overview_all_queues.html")
+	notifs = NewExtractor().ExtractNotifications(pageHTMLBytes)
+	assert.Equal(t, 1, len(notifs))
+	assert.Equal(t, int64(11), notifs[0].Count)
+}
+
+func TestExtractUniverseSpeeds(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v9.0.2/en/overview_all_queues.html")
+	speeds := NewExtractor().ExtractUniverseSpeeds(pageHTMLBytes)
+	assert.Equal(t, ogame.EffectiveSpeeds{Economy: 6, FleetPeaceful: 6, FleetWar: 4, FleetHolding: 6}, speeds)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/fleets_no_union.html")
+	speeds = NewExtractor().ExtractUniverseSpeeds(pageHTMLBytes)
+	assert.Equal(t, speeds.FleetPeaceful, speeds.FleetWar)
+	assert.Equal(t, speeds.FleetPeaceful, speeds.FleetHolding)
+	assert.True(t, speeds.FleetPeaceful > 0)
+}
+
+func TestExtractPlanet_relocationDuplicate(t *testing.T) {
+	// During a relocation, the planet list shows the old and the new planet at the same coordinate.
+	// Simulated by duplicating the planet of a captured page with another id.
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/overview_queues.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	original := doc.Find("div#planet-33677371")
+	original.AfterSelection(original.Clone().SetAttr("id", "planet-33699999"))
+	pageHTML, _ := doc.Html()
+
+	coord := ogame.Coordinate{1, 301, 8, ogame.PlanetType}
+	_, err := NewExtractor().ExtractPlanet([]byte(pageHTML), coord)
+	var ambiguousErr *ogame.ErrAmbiguousCoordinate
+	if assert.True(t, errors.As(err, &ambiguousErr)) {
+		assert.Equal(t, coord, ambiguousErr.Coordinate)
+		assert.Equal(t, []ogame.CelestialID{33677371, 33699999}, ambiguousErr.CandidateIDs)
+	}
+	planet, err := NewExtractor().ExtractPlanet([]byte(pageHTML), ogame.PlanetID(33699999))
+	assert.NoError(t, err)
+	assert.Equal(t, coord, planet.Coordinate)
+}
+
+func TestExtractMiniFleetToken(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/pl_overview.html")
+	token, err := NewExtractor().ExtractMiniFleetToken(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "6b88481530269383954ade9301125d68", token)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.0/en/overview.html")
+	token, err = NewExtractor().ExtractMiniFleetToken(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "5913a38be12b5c73dc3ca19901189f20", token)
+}
+
+func TestExtractBuildQueue(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/overview_queue_buildings.html")
+	queue := NewExtractor().ExtractBuildQueue(pageHTMLBytes)
+	assert.Equal(t, []ogame.QueuedBuild{
+		{ID: ogame.SolarPlantID, Level: 4, ListID: 637336},
+		{ID: ogame.MetalMineID, Level: 4, ListID: 637337},
+		{ID: ogame.MetalMineID, Level: 5, ListID: 637338},
+	}, queue)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/unversioned/overview_inactive.html")
+	queue = NewExtractor().ExtractBuildQueue(pageHTMLBytes)
+	assert.Equal(t, 0, len(queue))
+}
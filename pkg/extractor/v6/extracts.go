@@ -118,14 +118,30 @@ func extractCelestialByIDFromDoc(doc *goquery.Document, celestialID ogame.Celest
 	return nil, errors.New("invalid celestial id")
 }
 
+// extractCelestialByCoordFromDoc returns an *ogame.ErrAmbiguousCoordinate if several celestials are at the coordinate,
+// which happens while a planet is being relocated.
 func extractCelestialByCoordFromDoc(doc *goquery.Document, coord ogame.Coordinate) (ogame.Celestial, error) {
-	celestials := extractCelestialsFromDoc(doc)
-	for _, celestial := range celestials {
+	matches := extractCelestialsByCoordFromDoc(doc, coord)
+	if len(matches) == 0 {
+		return nil, errors.New("invalid coordinate")
+	}
+	if len(matches) > 1 {
+		err := &ogame.ErrAmbiguousCoordinate{Coordinate: coord}
+		for _, celestial := range matches {
+			err.CandidateIDs = append(err.CandidateIDs, celestial.GetID())
+		}
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+func extractCelestialsByCoordFromDoc(doc *goquery.Document, coord ogame.Coordinate) (out []ogame.Celestial) {
+	for _, celestial := range extractCelestialsFromDoc(doc) {
 		if celestial.GetCoordinate().Equal(coord) {
-			return celestial, nil
+			out = append(out, celestial)
 		}
 	}
-	return nil, errors.New("invalid coordinate")
+	return
 }
 
 func extractCelestialsFromDoc(doc *goquery.Document) []ogame.Celestial {
@@ -469,6 +485,128 @@ func extractAttacksFromDoc(doc *goquery.Document, clock clockwork.Clock, ownCoor
 	return out, nil
 }
 
+func extractEventsFromDoc(doc *goquery.Document, clock clockwork.Clock) ([]ogame.Event, error) {
+	out := make([]ogame.Event, 0)
+	if doc.Find("body").Size() == 1 && ExtractOGameSessionFromDoc(doc) != "" && doc.Find("div#eventListWrap").Size() == 0 {
+		return out, ogame.ErrEventsBoxNotDisplayed
+	} else if doc.Find("div#eventListWrap").Size() == 0 {
+		return out, ogame.ErrNotLogged
+	}
+
+	unionRgx := regexp.MustCompile(`^union(\d+)$`)
+	idRgx := regexp.MustCompile(`(?:eventRow|counter-eventlist)-(\d+)`)
+	events := make([]*ogame.Event, 0)
+	unions := make(map[int64]*ogame.Event)
+
+	doc.Find("tr.allianceAttack, tr.eventFleet").Each(func(i int, s *goquery.Selection) {
+		event := &ogame.Event{}
+		if m := idRgx.FindStringSubmatch(s.AttrOr("id", "")); len(m) == 2 {
+			event.ID = utils.DoParseI64(m[1])
+		} else if m := idRgx.FindStringSubmatch(s.Find("td.countDown").AttrOr("id", "")); len(m) == 2 {
+			event.ID = utils.DoParseI64(m[1])
+		}
+		for _, c := range strings.Fields(s.AttrOr("class", "")) {
+			if m := unionRgx.FindStringSubmatch(c); len(m) == 2 {
+				event.UnionID = utils.DoParseI64(m[1])
+			}
+		}
+
+		td := s.Find("td.countDown")
+		event.Type = ogame.NeutralEvent
+		if td.HasClass("hostile") || td.Find("span.hostile").Size() > 0 {
+			event.Type = ogame.HostileEvent
+		} else if td.HasClass("friendly") || td.Find("span.friendly").Size() > 0 {
+			event.Type = ogame.OwnEvent
+		}
+		event.MissionType = ogame.MissionID(utils.DoParseI64(s.AttrOr("data-mission-type", "")))
+		event.ReturnFlight = s.AttrOr("data-return-flight", "") == "true"
+		event.ArrivalTime = time.Unix(utils.DoParseI64(s.AttrOr("data-arrival-time", "")), 0)
+		event.ArriveIn = int64(clock.Until(event.ArrivalTime).Seconds())
+
+		event.Origin = ExtractCoord(strings.TrimSpace(s.Find("td.coordsOrigin").Text()))
+		event.Origin.Type = ogame.PlanetType
+		if s.Find("td.originFleet figure").HasClass("moon") {
+			event.Origin.Type = ogame.MoonType
+		}
+		event.OriginName = strings.TrimSpace(s.Find("td.originFleet").Text())
+		event.Destination = ExtractCoord(strings.TrimSpace(s.Find("td.destCoords").Text()))
+		event.Destination.Type = ogame.PlanetType
+		if s.Find("td.destFleet figure").HasClass("moon") {
+			event.Destination.Type = ogame.MoonType
+		}
+		event.DestinationName = strings.TrimSpace(s.Find("td.destFleet").Text())
+
+		linkSendMail := s.Find("a.sendMail")
+		event.PlayerID = utils.DoParseI64(linkSendMail.AttrOr("data-playerid", ""))
+		event.PlayerName = linkSendMail.AttrOr("title", "")
+
+		if event.MissionType == ogame.MissileAttack {
+			event.Missiles = utils.ParseInt(s.Find("td.detailsFleet span").First().Text())
+		} else {
+			event.ShipsCount = utils.ParseInt(s.Find("td.detailsFleet span").First().Text())
+		}
+
+		if movement, exists := s.Find("td.icon_movement span, td.icon_movement_reserve span").Attr("title"); exists {
+			if root, err := html.Parse(strings.NewReader(movement)); err == nil {
+				event.Ships = new(ogame.ShipsInfos)
+				section := 0 // 1: ships, 2: shipment
+				shipment := make([]int64, 0)
+				goquery.NewDocumentFromNode(root).Find("tr").Each(func(i int, s *goquery.Selection) {
+					if s.Find("th").Size() > 0 {
+						section++
+						return
+					}
+					if section == 2 {
+						shipment = append(shipment, utils.ParseInt(s.Find("td").Last().Text()))
+						return
+					}
+					name := s.Find("td").Eq(0).Text()
+					nbrTxt := s.Find("td").Eq(1).Text()
+					shipID := ogame.ShipName2ID(name)
+					if !shipID.IsShip() {
+						return
+					}
+					if nbr := utils.ParseInt(nbrTxt); nbr > 0 {
+						event.Ships.Set(shipID, nbr)
+					} else if nbrTxt == "?" {
+						event.Ships.Set(shipID, -1)
+					}
+				})
+				if len(shipment) >= 3 {
+					event.Resources = &ogame.Resources{Metal: shipment[0], Crystal: shipment[1], Deuterium: shipment[2]}
+				}
+			}
+		}
+
+		if s.HasClass("partnerInfo") {
+			if union, ok := unions[event.UnionID]; ok {
+				if event.Ships != nil {
+					if union.Ships == nil {
+						union.Ships = new(ogame.ShipsInfos)
+					}
+					union.Ships.Add(*event.Ships)
+				}
+				if union.PlayerID == 0 {
+					union.PlayerID, union.PlayerName = event.PlayerID, event.PlayerName
+				}
+				if union.Origin.Equal(ogame.Coordinate{}) {
+					union.Origin, union.OriginName = event.Origin, event.OriginName
+				}
+			}
+			return
+		}
+		if s.HasClass("allianceAttack") && event.UnionID != 0 {
+			unions[event.UnionID] = event
+		}
+		events = append(events, event)
+	})
+
+	for _, e := range events {
+		out = append(out, *e)
+	}
+	return out, nil
+}
+
 func extractOfferOfTheDayFromDoc(doc *goquery.Document) (price int64, importToken string, planetResources ogame.PlanetResources, multiplier ogame.Multiplier, err error) {
 	s := doc.Find("div.js_import_price")
 	if s.Size() == 0 {
@@ -582,6 +720,10 @@ func extractFleet1ShipsFromDoc(doc *goquery.Document) (s ogame.ShipsInfos) {
 	return
 }
 
+func extractAllowedSpeedsFromDoc(doc *goquery.Document) []ogame.Speed {
+	return append([]ogame.Speed{}, ogame.DefaultAllowedSpeeds...)
+}
+
 func extractFleetDispatchACSFromDoc(doc *goquery.Document) []ogame.ACSValues {
 	out := make([]ogame.ACSValues, 0)
 	doc.Find("select[name=acsValues] option").Each(func(i int, s *goquery.Selection) {
@@ -595,7 +737,7 @@ func extractFleetDispatchACSFromDoc(doc *goquery.Document) []ogame.ACSValues {
 	return out
 }
 
-func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.EspionageReportSummary, int64) {
+func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document, location *time.Location) ([]ogame.EspionageReportSummary, int64) {
 	msgs := make([]ogame.EspionageReportSummary, 0)
 	nbPage := utils.DoParseI64(doc.Find("ul.pagination li").Last().AttrOr("data-page", "1"))
 	doc.Find("li.msg").Each(func(i int, s *goquery.Selection) {
@@ -607,6 +749,7 @@ func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.Esp
 				}
 				report := ogame.EspionageReportSummary{ID: id, Type: messageType}
 				report.From = s.Find("span.msg_sender").Text()
+				report.CreatedAt, _ = time.ParseInLocation("02.01.2006 15:04:05", s.Find("span.msg_date").Text(), location)
 				spanLink := s.Find("span.msg_title a")
 				targetStr := spanLink.Text()
 				report.Target = ExtractCoord(targetStr)
@@ -614,6 +757,19 @@ func extractEspionageReportMessageIDsFromDoc(doc *goquery.Document) ([]ogame.Esp
 				if spanLink.Find("figure").HasClass("moon") {
 					report.Target.Type = ogame.MoonType
 				}
+				if messageType == ogame.Action {
+					defText := s.Find("span.espionageDefText")
+					originLink := defText.Find("a.txt_link").First()
+					report.AttackerOrigin = ExtractCoord(originLink.Text())
+					report.AttackerOrigin.Type = ogame.PlanetType
+					if originLink.Find("figure").HasClass("moon") {
+						report.AttackerOrigin.Type = ogame.MoonType
+					}
+					report.Attacker = strings.TrimSpace(defText.Find("span.player").Text())
+					if m := regexp.MustCompile(`(\d+)\s*%`).FindStringSubmatch(defText.Text()); len(m) == 2 {
+						report.CounterEspionage = utils.DoParseI64(m[1])
+					}
+				}
 				if messageType == ogame.Report {
 					s.Find("div.compacting").Each(func(i int, s *goquery.Selection) {
 						if regexp.MustCompile(`%`).MatchString(s.Text()) {
@@ -1083,6 +1239,73 @@ func extractFleetsFromEventListFromDoc(doc *goquery.Document) []ogame.Fleet {
 	return res
 }
 
+func extractUnionEventsFromDoc(doc *goquery.Document) []ogame.ACSUnionDetails {
+	res := make([]ogame.ACSUnionDetails, 0)
+	unionRgx := regexp.MustCompile(`^union(\d+)$`)
+	extractEventID := func(s *goquery.Selection) int64 {
+		for _, c := range strings.Fields(s.AttrOr("class", "")) {
+			if m := unionRgx.FindStringSubmatch(c); len(m) == 2 {
+				return utils.DoParseI64(m[1])
+			}
+		}
+		return 0
+	}
+	extractDestination := func(s *goquery.Selection) ogame.Coordinate {
+		coord := ExtractCoord(strings.TrimSpace(s.Find("td.destCoords").Text()))
+		coord.Type = ogame.PlanetType
+		if s.Find("td.destFleet figure").HasClass("moon") {
+			coord.Type = ogame.MoonType
+		}
+		return coord
+	}
+	doc.Find("tr.allianceAttack").Each(func(i int, s *goquery.Selection) {
+		eventID := extractEventID(s)
+		if eventID == 0 {
+			return
+		}
+		union := ogame.ACSUnionDetails{EventID: eventID, Members: make([]ogame.ACSUnionMember, 0)}
+		union.Destination = extractDestination(s)
+		union.ArrivalTime = time.Unix(utils.DoParseI64(s.AttrOr("data-arrival-time", "0")), 0)
+		union.TotalShips = utils.ParseInt(s.Find("td.detailsFleet span").First().Text())
+		res = append(res, union)
+	})
+	doc.Find("tr.partnerInfo").Each(func(i int, s *goquery.Selection) {
+		eventID := extractEventID(s)
+		for idx := range res {
+			if res[idx].EventID != eventID {
+				continue
+			}
+			member := ogame.ACSUnionMember{}
+			movement := s.Find("td.icon_movement span")
+			member.FleetID = ogame.FleetID(utils.DoParseI64(movement.AttrOr("data-federation-user-id", "0")))
+			member.Name = strings.TrimSpace(s.Find("td.descFleet").Text())
+			member.PlayerID = utils.DoParseI64(s.Find("a.sendMail").AttrOr("data-playerid", "0"))
+			member.Origin = ExtractCoord(strings.TrimSpace(s.Find("td.coordsOrigin").Text()))
+			member.Origin.Type = ogame.PlanetType
+			if s.Find("td.originFleet figure").HasClass("moon") {
+				member.Origin.Type = ogame.MoonType
+			}
+			member.ArrivalTime = time.Unix(utils.DoParseI64(s.AttrOr("data-arrival-time", "0")), 0)
+			if title := movement.AttrOr("title", ""); title != "" {
+				root, _ := html.Parse(strings.NewReader(title))
+				ships := ogame.ShipsInfos{}
+				goquery.NewDocumentFromNode(root).Find("tr").Each(func(i int, s *goquery.Selection) {
+					name := s.Find("td").Eq(0).Text()
+					nbr := utils.ParseInt(s.Find("td").Eq(1).Text())
+					if shipID := ogame.ShipName2ID(name); shipID.IsShip() && nbr > 0 {
+						ships.Set(shipID, nbr)
+					}
+				})
+				if ships.CountShips() > 0 {
+					member.Ships = &ships
+				}
+			}
+			res[idx].Members = append(res[idx].Members, member)
+		}
+	})
+	return res
+}
+
 func extractIPMFromDoc(doc *goquery.Document) (duration, max int64, token string) {
 	duration = utils.DoParseI64(doc.Find("span#timer").AttrOr("data-duration", "0"))
 	max = utils.DoParseI64(doc.Find("input[name=anz]").AttrOr("data-max", "0"))
@@ -1145,6 +1368,9 @@ func extractFleetsFromDoc(doc *goquery.Document, location *time.Location, lifefo
 		shipment.Metal = utils.ParseInt(trs.Eq(trs.Size() - metalTrOffset).Find("td").Eq(1).Text())
 		shipment.Crystal = utils.ParseInt(trs.Eq(trs.Size() - crystalTrOffset).Find("td").Eq(1).Text())
 		shipment.Deuterium = utils.ParseInt(trs.Eq(trs.Size() - DeuteriumTrOffset).Find("td").Eq(1).Text())
+		if lifeformEnabled {
+			shipment.Food = utils.ParseInt(trs.Eq(trs.Size() - 1).Find("td").Eq(1).Text())
+		}
 
 		fedAttackHref := s.Find("span.fedAttack a").AttrOr("href", "")
 		fedAttackURL, _ := url.Parse(fedAttackHref)
@@ -1160,6 +1386,7 @@ func extractFleetsFromDoc(doc *goquery.Document, location *time.Location, lifefo
 		fleet.ReturnFlight = returnFlight
 		fleet.InDeepSpace = inDeepSpace
 		fleet.Resources = shipment
+		fleet.CarriesResources = shipment.Total() > 0 || shipment.Food > 0
 		fleet.TargetPlanetID = targetPlanetID
 		fleet.UnionID = unionID
 		fleet.ArrivalTime = time.Unix(endTime, 0)
@@ -1241,6 +1468,105 @@ func extractServerTimeFromDoc(doc *goquery.Document) (time.Time, error) {
 	return serverTime, nil
 }
 
+func extractNotificationsFromDoc(doc *goquery.Document) []ogame.Notification {
+	res := make([]ogame.Notification, 0)
+	wrapper := doc.Find("div#message-wrapper")
+	for _, n := range []struct {
+		typ ogame.NotificationType
+		sel string
+	}{
+		{ogame.NotificationMessages, "span.totalMessages"},
+		{ogame.NotificationChat, "span.totalChatMessages"},
+	} {
+		span := wrapper.Find(n.sel)
+		count := utils.ParseInt(span.AttrOr("data-new-messages", "0"))
+		if count > 0 {
+			title := strings.TrimSpace(span.Parent().AttrOr("title", ""))
+			res = append(res, ogame.Notification{Type: n.typ, Count: count, Title: title})
+		}
+	}
+	alert := wrapper.Find("div#attack_alert")
+	if alert.HasClass("wreckField") {
+		tooltip := alert.Find("a").AttrOr("title", "")
+		notif := ogame.Notification{Type: ogame.NotificationWreckField}
+		notif.Countdown = utils.ParseInt(alert.Find("#wreckFieldCountDown").AttrOr("data-duration", "0"))
+		lines := strings.Split(strings.ReplaceAll(tooltip, "|", "<br/>"), "<br/>")
+		parts := make([]string, 0, len(lines))
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			parts = append(parts, line)
+			if idx := strings.LastIndex(line, ":"); idx != -1 {
+				notif.Count += utils.ParseInt(line[idx+1:])
+			}
+		}
+		notif.Title = strings.Join(parts, " ")
+		res = append(res, notif)
+	} else if alert.Length() > 0 && !alert.HasClass("noAttack") {
+		res = append(res, ogame.Notification{Type: ogame.NotificationAttack, Count: 1, Title: alert.AttrOr("title", "")})
+	}
+	return res
+}
+
+func extractUniverseSpeedsFromDoc(doc *goquery.Document) ogame.EffectiveSpeeds {
+	meta := func(name string) int64 {
+		return utils.DoParseI64(doc.Find(`meta[name="`+name+`"]`).AttrOr("content", "0"))
+	}
+	res := ogame.EffectiveSpeeds{
+		Economy:       meta("ogame-universe-speed"),
+		FleetPeaceful: meta("ogame-universe-speed-fleet-peaceful"),
+		FleetWar:      meta("ogame-universe-speed-fleet-war"),
+		FleetHolding:  meta("ogame-universe-speed-fleet-holding"),
+	}
+	// Older versions have a single fleet speed
+	if fleetSpeed := meta("ogame-universe-speed-fleet"); fleetSpeed > 0 {
+		if res.FleetPeaceful == 0 {
+			res.FleetPeaceful = fleetSpeed
+		}
+		if res.FleetWar == 0 {
+			res.FleetWar = fleetSpeed
+		}
+		if res.FleetHolding == 0 {
+			res.FleetHolding = fleetSpeed
+		}
+	}
+	return res
+}
+
+// extractFormValuesFromDoc returns the values a browser would submit for the form
+func extractFormValuesFromDoc(form *goquery.Selection) url.Values {
+	vals := url.Values{}
+	form.Find("input[name], select[name], textarea[name]").Each(func(_ int, s *goquery.Selection) {
+		name := s.AttrOr("name", "")
+		if name == "" {
+			return
+		}
+		switch goquery.NodeName(s) {
+		case "select":
+			option := s.Find("option[selected]").First()
+			if option.Length() == 0 {
+				option = s.Find("option").First()
+			}
+			vals.Add(name, option.AttrOr("value", strings.TrimSpace(option.Text())))
+		case "textarea":
+			vals.Add(name, s.Text())
+		default:
+			switch s.AttrOr("type", "text") {
+			case "checkbox", "radio":
+				if _, checked := s.Attr("checked"); checked {
+					vals.Add(name, s.AttrOr("value", "on"))
+				}
+			case "submit", "button", "image", "file":
+			default:
+				vals.Add(name, s.AttrOr("value", ""))
+			}
+		}
+	})
+	return vals
+}
+
 func extractSpioAnzFromDoc(doc *goquery.Document) int64 {
 	out := utils.DoParseI64(doc.Find("input[name=spio_anz]").AttrOr("value", "1"))
 	return out
@@ -1489,6 +1815,14 @@ func extractPlanetTypeFromDoc(doc *goquery.Document) (ogame.CelestialType, error
 	return 0, errors.New("invalid planet type : " + planetType)
 }
 
+func extractAllianceID(pageHTML []byte) int64 {
+	m := regexp.MustCompile(`<meta name="ogame-alliance-id" content="(\d+)"`).FindSubmatch(pageHTML)
+	if len(m) != 2 {
+		return 0
+	}
+	return utils.DoParseI64(string(m[1]))
+}
+
 func extractAjaxChatToken(pageHTML []byte) (string, error) {
 	r1 := regexp.MustCompile(`ajaxChatToken\s?=\s?['"](\w+)['"]`)
 	m1 := r1.FindSubmatch(pageHTML)
@@ -1499,6 +1833,14 @@ func extractAjaxChatToken(pageHTML []byte) (string, error) {
 	return token, nil
 }
 
+func extractMiniFleetToken(pageHTML []byte) (string, error) {
+	m := regexp.MustCompile(`miniFleetToken\s?=\s?['"](\w+)['"]`).FindSubmatch(pageHTML)
+	if len(m) < 2 {
+		return "", errors.New("unable to find mini fleet token")
+	}
+	return string(m[1]), nil
+}
+
 func extractUserInfos(pageHTML []byte, lang string) (ogame.UserInfos, error) {
 	playerIDRgx := regexp.MustCompile(`<meta name="ogame-player-id" content="(\d+)"/>`)
 	playerNameRgx := regexp.MustCompile(`<meta name="ogame-player-name" content="([^"]+)"/>`)
@@ -1598,9 +1940,17 @@ func extractUserInfos(pageHTML []byte, lang string) (ogame.UserInfos, error) {
 	return res, nil
 }
 
+// IsLogged returns either or not the page is an in-game page, it carries the session token or the player id
 func IsLogged(pageHTML []byte) bool {
 	return len(regexp.MustCompile(`<meta name="ogame-session" content="\w+"/>`).FindSubmatch(pageHTML)) == 1 ||
-		len(regexp.MustCompile(`var session = "\w+"`).FindSubmatch(pageHTML)) == 1
+		len(regexp.MustCompile(`var session = "\w+"`).FindSubmatch(pageHTML)) == 1 ||
+		regexp.MustCompile(`<meta name="ogame-player-id" content="\d+"\s*/?>`).Match(pageHTML)
+}
+
+// IsLobbyPage returns either or not the page is the lobby, that the game serves in place of any page once the session is gone
+func IsLobbyPage(pageHTML []byte) bool {
+	return bytes.Contains(pageHTML, []byte("browsergamelobby")) ||
+		bytes.Contains(pageHTML, []byte("<title>OGame Lobby</title>"))
 }
 
 func extractResourcesDetails(pageHTML []byte) (out ogame.ResourcesDetails, err error) {
@@ -1686,6 +2036,11 @@ func extractGalaxyInfos(pageHTML []byte, botPlayerName string, botPlayerID, botP
 	if isMobile {
 		return res, ogame.ErrMobileView
 	}
+	// A populated system always has 15 rows (empty positions included)
+	isSkeleton := doc.Find("tr.row").Length() == 0
+	if isSkeleton {
+		return res, ogame.ErrGalaxySkeleton
+	}
 	doc.Find("tr.row").Each(func(i int, s *goquery.Selection) {
 		classes, _ := s.Attr("class")
 		if !strings.Contains(classes, "empty_filter") {
@@ -1958,6 +2313,33 @@ func extractCancelBuildingInfos(pageHTML []byte) (token string, techID, listID i
 	return
 }
 
+// extractBuildQueueFromDoc the building being built and the buildings queued behind it.
+// Research, lifeform and shipyard queues use other cancel functions, so they are not picked up.
+func extractBuildQueueFromDoc(doc *goquery.Document) []ogame.QueuedBuild {
+	out := make([]ogame.QueuedBuild, 0)
+	seen := make(map[int64]bool)
+	rgx := regexp.MustCompile(`\bcancel(?:Production|building)\((\d+),\s?(\d+),`)
+	levelRgx := regexp.MustCompile(`\d+`)
+	doc.Find("a[onclick]").Each(func(i int, s *goquery.Selection) {
+		m := rgx.FindStringSubmatch(s.AttrOr("onclick", ""))
+		if len(m) != 3 {
+			return
+		}
+		listID := utils.DoParseI64(m[2])
+		if seen[listID] {
+			return
+		}
+		seen[listID] = true
+		levelTxt := s.Find("span").Text()
+		if s.Closest("table.queue").Length() == 0 {
+			levelTxt = s.Closest("table.construction").Find("span.level").Text()
+		}
+		level := utils.DoParseI64(levelRgx.FindString(levelTxt))
+		out = append(out, ogame.QueuedBuild{ID: ogame.ID(utils.DoParseI64(m[1])), Level: level, ListID: listID})
+	})
+	return out
+}
+
 func extractCancelResearchInfos(pageHTML []byte) (token string, techID, listID int64, err error) {
 	r1 := regexp.MustCompile(`page=overview&modus=2&token=(\w+)"\+"&techid="\+id\+"&listid="\+listId`)
 	m1 := r1.FindSubmatch(pageHTML)
@@ -152,6 +152,11 @@ func (e Extractor) ExtractFleet1ShipsFromDoc(doc *goquery.Document) (s ogame.Shi
 	return extractFleet1ShipsFromDoc(doc)
 }
 
+// ExtractAllowedSpeedsFromDoc the fleet speeds of the fleet dispatch page
+func (e Extractor) ExtractAllowedSpeedsFromDoc(doc *goquery.Document) []ogame.Speed {
+	return extractAllowedSpeedsFromDoc(doc)
+}
+
 // ExtractResourceSettingsFromDoc ...
 func (e Extractor) ExtractResourceSettingsFromDoc(doc *goquery.Document) (ogame.ResourceSettings, string, error) {
 	return extractResourceSettingsFromDoc(doc)
@@ -192,6 +197,29 @@ func (e Extractor) ExtractResourcesBuildingsFromDoc(doc *goquery.Document) (ogam
 	return extractResourcesBuildingsFromDoc(doc)
 }
 
+// ExtractResourcesBuildingsWarningsFromDoc ...
+func (e Extractor) ExtractResourcesBuildingsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return technologiesWarnings(doc, "supplies", func(id ogame.ID) bool {
+		isDen := id == ogame.ShieldedMetalDenID || id == ogame.UndergroundCrystalDenID || id == ogame.SeabedDeuteriumDenID
+		return (id.IsResourceBuilding() && !isDen) || id == ogame.SolarSatelliteID
+	})
+}
+
+// ExtractFacilitiesWarningsFromDoc ...
+func (e Extractor) ExtractFacilitiesWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return technologiesWarnings(doc, "facilities", ogame.ID.IsFacility)
+}
+
+// ExtractShipsWarningsFromDoc ...
+func (e Extractor) ExtractShipsWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return technologiesWarnings(doc, "shipyard", ogame.ID.IsShip)
+}
+
+// ExtractDefenseWarningsFromDoc ...
+func (e Extractor) ExtractDefenseWarningsFromDoc(doc *goquery.Document) []ogame.ParseWarning {
+	return technologiesWarnings(doc, "defenses", ogame.ID.IsDefense)
+}
+
 // ExtractCombatReportMessagesFromDoc ...
 func (e Extractor) ExtractCombatReportMessagesFromDoc(doc *goquery.Document) ([]ogame.CombatReportSummary, int64) {
 	return extractCombatReportMessagesFromDoc(doc)
@@ -1,10 +1,13 @@
 package v7
 
 import (
+	"bytes"
+	"github.com/PuerkitoBio/goquery"
 	"github.com/alaingilbert/clockwork"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 )
@@ -310,6 +313,17 @@ func TestExtractFleetSlot_FleetDispatch(t *testing.T) {
 	assert.Equal(t, int64(1), s.ExpTotal)
 }
 
+func TestExtractAllowedSpeeds(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7/fleetdispatch.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	assert.Equal(t, ogame.DefaultAllowedSpeeds, NewExtractor().ExtractAllowedSpeedsFromDoc(doc))
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(`<div id="speedPercentage" class="percentageBar" value="20" steps="20" stepSize="5" minValue="1"></div>`))
+	speeds := NewExtractor().ExtractAllowedSpeedsFromDoc(doc)
+	assert.Len(t, speeds, 20)
+	assert.Equal(t, ogame.FivePercent, speeds[0])
+}
+
 func TestGetConstructionsV7(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7/overview_supplies_in_construction.html")
 	clock := clockwork.NewFakeClockAt(time.Date(2019, 11, 12, 9, 6, 43, 0, time.UTC))
@@ -319,3 +333,24 @@ func TestGetConstructionsV7(t *testing.T) {
 	assert.Equal(t, ogame.EnergyTechnologyID, researchID)
 	assert.Equal(t, int64(271), researchCountdown)
 }
+
+func TestExtractWarningsFromDoc(t *testing.T) {
+	e := NewExtractor()
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/v7/supplies.html")
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	assert.Equal(t, 0, len(e.ExtractResourcesBuildingsWarningsFromDoc(doc)))
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v7/defenses.html")
+	doc, _ = goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	assert.Equal(t, 0, len(e.ExtractDefenseWarningsFromDoc(doc)))
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v7/facilities.html")
+	doc, _ = goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
+	assert.Equal(t, 0, len(e.ExtractFacilitiesWarningsFromDoc(doc)))
+	// A building the extractor does not know, and one whose level is gone
+	doc.Find("li.technology[data-technology='14']").SetAttr("data-technology", "99")
+	doc.Find("li.technology[data-technology='21'] span.level").Remove()
+	warnings := e.ExtractFacilitiesWarningsFromDoc(doc)
+	assert.Equal(t, 2, len(warnings))
+	assert.Equal(t, ogame.ParseWarning{Page: "facilities", Field: "Robotics Factory", Message: "unknown technology 99"}, warnings[0])
+	assert.Equal(t, ogame.ParseWarning{Page: "facilities", Field: ogame.ShipyardID.String(), Message: "level not found"}, warnings[1])
+}
@@ -25,6 +25,24 @@ func getNbrShips(doc *goquery.Document, name string) int64 {
 	return val
 }
 
+// technologiesWarnings checks the technologies listed on a page. It warns about the ones the extractor does not know,
+// and the ones whose level/amount cannot be read, both would otherwise silently be zero.
+func technologiesWarnings(doc *goquery.Document, page string, isKnown func(ogame.ID) bool) []ogame.ParseWarning {
+	warnings := make([]ogame.ParseWarning, 0)
+	doc.Find("li.technology[data-technology]").Each(func(_ int, s *goquery.Selection) {
+		id := ogame.ID(utils.DoParseI64(s.AttrOr("data-technology", "")))
+		if !isKnown(id) {
+			warnings = append(warnings, ogame.ParseWarning{Page: page, Field: s.AttrOr("aria-label", ""),
+				Message: "unknown technology " + utils.FI64(id)})
+			return
+		}
+		if _, err := utils.ParseI64(s.Find("span.level, span.amount").First().AttrOr("data-value", "")); err != nil {
+			warnings = append(warnings, ogame.ParseWarning{Page: page, Field: id.String(), Message: "level not found"})
+		}
+	})
+	return warnings
+}
+
 func extractPremiumToken(pageHTML []byte, days int64) (token string, err error) {
 	rgx := regexp.MustCompile(`\?page=premium&buynow=1&type=\d&days=` + utils.FI64(days) + `&token=(\w+)`)
 	m := rgx.FindSubmatch(pageHTML)
@@ -243,6 +261,17 @@ func extractIPMFromDoc(doc *goquery.Document) (duration, max int64, token string
 	return
 }
 
+func extractAllowedSpeedsFromDoc(doc *goquery.Document) []ogame.Speed {
+	bar := doc.Find("#speedPercentage")
+	maxStep := utils.DoParseI64(bar.AttrOr("steps", "0"))
+	stepSize := utils.DoParseI64(bar.AttrOr("stepsize", "0"))
+	minStep := utils.DoParseI64(bar.AttrOr("minvalue", "1"))
+	if maxStep <= 0 || stepSize <= 0 {
+		return append([]ogame.Speed{}, ogame.DefaultAllowedSpeeds...)
+	}
+	return ogame.NewAllowedSpeeds(minStep, maxStep, stepSize)
+}
+
 func extractFleet1ShipsFromDoc(doc *goquery.Document) (s ogame.ShipsInfos) {
 	onclick := doc.Find("div#fleetdispatchcomponent")
 	h, _ := onclick.Html()
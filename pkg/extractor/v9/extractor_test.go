@@ -146,6 +146,8 @@ func TestExtractFleetResources(t *testing.T) {
 	assert.Equal(t, int64(1), fleets[0].Resources.Metal)
 	assert.Equal(t, int64(2), fleets[0].Resources.Crystal)
 	assert.Equal(t, int64(3), fleets[0].Resources.Deuterium)
+	assert.Equal(t, int64(0), fleets[0].Resources.Food)
+	assert.True(t, fleets[0].CarriesResources)
 }
 
 func TestExtractLfBuildings(t *testing.T) {
@@ -195,6 +197,11 @@ func TestExtractTechnologyDetails(t *testing.T) {
 	assert.Equal(t, int64(40000), details.Price.Crystal)
 	assert.Equal(t, int64(50000), details.Price.Deuterium)
 	assert.Equal(t, int64(100000000), details.Price.Population)
+	assert.Equal(t, int64(30), details.EnergyNeeded)
+	assert.Equal(t, "Neuro-Calibration Centre", details.Name)
+	assert.Equal(t, "T2 Humans are trained up into T3 Humans in the Neuro-Calibration Centre. Each level increases the number of trained individuals.", details.Description)
+	assert.True(t, details.HasRequirements)
+	assert.False(t, details.UpgradeEnabled)
 	assert.False(t, details.TearDownEnabled)
 
 	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.4/en/lifeform/technologyDetails_lfbuilding_teardown_enabled.html")
@@ -215,6 +222,11 @@ func TestExtractTechnologyDetails(t *testing.T) {
 
 	pageHTMLBytes, _ = ioutil.ReadFile("../../../samples/v9.0.4/en/lifeform/technologyDetails_supplies.html")
 	details, _ = NewExtractor().ExtractTechnologyDetails(pageHTMLBytes)
+	assert.Equal(t, ogame.ID(1), details.TechnologyID)
+	assert.Equal(t, "Metal Mine", details.Name)
+	assert.Equal(t, int64(269), details.EnergyNeeded)
+	assert.False(t, details.HasRequirements)
+	assert.True(t, details.UpgradeEnabled)
 	assert.True(t, details.TearDownEnabled)
 }
 
@@ -227,3 +239,14 @@ func TestExtractOverviewProduction_ships(t *testing.T) {
 	assert.Equal(t, ogame.SmallCargoID, prod[1].ID)
 	assert.Equal(t, int64(1), prod[1].Nbr)
 }
+
+func TestExtractLfResearch(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../../samples/unversioned/lfresearch.html")
+	res, err := NewExtractor().ExtractLfResearch(pageHTMLBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), res.IntergalacticEnvoys)
+	assert.Equal(t, int64(1), res.ImprovedLabTechnology)
+	assert.Equal(t, int64(2), res.PlasmaTerraformer)
+	assert.Equal(t, int64(4), res.RocktalCollectorEnhancement)
+	assert.Equal(t, int64(0), res.HighPerformanceExtractors)
+}
@@ -656,7 +656,7 @@ func extractLfResearchFromDoc(doc *goquery.Document) (ogame.LfResearches, error)
 	res.LightFighterMkII = GetNbr(doc, "lifeformTech11209")
 	res.CruiserMkII = GetNbr(doc, "lifeformTech11210")
 	res.ImprovedLabTechnology = GetNbr(doc, "lifeformTech11211")
-	res.PlasmaTerraformer = GetNbr(doc, "lifeformTech11112")
+	res.PlasmaTerraformer = GetNbr(doc, "lifeformTech11212")
 	res.LowTemperatureDrives = GetNbr(doc, "lifeformTech11213")
 	res.BomberMkII = GetNbr(doc, "lifeformTech11214")
 	res.DestroyerMkII = GetNbr(doc, "lifeformTech11215")
@@ -723,6 +723,9 @@ func extractLfResearchFromDoc(doc *goquery.Document) (ogame.LfResearches, error)
 
 func extractTechnologyDetailsFromDoc(doc *goquery.Document) (out ogame.TechnologyDetails, err error) {
 	out.TechnologyID = ogame.ID(utils.DoParseI64(doc.Find("div#technologydetails").AttrOr("data-technology-id", "")))
+	out.Name = strings.TrimSpace(doc.Find("div.content h3").First().Text())
+	out.Description = strings.Join(strings.Fields(doc.Find("div.description span.text").Text()), " ")
+	out.HasRequirements = !doc.Find("button.technology_tree").HasClass("no_prerequisites")
 
 	durationStr := doc.Find("li.build_duration time").AttrOr("datetime", "")
 	rgx := regexp.MustCompile(`PT(?:(\d+)H)?(?:(\d+)M)?(\d+)S`)
@@ -741,7 +744,11 @@ func extractTechnologyDetailsFromDoc(doc *goquery.Document) (out ogame.Technolog
 	out.Price.Crystal = utils.DoParseI64(doc.Find("div.costs li.crystal").AttrOr("data-value", ""))
 	out.Price.Deuterium = utils.DoParseI64(doc.Find("div.costs li.deuterium").AttrOr("data-value", ""))
 	out.Price.Population = utils.DoParseI64(doc.Find("div.costs li.population").AttrOr("data-value", ""))
+	out.EnergyNeeded = utils.DoParseI64(doc.Find("li.additional_energy_consumption span.value").AttrOr("data-value", "0"))
 
+	upgradeBtn := doc.Find("button.upgrade")
+	_, disabled := upgradeBtn.Attr("disabled")
+	out.UpgradeEnabled = upgradeBtn.Length() == 1 && !disabled
 	out.TearDownEnabled = extractTearDownButtonEnabledFromDoc(doc)
 
 	return out, err
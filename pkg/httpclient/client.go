@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
 )
 
+// DefaultMaxResponseBytes default maximum size of a response body
+const DefaultMaxResponseBytes = 64 << 20
+
 type IHttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 	Get(url string) (*http.Response, error)
@@ -32,6 +36,7 @@ type Client struct {
 	rpsStartTime    int64 // atomic
 	bytesDownloaded int64
 	bytesUploaded   int64
+	maxRespBytes    int64 // atomic
 }
 
 func (c *Client) BytesDownloaded() int64 {
@@ -52,7 +57,8 @@ func NewClient() *Client {
 		Client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		maxRPS: 0,
+		maxRPS:       0,
+		maxRespBytes: DefaultMaxResponseBytes,
 	}
 
 	const delay = 1
@@ -74,6 +80,17 @@ func (c *Client) SetMaxRPS(maxRPS int32) {
 	atomic.StoreInt32(&c.maxRPS, maxRPS)
 }
 
+// SetMaxResponseBytes sets the maximum size of a response body, bigger responses fail with utils.ErrResponseTooLarge.
+// 0 means no limit.
+func (c *Client) SetMaxResponseBytes(maxBytes int64) {
+	atomic.StoreInt64(&c.maxRespBytes, maxBytes)
+}
+
+// MaxResponseBytes returns the maximum size of a response body
+func (c *Client) MaxResponseBytes() int64 {
+	return atomic.LoadInt64(&c.maxRespBytes)
+}
+
 func (c *Client) incrRPS() {
 	newRPS := atomic.AddInt32(&c.rpsCounter, 1)
 	maxRPS := atomic.LoadInt32(&c.maxRPS)
@@ -121,13 +138,16 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	body, _ := ioutil.ReadAll(resp.Body)
 	defer resp.Body.Close()
+	body, err := utils.ReadAllLimit(resp.Body, c.MaxResponseBytes())
+	if err == utils.ErrResponseTooLarge {
+		return nil, err
+	}
 	c.bytesDownloaded += int64(len(body))
 	c.bytesUploaded += req.ContentLength
 	// Reset resp.Body so it can be use again
 	resp.Body = io.NopCloser(bytes.NewBuffer(body))
-	return resp, err
+	return resp, nil
 }
 
 func (c *Client) WithTransport(tr http.RoundTripper, clb func(*Client) error) error {
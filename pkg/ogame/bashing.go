@@ -0,0 +1,38 @@
+package ogame
+
+import (
+	"sort"
+	"time"
+)
+
+// BashingLimit maximum number of attacks that can be launched against a same planet or moon within BashingWindow
+const BashingLimit = 6
+
+// BashingWindow period over which the attacks against a target are counted for the bashing limit
+const BashingWindow = 24 * time.Hour
+
+// IsBashingMission returns either or not a mission counts toward the bashing limit
+func IsBashingMission(mission MissionID) bool {
+	return mission == Attack || mission == GroupedAttack || mission == Destroy
+}
+
+// AttacksRemaining returns the number of attacks that can still be launched against a target,
+// given the launch times of the previous attacks against it.
+// resetAt is when the oldest attack of the window expires and frees one more attack, zero time if no attack is in the window.
+func AttacksRemaining(launches []time.Time, now time.Time) (remaining int64, resetAt time.Time) {
+	inWindow := make([]time.Time, 0, len(launches))
+	for _, launch := range launches {
+		if now.Sub(launch) < BashingWindow {
+			inWindow = append(inWindow, launch)
+		}
+	}
+	sort.Slice(inWindow, func(i, j int) bool { return inWindow[i].Before(inWindow[j]) })
+	remaining = BashingLimit - int64(len(inWindow))
+	if remaining < 0 {
+		remaining = 0
+	}
+	if len(inWindow) > 0 {
+		resetAt = inWindow[0].Add(BashingWindow)
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttacksRemaining(t *testing.T) {
+	now := time.Date(2022, 8, 21, 12, 0, 0, 0, time.UTC)
+	remaining, resetAt := AttacksRemaining(nil, now)
+	assert.Equal(t, int64(6), remaining)
+	assert.True(t, resetAt.IsZero())
+
+	launches := []time.Time{
+		now.Add(-25 * time.Hour), // Out of the window
+		now.Add(-time.Hour),
+		now.Add(-20 * time.Hour),
+	}
+	remaining, resetAt = AttacksRemaining(launches, now)
+	assert.Equal(t, int64(4), remaining)
+	assert.Equal(t, now.Add(4*time.Hour), resetAt)
+
+	for i := 0; i < 6; i++ {
+		launches = append(launches, now.Add(-time.Duration(i)*time.Minute))
+	}
+	remaining, _ = AttacksRemaining(launches, now)
+	assert.Equal(t, int64(0), remaining)
+}
+
+func TestIsBashingMission(t *testing.T) {
+	assert.True(t, IsBashingMission(Attack))
+	assert.True(t, IsBashingMission(GroupedAttack))
+	assert.True(t, IsBashingMission(Destroy))
+	assert.False(t, IsBashingMission(Spy))
+	assert.False(t, IsBashingMission(MissileAttack))
+}
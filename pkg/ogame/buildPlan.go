@@ -0,0 +1,50 @@
+package ogame
+
+import (
+	"time"
+)
+
+// BuildStep one upgrade of a build plan
+type BuildStep struct {
+	ID       ID
+	Level    int64 // Level reached once the upgrade is done
+	Price    Resources
+	Duration time.Duration
+}
+
+// planBuildingsIDs resource buildings that can be planned, solar satellites are ships and are left out
+var planBuildingsIDs = []ID{MetalMineID, CrystalMineID, DeuteriumSynthesizerID, SolarPlantID, FusionReactorID,
+	MetalStorageID, CrystalStorageID, DeuteriumTankID}
+
+// PlanToMatch returns the ordered upgrades needed to bring the current resource buildings up to the target levels,
+// the total price and the total construction time.
+// The cheapest upgrade available is always done first, so mines and energy buildings grow together.
+// Buildings already above the target level are not torn down.
+func PlanToMatch(current, target ResourcesBuildings, facilities BuildAccelerators, universeSpeed int64) ([]BuildStep, Resources, time.Duration) {
+	steps := make([]BuildStep, 0)
+	var totalPrice Resources
+	var totalDuration time.Duration
+	for {
+		var next *BuildStep
+		for _, id := range planBuildingsIDs {
+			level := current.ByID(id)
+			if level >= target.ByID(id) {
+				continue
+			}
+			obj := Objs.ByID(id)
+			price := obj.GetPrice(level + 1)
+			if next == nil || price.Total() < next.Price.Total() {
+				next = &BuildStep{ID: id, Level: level + 1, Price: price}
+			}
+		}
+		if next == nil {
+			break
+		}
+		next.Duration = Objs.ByID(next.ID).ConstructionTime(next.Level, universeSpeed, facilities, false, false)
+		current.Set(next.ID, next.Level)
+		totalPrice = totalPrice.Add(next.Price)
+		totalDuration += next.Duration
+		steps = append(steps, *next)
+	}
+	return steps, totalPrice, totalDuration
+}
@@ -0,0 +1,26 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanToMatch(t *testing.T) {
+	current := ResourcesBuildings{CrystalMine: 5}
+	target := ResourcesBuildings{MetalMine: 2, SolarPlant: 1, CrystalMine: 3, SolarSatellite: 10}
+	steps, price, duration := PlanToMatch(current, target, Facilities{}, 1)
+	assert.Equal(t, 3, len(steps))
+	assert.Equal(t, BuildStep{ID: MetalMineID, Level: 1, Price: MetalMine.GetPrice(1), Duration: steps[0].Duration}, steps[0])
+	assert.Equal(t, SolarPlantID, steps[1].ID)
+	assert.Equal(t, MetalMineID, steps[2].ID)
+	assert.Equal(t, int64(2), steps[2].Level)
+	assert.Equal(t, MetalMine.GetPrice(1).Add(MetalMine.GetPrice(2)).Add(SolarPlant.GetPrice(1)), price)
+	assert.Equal(t, steps[0].Duration+steps[1].Duration+steps[2].Duration, duration)
+	assert.True(t, duration > 0)
+
+	steps, price, duration = PlanToMatch(target, target, Facilities{}, 1)
+	assert.Equal(t, 0, len(steps))
+	assert.Equal(t, Resources{}, price)
+	assert.Equal(t, int64(0), int64(duration))
+}
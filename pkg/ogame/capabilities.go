@@ -0,0 +1,63 @@
+package ogame
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Capability name of a feature that only some servers support
+type Capability string
+
+// Capabilities names
+const (
+	CapabilityLifeform         Capability = "lifeform"
+	CapabilityMarketplace      Capability = "marketplace"
+	CapabilityCrawlers         Capability = "crawlers"
+	CapabilityWars             Capability = "wars"
+	CapabilityNewMessagesAPI   Capability = "new_messages_api"
+	CapabilityThreeFleetSpeeds Capability = "three_fleet_speeds"
+)
+
+// Capabilities feature name -> supported by the server and the bot
+type Capabilities map[Capability]bool
+
+// Supports returns either or not a capability is supported, unknown capabilities are not
+func (c Capabilities) Supports(capability Capability) bool {
+	return c[capability]
+}
+
+// ServerFeatures what the server data and the game pages tell about a server
+type ServerFeatures struct {
+	Version                 string // eg: 9.0.4
+	LifeformEnabled         bool
+	MarketplaceEnabled      bool
+	CharacterClassesEnabled bool
+	SpeedFleetPeaceful      int64
+}
+
+// versionAtLeast returns either or not a "major.minor.patch" version is at least major.minor
+func versionAtLeast(v string, major, minor int64) bool {
+	parts := strings.SplitN(v, ".", 3)
+	gotMajor, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return false
+	}
+	var gotMinor int64
+	if len(parts) > 1 {
+		gotMinor, _ = strconv.ParseInt(strings.TrimFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' }), 10, 64)
+	}
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
+
+// NewCapabilities returns the capabilities of a server.
+// Alliance wars and the JSON messages API are not implemented by the bot, they are always reported unsupported.
+func NewCapabilities(features ServerFeatures) Capabilities {
+	return Capabilities{
+		CapabilityLifeform:         features.LifeformEnabled,
+		CapabilityMarketplace:      features.MarketplaceEnabled && versionAtLeast(features.Version, 7, 0),
+		CapabilityCrawlers:         features.CharacterClassesEnabled && versionAtLeast(features.Version, 7, 0),
+		CapabilityWars:             false,
+		CapabilityNewMessagesAPI:   false,
+		CapabilityThreeFleetSpeeds: features.SpeedFleetPeaceful > 0 || versionAtLeast(features.Version, 8, 1),
+	}
+}
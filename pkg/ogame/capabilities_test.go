@@ -0,0 +1,35 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	assert.True(t, versionAtLeast("8.1.0", 8, 1))
+	assert.True(t, versionAtLeast("9.0.4", 8, 1))
+	assert.True(t, versionAtLeast("10.0.0-beta", 8, 1))
+	assert.False(t, versionAtLeast("8.0.9", 8, 1))
+	assert.False(t, versionAtLeast("7.6.7", 8, 1))
+	assert.False(t, versionAtLeast("", 7, 0))
+	assert.True(t, versionAtLeast("7", 7, 0))
+}
+
+func TestNewCapabilities(t *testing.T) {
+	v6 := NewCapabilities(ServerFeatures{Version: "6.8.8-pl2"})
+	assert.False(t, v6.Supports(CapabilityLifeform))
+	assert.False(t, v6.Supports(CapabilityMarketplace))
+	assert.False(t, v6.Supports(CapabilityCrawlers))
+	assert.False(t, v6.Supports(CapabilityThreeFleetSpeeds))
+
+	v9 := NewCapabilities(ServerFeatures{Version: "9.0.4", LifeformEnabled: true, MarketplaceEnabled: true, CharacterClassesEnabled: true, SpeedFleetPeaceful: 2})
+	assert.True(t, v9.Supports(CapabilityLifeform))
+	assert.True(t, v9.Supports(CapabilityMarketplace))
+	assert.True(t, v9.Supports(CapabilityCrawlers))
+	assert.True(t, v9.Supports(CapabilityThreeFleetSpeeds))
+	assert.False(t, v9.Supports(CapabilityWars))
+	assert.False(t, v9.Supports(CapabilityNewMessagesAPI))
+	assert.False(t, v9.Supports("unknown"))
+	assert.Len(t, v9, 6)
+}
@@ -2,6 +2,7 @@ package ogame
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -54,3 +55,14 @@ func (c Coordinate) Moon() Coordinate {
 func (c Coordinate) Debris() Coordinate {
 	return Coordinate{Galaxy: c.Galaxy, System: c.System, Position: c.Position, Type: DebrisType}
 }
+
+// SystemDistance returns the number of systems between two systems of a galaxy
+func SystemDistance(nbSystems, system1, system2 int64, donutSystem bool) int64 {
+	if !donutSystem {
+		return int64(math.Abs(float64(system2 - system1)))
+	}
+	if system1 > system2 {
+		system1, system2 = system2, system1
+	}
+	return int64(math.Min(float64(system2-system1), float64((system1+nbSystems)-system2)))
+}
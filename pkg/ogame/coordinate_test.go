@@ -54,3 +54,9 @@ func TestCoordinate_Debris(t *testing.T) {
 	assert.Equal(t, Coordinate{1, 2, 3, MoonType}.Debris(), Coordinate{1, 2, 3, DebrisType})
 	assert.Equal(t, Coordinate{1, 2, 3, DebrisType}.Debris(), Coordinate{1, 2, 3, DebrisType})
 }
+
+func TestSystemDistance(t *testing.T) {
+	assert.Equal(t, int64(3), SystemDistance(499, 10, 13, false))
+	assert.Equal(t, int64(497), SystemDistance(499, 2, 499, false))
+	assert.Equal(t, int64(2), SystemDistance(499, 2, 499, true))
+}
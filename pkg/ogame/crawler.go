@@ -1,5 +1,11 @@
 package ogame
 
+import (
+	"math"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
 type crawler struct {
 	BaseShip
 }
@@ -22,3 +28,25 @@ func newCrawler() *crawler {
 	c.Requirements = map[ID]int64{ShipyardID: 5, CombustionDriveID: 4, ArmourTechnologyID: 4, LaserTechnologyID: 4}
 	return c
 }
+
+// crawlerEnergyConsumption energy consumed by one crawler at 100% utilization
+const crawlerEnergyConsumption = 50
+
+// GetMaxCrawlers returns how many crawlers can boost the mines production, 8 per mine level
+func (c crawler) GetMaxCrawlers(resourcesBuildings ResourcesBuildings) int64 {
+	return 8 * (resourcesBuildings.MetalMine + resourcesBuildings.CrystalMine + resourcesBuildings.DeuteriumSynthesizer)
+}
+
+// GetUtilization returns the crawler utilization setting (by steps of 10%) that avoids an energy deficit.
+// An energy deficit lowers the utilization, an energy surplus raises it back up to 100%.
+func (c crawler) GetUtilization(current, nbCrawlers, energyAvailable int64) int64 {
+	if nbCrawlers <= 0 {
+		return current
+	}
+	energyPerPercent := float64(nbCrawlers*crawlerEnergyConsumption) / 100
+	target := int64(math.Floor((float64(current)+float64(energyAvailable)/energyPerPercent)/10)) * 10
+	if energyAvailable >= 0 {
+		return utils.MaxInt(current, utils.MinInt(target, 100))
+	}
+	return utils.Clamp(target, 0, current)
+}
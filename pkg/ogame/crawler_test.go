@@ -0,0 +1,26 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawler_GetMaxCrawlers(t *testing.T) {
+	c := newCrawler()
+	assert.Equal(t, int64(0), c.GetMaxCrawlers(ResourcesBuildings{}))
+	assert.Equal(t, int64(8*(20+18+15)), c.GetMaxCrawlers(ResourcesBuildings{MetalMine: 20, CrystalMine: 18, DeuteriumSynthesizer: 15, SolarPlant: 22}))
+}
+
+func TestCrawler_GetUtilization(t *testing.T) {
+	c := newCrawler()
+	assert.Equal(t, int64(100), c.GetUtilization(100, 0, -500))
+	// 100 crawlers consume 50 energy per percent
+	assert.Equal(t, int64(80), c.GetUtilization(100, 100, -600))
+	assert.Equal(t, int64(90), c.GetUtilization(100, 100, -500))
+	assert.Equal(t, int64(0), c.GetUtilization(100, 100, -100000))
+	assert.Equal(t, int64(100), c.GetUtilization(100, 100, 10))
+	assert.Equal(t, int64(70), c.GetUtilization(50, 100, 1000))
+	assert.Equal(t, int64(100), c.GetUtilization(50, 100, 100000))
+	assert.Equal(t, int64(150), c.GetUtilization(150, 100, 0))
+}
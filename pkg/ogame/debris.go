@@ -0,0 +1,13 @@
+package ogame
+
+import (
+	"math"
+)
+
+// maxMoonChance maximum chance (percent) of a debris field to create a moon
+const maxMoonChance = 20
+
+// MoonChance returns the chance (percent) of a debris field to create a moon, 1% per 100,000 resources, up to 20%
+func MoonChance(debris Resources) int64 {
+	return int64(math.Min(float64(debris.Total())/100_000, maxMoonChance))
+}
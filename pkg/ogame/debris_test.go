@@ -0,0 +1,13 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoonChance(t *testing.T) {
+	assert.Equal(t, int64(0), MoonChance(Resources{Metal: 90000}))
+	assert.Equal(t, int64(1), MoonChance(Resources{Metal: 90000, Crystal: 30000}))
+	assert.Equal(t, int64(20), MoonChance(Resources{Metal: 5_000_000}))
+}
@@ -43,3 +43,10 @@ func TestDeuteriumSynthesizer_EnergyConsumption(t *testing.T) {
 	ds := newDeuteriumSynthesizer()
 	assert.Equal(t, int64(6198), ds.EnergyConsumption(26))
 }
+
+func TestDeuteriumSynthesizer_TemperatureFactor(t *testing.T) {
+	ds := newDeuteriumSynthesizer()
+	assert.InDelta(t, 1.36, ds.TemperatureFactor(0), 0.0001)
+	assert.InDelta(t, 1.76, ds.TemperatureFactor(-100), 0.0001)
+	assert.InDelta(t, 0.96, ds.TemperatureFactor(100), 0.0001)
+}
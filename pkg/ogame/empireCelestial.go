@@ -17,3 +17,28 @@ type EmpireCelestial struct {
 	Researches  Researches
 	Ships       ShipsInfos
 }
+
+// EmpirePlanets builds the planets, with their moon, from the planets and moons of the empire page
+func EmpirePlanets(planets, moons []EmpireCelestial) []Planet {
+	moonsByCoord := make(map[Coordinate]EmpireCelestial, len(moons))
+	for _, moon := range moons {
+		moonsByCoord[moon.Coordinate.Planet()] = moon
+	}
+	out := make([]Planet, 0, len(planets))
+	for _, p := range planets {
+		planet := Planet{
+			Img:         p.Img,
+			ID:          PlanetID(p.ID),
+			Name:        p.Name,
+			Diameter:    p.Diameter,
+			Coordinate:  p.Coordinate,
+			Fields:      p.Fields,
+			Temperature: p.Temperature,
+		}
+		if m, ok := moonsByCoord[p.Coordinate.Planet()]; ok {
+			planet.Moon = &Moon{ID: MoonID(m.ID), Img: m.Img, Name: m.Name, Diameter: m.Diameter, Coordinate: m.Coordinate, Fields: m.Fields}
+		}
+		out = append(out, planet)
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmpirePlanets(t *testing.T) {
+	planets := []EmpireCelestial{
+		{ID: 1, Name: "Homeworld", Type: PlanetType, Diameter: 12800, Coordinate: Coordinate{1, 2, 3, PlanetType}, Fields: Fields{Built: 10, Total: 163}, Temperature: Temperature{Min: -10, Max: 30}},
+		{ID: 2, Name: "Colony", Type: PlanetType, Coordinate: Coordinate{1, 2, 8, PlanetType}},
+	}
+	moons := []EmpireCelestial{{ID: 3, Name: "Moon", Type: MoonType, Diameter: 8000, Coordinate: Coordinate{1, 2, 3, MoonType}}}
+	res := EmpirePlanets(planets, moons)
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, PlanetID(1), res[0].ID)
+	assert.Equal(t, "Homeworld", res[0].Name)
+	assert.Equal(t, Fields{Built: 10, Total: 163}, res[0].Fields)
+	assert.Equal(t, Temperature{Min: -10, Max: 30}, res[0].Temperature)
+	assert.Equal(t, MoonID(3), res[0].Moon.ID)
+	assert.Equal(t, Coordinate{1, 2, 3, MoonType}, res[0].Moon.Coordinate)
+	assert.Nil(t, res[1].Moon)
+}
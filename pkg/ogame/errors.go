@@ -1,6 +1,11 @@
 package ogame
 
-import "errors"
+import (
+	"errors"
+	"strings"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
 
 // ErrNotLogged returned when the bot is not logged
 var ErrNotLogged = errors.New("not logged")
@@ -44,6 +49,49 @@ var ErrDeactivateHidePictures = errors.New("deactivate 'Hide pictures in reports
 // ErrEventsBoxNotDisplayed returned when trying to get attacks from a full page without event box
 var ErrEventsBoxNotDisplayed = errors.New("eventList box is not displayed")
 
+// ErrNotSupported returned when the extractor of the game version does not support a page or a feature
+var ErrNotSupported = errors.New("not supported by this game version")
+
+// ErrPositionNotEmpty returned when a colonization or discovery mission targets a position that is not empty
+var ErrPositionNotEmpty = errors.New("position is not empty")
+
+// ErrIgnoredUser returned when sending a chat message to a player that is in our own ignore list (IGNORED_USER).
+// It does not mean that the recipient ignores us, the game does not tell.
+var ErrIgnoredUser = errors.New("ignored user")
+
+// ErrSendMessageFailed returned when the game refuses a chat message with the SEND_FAILED status (a generic error in its chatLoca)
+var ErrSendMessageFailed = errors.New("send message failed")
+
+// ErrNotCombatUnit returned when an ogame id is not a ship or a defense
+var ErrNotCombatUnit = errors.New("not a ship or a defense")
+
+// ErrNotMine returned when an ogame id is not a metal mine, a crystal mine or a deuterium synthesizer
+var ErrNotMine = errors.New("not a mine")
+
+// ErrNoProductionGain returned when a mine upgrade does not increase the production, usually because the planet lacks energy
+var ErrNoProductionGain = errors.New("upgrade does not increase production")
+
+// ErrGalaxySkeleton returned when the galaxy content is an empty skeleton (system rows not loaded yet)
+var ErrGalaxySkeleton = errors.New("galaxy content not loaded")
+
+// ErrInvalidLobby returned when the lobby is not a known gameforge lobby
+var ErrInvalidLobby = errors.New("invalid lobby")
+
+// ErrInvalidAPINewHostname returned when the api new hostname is not of the form scheme://host[:port]
+var ErrInvalidAPINewHostname = errors.New("invalid api new hostname, expected scheme://host[:port]")
+
+// ErrServerNotFound returned when the universe is not found in the lobby servers
+var ErrServerNotFound = errors.New("server not found")
+
+// ErrMaintenance returned when the game server is in maintenance
+var ErrMaintenance = errors.New("server is in maintenance")
+
+// ErrThrottled returned when the game server refuses requests because too many were sent
+var ErrThrottled = errors.New("too many requests, throttled by the game server")
+
+// ErrResponseTooLarge returned when a response body exceeds the maximum size (see Params.MaxResponseBytes)
+var ErrResponseTooLarge = utils.ErrResponseTooLarge
+
 // Send fleet errors
 var (
 	ErrUnionNotFound                      = errors.New("union not found")
@@ -61,4 +109,70 @@ var (
 	ErrNoRecyclerAvailable                = errors.New("no recycler available")
 	ErrNoEventsRunning                    = errors.New("there are currently no events running")
 	ErrPlanetAlreadyReservedForRelocation = errors.New("this planet has already been reserved for a relocation")
+	ErrSameOriginDestination              = errors.New("origin and destination are the same")
+	ErrSpyOwnCelestial                    = errors.New("you cannot spy yourself")
+	ErrAttackOwnCelestial                 = errors.New("you cannot attack yourself")
+	ErrNotEnoughDeuterium                 = errors.New("not enough deuterium")
+	ErrNotEnoughCargo                     = errors.New("not enough cargo capacity")
+	ErrPlanetAlreadyInhabited             = errors.New("planet is already inhabited")
 )
+
+// ErrFleetNotFound returned when none of our fleets in flight has the requested id
+var ErrFleetNotFound = errors.New("fleet not found")
+
+// ErrRecallJobNotFound returned when a scheduled recall job does not exist (already executed or cancelled)
+var ErrRecallJobNotFound = errors.New("recall job not found")
+
+// ErrRecallAfterArrival returned when a fleet would arrive before its scheduled recall
+var ErrRecallAfterArrival = errors.New("fleet arrives before its recall")
+
+// ErrProbeRaidsDisabled returned when a fleet of espionage probes is sent to attack on a server without probe raids
+var ErrProbeRaidsDisabled = errors.New("espionage probes cannot attack on this server")
+
+// ErrSlotsReserved returned when the free fleet slots are reserved by other owners
+var ErrSlotsReserved = errors.New("free fleet slots are reserved")
+
+// ErrSlotReservationNotFound returned when releasing a slots reservation that does not exist (expired, consumed or released)
+var ErrSlotReservationNotFound = errors.New("slot reservation not found")
+
+// ErrNoJumpGate returned when trying to use the jump gate of a moon that does not have one
+var ErrNoJumpGate = errors.New("moon has no jump gate")
+
+// ErrPayloadExceedsCargo returned when the fixed amounts of a payload do not fit in the cargo of the fleet
+var ErrPayloadExceedsCargo = errors.New("payload exceeds the fleet cargo capacity")
+
+// ErrResourceProfileNotFound returned when applying a resource settings profile that does not exist
+var ErrResourceProfileNotFound = errors.New("resource settings profile not found")
+
+// ErrNoResourceProfileChange returned when reverting a planet on which no profile was applied
+var ErrNoResourceProfileChange = errors.New("no resource settings profile applied on this planet")
+
+// ErrNoActivity returned when the bot did not load any game page since it started
+var ErrNoActivity = errors.New("no activity recorded yet")
+
+// ErrInvalidQueueIndex returned when cancelling a building queue entry that is not waiting in the queue
+var ErrInvalidQueueIndex = errors.New("invalid building queue index")
+
+// ErrSystemRangeTooLarge returned when a scan covers more systems than allowed in one task
+var ErrSystemRangeTooLarge = errors.New("system range too large")
+
+// ErrInvalidSpeed returned when the fleet speed is not one of the speeds allowed by the fleet dispatch page
+var ErrInvalidSpeed = errors.New("invalid fleet speed")
+
+// ErrNotEnoughEspionageReports returned when there are less than two espionage reports of a target to compare
+var ErrNotEnoughEspionageReports = errors.New("not enough espionage reports")
+
+// ErrAmbiguousCoordinate returned when several celestials are found at the same coordinate (eg: during a planet relocation)
+type ErrAmbiguousCoordinate struct {
+	Coordinate   Coordinate
+	CandidateIDs []CelestialID
+}
+
+// Error ...
+func (e *ErrAmbiguousCoordinate) Error() string {
+	ids := make([]string, len(e.CandidateIDs))
+	for i, id := range e.CandidateIDs {
+		ids[i] = utils.FI64(id)
+	}
+	return "ambiguous coordinate " + e.Coordinate.String() + ", candidates: " + strings.Join(ids, ", ")
+}
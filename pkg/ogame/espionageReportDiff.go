@@ -0,0 +1,79 @@
+package ogame
+
+import (
+	"time"
+)
+
+// EspionageReportDiff what changed at a target between two espionage reports.
+// Ships, Defenses and Buildings only hold what changed, a positive value is an increase.
+// They are nil when one of the reports does not have the information (not enough probes).
+type EspionageReportDiff struct {
+	Coordinate Coordinate
+	OlderID    int64
+	NewerID    int64
+	OlderDate  time.Time
+	NewerDate  time.Time
+	Resources  Resources // Can be negative
+	Ships      map[ID]int64
+	Defenses   map[ID]int64
+	Buildings  map[ID]int64 // Resources buildings and facilities levels
+}
+
+// DefensesGrew returns either or not defenses were added to the target
+func (d EspionageReportDiff) DefensesGrew() bool {
+	for _, delta := range d.Defenses {
+		if delta > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffEspionageReports returns what changed at a target between the older and the newer espionage reports
+func DiffEspionageReports(older, newer EspionageReport) EspionageReportDiff {
+	diff := EspionageReportDiff{
+		Coordinate: newer.Coordinate,
+		OlderID:    older.ID,
+		NewerID:    newer.ID,
+		OlderDate:  older.Date,
+		NewerDate:  newer.Date,
+		Resources: Resources{
+			Metal:     newer.Metal - older.Metal,
+			Crystal:   newer.Crystal - older.Crystal,
+			Deuterium: newer.Deuterium - older.Deuterium,
+		},
+	}
+	if olderShips, newerShips := older.ShipsInfos(), newer.ShipsInfos(); olderShips != nil && newerShips != nil {
+		diff.Ships = make(map[ID]int64)
+		for _, ship := range Ships {
+			addDelta(diff.Ships, ship.GetID(), olderShips.ByID(ship.GetID()), newerShips.ByID(ship.GetID()))
+		}
+	}
+	if olderDefenses, newerDefenses := older.DefensesInfos(), newer.DefensesInfos(); olderDefenses != nil && newerDefenses != nil {
+		diff.Defenses = make(map[ID]int64)
+		for _, defense := range Defenses {
+			addDelta(diff.Defenses, defense.GetID(), olderDefenses.ByID(defense.GetID()), newerDefenses.ByID(defense.GetID()))
+		}
+	}
+	if older.HasBuildingsInformation && newer.HasBuildingsInformation {
+		diff.Buildings = make(map[ID]int64)
+		olderResourcesBuildings, newerResourcesBuildings := older.ResourcesBuildings(), newer.ResourcesBuildings()
+		olderFacilities, newerFacilities := older.Facilities(), newer.Facilities()
+		for _, building := range Buildings {
+			id := building.GetID()
+			if id.IsShip() { // Solar satellites are in the ships diff
+				continue
+			}
+			addDelta(diff.Buildings, id,
+				olderResourcesBuildings.ByID(id)+olderFacilities.ByID(id),
+				newerResourcesBuildings.ByID(id)+newerFacilities.ByID(id))
+		}
+	}
+	return diff
+}
+
+func addDelta(m map[ID]int64, id ID, older, newer int64) {
+	if newer != older {
+		m[id] = newer - older
+	}
+}
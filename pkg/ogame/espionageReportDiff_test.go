@@ -0,0 +1,35 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEspionageReports(t *testing.T) {
+	older := EspionageReport{ID: 1, Resources: Resources{Metal: 1000, Crystal: 500},
+		HasFleetInformation: true, SmallCargo: utils.I64Ptr(10),
+		HasDefensesInformation: true, RocketLauncher: utils.I64Ptr(5),
+		HasBuildingsInformation: true, MetalMine: utils.I64Ptr(20), Shipyard: utils.I64Ptr(4)}
+	newer := EspionageReport{ID: 2, Resources: Resources{Metal: 3000, Crystal: 200},
+		HasFleetInformation: true, LightFighter: utils.I64Ptr(3),
+		HasDefensesInformation: true, RocketLauncher: utils.I64Ptr(5), LightLaser: utils.I64Ptr(10),
+		HasBuildingsInformation: true, MetalMine: utils.I64Ptr(21), Shipyard: utils.I64Ptr(4)}
+	diff := DiffEspionageReports(older, newer)
+	assert.Equal(t, int64(1), diff.OlderID)
+	assert.Equal(t, int64(2), diff.NewerID)
+	assert.Equal(t, Resources{Metal: 2000, Crystal: -300}, diff.Resources)
+	assert.Equal(t, map[ID]int64{SmallCargoID: -10, LightFighterID: 3}, diff.Ships)
+	assert.Equal(t, map[ID]int64{LightLaserID: 10}, diff.Defenses)
+	assert.Equal(t, map[ID]int64{MetalMineID: 1}, diff.Buildings)
+	assert.True(t, diff.DefensesGrew())
+
+	// Not enough probes on the newer report
+	newer = EspionageReport{ID: 3}
+	diff = DiffEspionageReports(older, newer)
+	assert.Nil(t, diff.Ships)
+	assert.Nil(t, diff.Defenses)
+	assert.Nil(t, diff.Buildings)
+	assert.False(t, diff.DefensesGrew())
+}
@@ -0,0 +1,35 @@
+package ogame
+
+import "time"
+
+// EventType hostility of an event list entry
+type EventType string
+
+// Event types
+const (
+	HostileEvent EventType = "hostile"
+	OwnEvent     EventType = "own"
+	NeutralEvent EventType = "neutral"
+)
+
+// Event any entry of the event list, hostile, own or neutral.
+// ACS partner fleets are folded into their union entry.
+type Event struct {
+	ID              int64
+	Type            EventType
+	MissionType     MissionID
+	ReturnFlight    bool
+	Origin          Coordinate
+	OriginName      string
+	Destination     Coordinate
+	DestinationName string
+	ArrivalTime     time.Time
+	ArriveIn        int64
+	PlayerID        int64
+	PlayerName      string
+	UnionID         int64
+	Missiles        int64
+	ShipsCount      int64       // total number of ships, shown even when the fleet details are hidden
+	Ships           *ShipsInfos // nil when the fleet details are hidden
+	Resources       *Resources  // nil when the shipment is not shown
+}
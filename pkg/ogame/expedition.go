@@ -0,0 +1,104 @@
+package ogame
+
+import (
+	"math"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// ExpeditionOddsResult approximate probability (0 to 1) of each expedition outcome,
+// and the maximum resources an expedition can find.
+type ExpeditionOddsResult struct {
+	Resources           float64
+	Ships               float64
+	DarkMatter          float64
+	Nothing             float64
+	Delay               float64
+	Early               float64
+	Pirates             float64
+	Aliens              float64
+	Merchant            float64
+	BlackHole           float64
+	MaxExpeditionPoints int64   // Expedition points needed to find the maximum amounts
+	FleetPointsRatio    float64 // How much of the maximum amounts the fleet can find
+	MaxResources        int64   // Maximum resources found, in metal (crystal is /2, deuterium is /3)
+}
+
+// expeditionMaxPoints maximum expedition points, given the points of the top 1 player
+func expeditionMaxPoints(topPoints int64) int64 {
+	switch {
+	case topPoints < 10_000:
+		return 200
+	case topPoints < 100_000:
+		return 2_500
+	case topPoints < 1_000_000:
+		return 6_000
+	case topPoints < 5_000_000:
+		return 9_000
+	case topPoints < 25_000_000:
+		return 12_000
+	case topPoints < 50_000_000:
+		return 15_000
+	case topPoints < 75_000_000:
+		return 18_000
+	case topPoints < 100_000_000:
+		return 21_000
+	}
+	return 25_000
+}
+
+// ExpeditionOdds returns the approximate probability of each expedition outcome.
+// fleetPoints is the expedition points of the fleet sent, topPoints the points of the top 1 player of the server.
+// The discoverer class meets half as many pirates/aliens, and finds 50% more resources.
+// The universe economy speed bonus of the discoverer class is not applied.
+func ExpeditionOdds(fleetPoints, topPoints int64, class CharacterClass) ExpeditionOddsResult {
+	res := ExpeditionOddsResult{
+		Resources:  0.325,
+		Ships:      0.22,
+		DarkMatter: 0.09,
+		Delay:      0.07,
+		Early:      0.02,
+		Pirates:    0.058,
+		Aliens:     0.026,
+		Merchant:   0.007,
+		BlackHole:  0.0033,
+	}
+	if class.IsDiscoverer() {
+		res.Pirates /= 2
+		res.Aliens /= 2
+	}
+	res.Nothing = 1 - (res.Resources + res.Ships + res.DarkMatter + res.Delay + res.Early +
+		res.Pirates + res.Aliens + res.Merchant + res.BlackHole)
+
+	res.MaxExpeditionPoints = expeditionMaxPoints(topPoints)
+	res.FleetPointsRatio = math.Min(1, math.Max(0, float64(fleetPoints)/float64(res.MaxExpeditionPoints)))
+	res.MaxResources = int64(float64(ExpeditionMaxCargo(topPoints, class)) * res.FleetPointsRatio)
+	return res
+}
+
+// ExpeditionMaxCargo returns the maximum resources (in metal) an expedition can find, given the points of the top 1 player.
+// Cargo capacity above this amount is useless on an expedition.
+// The discoverer class finds 50% more resources, the universe economy speed bonus is not applied.
+func ExpeditionMaxCargo(topPlayerPoints int64, class CharacterClass) int64 {
+	maxCargo := expeditionMaxPoints(topPlayerPoints) * 200
+	if class.IsDiscoverer() {
+		maxCargo = maxCargo * 3 / 2
+	}
+	return maxCargo
+}
+
+// TrimExpeditionCargo removes the large cargos, then the small cargos, that are not needed
+// to carry maxCargo resources. The other ships are kept.
+func TrimExpeditionCargo(ships ShipsInfos, maxCargo int64, techs Researches, probeRaids, isCollector, isPioneers bool) ShipsInfos {
+	excess := ships.Cargo(techs, probeRaids, isCollector, isPioneers) - maxCargo
+	for _, ship := range []Ship{LargeCargo, SmallCargo} {
+		capacity := ship.GetCargoCapacity(techs, probeRaids, isCollector, isPioneers)
+		if excess <= 0 || capacity <= 0 {
+			continue
+		}
+		nbr := utils.MinInt(ships.ByID(ship.GetID()), excess/capacity)
+		ships.SubShips(ship.GetID(), nbr)
+		excess -= nbr * capacity
+	}
+	return ships
+}
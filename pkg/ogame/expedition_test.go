@@ -0,0 +1,63 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpeditionOdds(t *testing.T) {
+	odds := ExpeditionOdds(25_000, 150_000_000, Collector)
+	sum := odds.Resources + odds.Ships + odds.DarkMatter + odds.Nothing + odds.Delay + odds.Early +
+		odds.Pirates + odds.Aliens + odds.Merchant + odds.BlackHole
+	assert.InDelta(t, 1, sum, 0.0001)
+	assert.InDelta(t, 0.1807, odds.Nothing, 0.0001)
+	assert.Equal(t, int64(25_000), odds.MaxExpeditionPoints)
+	assert.Equal(t, 1.0, odds.FleetPointsRatio)
+	assert.Equal(t, int64(5_000_000), odds.MaxResources)
+
+	odds = ExpeditionOdds(3_000, 2_000_000, NoClass)
+	assert.Equal(t, int64(9_000), odds.MaxExpeditionPoints)
+	assert.InDelta(t, 0.3333, odds.FleetPointsRatio, 0.0001)
+	assert.Equal(t, int64(600_000), odds.MaxResources)
+
+	odds = ExpeditionOdds(50_000, 5_000, Discoverer)
+	assert.Equal(t, int64(200), odds.MaxExpeditionPoints)
+	assert.Equal(t, int64(60_000), odds.MaxResources)
+	assert.InDelta(t, 0.029, odds.Pirates, 0.0001)
+	assert.InDelta(t, 0.013, odds.Aliens, 0.0001)
+	assert.InDelta(t, 0.2227, odds.Nothing, 0.0001)
+
+	assert.Equal(t, 0.0, ExpeditionOdds(-10, 0, NoClass).FleetPointsRatio)
+}
+
+func TestExpeditionMaxCargo(t *testing.T) {
+	assert.Equal(t, int64(40_000), ExpeditionMaxCargo(0, NoClass))
+	assert.Equal(t, int64(40_000), ExpeditionMaxCargo(9_999, NoClass))
+	assert.Equal(t, int64(500_000), ExpeditionMaxCargo(10_000, NoClass))
+	assert.Equal(t, int64(500_000), ExpeditionMaxCargo(99_999, NoClass))
+	assert.Equal(t, int64(1_200_000), ExpeditionMaxCargo(100_000, NoClass))
+	assert.Equal(t, int64(1_800_000), ExpeditionMaxCargo(1_000_000, NoClass))
+	assert.Equal(t, int64(2_400_000), ExpeditionMaxCargo(5_000_000, NoClass))
+	assert.Equal(t, int64(2_400_000), ExpeditionMaxCargo(24_999_999, NoClass))
+	assert.Equal(t, int64(3_000_000), ExpeditionMaxCargo(25_000_000, NoClass))
+	assert.Equal(t, int64(3_600_000), ExpeditionMaxCargo(50_000_000, NoClass))
+	assert.Equal(t, int64(4_200_000), ExpeditionMaxCargo(75_000_000, NoClass))
+	assert.Equal(t, int64(4_200_000), ExpeditionMaxCargo(99_999_999, Collector))
+	assert.Equal(t, int64(5_000_000), ExpeditionMaxCargo(100_000_000, NoClass))
+	assert.Equal(t, int64(7_500_000), ExpeditionMaxCargo(100_000_000, Discoverer))
+	assert.Equal(t, int64(60_000), ExpeditionMaxCargo(5_000, Discoverer))
+}
+
+func TestTrimExpeditionCargo(t *testing.T) {
+	ships := ShipsInfos{LargeCargo: 100, SmallCargo: 10, EspionageProbe: 1, Pathfinder: 1}
+	trimmed := TrimExpeditionCargo(ships, 500_000, Researches{}, false, false, false)
+	assert.Equal(t, int64(1), trimmed.EspionageProbe)
+	assert.Equal(t, int64(1), trimmed.Pathfinder)
+	assert.Equal(t, int64(18), trimmed.LargeCargo)
+	assert.Equal(t, int64(8), trimmed.SmallCargo)
+	assert.Equal(t, int64(100), ships.LargeCargo)
+
+	ships = ShipsInfos{SmallCargo: 10}
+	assert.Equal(t, ships, TrimExpeditionCargo(ships, 500_000, Researches{}, false, false, false))
+}
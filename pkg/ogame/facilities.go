@@ -35,6 +35,14 @@ type Facilities struct {
 	JumpGate        int64 // 43
 }
 
+// MoonFacilities moon facilities with the phalanx range and the jump gate recharge countdown
+type MoonFacilities struct {
+	Facilities
+	PhalanxRange              int64 // Range of the sensor phalanx in systems
+	HasJumpGate               bool  // The moon has a jump gate (level > 0)
+	JumpGateRechargeCountdown int64 // Seconds before the jump gate can be used again, 0 if ready
+}
+
 func (f Facilities) GetRoboticsFactory() int64 { return f.RoboticsFactory }
 func (f Facilities) GetShipyard() int64        { return f.Shipyard }
 func (f Facilities) GetResearchLab() int64     { return f.ResearchLab }
@@ -10,3 +10,14 @@ type Fields struct {
 func (f Fields) HasFieldAvailable() bool {
 	return f.Built < f.Total
 }
+
+// averageFieldsByPosition average number of fields of a freshly colonized planet, indexed by position-1
+var averageFieldsByPosition = [15]int64{134, 140, 147, 163, 183, 194, 200, 204, 198, 187, 173, 153, 141, 126, 114}
+
+// ExpectedPlanetFields returns the average number of fields a new colony gets at the given position
+func ExpectedPlanetFields(position int64) int64 {
+	if position < 1 || position > 15 {
+		return 0
+	}
+	return averageFieldsByPosition[position-1]
+}
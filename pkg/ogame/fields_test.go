@@ -0,0 +1,16 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectedPlanetFields(t *testing.T) {
+	assert.Equal(t, int64(204), ExpectedPlanetFields(8))
+	assert.Equal(t, int64(134), ExpectedPlanetFields(1))
+	assert.Equal(t, int64(114), ExpectedPlanetFields(15))
+	assert.Equal(t, int64(0), ExpectedPlanetFields(0))
+	assert.Equal(t, int64(0), ExpectedPlanetFields(16))
+	assert.True(t, ExpectedPlanetFields(7) > ExpectedPlanetFields(4))
+}
@@ -6,19 +6,89 @@ import (
 
 // Fleet represent a player fleet information
 type Fleet struct {
-	Mission        MissionID
-	ReturnFlight   bool
-	InDeepSpace    bool
-	ID             FleetID
-	Resources      Resources
-	Origin         Coordinate
-	Destination    Coordinate
-	Ships          ShipsInfos
-	StartTime      time.Time
-	ArrivalTime    time.Time
-	BackTime       time.Time
-	ArriveIn       int64
-	BackIn         int64
-	UnionID        int64
-	TargetPlanetID int64
+	Mission          MissionID
+	ReturnFlight     bool
+	InDeepSpace      bool
+	ID               FleetID
+	Resources        Resources
+	CarriesResources bool // Either or not the fleet has resources in its shipment (eg: loaded transport coming back)
+	Origin           Coordinate
+	Destination      Coordinate
+	Ships            ShipsInfos
+	StartTime        time.Time
+	ArrivalTime      time.Time
+	BackTime         time.Time
+	ArriveIn         int64
+	BackIn           int64
+	UnionID          int64
+	TargetPlanetID   int64
+	CombatReportID   int64 // Combat report of the battle of a returning attack fleet, 0 if unknown (see LinkCombatReports)
+	Phalanxable      bool  // Either or not a sensor phalanx can see the fleet (see IsPhalanxable)
+}
+
+// IsPhalanxable returns either or not a fleet flying between origin and destination can be seen by a sensor phalanx.
+// A phalanx only scans planets, so the fleet is visible if one end of its route is a planet,
+// expeditions excepted since they fly to deep space.
+func IsPhalanxable(origin, destination Coordinate, mission MissionID) bool {
+	if mission == Expedition {
+		return false
+	}
+	return origin.IsPlanet() || destination.IsPlanet()
+}
+
+// SetPhalanxable sets the Phalanxable flag of the fleets
+func SetPhalanxable(fleets []Fleet) {
+	for i, fleet := range fleets {
+		fleets[i].Phalanxable = IsPhalanxable(fleet.Origin, fleet.Destination, fleet.Mission)
+	}
+}
+
+// combatReportMaxDelay maximum gap between the departure of a returning fleet and the creation of its combat report
+const combatReportMaxDelay = time.Minute
+
+// LinkCombatReports sets the CombatReportID of the returning attack fleets.
+// A returning fleet leaves its destination right after the battle, so its combat report is the one of the same
+// destination created the closest to its StartTime.
+func LinkCombatReports(fleets []Fleet, reports []CombatReportSummary) {
+	for i, fleet := range fleets {
+		if !fleet.ReturnFlight || !IsBashingMission(fleet.Mission) || fleet.StartTime.IsZero() {
+			continue
+		}
+		var bestDelay time.Duration
+		for _, report := range reports {
+			if !report.Destination.Equal(fleet.Destination) {
+				continue
+			}
+			delay := report.CreatedAt.Sub(fleet.StartTime)
+			if delay < 0 {
+				delay = -delay
+			}
+			if delay <= combatReportMaxDelay && (fleets[i].CombatReportID == 0 || delay < bestDelay) {
+				fleets[i].CombatReportID = report.ID
+				bestDelay = delay
+			}
+		}
+	}
+}
+
+// ACSUnionMember fleet of a player taking part in an ACS union
+type ACSUnionMember struct {
+	FleetID     FleetID
+	Name        string // Player name, or "Own fleet" for our own fleets
+	PlayerID    int64
+	Origin      Coordinate
+	ArrivalTime time.Time
+	Ships       *ShipsInfos // nil if the ships are not visible
+}
+
+// ACSUnionDetails details of an ACS union and of the fleets of its members
+type ACSUnionDetails struct {
+	UnionID     int64
+	EventID     int64 // ID of the union in the event list
+	Name        string
+	Users       []string // Players invited in the union
+	Destination Coordinate
+	ArrivalTime time.Time
+	TotalShips  int64
+	Members     []ACSUnionMember
 }
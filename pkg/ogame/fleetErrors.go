@@ -0,0 +1,106 @@
+package ogame
+
+import (
+	"errors"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// FleetDispatchError error returned by the game when dispatching a fleet, for the codes without a dedicated error
+type FleetDispatchError struct {
+	Code    int64
+	Message string
+}
+
+// Error ...
+func (e *FleetDispatchError) Error() string {
+	return e.Message + " (" + utils.FI64(e.Code) + ")"
+}
+
+// fleetDispatchErrors errors of the fleet dispatch error codes of the game.
+// 6xx codes are the errorCodeMap of the fleetdispatch page, 4xxx codes are returned by checkTarget/sendFleet.
+var fleetDispatchErrors = map[int64]error{
+	602:  ErrNoMoonAvailable,
+	603:  ErrNoobProtection,
+	604:  ErrPlayerTooStrong,
+	605:  ErrPlayerInVacationMode,
+	606:  ErrAccountInVacationMode,
+	610:  ErrNotEnoughShips,
+	611:  ErrNoShipSelected,
+	612:  ErrAllSlotsInUse,
+	613:  ErrNotEnoughDeuterium,
+	614:  ErrUninhabitedPlanet,
+	615:  ErrNotEnoughCargo,
+	617:  ErrAdminOrGM,
+	4029: ErrNotEnoughCargo,
+	4053: ErrPlanetAlreadyInhabited,
+	4059: ErrNoShipSelected,
+}
+
+// NewFleetDispatchError returns the error of a fleet dispatch error code of the game,
+// a *FleetDispatchError if the code has no dedicated error
+func NewFleetDispatchError(code int64, message string) error {
+	if err, ok := fleetDispatchErrors[code]; ok {
+		return err
+	}
+	return &FleetDispatchError{Code: code, Message: message}
+}
+
+// transientFleetDispatchErrorCodes fleet dispatch error codes of the game that do not mean the fleet cannot be sent,
+// sending it again later can succeed
+var transientFleetDispatchErrorCodes = map[int64]bool{
+	601:  true, // An error has occurred
+	4047: true, // Fleet launch failure: The fleet could not be launched. Please try again later.
+}
+
+// fleetErrorCodes machine-readable codes of the errors SendFleet can return
+var fleetErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrInvalidPlanetID, "invalid_planet_id"},
+	{ErrAllSlotsInUse, "all_slots_in_use"},
+	{ErrSlotsReserved, "slots_reserved"},
+	{ErrInvalidSpeed, "invalid_speed"},
+	{ErrUnionNotFound, "union_not_found"},
+	{ErrAccountInVacationMode, "account_in_vacation_mode"},
+	{ErrNoShipSelected, "no_ship_selected"},
+	{ErrNotEnoughShips, "not_enough_ships"},
+	{ErrProbeRaidsDisabled, "probe_raids_disabled"},
+	{ErrUninhabitedPlanet, "uninhabited_planet"},
+	{ErrNoDebrisField, "no_debris_field"},
+	{ErrPlayerInVacationMode, "player_in_vacation_mode"},
+	{ErrAdminOrGM, "admin_or_gm"},
+	{ErrNoAstrophysics, "no_astrophysics"},
+	{ErrNoobProtection, "noob_protection"},
+	{ErrPlayerTooStrong, "player_too_strong"},
+	{ErrNoMoonAvailable, "no_moon_available"},
+	{ErrNoRecyclerAvailable, "no_recycler_available"},
+	{ErrNoEventsRunning, "no_events_running"},
+	{ErrPlanetAlreadyReservedForRelocation, "planet_reserved_for_relocation"},
+	{ErrSameOriginDestination, "same_origin_destination"},
+	{ErrSpyOwnCelestial, "spy_own_celestial"},
+	{ErrAttackOwnCelestial, "attack_own_celestial"},
+	{ErrNotEnoughDeuterium, "not_enough_deuterium"},
+	{ErrNotEnoughCargo, "not_enough_cargo"},
+	{ErrPlanetAlreadyInhabited, "planet_already_inhabited"},
+	{ErrPayloadExceedsCargo, "payload_exceeds_cargo"},
+}
+
+// IsFleetError returns the machine-readable code of an error returned by SendFleet because the fleet cannot be sent.
+// ok is false for the other errors (network, session, unexpected page, transient dispatch error...).
+func IsFleetError(err error) (code string, ok bool) {
+	if err == nil {
+		return "", false
+	}
+	for _, e := range fleetErrorCodes {
+		if errors.Is(err, e.err) {
+			return e.code, true
+		}
+	}
+	var dispatchErr *FleetDispatchError
+	if errors.As(err, &dispatchErr) && !transientFleetDispatchErrorCodes[dispatchErr.Code] {
+		return "dispatch_" + utils.FI64(dispatchErr.Code), true
+	}
+	return "", false
+}
@@ -0,0 +1,105 @@
+package ogame
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFleetDispatchError_errorCodeMap(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../samples/v7/fleetdispatch.html")
+	m := regexp.MustCompile(`errorCodeMap = ({[^;]+});`).FindSubmatch(pageHTMLBytes)
+	assert.Len(t, m, 2)
+	var errorCodeMap map[string]string
+	assert.NoError(t, json.Unmarshal(m[1], &errorCodeMap))
+	expected := map[int64]error{
+		602: ErrNoMoonAvailable,
+		603: ErrNoobProtection,
+		604: ErrPlayerTooStrong,
+		605: ErrPlayerInVacationMode,
+		606: ErrAccountInVacationMode,
+		610: ErrNotEnoughShips,
+		611: ErrNoShipSelected,
+		612: ErrAllSlotsInUse,
+		613: ErrNotEnoughDeuterium,
+		614: ErrUninhabitedPlanet,
+		615: ErrNotEnoughCargo,
+		617: ErrAdminOrGM,
+	}
+	for codeStr, message := range errorCodeMap {
+		code, _ := strconv.ParseInt(codeStr, 10, 64)
+		err := NewFleetDispatchError(code, message)
+		if expectedErr, ok := expected[code]; ok {
+			assert.Equal(t, expectedErr, err, codeStr)
+			continue
+		}
+		var dispatchErr *FleetDispatchError
+		assert.True(t, errors.As(err, &dispatchErr), codeStr)
+		assert.Equal(t, message+" ("+codeStr+")", err.Error())
+	}
+}
+
+func TestNewFleetDispatchError_sendFleetResponses(t *testing.T) {
+	responses := map[string]error{
+		`{"success":false,"errors":[{"message":"Not enough cargo space!","error":4029}],"fleetSendingToken":"b4786751c6d5e64e56d8eb94807fbf88","components":[]}`:                            ErrNotEnoughCargo,
+		`{"success":false,"errors":[{"message":"Error, no ships available","error":4059}],"fleetSendingToken":"b369e37ce34bb64e3a59fa26bd8d5602","components":[]}`:                          ErrNoShipSelected,
+		`{"success":false,"errors":[{"message":"Planet is already inhabited!","error":4053}],"fleetSendingToken":"3281f9ad5b4cba6c0c26a24d3577bd4c","components":[]}`:                       ErrPlanetAlreadyInhabited,
+		`{"success":false,"errors":[{"message":"You have to select a valid target.","error":4049}],"fleetSendingToken":"19218f446d0985dfd79e03c3ec008514","components":[]}`:                 &FleetDispatchError{Code: 4049, Message: "You have to select a valid target."},
+		`{"success":false,"errors":[{"message":"Colony ships must be sent to colonise this planet!","error":4038}],"fleetSendingToken":"8700c275a055c59ca276a7f66c81b205","components":[]}`: &FleetDispatchError{Code: 4038, Message: "Colony ships must be sent to colonise this planet!"},
+	}
+	for response, expected := range responses {
+		var resStruct struct {
+			Errors []struct {
+				Message string `json:"message"`
+				Error   int64  `json:"error"`
+			} `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(response), &resStruct))
+		assert.Equal(t, expected, NewFleetDispatchError(resStruct.Errors[0].Error, resStruct.Errors[0].Message))
+	}
+}
+
+func TestIsFleetError(t *testing.T) {
+	code, ok := IsFleetError(ErrUninhabitedPlanet)
+	assert.True(t, ok)
+	assert.Equal(t, "uninhabited_planet", code)
+
+	code, ok = IsFleetError(fmt.Errorf("%w, %s", ErrNotEnoughShips, "Small Cargo"))
+	assert.True(t, ok)
+	assert.Equal(t, "not_enough_ships", code)
+
+	code, ok = IsFleetError(NewFleetDispatchError(613, "Error, you don`t have enough deuterium"))
+	assert.True(t, ok)
+	assert.Equal(t, "not_enough_deuterium", code)
+
+	code, ok = IsFleetError(NewFleetDispatchError(4049, "You have to select a valid target."))
+	assert.True(t, ok)
+	assert.Equal(t, "dispatch_4049", code)
+
+	// Transient errors, the fleet can be sent again later
+	_, ok = IsFleetError(NewFleetDispatchError(4047, "Fleet launch failure: The fleet could not be launched. Please try again later."))
+	assert.False(t, ok)
+	_, ok = IsFleetError(NewFleetDispatchError(601, "An error has occurred"))
+	assert.False(t, ok)
+
+	_, ok = IsFleetError(ErrNotLogged)
+	assert.False(t, ok)
+	_, ok = IsFleetError(nil)
+	assert.False(t, ok)
+
+	// Every sentinel has its own code
+	codes := make(map[string]bool)
+	for _, e := range fleetErrorCodes {
+		code, ok := IsFleetError(e.err)
+		assert.True(t, ok)
+		assert.Equal(t, e.code, code)
+		assert.False(t, codes[code], code)
+		codes[code] = true
+	}
+}
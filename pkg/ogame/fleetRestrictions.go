@@ -0,0 +1,114 @@
+package ogame
+
+// FleetRestrictionReason why the game would refuse to send a mission to a target
+type FleetRestrictionReason string
+
+// Fleet restriction reasons
+const (
+	RestrictionAccountVacation FleetRestrictionReason = "account_in_vacation_mode"
+	RestrictionInvalidPosition FleetRestrictionReason = "invalid_position"
+	RestrictionNoAstrophysics  FleetRestrictionReason = "no_astrophysics"
+	RestrictionPositionTaken   FleetRestrictionReason = "position_not_empty"
+	RestrictionUninhabited     FleetRestrictionReason = "uninhabited_planet"
+	RestrictionNoMoon          FleetRestrictionReason = "no_moon"
+	RestrictionNotAMoon        FleetRestrictionReason = "not_a_moon"
+	RestrictionNoDebris        FleetRestrictionReason = "no_debris_field"
+	RestrictionOwnTarget       FleetRestrictionReason = "own_target"
+	RestrictionNotOwnTarget    FleetRestrictionReason = "not_own_target"
+	RestrictionVacation        FleetRestrictionReason = "player_in_vacation_mode"
+	RestrictionAdmin           FleetRestrictionReason = "admin_or_gm"
+	RestrictionNoobProtection  FleetRestrictionReason = "noob_protection"
+	RestrictionTooStrong       FleetRestrictionReason = "player_too_strong"
+)
+
+// FleetRestriction a restriction the game applies to a mission towards a target
+type FleetRestriction struct {
+	Reason  FleetRestrictionReason
+	Message string
+}
+
+// NewFleetRestriction creates a fleet restriction
+func NewFleetRestriction(reason FleetRestrictionReason, message string) FleetRestriction {
+	return FleetRestriction{Reason: reason, Message: message}
+}
+
+func isHostileMission(mission MissionID) bool {
+	return mission == Attack || mission == GroupedAttack || mission == Spy || mission == Destroy || mission == MissileAttack
+}
+
+// FleetTargetRestrictions returns the restrictions the game applies to a mission towards a target, an empty slice
+// when none is known. targetInfos is the galaxy information of the target position, nil if the position is empty.
+// Restrictions depending on the fleet itself (ships, cargo, fuel) or on alliances/buddies are not covered.
+func FleetTargetRestrictions(where Coordinate, mission MissionID, targetInfos *PlanetInfos, playerID int64, researches Researches) []FleetRestriction {
+	restrictions := make([]FleetRestriction, 0)
+	add := func(reason FleetRestrictionReason, message string) {
+		restrictions = append(restrictions, NewFleetRestriction(reason, message))
+	}
+
+	if mission == Expedition {
+		if where.Position != 16 {
+			add(RestrictionInvalidPosition, "expeditions are sent to position 16")
+		}
+		if researches.Astrophysics == 0 {
+			add(RestrictionNoAstrophysics, ErrNoAstrophysics.Error())
+		}
+		return restrictions
+	}
+	if where.Position == 16 {
+		add(RestrictionInvalidPosition, "only expeditions can be sent to position 16")
+		return restrictions
+	}
+
+	switch mission {
+	case Colonize:
+		if targetInfos != nil && !targetInfos.Destroyed {
+			add(RestrictionPositionTaken, ErrPositionNotEmpty.Error())
+		}
+		if researches.Astrophysics == 0 {
+			add(RestrictionNoAstrophysics, ErrNoAstrophysics.Error())
+		}
+		return restrictions
+	case RecycleDebrisField:
+		if targetInfos == nil || targetInfos.Debris.Metal+targetInfos.Debris.Crystal == 0 {
+			add(RestrictionNoDebris, ErrNoDebrisField.Error())
+		}
+		return restrictions
+	}
+
+	if targetInfos == nil || targetInfos.Destroyed {
+		add(RestrictionUninhabited, ErrUninhabitedPlanet.Error())
+		return restrictions
+	}
+	if mission == Destroy && where.Type != MoonType {
+		add(RestrictionNotAMoon, "only moons can be destroyed")
+	}
+	if where.Type == MoonType && targetInfos.Moon == nil {
+		add(RestrictionNoMoon, ErrNoMoonAvailable.Error())
+	}
+
+	ownTarget := targetInfos.Player.ID == playerID
+	if mission == Park && !ownTarget {
+		add(RestrictionNotOwnTarget, "fleets can only be deployed to your own planets")
+	}
+	if ownTarget {
+		if isHostileMission(mission) {
+			add(RestrictionOwnTarget, "you cannot target your own planets")
+		}
+		return restrictions
+	}
+	if targetInfos.Vacation {
+		add(RestrictionVacation, ErrPlayerInVacationMode.Error())
+	}
+	if isHostileMission(mission) {
+		if targetInfos.Administrator {
+			add(RestrictionAdmin, ErrAdminOrGM.Error())
+		}
+		if targetInfos.Newbie {
+			add(RestrictionNoobProtection, ErrNoobProtection.Error())
+		}
+		if targetInfos.StrongPlayer {
+			add(RestrictionTooStrong, ErrPlayerTooStrong.Error())
+		}
+	}
+	return restrictions
+}
@@ -0,0 +1,51 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func reasons(restrictions []FleetRestriction) []FleetRestrictionReason {
+	out := make([]FleetRestrictionReason, 0)
+	for _, restriction := range restrictions {
+		out = append(out, restriction.Reason)
+	}
+	return out
+}
+
+func TestFleetTargetRestrictions(t *testing.T) {
+	const playerID = 100
+	researches := Researches{Astrophysics: 1}
+	own := &PlanetInfos{}
+	own.Player.ID = playerID
+	other := &PlanetInfos{Newbie: true, Vacation: true}
+	other.Player.ID = 200
+	withDebris := &PlanetInfos{}
+	withDebris.Debris.Metal = 1000
+
+	planet := Coordinate{1, 2, 3, PlanetType}
+	moon := Coordinate{1, 2, 3, MoonType}
+	exp := Coordinate{1, 2, 16, PlanetType}
+
+	assert.Equal(t, []FleetRestrictionReason{}, reasons(FleetTargetRestrictions(exp, Expedition, nil, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionInvalidPosition, RestrictionNoAstrophysics}, reasons(FleetTargetRestrictions(planet, Expedition, nil, playerID, Researches{})))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionInvalidPosition}, reasons(FleetTargetRestrictions(exp, Transport, nil, playerID, researches)))
+
+	assert.Equal(t, []FleetRestrictionReason{}, reasons(FleetTargetRestrictions(planet, Colonize, nil, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionPositionTaken}, reasons(FleetTargetRestrictions(planet, Colonize, other, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{}, reasons(FleetTargetRestrictions(planet, Colonize, &PlanetInfos{Destroyed: true}, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionNoDebris}, reasons(FleetTargetRestrictions(planet, RecycleDebrisField, other, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{}, reasons(FleetTargetRestrictions(planet, RecycleDebrisField, withDebris, playerID, researches)))
+
+	assert.Equal(t, []FleetRestrictionReason{RestrictionUninhabited}, reasons(FleetTargetRestrictions(planet, Attack, nil, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionNoMoon}, reasons(FleetTargetRestrictions(moon, Transport, own, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionOwnTarget}, reasons(FleetTargetRestrictions(planet, Spy, own, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{}, reasons(FleetTargetRestrictions(planet, Park, own, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionNotOwnTarget, RestrictionVacation}, reasons(FleetTargetRestrictions(planet, Park, other, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionVacation, RestrictionNoobProtection}, reasons(FleetTargetRestrictions(planet, Attack, other, playerID, researches)))
+	assert.Equal(t, []FleetRestrictionReason{RestrictionNotAMoon, RestrictionVacation, RestrictionNoobProtection}, reasons(FleetTargetRestrictions(planet, Destroy, other, playerID, researches)))
+
+	restrictions := FleetTargetRestrictions(planet, Attack, other, playerID, researches)
+	assert.Equal(t, ErrNoobProtection.Error(), restrictions[1].Message)
+}
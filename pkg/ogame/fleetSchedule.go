@@ -0,0 +1,47 @@
+package ogame
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// FleetScheduleEntry a fleet movement laid out on a timeline
+type FleetScheduleEntry struct {
+	EventID      string // Stable identifier of the entry, derived from the fleet id
+	FleetID      FleetID
+	Mission      MissionID
+	ReturnFlight bool
+	Origin       Coordinate
+	Destination  Coordinate
+	Start        time.Time // Departure from the origin
+	Arrival      time.Time // Arrival at the destination
+	Return       time.Time // Back at the origin
+	End          time.Time // Last timestamp of the movement
+}
+
+// NewFleetSchedule converts fleets into timeline entries, sorted by start time
+func NewFleetSchedule(fleets []Fleet) []FleetScheduleEntry {
+	out := make([]FleetScheduleEntry, 0, len(fleets))
+	for _, fleet := range fleets {
+		entry := FleetScheduleEntry{
+			EventID:      "fleet-" + utils.FI64(fleet.ID),
+			FleetID:      fleet.ID,
+			Mission:      fleet.Mission,
+			ReturnFlight: fleet.ReturnFlight,
+			Origin:       fleet.Origin,
+			Destination:  fleet.Destination,
+			Start:        fleet.StartTime,
+			Arrival:      fleet.ArrivalTime,
+			Return:       fleet.BackTime,
+			End:          fleet.ArrivalTime,
+		}
+		if fleet.BackTime.After(entry.End) {
+			entry.End = fleet.BackTime
+		}
+		out = append(out, entry)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
@@ -0,0 +1,24 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFleetSchedule(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	fleets := []Fleet{
+		{ID: 2, Mission: Transport, StartTime: now, ArrivalTime: now.Add(time.Hour), BackTime: now.Add(2 * time.Hour)},
+		{ID: 1, Mission: Park, StartTime: now.Add(-time.Hour), ArrivalTime: now.Add(30 * time.Minute)},
+	}
+	schedule := NewFleetSchedule(fleets)
+	assert.Equal(t, 2, len(schedule))
+	assert.Equal(t, "fleet-1", schedule[0].EventID)
+	assert.Equal(t, now.Add(30*time.Minute), schedule[0].End)
+	assert.Equal(t, "fleet-2", schedule[1].EventID)
+	assert.Equal(t, Transport, schedule[1].Mission)
+	assert.Equal(t, now.Add(time.Hour), schedule[1].Arrival)
+	assert.Equal(t, now.Add(2*time.Hour), schedule[1].End)
+}
@@ -0,0 +1,48 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkCombatReports(t *testing.T) {
+	battle := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	target := Coordinate{Galaxy: 1, System: 2, Position: 3, Type: PlanetType}
+	other := Coordinate{Galaxy: 1, System: 2, Position: 4, Type: PlanetType}
+	fleets := []Fleet{
+		{ID: 1, Mission: Attack, ReturnFlight: true, Destination: target, StartTime: battle},
+		{ID: 2, Mission: Attack, ReturnFlight: false, Destination: target, StartTime: battle},
+		{ID: 3, Mission: Transport, ReturnFlight: true, Destination: target, StartTime: battle},
+		{ID: 4, Mission: Attack, ReturnFlight: true, Destination: target, StartTime: battle.Add(time.Hour)},
+	}
+	reports := []CombatReportSummary{
+		{ID: 10, Destination: other, CreatedAt: battle},
+		{ID: 11, Destination: target, CreatedAt: battle.Add(40 * time.Second)},
+		{ID: 12, Destination: target, CreatedAt: battle.Add(time.Second)},
+	}
+	LinkCombatReports(fleets, reports)
+	assert.Equal(t, int64(12), fleets[0].CombatReportID)
+	assert.Equal(t, int64(0), fleets[1].CombatReportID)
+	assert.Equal(t, int64(0), fleets[2].CombatReportID)
+	assert.Equal(t, int64(0), fleets[3].CombatReportID)
+}
+
+func TestIsPhalanxable(t *testing.T) {
+	planet := Coordinate{Galaxy: 1, System: 2, Position: 3, Type: PlanetType}
+	moon := Coordinate{Galaxy: 1, System: 2, Position: 3, Type: MoonType}
+	otherMoon := Coordinate{Galaxy: 1, System: 5, Position: 8, Type: MoonType}
+	debris := Coordinate{Galaxy: 1, System: 5, Position: 8, Type: DebrisType}
+	deepSpace := Coordinate{Galaxy: 1, System: 2, Position: 16, Type: PlanetType}
+	assert.True(t, IsPhalanxable(planet, otherMoon, Attack))
+	assert.True(t, IsPhalanxable(moon, planet, Park))
+	assert.False(t, IsPhalanxable(moon, otherMoon, Park))
+	assert.False(t, IsPhalanxable(moon, debris, RecycleDebrisField))
+	assert.False(t, IsPhalanxable(planet, deepSpace, Expedition))
+
+	fleets := []Fleet{{Origin: planet, Destination: deepSpace, Mission: Expedition}, {Origin: moon, Destination: planet, Mission: Transport}}
+	SetPhalanxable(fleets)
+	assert.False(t, fleets[0].Phalanxable)
+	assert.True(t, fleets[1].Phalanxable)
+}
@@ -48,3 +48,17 @@ func (h HighscorePlayer) String() string {
 		"   Homeworld: " + h.Homeworld.String() + "\n" +
 		"       Ships: " + utils.FI64(h.Ships) + "\n"
 }
+
+// Points points of a player in each highscore type
+type Points struct {
+	Position          int64 // Position in the total highscore
+	Total             int64
+	Economy           int64
+	Research          int64
+	Military          int64
+	MilitaryBuilt     int64
+	MilitaryDestroyed int64
+	MilitaryLost      int64
+	Honor             int64
+	Ships             int64 // Number of ships, from the military highscore
+}
@@ -0,0 +1,34 @@
+package ogame
+
+import "time"
+
+// MessageSummary common summary of a message, whatever the tab it comes from
+type MessageSummary struct {
+	ID         int64
+	TabID      MessagesTabID
+	Type       string // action | report | combat | expedition
+	Coordinate Coordinate
+	CreatedAt  time.Time
+}
+
+// MessageFilter simple filter used to select messages to delete.
+// Zero values are ignored, a message matches if it satisfies every set criteria.
+type MessageFilter struct {
+	OlderThan  time.Duration
+	Coordinate *Coordinate
+	Type       string
+}
+
+// Match returns either or not the message matches the filter, now is used to compute the age of the message
+func (f MessageFilter) Match(msg MessageSummary, now time.Time) bool {
+	if f.OlderThan > 0 && (msg.CreatedAt.IsZero() || !msg.CreatedAt.Before(now.Add(-f.OlderThan))) {
+		return false
+	}
+	if f.Coordinate != nil && !msg.Coordinate.Equal(*f.Coordinate) {
+		return false
+	}
+	if f.Type != "" && f.Type != msg.Type {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package ogame
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageFilter_Match(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	coord := Coordinate{Galaxy: 1, System: 2, Position: 3, Type: PlanetType}
+	msg := MessageSummary{ID: 1, TabID: 20, Type: "report", Coordinate: coord, CreatedAt: now.Add(-48 * time.Hour)}
+
+	assert.True(t, MessageFilter{}.Match(msg, now))
+	assert.True(t, MessageFilter{OlderThan: 24 * time.Hour}.Match(msg, now))
+	assert.False(t, MessageFilter{OlderThan: 72 * time.Hour}.Match(msg, now))
+	assert.True(t, MessageFilter{Coordinate: &coord, Type: "report"}.Match(msg, now))
+	assert.False(t, MessageFilter{Type: "action"}.Match(msg, now))
+	other := Coordinate{Galaxy: 1, System: 2, Position: 4, Type: PlanetType}
+	assert.False(t, MessageFilter{Coordinate: &other}.Match(msg, now))
+	assert.False(t, MessageFilter{OlderThan: time.Hour}.Match(MessageSummary{}, now))
+}
@@ -0,0 +1,93 @@
+package ogame
+
+import (
+	"errors"
+	"math"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// IPMNeeded returns how many interplanetary missiles are needed to destroy all the target defenses.
+// weaponsTech is the weapons technology level of the attacker, armourTech is the armour technology level of the target.
+// A missile damages the armour of the defenses, which is a tenth of their structural integrity.
+// Each anti-ballistic missile of the target intercepts one interplanetary missile.
+func IPMNeeded(target DefensesInfos, weaponsTech, armourTech int64) (int64, error) {
+	if weaponsTech < 0 || armourTech < 0 {
+		return 0, errors.New("invalid technology level")
+	}
+	damage := InterplanetaryMissiles.GetWeaponPower(Researches{WeaponsTechnology: weaponsTech})
+	targetResearches := Researches{ArmourTechnology: armourTech}
+	var armour float64
+	for _, defense := range Defenses {
+		if defense == InterplanetaryMissiles || defense == AntiBallisticMissiles {
+			continue
+		}
+		armour += float64(target.ByID(defense.GetID())) * float64(defense.GetStructuralIntegrity(targetResearches)) / 10
+	}
+	if armour == 0 {
+		return 0, nil
+	}
+	return target.AntiBallisticMissiles + int64(math.Ceil(armour/float64(damage))), nil
+}
+
+// ABMNeeded returns how many more anti-ballistic missiles the defender needs to intercept all the incoming
+// interplanetary missiles.
+func ABMNeeded(incomingIPMs, defenderABMs int64) int64 {
+	return utils.MaxInt(0, incomingIPMs-defenderABMs)
+}
+
+// IPMRange returns the range (in systems) of interplanetary missiles given the impulse drive level
+func IPMRange(impulseDrive int64) int64 {
+	return utils.MaxInt(0, 5*impulseDrive-1)
+}
+
+// MissileSiloCapacity returns the number of missile slots of a missile silo.
+// An anti-ballistic missile takes one slot, an interplanetary missile takes two.
+func MissileSiloCapacity(siloLevel int64) int64 {
+	return utils.MaxInt(0, 10*siloLevel)
+}
+
+// MissileThreat interplanetary missiles of a hostile player, as seen in an espionage report
+type MissileThreat struct {
+	Coordinate             Coordinate
+	Username               string
+	InterplanetaryMissiles int64
+	ImpulseDrive           *int64 // nil if the espionage report has no researches information
+}
+
+// MissileDefense missile defense status of a planet
+type MissileDefense struct {
+	PlanetID               PlanetID
+	Coordinate             Coordinate
+	MissileSilo            int64
+	AntiBallisticMissiles  int64
+	InterplanetaryMissiles int64
+	EnemyIPMs              int64 // Interplanetary missiles of the hostile players within missile range
+	ABMNeeded              int64 // Anti-ballistic missiles to build, limited by the free slots of the missile silo
+	UncoveredIPMs          int64 // Enemy missiles that cannot be intercepted even with a full missile silo
+}
+
+// ComputeMissileDefense sets EnemyIPMs, ABMNeeded and UncoveredIPMs of each planet given the hostile missile threats.
+// A threat without impulse drive information is assumed to be in range of every planet of its galaxy.
+func ComputeMissileDefense(planets []MissileDefense, threats []MissileThreat, nbSystems int64, donutSystem bool) []MissileDefense {
+	out := make([]MissileDefense, 0, len(planets))
+	for _, planet := range planets {
+		planet.EnemyIPMs = 0
+		for _, threat := range threats {
+			if threat.Coordinate.Galaxy != planet.Coordinate.Galaxy {
+				continue
+			}
+			if threat.ImpulseDrive != nil &&
+				SystemDistance(nbSystems, threat.Coordinate.System, planet.Coordinate.System, donutSystem) > IPMRange(*threat.ImpulseDrive) {
+				continue
+			}
+			planet.EnemyIPMs += threat.InterplanetaryMissiles
+		}
+		freeSlots := utils.MaxInt(0, MissileSiloCapacity(planet.MissileSilo)-planet.AntiBallisticMissiles-2*planet.InterplanetaryMissiles)
+		needed := ABMNeeded(planet.EnemyIPMs, planet.AntiBallisticMissiles)
+		planet.ABMNeeded = utils.MinInt(needed, freeSlots)
+		planet.UncoveredIPMs = needed - planet.ABMNeeded
+		out = append(out, planet)
+	}
+	return out
+}
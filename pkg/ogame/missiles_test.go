@@ -0,0 +1,89 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPMNeeded(t *testing.T) {
+	nbr, err := IPMNeeded(DefensesInfos{}, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), nbr)
+
+	// 60 rocket launchers = 60 * 200 armour = 12000, exactly one missile
+	nbr, _ = IPMNeeded(DefensesInfos{RocketLauncher: 60}, 0, 0)
+	assert.Equal(t, int64(1), nbr)
+
+	nbr, _ = IPMNeeded(DefensesInfos{RocketLauncher: 61}, 0, 0)
+	assert.Equal(t, int64(2), nbr)
+
+	// Anti-ballistic missiles each intercept one missile
+	nbr, _ = IPMNeeded(DefensesInfos{RocketLauncher: 60, AntiBallisticMissiles: 3}, 0, 0)
+	assert.Equal(t, int64(4), nbr)
+
+	// 10 plasma turrets = 10 * 10000 armour, armour tech 10 => 200000, weapons 10 => 24000 damage per missile
+	nbr, _ = IPMNeeded(DefensesInfos{PlasmaTurret: 10}, 10, 10)
+	assert.Equal(t, int64(9), nbr)
+
+	_, err = IPMNeeded(DefensesInfos{}, -1, 0)
+	assert.Error(t, err)
+}
+
+func TestABMNeeded(t *testing.T) {
+	assert.Equal(t, int64(0), ABMNeeded(0, 0))
+	assert.Equal(t, int64(0), ABMNeeded(10, 15))
+	assert.Equal(t, int64(5), ABMNeeded(20, 15))
+}
+
+func TestIPMRange(t *testing.T) {
+	assert.Equal(t, int64(0), IPMRange(0))
+	assert.Equal(t, int64(4), IPMRange(1))
+	assert.Equal(t, int64(49), IPMRange(10))
+}
+
+func TestMissileSiloCapacity(t *testing.T) {
+	assert.Equal(t, int64(0), MissileSiloCapacity(0))
+	assert.Equal(t, int64(50), MissileSiloCapacity(5))
+}
+
+func TestComputeMissileDefense(t *testing.T) {
+	planets := []MissileDefense{
+		{PlanetID: 1, Coordinate: Coordinate{1, 100, 8, PlanetType}, MissileSilo: 5, AntiBallisticMissiles: 10},
+		{PlanetID: 2, Coordinate: Coordinate{1, 300, 8, PlanetType}, MissileSilo: 2, AntiBallisticMissiles: 5, InterplanetaryMissiles: 5},
+		{PlanetID: 3, Coordinate: Coordinate{2, 100, 8, PlanetType}, MissileSilo: 1},
+	}
+	threats := []MissileThreat{
+		{Coordinate: Coordinate{1, 104, 3, PlanetType}, InterplanetaryMissiles: 30, ImpulseDrive: utils.I64Ptr(1)}, // range 4
+		{Coordinate: Coordinate{1, 110, 3, PlanetType}, InterplanetaryMissiles: 20, ImpulseDrive: utils.I64Ptr(2)}, // range 9, out of range
+		{Coordinate: Coordinate{1, 490, 3, PlanetType}, InterplanetaryMissiles: 7},                                 // unknown range
+	}
+	out := ComputeMissileDefense(planets, threats, 499, false)
+	assert.Equal(t, 3, len(out))
+
+	// 30 + 7 enemy missiles, 10 ABM, 40 free slots
+	assert.Equal(t, int64(37), out[0].EnemyIPMs)
+	assert.Equal(t, int64(27), out[0].ABMNeeded)
+	assert.Equal(t, int64(0), out[0].UncoveredIPMs)
+
+	// 7 enemy missiles, 5 ABM, silo full (5 ABM + 5 IPM * 2 = 15 of 20 slots, 5 free)
+	assert.Equal(t, int64(7), out[1].EnemyIPMs)
+	assert.Equal(t, int64(2), out[1].ABMNeeded)
+	assert.Equal(t, int64(0), out[1].UncoveredIPMs)
+
+	// Other galaxy
+	assert.Equal(t, int64(0), out[2].EnemyIPMs)
+	assert.Equal(t, int64(0), out[2].ABMNeeded)
+
+	// Silo too small for the threat
+	out = ComputeMissileDefense(planets[2:], []MissileThreat{{Coordinate: Coordinate{2, 100, 1, PlanetType}, InterplanetaryMissiles: 15}}, 499, false)
+	assert.Equal(t, int64(15), out[0].EnemyIPMs)
+	assert.Equal(t, int64(10), out[0].ABMNeeded)
+	assert.Equal(t, int64(5), out[0].UncoveredIPMs)
+
+	// Donut system, 499 and 2 are 2 systems apart
+	out = ComputeMissileDefense([]MissileDefense{{Coordinate: Coordinate{1, 2, 8, PlanetType}, MissileSilo: 5}},
+		[]MissileThreat{{Coordinate: Coordinate{1, 499, 3, PlanetType}, InterplanetaryMissiles: 3, ImpulseDrive: utils.I64Ptr(1)}}, 499, true)
+	assert.Equal(t, int64(3), out[0].EnemyIPMs)
+}
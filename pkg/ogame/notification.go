@@ -0,0 +1,20 @@
+package ogame
+
+// NotificationType kind of notification displayed in the top bar
+type NotificationType string
+
+// Notification types
+const (
+	NotificationMessages   NotificationType = "messages"    // Unread messages
+	NotificationChat       NotificationType = "chat"        // Unread conversations
+	NotificationAttack     NotificationType = "attack"      // Hostile fleet incoming
+	NotificationWreckField NotificationType = "wreck_field" // Wreck field that can be repaired
+)
+
+// Notification active notification of the top bar (unread messages, incoming attack, wreck field...)
+type Notification struct {
+	Type      NotificationType
+	Count     int64  // Number of unread messages/conversations, or ships in the wreck field
+	Title     string // Tooltip of the notification
+	Countdown int64  // Seconds before the wreck field expires
+}
@@ -195,6 +195,19 @@ func (o ObjsStruct) ByID(id ID) BaseOgameObj {
 	return o.m[id]
 }
 
+// GetRapidfire returns the rapidfire multipliers a unit (ship or defense) has against other units
+func (o ObjsStruct) GetRapidfire(id ID) (map[ID]int64, error) {
+	defender, ok := o.ByID(id).(DefenderObj)
+	if !ok {
+		return nil, ErrNotCombatUnit
+	}
+	out := make(map[ID]int64, len(defender.GetRapidfireAgainst()))
+	for unitID, rapidfire := range defender.GetRapidfireAgainst() {
+		out[unitID] = rapidfire
+	}
+	return out, nil
+}
+
 var Objs = ObjsStruct{m: make(map[ID]BaseOgameObj)}
 
 func register[T BaseOgameObj](constructorFn func() T) T {
@@ -0,0 +1,18 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjs_GetRapidfire(t *testing.T) {
+	rapidfire, err := Objs.GetRapidfire(CruiserID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[ID]int64{EspionageProbeID: 5, SolarSatelliteID: 5, LightFighterID: 6, RocketLauncherID: 10, CrawlerID: 5}, rapidfire)
+
+	_, err = Objs.GetRapidfire(MetalMineID)
+	assert.Equal(t, ErrNotCombatUnit, err)
+	_, err = Objs.GetRapidfire(ID(0))
+	assert.Equal(t, ErrNotCombatUnit, err)
+}
@@ -194,6 +194,21 @@ type EspionageReportSummary struct {
 	From           string // Fleet Command | Space Monitoring
 	Target         Coordinate
 	LootPercentage float64
+	CreatedAt      time.Time
+	// Action messages only
+	Attacker         string     // Player who spied, empty if not visible
+	AttackerOrigin   Coordinate // Celestial the probes came from
+	CounterEspionage int64      // Chance of counter-espionage (percent)
+}
+
+// SpiedEvent an espionage action of another player on one of our celestials
+type SpiedEvent struct {
+	MessageID        int64
+	Attacker         string // Empty if not visible
+	AttackerOrigin   Coordinate
+	Target           Coordinate
+	Date             time.Time
+	CounterEspionage int64 // Chance of counter-espionage (percent)
 }
 
 // ExpeditionMessage ...
@@ -250,6 +265,12 @@ type Preferences struct {
 	}
 }
 
+// SpyReportSettings preferences that affect the espionage
+type SpyReportSettings struct {
+	ProbesCount  int64 // probes sent by the galaxy quick action, 1 to 99
+	ShowPictures bool  // show the units pictures in the espionage reports
+}
+
 type ACSValues struct {
 	ACSValues string
 	Union     int64
@@ -0,0 +1,9 @@
+package ogame
+
+// ParseWarning something on a page the extractor could not make sense of.
+// The result is still returned, but the field it concerns may be wrong (usually zero).
+type ParseWarning struct {
+	Page    string
+	Field   string
+	Message string
+}
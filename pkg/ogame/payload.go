@@ -0,0 +1,87 @@
+package ogame
+
+import "github.com/alaingilbert/ogame/pkg/utils"
+
+// PayloadResource resource used in the fill priority of a PayloadSpec
+type PayloadResource string
+
+// Payload resources
+const (
+	PayloadMetal     PayloadResource = "metal"
+	PayloadCrystal   PayloadResource = "crystal"
+	PayloadDeuterium PayloadResource = "deuterium"
+)
+
+// PayloadSpec payload of a fleet, resolved at dispatch time against the resources of the origin and the cargo of the fleet
+type PayloadSpec struct {
+	Fixed   Resources         // Amounts to load, limited to the resources available
+	Fill    []PayloadResource // Fill the remaining cargo with these resources, in order
+	Partial bool              // Load what fits when the cargo is smaller than the fixed amounts, instead of failing
+}
+
+// IsValid returns either or not the payload resource is known
+func (r PayloadResource) IsValid() bool {
+	return r == PayloadMetal || r == PayloadCrystal || r == PayloadDeuterium
+}
+
+func (r PayloadResource) get(res Resources) int64 {
+	switch r {
+	case PayloadMetal:
+		return res.Metal
+	case PayloadCrystal:
+		return res.Crystal
+	case PayloadDeuterium:
+		return res.Deuterium
+	}
+	return 0
+}
+
+func (r PayloadResource) add(res *Resources, nbr int64) {
+	switch r {
+	case PayloadMetal:
+		res.Metal += nbr
+	case PayloadCrystal:
+		res.Crystal += nbr
+	case PayloadDeuterium:
+		res.Deuterium += nbr
+	}
+}
+
+// Resolve returns the resources to load, given the resources available on the origin, the cargo of the fleet,
+// and the deuterium consumed by the flight, which cannot be loaded.
+// When the fixed amounts do not fit in the cargo, ErrPayloadExceedsCargo is returned,
+// unless Partial is set, in which case the fixed amounts are loaded in the Fill order,
+// then deuterium then crystal then metal (same priority as SendFleet).
+func (p PayloadSpec) Resolve(available Resources, cargo, fuel int64) (out Resources, err error) {
+	available.Deuterium -= utils.MaxInt(fuel, 0)
+	fixed := Resources{
+		Metal:     utils.MinInt(utils.MaxInt(p.Fixed.Metal, 0), utils.MaxInt(available.Metal, 0)),
+		Crystal:   utils.MinInt(utils.MaxInt(p.Fixed.Crystal, 0), utils.MaxInt(available.Crystal, 0)),
+		Deuterium: utils.MinInt(utils.MaxInt(p.Fixed.Deuterium, 0), utils.MaxInt(available.Deuterium, 0)),
+	}
+	cargo = utils.MaxInt(cargo, 0)
+	if fixed.Metal+fixed.Crystal+fixed.Deuterium > cargo {
+		if !p.Partial {
+			return out, ErrPayloadExceedsCargo
+		}
+		loaded := make(map[PayloadResource]bool)
+		for _, r := range append(append([]PayloadResource{}, p.Fill...), PayloadDeuterium, PayloadCrystal, PayloadMetal) {
+			if !r.IsValid() || loaded[r] {
+				continue
+			}
+			loaded[r] = true
+			nbr := utils.MinInt(r.get(fixed), cargo)
+			r.add(&out, nbr)
+			cargo -= nbr
+		}
+		return out, nil
+	}
+	out = fixed
+	remaining := cargo - (fixed.Metal + fixed.Crystal + fixed.Deuterium)
+	for _, r := range p.Fill {
+		nbr := utils.MinInt(utils.MaxInt(r.get(available)-r.get(out), 0), remaining)
+		r.add(&out, nbr)
+		remaining -= nbr
+	}
+	return out, nil
+}
@@ -0,0 +1,56 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadSpecResolve(t *testing.T) {
+	available := Resources{Metal: 100_000, Crystal: 50_000, Deuterium: 20_000}
+
+	spec := PayloadSpec{Fixed: Resources{Metal: 10_000, Crystal: 5_000}, Fill: []PayloadResource{PayloadDeuterium, PayloadMetal}}
+	res, err := spec.Resolve(available, 50_000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Metal: 25_000, Crystal: 5_000, Deuterium: 20_000}, res)
+
+	spec = PayloadSpec{Fixed: Resources{Metal: 200_000}}
+	res, err = spec.Resolve(available, 500_000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Metal: 100_000}, res)
+
+	spec = PayloadSpec{Fixed: Resources{Metal: 30_000, Deuterium: 10_000}}
+	_, err = spec.Resolve(available, 25_000, 0)
+	assert.Equal(t, ErrPayloadExceedsCargo, err)
+
+	spec.Partial = true
+	res, err = spec.Resolve(available, 25_000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Metal: 15_000, Deuterium: 10_000}, res)
+
+	// The fixed amounts are loaded in the Fill order when they do not fit
+	spec = PayloadSpec{Fixed: Resources{Metal: 30_000, Crystal: 10_000, Deuterium: 10_000}, Fill: []PayloadResource{PayloadMetal}, Partial: true}
+	res, err = spec.Resolve(available, 35_000, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Metal: 30_000, Deuterium: 5_000}, res)
+}
+
+func TestPayloadSpecResolve_fuel(t *testing.T) {
+	available := Resources{Metal: 100_000, Crystal: 50_000, Deuterium: 20_000}
+
+	// The deuterium needed for the flight is not loaded
+	spec := PayloadSpec{Fill: []PayloadResource{PayloadDeuterium}}
+	res, err := spec.Resolve(available, 50_000, 3_000)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Deuterium: 17_000}, res)
+
+	spec = PayloadSpec{Fixed: Resources{Deuterium: 20_000}, Fill: []PayloadResource{PayloadMetal}}
+	res, err = spec.Resolve(available, 30_000, 3_000)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{Metal: 13_000, Deuterium: 17_000}, res)
+
+	// Not enough deuterium left for the flight
+	res, err = spec.Resolve(Resources{Deuterium: 1_000}, 30_000, 3_000)
+	assert.NoError(t, err)
+	assert.Equal(t, Resources{}, res)
+}
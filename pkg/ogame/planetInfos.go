@@ -69,6 +69,44 @@ func (s SystemInfos) MarshalJSON() ([]byte, error) {
 	return json.Marshal(tmp)
 }
 
+// GalaxyRelation relation between the player and the owner of a galaxy position
+type GalaxyRelation string
+
+// Galaxy relations
+const (
+	RelationMine   GalaxyRelation = "mine"
+	RelationAllied GalaxyRelation = "allied"
+	RelationEnemy  GalaxyRelation = "enemy" // Any other player
+)
+
+// TagRelations sets the relation of every planet of the system to the player, allianceID 0 when not in an alliance
+func (s SystemInfos) TagRelations(playerID, allianceID int64) {
+	s.Each(func(planetInfo *PlanetInfos) {
+		if planetInfo == nil {
+			return
+		}
+		switch {
+		case planetInfo.Player.ID == playerID:
+			planetInfo.Relation = RelationMine
+		case allianceID != 0 && planetInfo.Alliance != nil && planetInfo.Alliance.ID == allianceID:
+			planetInfo.Relation = RelationAllied
+		default:
+			planetInfo.Relation = RelationEnemy
+		}
+	})
+}
+
+// FilterGalaxy returns the planets of the system matching the predicate, empty positions are skipped
+func FilterGalaxy(infos SystemInfos, pred func(PlanetInfos) bool) []PlanetInfos {
+	out := make([]PlanetInfos, 0)
+	infos.Each(func(planetInfo *PlanetInfos) {
+		if planetInfo != nil && pred(*planetInfo) {
+			out = append(out, *planetInfo)
+		}
+	})
+	return out
+}
+
 // MoonInfos public information of a moon in the galaxy page
 type MoonInfos struct {
 	ID       int64
@@ -114,4 +152,5 @@ type PlanetInfos struct {
 	}
 	Alliance *AllianceInfos
 	Date     time.Time
+	Relation GalaxyRelation `json:",omitempty"` // Only set by TagRelations
 }
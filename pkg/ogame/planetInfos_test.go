@@ -53,3 +53,35 @@ func TestSystemInfos_MarshalJSON(t *testing.T) {
 		`null,null,null,null,null,null,null,null,null,null,null,null,null],"ExpeditionDebris":{"Metal":0,"Crystal":0,"PathfindersNeeded":0}}`
 	assert.Equal(t, expected, string(by))
 }
+
+func TestSystemInfos_TagRelations(t *testing.T) {
+	mine, allied, enemy, lonely := &PlanetInfos{}, &PlanetInfos{}, &PlanetInfos{}, &PlanetInfos{}
+	mine.Player.ID = 1
+	allied.Player.ID = 2
+	allied.Alliance = &AllianceInfos{ID: 10}
+	enemy.Player.ID = 3
+	enemy.Alliance = &AllianceInfos{ID: 11}
+	lonely.Player.ID = 4
+	si := SystemInfos{}
+	si.Tmpplanets[0], si.Tmpplanets[1], si.Tmpplanets[2], si.Tmpplanets[3] = mine, allied, enemy, lonely
+	si.TagRelations(1, 10)
+	assert.Equal(t, RelationMine, mine.Relation)
+	assert.Equal(t, RelationAllied, allied.Relation)
+	assert.Equal(t, RelationEnemy, enemy.Relation)
+	assert.Equal(t, RelationEnemy, lonely.Relation)
+
+	si.TagRelations(1, 0)
+	assert.Equal(t, RelationEnemy, allied.Relation)
+}
+
+func TestFilterGalaxy(t *testing.T) {
+	si := SystemInfos{}
+	si.Tmpplanets[2] = &PlanetInfos{ID: 3, Inactive: true, Moon: &MoonInfos{ID: 33}}
+	si.Tmpplanets[5] = &PlanetInfos{ID: 6, Inactive: true}
+	si.Tmpplanets[8] = &PlanetInfos{ID: 9, Moon: &MoonInfos{ID: 99}}
+	res := FilterGalaxy(si, func(p PlanetInfos) bool { return p.Moon != nil && p.Inactive })
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, int64(3), res[0].ID)
+	assert.Equal(t, 3, len(FilterGalaxy(si, func(PlanetInfos) bool { return true })))
+	assert.Equal(t, 0, len(FilterGalaxy(SystemInfos{}, func(PlanetInfos) bool { return true })))
+}
@@ -0,0 +1,9 @@
+package ogame
+
+// QueuedBuild a building in the building queue of a celestial.
+// The first one is being built, the others are waiting (the queue needs the Commander).
+type QueuedBuild struct {
+	ID     ID
+	Level  int64 // Level reached once built
+	ListID int64 // Id of the queue entry, used to cancel it
+}
@@ -1,5 +1,7 @@
 package ogame
 
+import "sort"
+
 type researchLab struct {
 	BaseBuilding
 }
@@ -12,3 +14,19 @@ func newResearchLab() *researchLab {
 	b.BaseCost = Resources{Metal: 200, Crystal: 400, Deuterium: 200}
 	return b
 }
+
+// EffectiveLabLevel returns the lab level used to compute research times with the intergalactic research network.
+// The lab of the researching planet is always counted, and is joined by the irnLevel highest labs of the other planets.
+func EffectiveLabLevel(researchingLab int64, otherLabs []int64, irnLevel int64) int64 {
+	sorted := make([]int64, len(otherLabs))
+	copy(sorted, otherLabs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	total := researchingLab
+	for i, lvl := range sorted {
+		if int64(i) >= irnLevel {
+			break
+		}
+		total += lvl
+	}
+	return total
+}
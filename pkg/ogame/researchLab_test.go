@@ -11,3 +11,13 @@ func TestResearchLabCost(t *testing.T) {
 	assert.Equal(t, Resources{Metal: 1600, Crystal: 3200, Deuterium: 1600}, rl.GetPrice(4))
 	assert.Equal(t, Resources{Metal: 6400, Crystal: 12800, Deuterium: 6400}, rl.GetPrice(6))
 }
+
+func TestEffectiveLabLevel(t *testing.T) {
+	assert.Equal(t, int64(0), EffectiveLabLevel(0, nil, 3))
+	assert.Equal(t, int64(12), EffectiveLabLevel(12, []int64{10, 8}, 0))
+	assert.Equal(t, int64(22), EffectiveLabLevel(12, []int64{8, 10}, 1))
+	assert.Equal(t, int64(30), EffectiveLabLevel(12, []int64{8, 10}, 5))
+	// The researching lab is counted even when it is not one of the highest
+	assert.Equal(t, int64(3), EffectiveLabLevel(3, []int64{12, 10}, 0))
+	assert.Equal(t, int64(15), EffectiveLabLevel(3, []int64{10, 12}, 1))
+}
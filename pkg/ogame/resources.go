@@ -3,6 +3,7 @@ package ogame
 import (
 	"fmt"
 	stdmath "math"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/google/gxui/math"
@@ -14,18 +15,21 @@ type ResourcesDetails struct {
 		Available         int64
 		StorageCapacity   int64
 		CurrentProduction int64
+		HoursUntilFull    float64 // 0 if the storage is full, -1 if it never fills (see SetHoursUntilFull)
 		// DenCapacity       int
 	}
 	Crystal struct {
 		Available         int64
 		StorageCapacity   int64
 		CurrentProduction int64
+		HoursUntilFull    float64 // 0 if the storage is full, -1 if it never fills (see SetHoursUntilFull)
 		// DenCapacity       int
 	}
 	Deuterium struct {
 		Available         int64
 		StorageCapacity   int64
 		CurrentProduction int64
+		HoursUntilFull    float64 // 0 if the storage is full, -1 if it never fills (see SetHoursUntilFull)
 		// DenCapacity       int
 	}
 	Food struct {
@@ -55,6 +59,75 @@ type ResourcesDetails struct {
 		Purchased int64
 		Found     int64
 	}
+	MetalCapped     bool // Metal storage is full, production is lost
+	CrystalCapped   bool // Crystal storage is full, production is lost
+	DeuteriumCapped bool // Deuterium storage is full, production is lost
+}
+
+// SetCapped computes the capped flags, a resource is capped when the available amount reaches the storage capacity
+func (r *ResourcesDetails) SetCapped() {
+	isCapped := func(available, capacity int64) bool { return capacity > 0 && available >= capacity }
+	r.MetalCapped = isCapped(r.Metal.Available, r.Metal.StorageCapacity)
+	r.CrystalCapped = isCapped(r.Crystal.Available, r.Crystal.StorageCapacity)
+	r.DeuteriumCapped = isCapped(r.Deuterium.Available, r.Deuterium.StorageCapacity)
+}
+
+// SetHoursUntilFull computes the hours before the metal/crystal/deuterium storages are full at the current production,
+// rounded to the hundredth. 0 means the storage is already full, -1 that it never fills (no production).
+func (r *ResourcesDetails) SetHoursUntilFull() {
+	hours := func(d time.Duration) float64 {
+		if d < 0 {
+			return -1
+		}
+		return stdmath.Round(d.Hours()*100) / 100
+	}
+	metal, crystal, deuterium := r.StorageFullIn()
+	r.Metal.HoursUntilFull = hours(metal)
+	r.Crystal.HoursUntilFull = hours(crystal)
+	r.Deuterium.HoursUntilFull = hours(deuterium)
+}
+
+// storageFullIn returns how long before the storage is full, given the production per hour.
+// Returns 0 if the storage is already full, and -1 if it never fills.
+func storageFullIn(available, capacity, productionPerHour int64) time.Duration {
+	if capacity <= 0 {
+		return -1
+	}
+	if available >= capacity {
+		return 0
+	}
+	if productionPerHour <= 0 {
+		return -1
+	}
+	return time.Duration(float64(capacity-available) / float64(productionPerHour) * float64(time.Hour))
+}
+
+// StorageFullIn returns how long before the metal/crystal/deuterium storages are full at the current production.
+// 0 means the storage is already full, -1 that it never fills (no production).
+func (r ResourcesDetails) StorageFullIn() (metal, crystal, deuterium time.Duration) {
+	metal = storageFullIn(r.Metal.Available, r.Metal.StorageCapacity, r.Metal.CurrentProduction)
+	crystal = storageFullIn(r.Crystal.Available, r.Crystal.StorageCapacity, r.Crystal.CurrentProduction)
+	deuterium = storageFullIn(r.Deuterium.Available, r.Deuterium.StorageCapacity, r.Deuterium.CurrentProduction)
+	return
+}
+
+// wastedIn returns the production lost to a full storage during elapsed
+func wastedIn(available, capacity, productionPerHour int64, elapsed time.Duration) int64 {
+	fullIn := storageFullIn(available, capacity, productionPerHour)
+	if fullIn < 0 || fullIn >= elapsed {
+		return 0
+	}
+	return int64((elapsed - fullIn).Hours() * float64(productionPerHour))
+}
+
+// WastedIn estimates the metal/crystal/deuterium lost to full storages during elapsed, starting from these details.
+// It assumes the production is constant and nothing is spent or shipped meanwhile.
+func (r ResourcesDetails) WastedIn(elapsed time.Duration) Resources {
+	return Resources{
+		Metal:     wastedIn(r.Metal.Available, r.Metal.StorageCapacity, r.Metal.CurrentProduction, elapsed),
+		Crystal:   wastedIn(r.Crystal.Available, r.Crystal.StorageCapacity, r.Crystal.CurrentProduction, elapsed),
+		Deuterium: wastedIn(r.Deuterium.Available, r.Deuterium.StorageCapacity, r.Deuterium.CurrentProduction, elapsed),
+	}
 }
 
 // Available returns the resources available
@@ -71,6 +71,30 @@ func (r ResourcesBuildings) ByID(id ID) int64 {
 	return 0
 }
 
+// Set sets the resource building level using the building id
+func (r *ResourcesBuildings) Set(id ID, val int64) {
+	switch id {
+	case MetalMineID:
+		r.MetalMine = val
+	case CrystalMineID:
+		r.CrystalMine = val
+	case DeuteriumSynthesizerID:
+		r.DeuteriumSynthesizer = val
+	case SolarPlantID:
+		r.SolarPlant = val
+	case FusionReactorID:
+		r.FusionReactor = val
+	case SolarSatelliteID:
+		r.SolarSatellite = val
+	case MetalStorageID:
+		r.MetalStorage = val
+	case CrystalStorageID:
+		r.CrystalStorage = val
+	case DeuteriumTankID:
+		r.DeuteriumTank = val
+	}
+}
+
 func (r ResourcesBuildings) String() string {
 	return "\n" +
 		"           Metal Mine: " + utils.FI64(r.MetalMine) + "\n" +
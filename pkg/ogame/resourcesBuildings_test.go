@@ -54,3 +54,11 @@ func TestResourcesBuildings_String(t *testing.T) {
 		"       Deuterium Tank: 9"
 	assert.Equal(t, expected, r.String())
 }
+
+func TestResourcesBuildings_Set(t *testing.T) {
+	r := ResourcesBuildings{}
+	r.Set(MetalMineID, 10)
+	r.Set(DeuteriumTankID, 3)
+	r.Set(ShipyardID, 5)
+	assert.Equal(t, ResourcesBuildings{MetalMine: 10, DeuteriumTank: 3}, r)
+}
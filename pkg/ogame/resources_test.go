@@ -2,6 +2,7 @@ package ogame
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/gxui/math"
 	"github.com/stretchr/testify/assert"
@@ -100,3 +101,62 @@ func TestResources_FitsIn(t *testing.T) {
 	assert.Equal(t, int64(0), Resources{Metal: 100, Crystal: 200, Deuterium: 300}.FitsIn(EspionageProbe, Researches{}, false, false, false))
 	assert.Equal(t, int64(120), Resources{Metal: 100, Crystal: 200, Deuterium: 300}.FitsIn(EspionageProbe, Researches{}, true, false, false))
 }
+
+func TestResourcesDetails_SetCapped(t *testing.T) {
+	var details ResourcesDetails
+	details.Metal.Available = 10_000
+	details.Metal.StorageCapacity = 10_000
+	details.Crystal.Available = 5_000
+	details.Crystal.StorageCapacity = 10_000
+	details.Deuterium.Available = 5_000
+	details.SetCapped()
+	assert.True(t, details.MetalCapped)
+	assert.False(t, details.CrystalCapped)
+	assert.False(t, details.DeuteriumCapped)
+}
+
+func TestResourcesDetails_SetHoursUntilFull(t *testing.T) {
+	var details ResourcesDetails
+	details.Metal.Available = 10_000
+	details.Metal.StorageCapacity = 10_000
+	details.Metal.CurrentProduction = 1_000
+	details.Crystal.Available = 3_600
+	details.Crystal.StorageCapacity = 10_000
+	details.Crystal.CurrentProduction = 2_000
+	details.Deuterium.Available = 5_000
+	details.Deuterium.StorageCapacity = 10_000
+	details.SetHoursUntilFull()
+	assert.Equal(t, 0.0, details.Metal.HoursUntilFull)
+	assert.Equal(t, 3.2, details.Crystal.HoursUntilFull)
+	assert.Equal(t, -1.0, details.Deuterium.HoursUntilFull)
+}
+
+func TestResourcesDetails_StorageFullIn(t *testing.T) {
+	var details ResourcesDetails
+	details.Metal.Available = 10_000
+	details.Metal.StorageCapacity = 10_000
+	details.Metal.CurrentProduction = 1_000
+	details.Crystal.Available = 5_000
+	details.Crystal.StorageCapacity = 10_000
+	details.Crystal.CurrentProduction = 2_000
+	details.Deuterium.Available = 5_000
+	details.Deuterium.StorageCapacity = 10_000
+	metal, crystal, deuterium := details.StorageFullIn()
+	assert.Equal(t, time.Duration(0), metal)
+	assert.Equal(t, 150*time.Minute, crystal)
+	assert.Equal(t, time.Duration(-1), deuterium)
+}
+
+func TestResourcesDetails_WastedIn(t *testing.T) {
+	var details ResourcesDetails
+	details.Metal.Available = 10_000
+	details.Metal.StorageCapacity = 10_000
+	details.Metal.CurrentProduction = 1_000
+	details.Crystal.Available = 5_000
+	details.Crystal.StorageCapacity = 10_000
+	details.Crystal.CurrentProduction = 2_000
+	details.Deuterium.Available = 5_000
+	details.Deuterium.StorageCapacity = 10_000
+	assert.Equal(t, Resources{Metal: 3_000, Crystal: 1_000}, details.WastedIn(3*time.Hour))
+	assert.Equal(t, Resources{Metal: 2_000}, details.WastedIn(2*time.Hour))
+}
@@ -65,6 +65,27 @@ func (s ShipsInfos) HasFlyableShips() bool {
 	return false
 }
 
+// ValidateFleetComposition ensure the ships can be sent on the mission.
+// A fleet made only of espionage probes can attack only if the server allows probe raids.
+func ValidateFleetComposition(ships ShipsInfos, mission MissionID, probeRaids bool) error {
+	if !ships.HasFlyableShips() {
+		return ErrNoShipSelected
+	}
+	if (mission == Attack || mission == GroupedAttack) && !probeRaids {
+		probesOnly := ships.EspionageProbe > 0
+		for _, ship := range Ships {
+			if ship.GetID() != EspionageProbeID && ship.GetID().IsFlyableShip() && ships.ByID(ship.GetID()) > 0 {
+				probesOnly = false
+				break
+			}
+		}
+		if probesOnly {
+			return ErrProbeRaidsDisabled
+		}
+	}
+	return nil
+}
+
 // Speed returns the speed of the slowest ship
 func (s ShipsInfos) Speed(techs Researches, isCollector, isGeneral bool) int64 {
 	var minSpeed int64 = math.MaxInt64
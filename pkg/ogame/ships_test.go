@@ -17,6 +17,20 @@ func TestShipsInfos_Cargo(t *testing.T) {
 	}
 	techs := Researches{}
 	assert.Equal(t, int64(60000), ships.Cargo(techs, false, false, false))
+
+	probes := ShipsInfos{EspionageProbe: 10}
+	assert.Equal(t, int64(0), probes.Cargo(techs, false, false, false))
+	assert.Equal(t, int64(50), probes.Cargo(techs, true, false, false))
+}
+
+func TestValidateFleetComposition(t *testing.T) {
+	probes := ShipsInfos{EspionageProbe: 10}
+	assert.Equal(t, ErrProbeRaidsDisabled, ValidateFleetComposition(probes, Attack, false))
+	assert.Equal(t, ErrProbeRaidsDisabled, ValidateFleetComposition(probes, GroupedAttack, false))
+	assert.NoError(t, ValidateFleetComposition(probes, Attack, true))
+	assert.NoError(t, ValidateFleetComposition(probes, Spy, false))
+	assert.NoError(t, ValidateFleetComposition(ShipsInfos{EspionageProbe: 10, LightFighter: 1}, Attack, false))
+	assert.Equal(t, ErrNoShipSelected, ValidateFleetComposition(ShipsInfos{SolarSatellite: 10}, Transport, true))
 }
 
 func TestShipsInfos_FleetValue(t *testing.T) {
@@ -0,0 +1,24 @@
+package ogame
+
+// DefaultAllowedSpeeds fleet speeds of a standard universe, 10% to 100%
+var DefaultAllowedSpeeds = NewAllowedSpeeds(1, 10, 10)
+
+// NewAllowedSpeeds returns the speeds of a fleet speed selector made of steps of stepSize percent,
+// from minStep to maxStep (eg: 5% steps for the general class, up to 300% in some universes)
+func NewAllowedSpeeds(minStep, maxStep, stepSize int64) []Speed {
+	speeds := make([]Speed, 0)
+	for step := minStep; step <= maxStep; step++ {
+		speeds = append(speeds, Speed(float64(step*stepSize)/10))
+	}
+	return speeds
+}
+
+// IsSpeedAllowed returns either or not speed is one of the allowed speeds
+func IsSpeedAllowed(speed Speed, allowed []Speed) bool {
+	for _, s := range allowed {
+		if s == speed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package ogame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllowedSpeeds(t *testing.T) {
+	assert.Equal(t, []Speed{TenPercent, TwentyPercent, ThirtyPercent, FourtyPercent, FiftyPercent,
+		SixtyPercent, SeventyPercent, EightyPercent, NinetyPercent, HundredPercent}, DefaultAllowedSpeeds)
+
+	general := NewAllowedSpeeds(1, 20, 5)
+	assert.Len(t, general, 20)
+	assert.Equal(t, FivePercent, general[0])
+	assert.Equal(t, HundredPercent, general[19])
+
+	ultra := NewAllowedSpeeds(1, 30, 10)
+	assert.Equal(t, Speed(30), ultra[29])
+}
+
+func TestIsSpeedAllowed(t *testing.T) {
+	assert.True(t, IsSpeedAllowed(HundredPercent, DefaultAllowedSpeeds))
+	assert.False(t, IsSpeedAllowed(FivePercent, DefaultAllowedSpeeds))
+	assert.False(t, IsSpeedAllowed(Speed(20), DefaultAllowedSpeeds))
+	assert.True(t, IsSpeedAllowed(Speed(20), NewAllowedSpeeds(1, 30, 10)))
+	assert.True(t, IsSpeedAllowed(FivePercent, NewAllowedSpeeds(1, 20, 5)))
+}
@@ -0,0 +1,10 @@
+package ogame
+
+// EffectiveSpeeds speeds currently applied by the universe, including the temporary modifiers (events)
+type EffectiveSpeeds struct {
+	Economy       int64
+	Research      int64 // Economy speed multiplied by the research duration divisor
+	FleetPeaceful int64
+	FleetWar      int64
+	FleetHolding  int64
+}
@@ -2,10 +2,17 @@ package ogame
 
 import "time"
 
+// TechnologyDetails details of a building/research/ship/defense/lifeform technology,
+// as shown in the details panel of its page
 type TechnologyDetails struct {
 	TechnologyID       ID
+	Name               string // In the game language
+	Description        string
 	ProductionDuration time.Duration
 	Price              Resources
+	EnergyNeeded       int64 // Additional energy consumption of the next level
 	Level              int64
+	HasRequirements    bool // The technology has requirements in the techtree, met or not
+	UpgradeEnabled     bool // The improve/build button is enabled (requirements met, enough resources, free slot)
 	TearDownEnabled    bool
 }
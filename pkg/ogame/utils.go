@@ -12,9 +12,9 @@ import (
 	"unicode"
 )
 
-// ParseCoord parse a coordinate from a string
+// ParseCoord parse a coordinate from a string (eg: 1:234:8, M:1:234:8, DF:1:234:8, [P:1:234:8]), planet when no type is given
 func ParseCoord(str string) (coord Coordinate, err error) {
-	m := regexp.MustCompile(`^\[?(([PMD]):)?(\d{1,3}):(\d{1,3}):(\d{1,3})]?$`).FindStringSubmatch(str)
+	m := regexp.MustCompile(`^\[?((DF|[PMD]):)?(\d{1,3}):(\d{1,3}):(\d{1,3})]?$`).FindStringSubmatch(strings.TrimSpace(str))
 	if len(m) == 6 {
 		planetTypeStr := m[2]
 		galaxy := utils.DoParseI64(m[3])
@@ -23,7 +23,7 @@ func ParseCoord(str string) (coord Coordinate, err error) {
 		planetType := PlanetType
 		if planetTypeStr == "M" {
 			planetType = MoonType
-		} else if planetTypeStr == "D" {
+		} else if planetTypeStr == "D" || planetTypeStr == "DF" {
 			planetType = DebrisType
 		}
 		return Coordinate{galaxy, system, position, planetType}, nil
@@ -32,6 +32,42 @@ func TestParseCoord(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestParseCoordSpellings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Coordinate
+		valid    bool
+	}{
+		{"1:234:8", Coordinate{1, 234, 8, PlanetType}, true},
+		{"[1:234:8]", Coordinate{1, 234, 8, PlanetType}, true},
+		{" 1:234:8 ", Coordinate{1, 234, 8, PlanetType}, true},
+		{"P:1:234:8", Coordinate{1, 234, 8, PlanetType}, true},
+		{"[P:1:234:8]", Coordinate{1, 234, 8, PlanetType}, true},
+		{"M:1:234:8", Coordinate{1, 234, 8, MoonType}, true},
+		{"[M:1:234:8]", Coordinate{1, 234, 8, MoonType}, true},
+		{"D:1:234:8", Coordinate{1, 234, 8, DebrisType}, true},
+		{"DF:1:234:8", Coordinate{1, 234, 8, DebrisType}, true},
+		{"[DF:1:234:8]", Coordinate{1, 234, 8, DebrisType}, true},
+		{"", Coordinate{}, false},
+		{"1:234", Coordinate{}, false},
+		{"1:234:8:9", Coordinate{}, false},
+		{"X:1:234:8", Coordinate{}, false},
+		{"DM:1:234:8", Coordinate{}, false},
+		{"1-234-8", Coordinate{}, false},
+	}
+	for _, tt := range tests {
+		coord, err := ParseCoord(tt.input)
+		if !tt.valid {
+			assert.Error(t, err, tt.input)
+			continue
+		}
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, coord, tt.input)
+		roundTrip, _ := ParseCoord(coord.String())
+		assert.Equal(t, coord, roundTrip, tt.input)
+	}
+}
+
 func TestName2id(t *testing.T) {
 	assert.Equal(t, ID(0), DefenceName2ID("Not valid"))
 	assert.Equal(t, RocketLauncherID, DefenceName2ID("Rocket Launcher"))
@@ -0,0 +1,299 @@
+// Package ogametest provides a scriptable fake of the bot, to test strategies without network.
+package ogametest
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/wrapper"
+)
+
+// ErrNotScripted returned by the Fake methods that have no scripted response
+var ErrNotScripted = errors.New("ogametest: method not scripted")
+
+// Fake implements wrapper.FleetSender, wrapper.CelestialReader, wrapper.GalaxyScanner and wrapper.Messenger.
+// Each method calls the matching XxxFunc field if it is set, and returns zero values (and ErrNotScripted when the
+// method returns an error) otherwise. The name of every called method is recorded in Calls.
+type Fake struct {
+	mu    sync.Mutex
+	calls []string
+
+	// FleetSender
+	CancelFleetFunc func(ogame.FleetID) error
+	EnsureFleetFunc func(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+	FlightTimeFunc  func(origin, destination ogame.Coordinate, speed ogame.Speed, ships ogame.ShipsInfos, mission ogame.MissionID) (secs, fuel int64)
+	GetFleetsFunc   func(...wrapper.Option) ([]ogame.Fleet, ogame.Slots)
+	GetSlotsFunc    func() ogame.Slots
+	SendFleetFunc   func(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+
+	// CelestialReader
+	GetCelestialFunc          func(any) (wrapper.Celestial, error)
+	GetCelestialsFunc         func() ([]wrapper.Celestial, error)
+	GetDefenseFunc            func(ogame.CelestialID, ...wrapper.Option) (ogame.DefensesInfos, error)
+	GetFacilitiesFunc         func(ogame.CelestialID, ...wrapper.Option) (ogame.Facilities, error)
+	GetMoonsFunc              func() []wrapper.Moon
+	GetPlanetsFunc            func() []wrapper.Planet
+	GetResearchFunc           func() ogame.Researches
+	GetResourcesFunc          func(ogame.CelestialID) (ogame.Resources, error)
+	GetResourcesBuildingsFunc func(ogame.CelestialID, ...wrapper.Option) (ogame.ResourcesBuildings, error)
+	GetShipsFunc              func(ogame.CelestialID, ...wrapper.Option) (ogame.ShipsInfos, error)
+
+	// GalaxyScanner
+	FindColonizationSlotsFunc func(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error)
+	GalaxyInfosFunc           func(galaxy, system int64, opts ...wrapper.Option) (ogame.SystemInfos, error)
+	GetEspionageReportForFunc func(ogame.Coordinate) (ogame.EspionageReport, error)
+
+	// Messenger
+	DeleteAllMessagesFromTabFunc   func(tabID ogame.MessagesTabID) error
+	DeleteMessageFunc              func(msgID int64) error
+	GetEspionageReportMessagesFunc func() ([]ogame.EspionageReportSummary, error)
+	GetMessageSummariesFunc        func(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error)
+	SendMessageFunc                func(playerID int64, message string) error
+	SendMessageAllianceFunc        func(associationID int64, message string) error
+}
+
+var (
+	_ wrapper.FleetSender     = (*Fake)(nil)
+	_ wrapper.CelestialReader = (*Fake)(nil)
+	_ wrapper.GalaxyScanner   = (*Fake)(nil)
+	_ wrapper.Messenger       = (*Fake)(nil)
+)
+
+// Calls returns the names of the called methods, in order
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.calls...)
+}
+
+func (f *Fake) record(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, name)
+}
+
+// CancelFleet ...
+func (f *Fake) CancelFleet(fleetID ogame.FleetID) error {
+	f.record("CancelFleet")
+	if f.CancelFleetFunc == nil {
+		return ErrNotScripted
+	}
+	return f.CancelFleetFunc(fleetID)
+}
+
+// EnsureFleet ...
+func (f *Fake) EnsureFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
+	f.record("EnsureFleet")
+	if f.EnsureFleetFunc == nil {
+		return ogame.Fleet{}, ErrNotScripted
+	}
+	return f.EnsureFleetFunc(celestialID, ships, speed, where, mission, resources, holdingTime, unionID)
+}
+
+// FlightTime ...
+func (f *Fake) FlightTime(origin, destination ogame.Coordinate, speed ogame.Speed, ships ogame.ShipsInfos, mission ogame.MissionID) (secs, fuel int64) {
+	f.record("FlightTime")
+	if f.FlightTimeFunc == nil {
+		return 0, 0
+	}
+	return f.FlightTimeFunc(origin, destination, speed, ships, mission)
+}
+
+// GetFleets ...
+func (f *Fake) GetFleets(opts ...wrapper.Option) ([]ogame.Fleet, ogame.Slots) {
+	f.record("GetFleets")
+	if f.GetFleetsFunc == nil {
+		return []ogame.Fleet{}, ogame.Slots{}
+	}
+	return f.GetFleetsFunc(opts...)
+}
+
+// GetSlots ...
+func (f *Fake) GetSlots() ogame.Slots {
+	f.record("GetSlots")
+	if f.GetSlotsFunc == nil {
+		return ogame.Slots{}
+	}
+	return f.GetSlotsFunc()
+}
+
+// SendFleet ...
+func (f *Fake) SendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
+	f.record("SendFleet")
+	if f.SendFleetFunc == nil {
+		return ogame.Fleet{}, ErrNotScripted
+	}
+	return f.SendFleetFunc(celestialID, ships, speed, where, mission, resources, holdingTime, unionID)
+}
+
+// GetCelestial ...
+func (f *Fake) GetCelestial(v any) (wrapper.Celestial, error) {
+	f.record("GetCelestial")
+	if f.GetCelestialFunc == nil {
+		return nil, ErrNotScripted
+	}
+	return f.GetCelestialFunc(v)
+}
+
+// GetCelestials ...
+func (f *Fake) GetCelestials() ([]wrapper.Celestial, error) {
+	f.record("GetCelestials")
+	if f.GetCelestialsFunc == nil {
+		return []wrapper.Celestial{}, ErrNotScripted
+	}
+	return f.GetCelestialsFunc()
+}
+
+// GetDefense ...
+func (f *Fake) GetDefense(celestialID ogame.CelestialID, opts ...wrapper.Option) (ogame.DefensesInfos, error) {
+	f.record("GetDefense")
+	if f.GetDefenseFunc == nil {
+		return ogame.DefensesInfos{}, ErrNotScripted
+	}
+	return f.GetDefenseFunc(celestialID, opts...)
+}
+
+// GetFacilities ...
+func (f *Fake) GetFacilities(celestialID ogame.CelestialID, opts ...wrapper.Option) (ogame.Facilities, error) {
+	f.record("GetFacilities")
+	if f.GetFacilitiesFunc == nil {
+		return ogame.Facilities{}, ErrNotScripted
+	}
+	return f.GetFacilitiesFunc(celestialID, opts...)
+}
+
+// GetMoons ...
+func (f *Fake) GetMoons() []wrapper.Moon {
+	f.record("GetMoons")
+	if f.GetMoonsFunc == nil {
+		return []wrapper.Moon{}
+	}
+	return f.GetMoonsFunc()
+}
+
+// GetPlanets ...
+func (f *Fake) GetPlanets() []wrapper.Planet {
+	f.record("GetPlanets")
+	if f.GetPlanetsFunc == nil {
+		return []wrapper.Planet{}
+	}
+	return f.GetPlanetsFunc()
+}
+
+// GetResearch ...
+func (f *Fake) GetResearch() ogame.Researches {
+	f.record("GetResearch")
+	if f.GetResearchFunc == nil {
+		return ogame.Researches{}
+	}
+	return f.GetResearchFunc()
+}
+
+// GetResources ...
+func (f *Fake) GetResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
+	f.record("GetResources")
+	if f.GetResourcesFunc == nil {
+		return ogame.Resources{}, ErrNotScripted
+	}
+	return f.GetResourcesFunc(celestialID)
+}
+
+// GetResourcesBuildings ...
+func (f *Fake) GetResourcesBuildings(celestialID ogame.CelestialID, opts ...wrapper.Option) (ogame.ResourcesBuildings, error) {
+	f.record("GetResourcesBuildings")
+	if f.GetResourcesBuildingsFunc == nil {
+		return ogame.ResourcesBuildings{}, ErrNotScripted
+	}
+	return f.GetResourcesBuildingsFunc(celestialID, opts...)
+}
+
+// GetShips ...
+func (f *Fake) GetShips(celestialID ogame.CelestialID, opts ...wrapper.Option) (ogame.ShipsInfos, error) {
+	f.record("GetShips")
+	if f.GetShipsFunc == nil {
+		return ogame.ShipsInfos{}, ErrNotScripted
+	}
+	return f.GetShipsFunc(celestialID, opts...)
+}
+
+// FindColonizationSlots ...
+func (f *Fake) FindColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error) {
+	f.record("FindColonizationSlots")
+	if f.FindColonizationSlotsFunc == nil {
+		return []ogame.Coordinate{}, ErrNotScripted
+	}
+	return f.FindColonizationSlotsFunc(galaxy, fromSystem, toSystem, preferredPositions)
+}
+
+// GalaxyInfos ...
+func (f *Fake) GalaxyInfos(galaxy, system int64, opts ...wrapper.Option) (ogame.SystemInfos, error) {
+	f.record("GalaxyInfos")
+	if f.GalaxyInfosFunc == nil {
+		return ogame.SystemInfos{}, ErrNotScripted
+	}
+	return f.GalaxyInfosFunc(galaxy, system, opts...)
+}
+
+// GetEspionageReportFor ...
+func (f *Fake) GetEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageReport, error) {
+	f.record("GetEspionageReportFor")
+	if f.GetEspionageReportForFunc == nil {
+		return ogame.EspionageReport{}, ErrNotScripted
+	}
+	return f.GetEspionageReportForFunc(coord)
+}
+
+// DeleteAllMessagesFromTab ...
+func (f *Fake) DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error {
+	f.record("DeleteAllMessagesFromTab")
+	if f.DeleteAllMessagesFromTabFunc == nil {
+		return ErrNotScripted
+	}
+	return f.DeleteAllMessagesFromTabFunc(tabID)
+}
+
+// DeleteMessage ...
+func (f *Fake) DeleteMessage(msgID int64) error {
+	f.record("DeleteMessage")
+	if f.DeleteMessageFunc == nil {
+		return ErrNotScripted
+	}
+	return f.DeleteMessageFunc(msgID)
+}
+
+// GetEspionageReportMessages ...
+func (f *Fake) GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error) {
+	f.record("GetEspionageReportMessages")
+	if f.GetEspionageReportMessagesFunc == nil {
+		return []ogame.EspionageReportSummary{}, ErrNotScripted
+	}
+	return f.GetEspionageReportMessagesFunc()
+}
+
+// GetMessageSummaries ...
+func (f *Fake) GetMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error) {
+	f.record("GetMessageSummaries")
+	if f.GetMessageSummariesFunc == nil {
+		return []ogame.MessageSummary{}, ErrNotScripted
+	}
+	return f.GetMessageSummariesFunc(tabID)
+}
+
+// SendMessage ...
+func (f *Fake) SendMessage(playerID int64, message string) error {
+	f.record("SendMessage")
+	if f.SendMessageFunc == nil {
+		return ErrNotScripted
+	}
+	return f.SendMessageFunc(playerID, message)
+}
+
+// SendMessageAlliance ...
+func (f *Fake) SendMessageAlliance(associationID int64, message string) error {
+	f.record("SendMessageAlliance")
+	if f.SendMessageAllianceFunc == nil {
+		return ErrNotScripted
+	}
+	return f.SendMessageAllianceFunc(associationID, message)
+}
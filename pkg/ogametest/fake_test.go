@@ -0,0 +1,41 @@
+package ogametest
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/wrapper"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendToFirstPlanet example of a strategy depending only on the interfaces it needs
+func sendToFirstPlanet(reader wrapper.CelestialReader, sender wrapper.FleetSender, from ogame.CelestialID) (ogame.Fleet, error) {
+	planets := reader.GetPlanets()
+	ships, err := reader.GetShips(from)
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	return sender.SendFleet(from, ships.ToQuantifiables(), ogame.HundredPercent, planets[0].Coordinate, ogame.Transport, ogame.Resources{}, 0, 0)
+}
+
+func TestFake(t *testing.T) {
+	fake := &Fake{
+		GetPlanetsFunc: func() []wrapper.Planet {
+			return []wrapper.Planet{{Planet: ogame.Planet{Coordinate: ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}}}}
+		},
+		GetShipsFunc: func(ogame.CelestialID, ...wrapper.Option) (ogame.ShipsInfos, error) {
+			return ogame.ShipsInfos{SmallCargo: 5}, nil
+		},
+		SendFleetFunc: func(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
+			return ogame.Fleet{ID: 42, Destination: where}, nil
+		},
+	}
+	fleet, err := sendToFirstPlanet(fake, fake, 123)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.FleetID(42), fleet.ID)
+	assert.Equal(t, int64(3), fleet.Destination.Position)
+	assert.Equal(t, []string{"GetPlanets", "GetShips", "SendFleet"}, fake.Calls())
+
+	_, err = fake.GalaxyInfos(1, 1)
+	assert.Equal(t, ErrNotScripted, err)
+}
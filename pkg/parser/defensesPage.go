@@ -5,3 +5,13 @@ import "github.com/alaingilbert/ogame/pkg/ogame"
 func (p DefensesPage) ExtractDefense() (ogame.DefensesInfos, error) {
 	return p.e.ExtractDefenseFromDoc(p.GetDoc())
 }
+
+// ExtractDefenseWithWarnings same as ExtractDefense, with what could not be parsed on the page
+func (p DefensesPage) ExtractDefenseWithWarnings() (ogame.DefensesInfos, []ogame.ParseWarning, error) {
+	doc := p.GetDoc()
+	res, err := p.e.ExtractDefenseFromDoc(doc)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, p.e.ExtractDefenseWarningsFromDoc(doc), nil
+}
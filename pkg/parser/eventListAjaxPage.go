@@ -5,3 +5,11 @@ import "github.com/alaingilbert/ogame/pkg/ogame"
 func (p EventListAjaxPage) ExtractAttacks(ownCoords []ogame.Coordinate) ([]ogame.AttackEvent, error) {
 	return p.e.ExtractAttacksFromDoc(p.GetDoc(), ownCoords)
 }
+
+func (p EventListAjaxPage) ExtractEvents() ([]ogame.Event, error) {
+	return p.e.ExtractEventsFromDoc(p.GetDoc())
+}
+
+func (p EventListAjaxPage) ExtractUnionEvents() []ogame.ACSUnionDetails {
+	return p.e.ExtractUnionEventsFromDoc(p.GetDoc())
+}
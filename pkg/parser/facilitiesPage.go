@@ -5,3 +5,13 @@ import "github.com/alaingilbert/ogame/pkg/ogame"
 func (p FacilitiesPage) ExtractFacilities() (ogame.Facilities, error) {
 	return p.e.ExtractFacilitiesFromDoc(p.GetDoc())
 }
+
+// ExtractFacilitiesWithWarnings same as ExtractFacilities, with what could not be parsed on the page
+func (p FacilitiesPage) ExtractFacilitiesWithWarnings() (ogame.Facilities, []ogame.ParseWarning, error) {
+	doc := p.GetDoc()
+	res, err := p.e.ExtractFacilitiesFromDoc(doc)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, p.e.ExtractFacilitiesWarningsFromDoc(doc), nil
+}
@@ -18,6 +18,10 @@ func (p FullPage) ExtractAjaxChatToken() (string, error) {
 	return p.e.ExtractAjaxChatToken(p.content)
 }
 
+func (p FullPage) ExtractAllianceID() int64 {
+	return p.e.ExtractAllianceID(p.content)
+}
+
 func (p FullPage) ExtractCharacterClass() (ogame.CharacterClass, error) {
 	return p.e.ExtractCharacterClassFromDoc(p.GetDoc())
 }
@@ -50,6 +54,14 @@ func (p FullPage) ExtractServerTime() (time.Time, error) {
 	return p.e.ExtractServerTimeFromDoc(p.GetDoc())
 }
 
+func (p FullPage) ExtractNotifications() []ogame.Notification {
+	return p.e.ExtractNotificationsFromDoc(p.GetDoc())
+}
+
+func (p FullPage) ExtractUniverseSpeeds() ogame.EffectiveSpeeds {
+	return p.e.ExtractUniverseSpeedsFromDoc(p.GetDoc())
+}
+
 func (p FullPage) ExtractPlanets() []ogame.Planet {
 	return p.e.ExtractPlanetsFromDoc(p.GetDoc())
 }
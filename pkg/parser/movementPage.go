@@ -3,7 +3,9 @@ package parser
 import "github.com/alaingilbert/ogame/pkg/ogame"
 
 func (p MovementPage) ExtractFleets() []ogame.Fleet {
-	return p.e.ExtractFleetsFromDoc(p.GetDoc())
+	fleets := p.e.ExtractFleetsFromDoc(p.GetDoc())
+	ogame.SetPhalanxable(fleets)
+	return fleets
 }
 
 func (p MovementPage) ExtractSlots() ogame.Slots {
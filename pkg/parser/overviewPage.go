@@ -16,6 +16,10 @@ func (p OverviewPage) ExtractConstructions() (ogame.ID, int64, ogame.ID, int64,
 	return p.e.ExtractConstructions(p.content)
 }
 
+func (p OverviewPage) ExtractBuildQueue() []ogame.QueuedBuild {
+	return p.e.ExtractBuildQueue(p.content)
+}
+
 func (p OverviewPage) ExtractUserInfos() (ogame.UserInfos, error) {
 	return p.e.ExtractUserInfos(p.content)
 }
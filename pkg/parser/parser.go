@@ -87,6 +87,7 @@ type IFullPage interface {
 	ExtractIsInVacation() bool
 	ExtractPlanets() []ogame.Planet
 	ExtractAjaxChatToken() (string, error)
+	ExtractAllianceID() int64
 	ExtractCharacterClass() (ogame.CharacterClass, error)
 	ExtractCommander() bool
 	ExtractAdmiral() bool
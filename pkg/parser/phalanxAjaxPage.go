@@ -3,5 +3,7 @@ package parser
 import "github.com/alaingilbert/ogame/pkg/ogame"
 
 func (p PhalanxAjaxPage) ExtractPhalanx() ([]ogame.Fleet, error) {
-	return p.e.ExtractPhalanx(p.content)
+	fleets, err := p.e.ExtractPhalanx(p.content)
+	ogame.SetPhalanxable(fleets)
+	return fleets, err
 }
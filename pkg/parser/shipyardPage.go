@@ -9,3 +9,13 @@ func (p ShipyardPage) ExtractProduction() ([]ogame.Quantifiable, int64, error) {
 func (p ShipyardPage) ExtractShips() (ogame.ShipsInfos, error) {
 	return p.e.ExtractShipsFromDoc(p.GetDoc())
 }
+
+// ExtractShipsWithWarnings same as ExtractShips, with what could not be parsed on the page
+func (p ShipyardPage) ExtractShipsWithWarnings() (ogame.ShipsInfos, []ogame.ParseWarning, error) {
+	doc := p.GetDoc()
+	res, err := p.e.ExtractShipsFromDoc(doc)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, p.e.ExtractShipsWarningsFromDoc(doc), nil
+}
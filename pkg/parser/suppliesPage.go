@@ -5,3 +5,13 @@ import "github.com/alaingilbert/ogame/pkg/ogame"
 func (p SuppliesPage) ExtractResourcesBuildings() (ogame.ResourcesBuildings, error) {
 	return p.e.ExtractResourcesBuildingsFromDoc(p.GetDoc())
 }
+
+// ExtractResourcesBuildingsWithWarnings same as ExtractResourcesBuildings, with what could not be parsed on the page
+func (p SuppliesPage) ExtractResourcesBuildingsWithWarnings() (ogame.ResourcesBuildings, []ogame.ParseWarning, error) {
+	doc := p.GetDoc()
+	res, err := p.e.ExtractResourcesBuildingsFromDoc(doc)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, p.e.ExtractResourcesBuildingsWarningsFromDoc(doc), nil
+}
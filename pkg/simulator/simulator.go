@@ -546,6 +546,62 @@ func getUnitName(unitID uint64) string {
 	return ""
 }
 
+func getUnitOgameID(unitID uint64) ogame.ID {
+	switch unitID {
+	case smallCargoConst:
+		return ogame.SmallCargoID
+	case largeCargoConst:
+		return ogame.LargeCargoID
+	case lightFighterConst:
+		return ogame.LightFighterID
+	case heavyFighterConst:
+		return ogame.HeavyFighterID
+	case cruiserConst:
+		return ogame.CruiserID
+	case battleshipConst:
+		return ogame.BattleshipID
+	case colonyShipConst:
+		return ogame.ColonyShipID
+	case recyclerConst:
+		return ogame.RecyclerID
+	case espionageProbeConst:
+		return ogame.EspionageProbeID
+	case bomberConst:
+		return ogame.BomberID
+	case solarSatelliteConst:
+		return ogame.SolarSatelliteID
+	case destroyerConst:
+		return ogame.DestroyerID
+	case deathstarConst:
+		return ogame.DeathstarID
+	case battlecruiserConst:
+		return ogame.BattlecruiserID
+	case rocketLauncherConst:
+		return ogame.RocketLauncherID
+	case lightLaserConst:
+		return ogame.LightLaserID
+	case heavyLaserConst:
+		return ogame.HeavyLaserID
+	case gaussCannonConst:
+		return ogame.GaussCannonID
+	case ionCannonConst:
+		return ogame.IonCannonID
+	case plasmaTurretConst:
+		return ogame.PlasmaTurretID
+	case smallShieldDomeConst:
+		return ogame.SmallShieldDomeID
+	case largeShieldDomeConst:
+		return ogame.LargeShieldDomeID
+	case reaperConst:
+		return ogame.ReaperID
+	case pathfinderConst:
+		return ogame.PathfinderID
+	case crawlerConst:
+		return ogame.CrawlerID
+	}
+	return 0
+}
+
 func getUnitWeaponPower(unitID uint64, weaponTechno int) uint64 {
 	return uint64(float64(getUnitBaseWeapon(unitID)) * (1 + 0.1*float64(weaponTechno)))
 }
@@ -714,15 +770,17 @@ func newEntity() *entity {
 }
 
 type combatSimulator struct {
-	Attacker      entity
-	Defender      entity
-	MaxRounds     int
-	Rounds        int
-	FleetToDebris float64
-	Winner        string
-	IsLogging     bool
-	Logs          string
-	Debris        price
+	Attacker        entity
+	Defender        entity
+	MaxRounds       int
+	Rounds          int
+	FleetToDebris   float64
+	DefenceToDebris float64
+	Winner          string
+	IsLogging       bool
+	Logs            string
+	Debris          price
+	rng             *rand.Rand
 }
 
 func (simulator *combatSimulator) hasExploded(entity *entity, defendingUnit *CombatUnit) bool {
@@ -731,7 +789,7 @@ func (simulator *combatSimulator) hasExploded(entity *entity, defendingUnit *Com
 	hullPercentage := float64(getUnitHull(defendingUnit)) / float64(getUnitInitialHullPlating(entity.Armour, unitPrice.Metal, unitPrice.Crystal))
 	if hullPercentage <= 0.7 {
 		probabilityOfExploding := 1.0 - hullPercentage
-		dice := rand.Float64()
+		dice := simulator.rng.Float64()
 		msg := ""
 		if simulator.IsLogging {
 			msg += fmt.Sprintf("probability of exploding of %1.3f%%: dice value of %1.3f comparing with %1.3f: ", probabilityOfExploding*100, dice, 1-probabilityOfExploding)
@@ -759,7 +817,7 @@ func (simulator *combatSimulator) getAnotherShot(unit, targetUnit *CombatUnit) b
 	msg := ""
 	if rf > 0 {
 		chance := float64(rf-1) / float64(rf)
-		dice := rand.Float64()
+		dice := simulator.rng.Float64()
 		if simulator.IsLogging {
 			msg += fmt.Sprintf("dice was %1.3f, comparing with %1.3f: ", dice, chance)
 		}
@@ -826,7 +884,6 @@ func (simulator *combatSimulator) attack(attacker *entity, attackingUnit *Combat
 }
 
 func (simulator *combatSimulator) unitsFires(attacker, defender *entity) {
-	rand.Seed(time.Now().UnixNano())
 	for i := 0; i < attacker.TotalUnits; i++ {
 		unit := attacker.Units[i]
 		rapidFire := true
@@ -834,7 +891,7 @@ func (simulator *combatSimulator) unitsFires(attacker, defender *entity) {
 			if defender.TotalUnits == 0 {
 				break
 			}
-			targetUnit := &defender.Units[rand.Intn(defender.TotalUnits)]
+			targetUnit := &defender.Units[simulator.rng.Intn(defender.TotalUnits)]
 			rapidFire = simulator.getAnotherShot(&unit, targetUnit)
 			if isAlive(targetUnit) {
 				simulator.attack(attacker, &unit, defender, targetUnit)
@@ -900,10 +957,12 @@ func (simulator *combatSimulator) removeDestroyedUnits() {
 		unit := &simulator.Defender.Units[i]
 		if getUnitHull(unit) == 0 {
 			unitPrice := getUnitPrice(getUnitID(unit))
+			debrisFactor := simulator.DefenceToDebris
 			if isShip(unit) {
-				simulator.Debris.Metal += int(simulator.FleetToDebris * float64(unitPrice.Metal))
-				simulator.Debris.Crystal += int(simulator.FleetToDebris * float64(unitPrice.Crystal))
+				debrisFactor = simulator.FleetToDebris
 			}
+			simulator.Debris.Metal += int(debrisFactor * float64(unitPrice.Metal))
+			simulator.Debris.Crystal += int(debrisFactor * float64(unitPrice.Crystal))
 			simulator.Defender.Losses.add(unitPrice)
 			simulator.Defender.Units[i] = simulator.Defender.Units[simulator.Defender.TotalUnits-1]
 			simulator.Defender.TotalUnits--
@@ -918,10 +977,12 @@ func (simulator *combatSimulator) removeDestroyedUnits() {
 		unit := &simulator.Attacker.Units[i]
 		if getUnitHull(unit) == 0 {
 			unitPrice := getUnitPrice(getUnitID(unit))
+			debrisFactor := simulator.DefenceToDebris
 			if isShip(unit) {
-				simulator.Debris.Metal += int(simulator.FleetToDebris * float64(unitPrice.Metal))
-				simulator.Debris.Crystal += int(simulator.FleetToDebris * float64(unitPrice.Crystal))
+				debrisFactor = simulator.FleetToDebris
 			}
+			simulator.Debris.Metal += int(debrisFactor * float64(unitPrice.Metal))
+			simulator.Debris.Crystal += int(debrisFactor * float64(unitPrice.Crystal))
 			simulator.Attacker.Losses.add(unitPrice)
 			simulator.Attacker.Units[i] = simulator.Attacker.Units[simulator.Attacker.TotalUnits-1]
 			simulator.Attacker.TotalUnits--
@@ -955,8 +1016,7 @@ func (simulator *combatSimulator) isCombatDone() bool {
 }
 
 func (simulator *combatSimulator) getMoonchance() int {
-	debris := float64(simulator.Debris.Metal) + float64(simulator.Debris.Crystal)
-	return int(math.Min(debris/100000.0, 20.0))
+	return int(ogame.MoonChance(ogame.Resources{Metal: int64(simulator.Debris.Metal), Crystal: int64(simulator.Debris.Crystal)}))
 }
 
 func (simulator *combatSimulator) printWinner() {
@@ -1171,6 +1231,9 @@ func Simulate(attackerParam Attacker, defenderParam Defender, params SimulatorPa
 	attacker.Destroyer = int(attackerParam.Destroyer)
 	attacker.Deathstar = int(attackerParam.Deathstar)
 	attacker.Battlecruiser = int(attackerParam.Battlecruiser)
+	attacker.Reaper = int(attackerParam.Reaper)
+	attacker.Pathfinder = int(attackerParam.Pathfinder)
+	attacker.Crawler = int(attackerParam.Crawler)
 	attacker.RocketLauncher = 0
 	attacker.LightLaser = 0
 	attacker.HeavyLaser = 0
@@ -1200,6 +1263,9 @@ func Simulate(attackerParam Attacker, defenderParam Defender, params SimulatorPa
 	defender.Destroyer = int(defenderParam.Destroyer)
 	defender.Deathstar = int(defenderParam.Deathstar)
 	defender.Battlecruiser = int(defenderParam.Battlecruiser)
+	defender.Reaper = int(defenderParam.Reaper)
+	defender.Pathfinder = int(defenderParam.Pathfinder)
+	defender.Crawler = int(defenderParam.Crawler)
 	defender.RocketLauncher = int(defenderParam.RocketLauncher)
 	defender.LightLaser = int(defenderParam.LightLaser)
 	defender.HeavyLaser = int(defenderParam.HeavyLaser)
@@ -1212,8 +1278,16 @@ func Simulate(attackerParam Attacker, defenderParam Defender, params SimulatorPa
 	defender.Units = make([]CombatUnit, defender.TotalUnits+1)
 
 	cs := newCombatSimulator(attacker, defender)
-	cs.IsLogging = false
 	cs.FleetToDebris = params.FleetToDebris
+	cs.DefenceToDebris = params.DefenceToDebris
+	cs.IsLogging = params.IsLogging
+	seed := params.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	cs.rng = rand.New(rand.NewSource(seed))
+	attackerSurvivors := make(map[ogame.ID]int64)
+	defenderSurvivors := make(map[ogame.ID]int64)
 
 	for i := 0; i < nbSimulations; i++ {
 		cs.Rounds = 1
@@ -1232,6 +1306,12 @@ func Simulate(attackerParam Attacker, defenderParam Defender, params SimulatorPa
 		debris.add(cs.Debris)
 		rounds += cs.Rounds
 		moonchance += cs.getMoonchance()
+		for j := 0; j < cs.Attacker.TotalUnits; j++ {
+			attackerSurvivors[getUnitOgameID(getUnitID(&cs.Attacker.Units[j]))]++
+		}
+		for j := 0; j < cs.Defender.TotalUnits; j++ {
+			defenderSurvivors[getUnitOgameID(getUnitID(&cs.Defender.Units[j]))]++
+		}
 	}
 
 	result := SimulatorResult{}
@@ -1254,6 +1334,18 @@ func Simulate(attackerParam Attacker, defenderParam Defender, params SimulatorPa
 	result.Recycler = int(math.Ceil((float64(debris.Metal+debris.Crystal) / float64(nbSimulations)) / 20000.0))
 	result.Moonchance = int(float64(moonchance) / float64(nbSimulations))
 
+	for id, nbr := range attackerSurvivors {
+		result.AttackerSurvivors.Set(id, int64(math.Round(float64(nbr)/float64(nbSimulations))))
+	}
+	for id, nbr := range defenderSurvivors {
+		avg := int64(math.Round(float64(nbr) / float64(nbSimulations)))
+		if id.IsShip() {
+			result.DefenderShipsSurvivors.Set(id, avg)
+		} else {
+			result.DefenderDefensesSurvivors.Set(id, avg)
+		}
+	}
+
 	result.Logs = cs.Logs
 
 	return result
@@ -1281,8 +1373,11 @@ type Defender struct {
 
 // SimulatorParams ...
 type SimulatorParams struct {
-	Simulations   int
-	FleetToDebris float64
+	Simulations     int
+	FleetToDebris   float64
+	DefenceToDebris float64 // Part of the destroyed defences that goes to the debris field, 0 on most servers
+	Seed            int64   // Seeds the dice of the simulations so the result is reproducible, 0 picks a random seed
+	IsLogging       bool    // Logs every shot, rapid fire dice and explosion of every round, only sensible for a few simulations
 }
 
 // SimulatorResult ...
@@ -1298,6 +1393,10 @@ type SimulatorResult struct {
 	Recycler       int
 	Moonchance     int
 	Logs           string
+	// Units still alive at the end of the battle, averaged over the simulations
+	AttackerSurvivors         ogame.ShipsInfos
+	DefenderShipsSurvivors    ogame.ShipsInfos
+	DefenderDefensesSurvivors ogame.DefensesInfos
 }
 
 // String ...
@@ -0,0 +1,31 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate_seed(t *testing.T) {
+	attacker := Attacker{Weapon: 10, Shield: 10, Armour: 10, ShipsInfos: ogame.ShipsInfos{LightFighter: 200, Cruiser: 20}}
+	defender := Defender{Weapon: 8, Shield: 8, Armour: 8, DefensesInfos: ogame.DefensesInfos{RocketLauncher: 150, LightLaser: 50}}
+	params := SimulatorParams{Simulations: 5, FleetToDebris: 0.3, Seed: 42}
+	res1 := Simulate(attacker, defender, params)
+	res2 := Simulate(attacker, defender, params)
+	assert.Equal(t, res1, res2)
+}
+
+func TestSimulate_survivors(t *testing.T) {
+	attacker := Attacker{ShipsInfos: ogame.ShipsInfos{Reaper: 10, Pathfinder: 2}}
+	defender := Defender{DefensesInfos: ogame.DefensesInfos{RocketLauncher: 1}}
+	res := Simulate(attacker, defender, SimulatorParams{Simulations: 10, FleetToDebris: 0.3, Seed: 1})
+	assert.Equal(t, 100, res.AttackerWin)
+	assert.Equal(t, int64(10), res.AttackerSurvivors.Reaper)
+	assert.Equal(t, int64(2), res.AttackerSurvivors.Pathfinder)
+	assert.Equal(t, int64(0), res.DefenderDefensesSurvivors.RocketLauncher)
+	assert.Equal(t, 2000, res.DefenderLosses.Metal)
+
+	res = Simulate(Attacker{ShipsInfos: ogame.ShipsInfos{EspionageProbe: 1}}, Defender{ShipsInfos: ogame.ShipsInfos{SmallCargo: 3}}, SimulatorParams{Simulations: 1, Seed: 1})
+	assert.Equal(t, int64(3), res.DefenderShipsSurvivors.SmallCargo)
+}
@@ -3,6 +3,7 @@ package taskRunner
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 type Priority int64
@@ -46,10 +47,17 @@ type TaskRunner[T ITask] struct {
 	tasksLock   sync.Mutex
 	tasksPushCh chan *item
 	tasksPopCh  chan struct{}
+	pushedCh    chan struct{}
+	gate        Gate
+	gateLock    sync.Mutex
 	factory     func() T
 	ctx         context.Context
 }
 
+// Gate returns nil if a task of the given priority can be processed now, otherwise a channel
+// that fires when the gate must be checked again. The gate is also checked again when a task is queued.
+type Gate func(priority Priority) <-chan time.Time
+
 type ITask interface {
 	SetTaskDoneCh(ch chan struct{})
 }
@@ -61,6 +69,7 @@ func NewTaskRunner[T ITask](ctx context.Context, factory func() T) *TaskRunner[T
 	r.tasks = NewPriorityQueue[*item]()
 	r.tasksPushCh = make(chan *item, chanLen)
 	r.tasksPopCh = make(chan struct{}, chanLen)
+	r.pushedCh = make(chan struct{}, 1)
 	r.ctx = ctx
 	r.start()
 	return r
@@ -73,6 +82,10 @@ func (r *TaskRunner[T]) start() {
 			r.tasks.Push(t)
 			r.tasksLock.Unlock()
 			select {
+			case r.pushedCh <- struct{}{}:
+			default:
+			}
+			select {
 			case r.tasksPopCh <- struct{}{}:
 			case <-r.ctx.Done():
 				return
@@ -81,6 +94,9 @@ func (r *TaskRunner[T]) start() {
 	}()
 	go func() {
 		for range r.tasksPopCh {
+			if !r.waitGate() {
+				return
+			}
 			r.tasksLock.Lock()
 			task := r.tasks.Pop()
 			r.tasksLock.Unlock()
@@ -94,6 +110,38 @@ func (r *TaskRunner[T]) start() {
 	}()
 }
 
+// SetGate sets the gate that can hold the queued tasks, nil removes it
+func (r *TaskRunner[T]) SetGate(gate Gate) {
+	r.gateLock.Lock()
+	r.gate = gate
+	r.gateLock.Unlock()
+}
+
+// waitGate waits until the gate lets the highest priority task through, returns false if the runner is stopped
+func (r *TaskRunner[T]) waitGate() bool {
+	for {
+		r.gateLock.Lock()
+		gate := r.gate
+		r.gateLock.Unlock()
+		if gate == nil {
+			return true
+		}
+		r.tasksLock.Lock()
+		priority := r.tasks.Items()[0].priority
+		r.tasksLock.Unlock()
+		retryCh := gate(priority)
+		if retryCh == nil {
+			return true
+		}
+		select {
+		case <-retryCh:
+		case <-r.pushedCh: // A task with a higher priority might have been queued
+		case <-r.ctx.Done():
+			return false
+		}
+	}
+}
+
 func (r *TaskRunner[T]) WithPriority(priority Priority) T {
 	canBeProcessedCh := make(chan struct{})
 	taskIsDoneCh := make(chan struct{})
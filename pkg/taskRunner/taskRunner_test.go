@@ -1,8 +1,13 @@
 package taskRunner
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type testItem struct {
@@ -39,3 +44,94 @@ func (i *testItem) DoSomethingElse(name string) {
 //	go func() { time.Sleep(470 * time.Millisecond); tr.WithPriority(Important).DoSomething("F"); wg.Done() }()
 //	wg.Wait()
 //}
+
+type orderItem struct {
+	taskDoneCh chan struct{}
+	mu         *sync.Mutex
+	order      *[]string
+}
+
+func (i *orderItem) SetTaskDoneCh(ch chan struct{}) {
+	i.taskDoneCh = ch
+}
+
+func (i *orderItem) Do(name string) {
+	defer close(i.taskDoneCh)
+	i.mu.Lock()
+	*i.order = append(*i.order, name)
+	i.mu.Unlock()
+}
+
+func waitTasks(tr *TaskRunner[*orderItem], nbr int64) {
+	for tr.GetTasks().Total != nbr {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCriticalOvertakesLow(t *testing.T) {
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	factory := func() *orderItem { return &orderItem{mu: mu, order: &order} }
+	tr := NewTaskRunner[*orderItem](context.Background(), factory)
+
+	// Hold the runner busy while the other tasks are queued
+	blocking := tr.WithPriority(Normal)
+	wg := &sync.WaitGroup{}
+	for _, name := range []string{"scan1", "scan2", "scan3"} {
+		wg.Add(1)
+		go func(name string) { tr.WithPriority(Low).Do(name); wg.Done() }(name)
+	}
+	waitTasks(tr, 3)
+	wg.Add(1)
+	go func() { tr.WithPriority(Critical).Do("recall"); wg.Done() }()
+	waitTasks(tr, 4)
+	blocking.Do("blocking")
+	wg.Wait()
+
+	assert.Equal(t, 5, len(order))
+	assert.Equal(t, "blocking", order[0])
+	assert.Equal(t, "recall", order[1])
+}
+
+func TestGate(t *testing.T) {
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	factory := func() *orderItem { return &orderItem{mu: mu, order: &order} }
+	tr := NewTaskRunner[*orderItem](context.Background(), factory)
+
+	// Only critical tasks are let through until the gate opens
+	openCh := make(chan time.Time)
+	opened := make(chan struct{})
+	tr.SetGate(func(priority Priority) <-chan time.Time {
+		select {
+		case <-opened:
+			return nil
+		default:
+		}
+		if priority == Critical {
+			return nil
+		}
+		return openCh
+	})
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() { tr.WithPriority(Low).Do("scan"); wg.Done() }()
+	waitTasks(tr, 1)
+	go func() { tr.WithPriority(Critical).Do("recall"); wg.Done() }()
+	for {
+		mu.Lock()
+		nbr := len(order)
+		mu.Unlock()
+		if nbr == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int64(1), tr.GetTasks().Total)
+	assert.Equal(t, []string{"recall"}, order)
+
+	close(opened)
+	openCh <- time.Now()
+	wg.Wait()
+	assert.Equal(t, []string{"recall", "scan"}, order)
+}
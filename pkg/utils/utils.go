@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"github.com/PuerkitoBio/goquery"
 	"io"
 	"io/ioutil"
@@ -113,14 +114,39 @@ func GetNbrShips(doc *goquery.Document, name string) int64 {
 	return ParseInt(m[1])
 }
 
+// ErrResponseTooLarge returned when a response body is bigger than the allowed maximum
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ReadAllLimit reads r until EOF, and returns ErrResponseTooLarge if it has more than maxBytes.
+// maxBytes <= 0 means no limit.
+func ReadAllLimit(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	by, err := ioutil.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return by, err
+	}
+	if int64(len(by)) > maxBytes {
+		return []byte{}, ErrResponseTooLarge
+	}
+	return by, nil
+}
+
 func ReadBody(resp *http.Response) (respContent []byte, err error) {
+	return ReadBodyLimit(resp, 0)
+}
+
+// ReadBodyLimit same as ReadBody, but returns ErrResponseTooLarge if the (decompressed) body has more than maxBytes
+func ReadBodyLimit(resp *http.Response, maxBytes int64) (respContent []byte, err error) {
 	var reader io.ReadCloser
 	switch resp.Header.Get("Content-Encoding") {
 	case "gzip":
-		buf := new(bytes.Buffer)
-		_, _ = buf.ReadFrom(resp.Body)
-		var err error
-		reader, err = gzip.NewReader(buf)
+		compressed, err := ReadAllLimit(resp.Body, maxBytes)
+		if err != nil {
+			return []byte{}, err
+		}
+		reader, err = gzip.NewReader(bytes.NewReader(compressed))
 		if err != nil {
 			return []byte{}, err
 		}
@@ -128,7 +154,7 @@ func ReadBody(resp *http.Response) (respContent []byte, err error) {
 	default:
 		reader = resp.Body
 	}
-	by, err := ioutil.ReadAll(reader)
+	by, err := ReadAllLimit(reader, maxBytes)
 	if err != nil {
 		return []byte{}, err
 	}
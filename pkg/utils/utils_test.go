@@ -2,10 +2,14 @@ package utils
 
 import (
 	"bytes"
+	"compress/gzip"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -41,3 +45,32 @@ func TestI64Ptr(t *testing.T) {
 	v := int64(6)
 	assert.Equal(t, &v, I64Ptr(6))
 }
+
+func TestReadAllLimit(t *testing.T) {
+	by, err := ReadAllLimit(strings.NewReader("hello"), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(by))
+	_, err = ReadAllLimit(strings.NewReader("hello!"), 5)
+	assert.Equal(t, ErrResponseTooLarge, err)
+	by, err = ReadAllLimit(strings.NewReader("hello!"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello!", string(by))
+}
+
+func TestReadBodyLimit_gzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write(bytes.Repeat([]byte("a"), 1000))
+	_ = w.Close()
+	newResp := func() *http.Response {
+		resp := &http.Response{Header: make(http.Header), Body: io.NopCloser(bytes.NewReader(buf.Bytes()))}
+		resp.Header.Set("Content-Encoding", "gzip")
+		return resp
+	}
+	by, err := ReadBodyLimit(newResp(), 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, len(by))
+	// The compressed body fits, but not the decompressed one
+	_, err = ReadBodyLimit(newResp(), 999)
+	assert.Equal(t, ErrResponseTooLarge, err)
+}
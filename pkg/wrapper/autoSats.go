@@ -0,0 +1,201 @@
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// AutoSatsPriority priority of the tasks enqueued by the solar satellites builder
+const AutoSatsPriority = taskRunner.Low
+
+// DefaultAutoSatsMargin energy balance the solar satellites builder restores by default
+const DefaultAutoSatsMargin = 10
+
+const defaultAutoSatsInterval = 30 * time.Minute
+
+// AutoSatsPolicy settings of the solar satellites builder.
+// When enabled, the bot periodically checks the energy balance of every planet, and when it is negative,
+// builds enough solar satellites to bring it back to Margin.
+type AutoSatsPolicy struct {
+	Enabled         bool
+	IntervalSeconds int64            // Delay between two runs, default 30 minutes
+	PlanetIDs       []ogame.PlanetID // Planets to watch, all planets if empty
+	Margin          int64            // Energy balance to restore, DefaultAutoSatsMargin if 0
+	MaxCost         int64            // Maximum resources (total) spent on a planet per run, unlimited if 0
+}
+
+// AutoSatsPlanetStatus result of the last solar satellites builder run on a planet
+type AutoSatsPlanetStatus struct {
+	PlanetID       ogame.PlanetID
+	Energy         int64 // Energy balance before the run
+	EnergyPerSat   int64
+	Queued         int64 // Solar satellites in the shipyard queue
+	Needed         int64 // Solar satellites missing to restore the margin
+	Built          int64 // Solar satellites queued by the last run
+	CostCapReached bool  // Less satellites than needed were built because of MaxCost
+	Error          string
+	UpdatedAt      time.Time
+}
+
+// AutoSatsStatus status of the solar satellites builder
+type AutoSatsStatus struct {
+	Policy  AutoSatsPolicy
+	LastRun time.Time
+	Planets []AutoSatsPlanetStatus
+}
+
+// satellitesNeeded returns the number of solar satellites to build to bring the energy balance up to margin.
+// queued satellites are already counted as producing.
+func satellitesNeeded(energy, energyPerSat, queued, margin int64) int64 {
+	if energyPerSat <= 0 {
+		return 0
+	}
+	missing := margin - energy - queued*energyPerSat
+	if energy >= 0 || missing <= 0 {
+		return 0
+	}
+	return (missing + energyPerSat - 1) / energyPerSat
+}
+
+// capSatellites returns how many of the needed satellites can be built within maxCost (unlimited if 0)
+func capSatellites(needed, maxCost int64) int64 {
+	if maxCost <= 0 {
+		return needed
+	}
+	return utils.MinInt(needed, maxCost/ogame.SolarSatellite.GetPrice(1).Total())
+}
+
+// SetAutoSatsPolicy sets the solar satellites builder policy, and starts/stops the builder accordingly.
+// The builder is bound to the bot context, it stops when the bot is disabled and restarts when it is enabled.
+func (b *OGame) SetAutoSatsPolicy(policy AutoSatsPolicy) {
+	if policy.Margin <= 0 {
+		policy.Margin = DefaultAutoSatsMargin
+	}
+	b.autoSatsMu.Lock()
+	defer b.autoSatsMu.Unlock()
+	if b.autoSatsCancel != nil {
+		b.autoSatsCancel()
+		b.autoSatsCancel = nil
+	}
+	b.autoSatsStatus = AutoSatsStatus{Policy: policy}
+	if !policy.Enabled {
+		return
+	}
+	b.startAutoSats(policy)
+}
+
+// startAutoSats starts the builder loop, autoSatsMu must be held
+func (b *OGame) startAutoSats(policy AutoSatsPolicy) {
+	ctx, cancel := context.WithCancel(b.getContext())
+	b.autoSatsCancel = cancel
+	go b.autoSatsLoop(ctx, policy)
+}
+
+// restartAutoSats restarts the builder, if it is running, on the current bot context
+func (b *OGame) restartAutoSats() {
+	b.autoSatsMu.Lock()
+	defer b.autoSatsMu.Unlock()
+	if b.autoSatsCancel != nil {
+		b.autoSatsCancel()
+		b.startAutoSats(b.autoSatsStatus.Policy)
+	}
+}
+
+// GetAutoSatsStatus gets the solar satellites builder policy and the result of its last run
+func (b *OGame) GetAutoSatsStatus() AutoSatsStatus {
+	b.autoSatsMu.Lock()
+	defer b.autoSatsMu.Unlock()
+	status := b.autoSatsStatus
+	status.Planets = append([]AutoSatsPlanetStatus{}, status.Planets...)
+	return status
+}
+
+func (b *OGame) autoSatsLoop(ctx context.Context, policy AutoSatsPolicy) {
+	interval := defaultAutoSatsInterval
+	if policy.IntervalSeconds > 0 {
+		interval = time.Duration(policy.IntervalSeconds) * time.Second
+	}
+	for {
+		if b.isEnabled() && b.IsLoggedIn() && !b.IsInMaintenance() {
+			b.runAutoSats(ctx, policy)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(interval):
+		}
+	}
+}
+
+func (b *OGame) runAutoSats(ctx context.Context, policy AutoSatsPolicy) {
+	planetIDs := policy.PlanetIDs
+	if len(planetIDs) == 0 {
+		for _, p := range b.GetCachedPlanets() {
+			planetIDs = append(planetIDs, p.ID)
+		}
+	}
+	statuses := make([]AutoSatsPlanetStatus, 0, len(planetIDs))
+	for _, planetID := range planetIDs {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		status, err := b.fixEnergyDeficit(planetID, policy)
+		if err != nil {
+			status.Error = err.Error()
+			b.error("auto sats", planetID, err)
+		}
+		statuses = append(statuses, status)
+	}
+	b.autoSatsMu.Lock()
+	defer b.autoSatsMu.Unlock()
+	b.autoSatsStatus.LastRun = b.clock.Now()
+	b.autoSatsStatus.Planets = statuses
+}
+
+// fixEnergyDeficit builds the solar satellites missing to restore the energy balance of a planet,
+// every step is a separate task
+func (b *OGame) fixEnergyDeficit(planetID ogame.PlanetID, policy AutoSatsPolicy) (AutoSatsPlanetStatus, error) {
+	status := AutoSatsPlanetStatus{PlanetID: planetID, UpdatedAt: b.clock.Now()}
+	celestialID := planetID.Celestial()
+	details, err := b.WithPriority(AutoSatsPriority).GetResourcesDetails(celestialID)
+	if err != nil {
+		return status, err
+	}
+	status.Energy = details.Energy.Available
+	if status.Energy >= 0 {
+		return status, nil
+	}
+	energyPerSat, _, err := b.WithPriority(AutoSatsPriority).GetTemperatureEffects(celestialID)
+	if err != nil {
+		return status, err
+	}
+	status.EnergyPerSat = energyPerSat
+	production, _, err := b.WithPriority(AutoSatsPriority).GetProduction(celestialID)
+	if err != nil {
+		return status, err
+	}
+	for _, q := range production {
+		if q.ID == ogame.SolarSatelliteID {
+			status.Queued += q.Nbr
+		}
+	}
+	status.Needed = satellitesNeeded(status.Energy, energyPerSat, status.Queued, policy.Margin)
+	nbr := capSatellites(status.Needed, policy.MaxCost)
+	status.CostCapReached = nbr < status.Needed
+	nbr = utils.MinInt(nbr, details.Available().Div(ogame.SolarSatellite.GetPrice(1)))
+	if nbr <= 0 {
+		return status, nil
+	}
+	if err := b.WithPriority(AutoSatsPriority).BuildShips(celestialID, ogame.SolarSatelliteID, nbr); err != nil {
+		return status, err
+	}
+	status.Built = nbr
+	b.info("auto sats", planetID, "build", nbr, "solar satellites, energy", status.Energy)
+	return status, nil
+}
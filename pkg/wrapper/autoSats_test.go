@@ -0,0 +1,129 @@
+package wrapper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/extractor/v7"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatellitesNeeded(t *testing.T) {
+	assert.Equal(t, int64(0), satellitesNeeded(5, 30, 0, 10))
+	assert.Equal(t, int64(0), satellitesNeeded(0, 30, 0, 10))
+	assert.Equal(t, int64(4), satellitesNeeded(-100, 30, 0, 10)) // 110 / 30 rounded up
+	assert.Equal(t, int64(1), satellitesNeeded(-100, 30, 3, 10)) // 3 queued produce 90
+	assert.Equal(t, int64(0), satellitesNeeded(-100, 30, 4, 10))
+	assert.Equal(t, int64(0), satellitesNeeded(-100, 0, 0, 10))
+}
+
+func TestCapSatellites(t *testing.T) {
+	assert.Equal(t, int64(40), capSatellites(40, 0))
+	assert.Equal(t, int64(10), capSatellites(40, 25_000)) // 2500 per satellite
+	assert.Equal(t, int64(5), capSatellites(5, 25_000))
+}
+
+func TestAutoSatsLoop_interval(t *testing.T) {
+	b, clock := newLoopTestBot()
+	b.SetAutoSatsPolicy(AutoSatsPolicy{Enabled: true, IntervalSeconds: 60})
+	defer b.SetAutoSatsPolicy(AutoSatsPolicy{})
+	assertRunsEveryMinute(t, b, clock, func() time.Time { return b.GetAutoSatsStatus().LastRun })
+}
+
+// autoSatsTestExtractor returns the energy, temperature and shipyard queue set by the test instead of parsing the pages
+type autoSatsTestExtractor struct {
+	*v7.Extractor
+	energy     int64
+	production []ogame.Quantifiable
+}
+
+func (e *autoSatsTestExtractor) ExtractResourcesDetails([]byte) (out ogame.ResourcesDetails, err error) {
+	out.Crystal.Available = 100_000
+	out.Deuterium.Available = 100_000
+	out.Energy.Available = e.energy
+	return out, nil
+}
+
+func (e *autoSatsTestExtractor) ExtractPlanetFromDoc(*goquery.Document, any) (ogame.Planet, error) {
+	return ogame.Planet{ID: 1, Temperature: ogame.Temperature{Min: 20, Max: 60}}, nil // 33 energy per satellite
+}
+
+func (e *autoSatsTestExtractor) ExtractProduction([]byte) ([]ogame.Quantifiable, int64, error) {
+	return e.production, 0, nil
+}
+
+func (e *autoSatsTestExtractor) ExtractUpgradeToken([]byte) (string, error) {
+	return "token", nil
+}
+
+func TestFixEnergyDeficit(t *testing.T) {
+	var mu sync.Mutex
+	var built []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("modus") == "1" && q.Get("type") == "212" {
+			mu.Lock()
+			built = append(built, q.Get("menge"))
+			mu.Unlock()
+		}
+		_, _ = w.Write([]byte(`<meta name="ogame-session" content="session"/><script>currentPage = "` + q.Get("component") + `";</script>`))
+	}))
+	defer srv.Close()
+	getBuilt := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := built
+		built = nil
+		return out
+	}
+	ext := &autoSatsTestExtractor{Extractor: v7.NewExtractor()}
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clockwork.NewFakeClock(), quiet: true,
+		serverURL: srv.URL, extractor: ext}
+	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), func() *Prioritize { return &Prioritize{bot: b} })
+	b.isEnabledAtom = 1
+	b.isLoggedInAtom = 1
+	policy := AutoSatsPolicy{Margin: 10}
+
+	// No deficit, nothing is built
+	ext.energy = 5
+	status, err := b.fixEnergyDeficit(ogame.PlanetID(1), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), status.Built)
+	assert.Empty(t, getBuilt())
+
+	// 110 energy missing, 4 satellites of 33 energy
+	ext.energy = -100
+	status, err = b.fixEnergyDeficit(ogame.PlanetID(1), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(33), status.EnergyPerSat)
+	assert.Equal(t, int64(4), status.Needed)
+	assert.Equal(t, int64(4), status.Built)
+	assert.Equal(t, []string{"4"}, getBuilt())
+
+	// The satellites already queued produce 99 energy
+	ext.production = []ogame.Quantifiable{{ID: ogame.SolarSatelliteID, Nbr: 3}, {ID: ogame.SmallCargoID, Nbr: 5}}
+	status, err = b.fixEnergyDeficit(ogame.PlanetID(1), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), status.Queued)
+	assert.Equal(t, int64(1), status.Built)
+	assert.Equal(t, []string{"1"}, getBuilt())
+
+	// 2500 per satellite, only 2 satellites within the cost cap
+	ext.production = nil
+	policy.MaxCost = 5000
+	status, err = b.fixEnergyDeficit(ogame.PlanetID(1), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), status.Needed)
+	assert.Equal(t, int64(2), status.Built)
+	assert.True(t, status.CostCapReached)
+	assert.Equal(t, []string{"2"}, getBuilt())
+}
@@ -0,0 +1,64 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/parser"
+)
+
+// bashingAttack attack launched by the bot, counted toward the bashing limit of its target
+type bashingAttack struct {
+	FleetID    ogame.FleetID
+	Target     ogame.Coordinate
+	LaunchedAt time.Time
+}
+
+// recordAttack keeps track of an attack sent by the bot, so the bashing limit can be computed
+// once the fleet is no longer listed in the fleet movement
+func (b *OGame) recordAttack(fleetID ogame.FleetID, target ogame.Coordinate, mission ogame.MissionID) {
+	if !ogame.IsBashingMission(mission) {
+		return
+	}
+	b.bashingMu.Lock()
+	defer b.bashingMu.Unlock()
+	now := b.clock.Now()
+	attacks := make([]bashingAttack, 0, len(b.bashingAttacks)+1)
+	for _, attack := range b.bashingAttacks {
+		if now.Sub(attack.LaunchedAt) < ogame.BashingWindow {
+			attacks = append(attacks, attack)
+		}
+	}
+	b.bashingAttacks = append(attacks, bashingAttack{FleetID: fleetID, Target: target, LaunchedAt: now})
+}
+
+func (b *OGame) attacksRemainingAgainst(target ogame.Coordinate) (int64, time.Time, error) {
+	if target.Type == 0 {
+		target.Type = ogame.PlanetType
+	}
+	page, err := getPage[parser.MovementPage](b)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	launches := make([]time.Time, 0)
+	known := make(map[ogame.FleetID]struct{})
+	b.bashingMu.Lock()
+	for _, attack := range b.bashingAttacks {
+		if attack.Target.Equal(target) {
+			launches = append(launches, attack.LaunchedAt)
+			known[attack.FleetID] = struct{}{}
+		}
+	}
+	b.bashingMu.Unlock()
+	// Attacks that were not sent by the bot, or sent before it started, are still visible while in flight
+	for _, fleet := range page.ExtractFleets() {
+		if _, ok := known[fleet.ID]; ok {
+			continue
+		}
+		if ogame.IsBashingMission(fleet.Mission) && fleet.Destination.Equal(target) {
+			launches = append(launches, fleet.StartTime)
+		}
+	}
+	remaining, resetAt := ogame.AttacksRemaining(launches, b.clock.Now())
+	return remaining, resetAt, nil
+}
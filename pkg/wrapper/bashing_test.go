@@ -0,0 +1,26 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAttack(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock}
+	target := ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}
+	b.recordAttack(1, target, ogame.Transport)
+	assert.Len(t, b.bashingAttacks, 0)
+	b.recordAttack(2, target, ogame.Attack)
+	assert.Len(t, b.bashingAttacks, 1)
+	assert.Equal(t, clock.Now(), b.bashingAttacks[0].LaunchedAt)
+
+	// Attacks older than the bashing window are forgotten
+	clock.Advance(ogame.BashingWindow)
+	b.recordAttack(3, target, ogame.Attack)
+	assert.Len(t, b.bashingAttacks, 1)
+	assert.Equal(t, ogame.FleetID(3), b.bashingAttacks[0].FleetID)
+}
@@ -0,0 +1,116 @@
+package wrapper
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// BotInfo runtime information about the bot.
+// It is built from atomics and small feature locks only, so it never waits on the task runner
+// and never triggers a game request.
+type BotInfo struct {
+	StartedAt       time.Time
+	Uptime          int64 // seconds since the bot was created
+	Enabled         bool
+	LoggedIn        bool
+	Connected       bool
+	Locked          bool
+	State           string    // name of the function currently locking the bot
+	Throttled       bool      // task queue paused because the game server throttled the bot
+	ThrottleRetryAt time.Time // time at which the task queue resumes, zero if not throttled
+	LastRequestAt   time.Time // last successful game request, zero if none
+	RequestsCount   int64     // game requests sent this session
+	LoginCount      int64     // successful logins this session
+	LogoutsDetected int64     // responses that triggered a relogin
+	FalseLogouts    int64     // unexpected responses that were not logged out pages, no relogin was done
+	ParseWarnings   int64     // things the extractors could not make sense of, see ogame.ParseWarning
+	Watchers        []string  // background watchers currently running
+	Universe        string
+	Language        string
+}
+
+// countRequest updates the request counters exposed by Info
+func (b *OGame) countRequest(info RequestInfo) {
+	atomic.AddInt64(&b.requestsCountAtom, 1)
+	if info.Err == nil && info.StatusCode < 400 {
+		atomic.StoreInt64(&b.lastRequestAtom, b.clock.Now().UnixNano())
+	}
+}
+
+// GetLastActivity returns when the account last performed an action visible to other players.
+// The game marks a planet as active when one of its full pages is loaded, ajax requests do not count,
+// so this is the time of the last full page the bot loaded.
+func (b *OGame) GetLastActivity() (time.Time, error) {
+	lastActivity := atomic.LoadInt64(&b.lastActivityAtom)
+	if lastActivity == 0 {
+		return time.Time{}, ogame.ErrNoActivity
+	}
+	return time.Unix(0, lastActivity), nil
+}
+
+// Info returns runtime information about the bot, safe to call while the bot is busy
+func (b *OGame) Info() BotInfo {
+	locked, state := b.GetState()
+	info := BotInfo{
+		StartedAt:       b.startedAt,
+		Uptime:          int64(b.clock.Since(b.startedAt).Seconds()),
+		Enabled:         b.IsEnabled(),
+		LoggedIn:        b.IsLoggedIn(),
+		Connected:       b.IsConnected(),
+		Locked:          locked,
+		State:           state,
+		RequestsCount:   atomic.LoadInt64(&b.requestsCountAtom),
+		LoginCount:      atomic.LoadInt64(&b.loginCountAtom),
+		LogoutsDetected: atomic.LoadInt64(&b.logoutsDetectedAtom),
+		FalseLogouts:    atomic.LoadInt64(&b.falseLogoutsAtom),
+		ParseWarnings:   atomic.LoadInt64(&b.parseWarningsAtom),
+		Throttled:       b.IsThrottled(),
+		Watchers:        b.activeWatchers(),
+		Universe:        b.Universe,
+		Language:        b.language,
+	}
+	if info.Throttled {
+		info.ThrottleRetryAt = b.GetThrottleState().RetryAt
+	}
+	if lastRequest := atomic.LoadInt64(&b.lastRequestAtom); lastRequest > 0 {
+		info.LastRequestAt = time.Unix(0, lastRequest)
+	}
+	return info
+}
+
+func (b *OGame) activeWatchers() []string {
+	watchers := make([]string, 0)
+	b.crawlerPolicyMu.Lock()
+	if b.crawlerPolicyCancel != nil {
+		watchers = append(watchers, "crawler-policy")
+	}
+	b.crawlerPolicyMu.Unlock()
+	b.autoSatsMu.Lock()
+	if b.autoSatsCancel != nil {
+		watchers = append(watchers, "auto-sats")
+	}
+	b.autoSatsMu.Unlock()
+	b.exposureAlertMu.Lock()
+	if b.exposureAlertCancel != nil {
+		watchers = append(watchers, "exposure-alert")
+	}
+	b.exposureAlertMu.Unlock()
+	b.storageWebhookMu.Lock()
+	if b.storageWebhookCancel != nil {
+		watchers = append(watchers, "storage-webhook")
+	}
+	b.storageWebhookMu.Unlock()
+	b.recallJobsMu.Lock()
+	if len(b.recallJobs) > 0 {
+		watchers = append(watchers, "recall-jobs")
+	}
+	b.recallJobsMu.Unlock()
+	b.eventSubscribersMu.Lock()
+	if len(b.eventSubscribers) > 0 {
+		watchers = append(watchers, "event-stream")
+	}
+	b.eventSubscribersMu.Unlock()
+	return watchers
+}
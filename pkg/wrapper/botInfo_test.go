@@ -0,0 +1,42 @@
+package wrapper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfo(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock, startedAt: clock.Now(), Universe: "Bellatrix", language: "en"}
+	clock.Advance(90 * time.Second)
+	b.countRequest(RequestInfo{StatusCode: 200})
+	lastRequest := clock.Now()
+	clock.Advance(time.Minute)
+	b.countRequest(RequestInfo{Err: errors.New("timeout")})
+	b.countRequest(RequestInfo{StatusCode: 502})
+	b.recallJobs = map[int64]*RecallJob{1: {ID: 1, FleetID: ogame.FleetID(1)}}
+
+	info := b.Info()
+	assert.Equal(t, int64(150), info.Uptime)
+	assert.Equal(t, int64(3), info.RequestsCount)
+	assert.True(t, info.LastRequestAt.Equal(lastRequest))
+	assert.Equal(t, "Bellatrix", info.Universe)
+	assert.Equal(t, []string{"recall-jobs"}, info.Watchers)
+}
+
+func TestGetLastActivity(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock}
+	_, err := b.GetLastActivity()
+	assert.Equal(t, ogame.ErrNoActivity, err)
+
+	// Ajax pages do not count as an activity
+	assert.NoError(t, processResponseHTML("GET", b, []byte(`{}`), "fetchEventbox", nil, map[string][]string{"page": {"fetchEventbox"}, "ajax": {"1"}}))
+	_, err = b.GetLastActivity()
+	assert.Equal(t, ogame.ErrNoActivity, err)
+}
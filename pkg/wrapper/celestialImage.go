@@ -0,0 +1,65 @@
+package wrapper
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+type celestialImage struct {
+	data        []byte
+	contentType string
+}
+
+// GetCelestialImage returns the image of a cached celestial and its content type.
+// Image urls are content addressed by the game CDN, so images are kept in memory once downloaded.
+func (b *OGame) GetCelestialImage(celestialID ogame.CelestialID) ([]byte, string, error) {
+	celestial := b.GetCachedCelestialByID(celestialID)
+	if celestial == nil {
+		return nil, "", ogame.ErrInvalidPlanetID
+	}
+	imgURL := celestial.GetImg()
+	if imgURL == "" {
+		return nil, "", errors.New("celestial has no image")
+	}
+	if strings.HasPrefix(imgURL, "/") {
+		imgURL = b.serverURL + imgURL
+	}
+
+	b.celestialImagesMu.Lock()
+	img, ok := b.celestialImages[imgURL]
+	b.celestialImagesMu.Unlock()
+	if ok {
+		return img.data, img.contentType, nil
+	}
+
+	req, err := http.NewRequestWithContext(b.ctx, http.MethodGet, imgURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.New("failed to fetch celestial image: " + resp.Status)
+	}
+	data, err := utils.ReadBody(resp)
+	if err != nil {
+		return nil, "", err
+	}
+	img = celestialImage{data: data, contentType: http.DetectContentType(data)}
+
+	b.celestialImagesMu.Lock()
+	if b.celestialImages == nil {
+		b.celestialImages = make(map[string]celestialImage)
+	}
+	b.celestialImages[imgURL] = img
+	b.celestialImagesMu.Unlock()
+	return img.data, img.contentType, nil
+}
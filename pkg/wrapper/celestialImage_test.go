@@ -0,0 +1,42 @@
+package wrapper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+type imageRoundTripper struct {
+	calls int
+}
+
+func (rt *imageRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.calls++
+	body := io.NopCloser(strings.NewReader("\x89PNG\r\n\x1a\nplanet"))
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: make(http.Header), Body: body, Request: req}, nil
+}
+
+func TestGetCelestialImage(t *testing.T) {
+	rt := &imageRoundTripper{}
+	client := httpclient.NewClient()
+	client.Transport = rt
+	bot := &OGame{client: client, ctx: context.Background()}
+	bot.planets = []Planet{{Planet: ogame.Planet{ID: 123, Img: "https://gf2.geo.gfsrv.net/cdna7/175ddb94f60c239680a8ddadc22516.png"}}}
+
+	img, contentType, err := bot.GetCelestialImage(123)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "\x89PNG\r\n\x1a\nplanet", string(img))
+	_, _, err = bot.GetCelestialImage(123)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rt.calls)
+
+	_, _, err = bot.GetCelestialImage(456)
+	assert.ErrorIs(t, err, ogame.ErrInvalidPlanetID)
+}
@@ -0,0 +1,194 @@
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// CrawlerPolicyPriority priority of the tasks enqueued by the crawler balancer
+const CrawlerPolicyPriority = taskRunner.Low
+
+const defaultCrawlerPolicyInterval = 30 * time.Minute
+
+// CrawlerPolicy settings of the crawler balancer.
+// When enabled, the bot periodically builds the missing crawlers of every planet (8 per mine level)
+// and lowers the crawler utilization when the planet has an energy deficit.
+type CrawlerPolicy struct {
+	Enabled         bool
+	IntervalSeconds int64            // Delay between two runs, default 30 minutes
+	PlanetIDs       []ogame.PlanetID // Planets to balance, all planets if empty
+	BuildCrawlers   bool             // Build the missing crawlers when resources allow
+	AdjustSettings  bool             // Adjust the crawler utilization to avoid energy deficits
+}
+
+// CrawlerPlanetStatus result of the last crawler balancer run on a planet
+type CrawlerPlanetStatus struct {
+	PlanetID    ogame.PlanetID
+	Crawlers    int64
+	MaxCrawlers int64
+	Queued      int64 // Crawlers in the shipyard queue
+	Built       int64 // Crawlers queued by the last run
+	Utilization int64
+	Error       string
+	UpdatedAt   time.Time
+}
+
+// CrawlerPolicyStatus status of the crawler balancer
+type CrawlerPolicyStatus struct {
+	Policy  CrawlerPolicy
+	LastRun time.Time
+	Planets []CrawlerPlanetStatus
+}
+
+// SetCrawlerPolicy sets the crawler balancer policy, and starts/stops the balancer accordingly.
+// The balancer is bound to the bot context, it stops when the bot is disabled and restarts when it is enabled.
+func (b *OGame) SetCrawlerPolicy(policy CrawlerPolicy) {
+	b.crawlerPolicyMu.Lock()
+	defer b.crawlerPolicyMu.Unlock()
+	if b.crawlerPolicyCancel != nil {
+		b.crawlerPolicyCancel()
+		b.crawlerPolicyCancel = nil
+	}
+	b.crawlerPolicyStatus = CrawlerPolicyStatus{Policy: policy}
+	if !policy.Enabled {
+		return
+	}
+	b.startCrawlerPolicy(policy)
+}
+
+// startCrawlerPolicy starts the balancer loop, crawlerPolicyMu must be held
+func (b *OGame) startCrawlerPolicy(policy CrawlerPolicy) {
+	ctx, cancel := context.WithCancel(b.getContext())
+	b.crawlerPolicyCancel = cancel
+	go b.crawlerPolicyLoop(ctx, policy)
+}
+
+// restartCrawlerPolicy restarts the balancer, if it is running, on the current bot context
+func (b *OGame) restartCrawlerPolicy() {
+	b.crawlerPolicyMu.Lock()
+	defer b.crawlerPolicyMu.Unlock()
+	if b.crawlerPolicyCancel != nil {
+		b.crawlerPolicyCancel()
+		b.startCrawlerPolicy(b.crawlerPolicyStatus.Policy)
+	}
+}
+
+// GetCrawlerPolicyStatus gets the crawler balancer policy and the result of its last run
+func (b *OGame) GetCrawlerPolicyStatus() CrawlerPolicyStatus {
+	b.crawlerPolicyMu.Lock()
+	defer b.crawlerPolicyMu.Unlock()
+	status := b.crawlerPolicyStatus
+	status.Planets = append([]CrawlerPlanetStatus{}, status.Planets...)
+	return status
+}
+
+func (b *OGame) crawlerPolicyLoop(ctx context.Context, policy CrawlerPolicy) {
+	interval := defaultCrawlerPolicyInterval
+	if policy.IntervalSeconds > 0 {
+		interval = time.Duration(policy.IntervalSeconds) * time.Second
+	}
+	for {
+		if b.isEnabled() && b.IsLoggedIn() && !b.IsInMaintenance() {
+			b.runCrawlerPolicy(ctx, policy)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(interval):
+		}
+	}
+}
+
+func (b *OGame) runCrawlerPolicy(ctx context.Context, policy CrawlerPolicy) {
+	planetIDs := policy.PlanetIDs
+	if len(planetIDs) == 0 {
+		for _, p := range b.GetCachedPlanets() {
+			planetIDs = append(planetIDs, p.ID)
+		}
+	}
+	statuses := make([]CrawlerPlanetStatus, 0, len(planetIDs))
+	for _, planetID := range planetIDs {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		status, err := b.balanceCrawlers(planetID, policy)
+		if err != nil {
+			status.Error = err.Error()
+			b.error("crawler policy", planetID, err)
+		}
+		statuses = append(statuses, status)
+	}
+	b.crawlerPolicyMu.Lock()
+	defer b.crawlerPolicyMu.Unlock()
+	b.crawlerPolicyStatus.LastRun = b.clock.Now()
+	b.crawlerPolicyStatus.Planets = statuses
+}
+
+// balanceCrawlers runs the crawler policy on a planet, every step is a separate task
+func (b *OGame) balanceCrawlers(planetID ogame.PlanetID, policy CrawlerPolicy) (CrawlerPlanetStatus, error) {
+	status := CrawlerPlanetStatus{PlanetID: planetID, UpdatedAt: b.clock.Now()}
+	celestialID := planetID.Celestial()
+	resourcesBuildings, err := b.WithPriority(CrawlerPolicyPriority).GetResourcesBuildings(celestialID)
+	if err != nil {
+		return status, err
+	}
+	ships, err := b.WithPriority(CrawlerPolicyPriority).GetShips(celestialID)
+	if err != nil {
+		return status, err
+	}
+	production, _, err := b.WithPriority(CrawlerPolicyPriority).GetProduction(celestialID)
+	if err != nil {
+		return status, err
+	}
+	for _, q := range production {
+		if q.ID == ogame.CrawlerID {
+			status.Queued += q.Nbr
+		}
+	}
+	status.Crawlers = ships.Crawler
+	status.MaxCrawlers = ogame.Crawler.GetMaxCrawlers(resourcesBuildings)
+
+	missing := status.MaxCrawlers - status.Crawlers - status.Queued
+	if policy.BuildCrawlers && missing > 0 && b.isCollector() {
+		resources, err := b.WithPriority(CrawlerPolicyPriority).GetResources(celestialID)
+		if err != nil {
+			return status, err
+		}
+		nbr := utils.MinInt(missing, resources.Div(ogame.Crawler.GetPrice(1)))
+		if nbr > 0 {
+			if err := b.WithPriority(CrawlerPolicyPriority).BuildShips(celestialID, ogame.CrawlerID, nbr); err != nil {
+				return status, err
+			}
+			status.Built = nbr
+			b.info("crawler policy", planetID, "build", nbr, "crawlers,", status.Crawlers, "/", status.MaxCrawlers)
+		}
+	}
+
+	settings, err := b.WithPriority(CrawlerPolicyPriority).GetResourceSettings(planetID)
+	if err != nil {
+		return status, err
+	}
+	status.Utilization = settings.Crawler
+	if policy.AdjustSettings {
+		details, err := b.WithPriority(CrawlerPolicyPriority).GetResourcesDetails(celestialID)
+		if err != nil {
+			return status, err
+		}
+		utilization := ogame.Crawler.GetUtilization(settings.Crawler, status.Crawlers, details.Energy.Available)
+		if utilization != settings.Crawler {
+			settings.Crawler = utilization
+			if err := b.WithPriority(CrawlerPolicyPriority).SetResourceSettings(planetID, settings); err != nil {
+				return status, err
+			}
+			b.info("crawler policy", planetID, "crawler utilization", status.Utilization, "->", utilization, "energy", details.Energy.Available)
+			status.Utilization = utilization
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,24 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrawlerPolicyLoop_interval(t *testing.T) {
+	b, clock := newLoopTestBot()
+	b.SetCrawlerPolicy(CrawlerPolicy{Enabled: true, IntervalSeconds: 60})
+	defer b.SetCrawlerPolicy(CrawlerPolicy{})
+	assertRunsEveryMinute(t, b, clock, func() time.Time { return b.GetCrawlerPolicyStatus().LastRun })
+}
+
+func TestSetCrawlerPolicy_disabled(t *testing.T) {
+	b := &OGame{clock: clockwork.NewFakeClock()}
+	policy := CrawlerPolicy{Enabled: false, BuildCrawlers: true}
+	b.SetCrawlerPolicy(policy)
+	assert.Nil(t, b.crawlerPolicyCancel)
+	assert.Equal(t, policy, b.GetCrawlerPolicyStatus().Policy)
+}
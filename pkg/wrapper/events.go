@@ -0,0 +1,107 @@
+package wrapper
+
+import (
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// Event types sent to the events subscribers
+const (
+	AuctioneerEventType      = "auctioneer"
+	ChatEventType            = "chat"
+	ExposureEventType        = "exposure"
+	FriendlyArrivalEventType = "friendly_arrival"
+	MaintenanceEventType     = "maintenance"
+	SelfTestEventType        = "self_test" // Data holds the failed checks
+	SpiedEventType           = "spied"
+	WSStateEventType         = "ws_state"
+)
+
+// OGameEvent event received from the game websocket
+type OGameEvent struct {
+	Type      string `json:"type"`
+	Name      string `json:"name,omitempty"`
+	Connected *bool  `json:"connected,omitempty"` // Only set for ws_state events
+	Data      any    `json:"data,omitempty"`
+}
+
+func auctioneerEventName(packet any) string {
+	switch packet.(type) {
+	case ogame.AuctioneerNewBid:
+		return "new bid"
+	case ogame.AuctioneerNewAuction:
+		return "new auction"
+	case ogame.AuctioneerAuctionFinished:
+		return "auction finished"
+	case ogame.AuctioneerTimeRemaining, ogame.AuctioneerNextAuction:
+		return "timeLeft"
+	}
+	return ""
+}
+
+func (b *OGame) subscribeEvents() (<-chan OGameEvent, func()) {
+	ch := make(chan OGameEvent, 100)
+	b.eventSubscribersMu.Lock()
+	b.eventSubscribers[ch] = struct{}{}
+	b.eventSubscribersMu.Unlock()
+	unsubscribe := func() {
+		b.eventSubscribersMu.Lock()
+		defer b.eventSubscribersMu.Unlock()
+		if _, ok := b.eventSubscribers[ch]; ok {
+			delete(b.eventSubscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publishEvent sends the event to all subscribers. Slow subscribers that have a full buffer miss the event.
+func (b *OGame) publishEvent(evt OGameEvent) {
+	b.eventSubscribersMu.Lock()
+	defer b.eventSubscribersMu.Unlock()
+	for ch := range b.eventSubscribers {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+func (b *OGame) publishAuctioneerEvent(packet any) {
+	b.publishEvent(OGameEvent{Type: AuctioneerEventType, Name: auctioneerEventName(packet), Data: packet})
+}
+
+func (b *OGame) publishChatEvent(msg ogame.ChatMsg) {
+	b.publishEvent(OGameEvent{Type: ChatEventType, Data: msg})
+}
+
+// publishWSStateEvent notify the subscribers that the game websocket got connected/disconnected.
+// The subscribers stay subscribed while the bot reconnects to the game websocket.
+func (b *OGame) publishWSStateEvent(connected bool) {
+	b.publishEvent(OGameEvent{Type: WSStateEventType, Connected: &connected})
+}
+
+// publishMaintenanceEvent notify the subscribers that a maintenance started (End is zero) or ended
+func (b *OGame) publishMaintenanceEvent(window MaintenanceWindow) {
+	b.publishEvent(OGameEvent{Type: MaintenanceEventType, Data: window})
+}
+
+// publishExposureEvent notify the subscribers that celestials are exposed before bedtime
+func (b *OGame) publishExposureEvent(report ExposureReport) {
+	b.publishEvent(OGameEvent{Type: ExposureEventType, Data: report})
+}
+
+// publishFriendlyArrivalEvent notify the subscribers that a fleet of another player is coming to one of our celestials
+func (b *OGame) publishFriendlyArrivalEvent(arrival ogame.Event) {
+	b.publishEvent(OGameEvent{Type: FriendlyArrivalEventType, Data: arrival})
+}
+
+// publishSpiedEvent notify the subscribers that another player spied one of our celestials
+func (b *OGame) publishSpiedEvent(event ogame.SpiedEvent) {
+	b.publishEvent(OGameEvent{Type: SpiedEventType, Data: event})
+}
+
+// SubscribeEvents returns a channel that receives the game websocket events (auctioneer, chat, ws_state),
+// the maintenance, exposure, friendly arrival and spied events, and a function to call to unsubscribe.
+func (b *OGame) SubscribeEvents() (<-chan OGameEvent, func()) {
+	return b.subscribeEvents()
+}
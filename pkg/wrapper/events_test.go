@@ -0,0 +1,105 @@
+package wrapper
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func newEventsTestBot() *OGame {
+	return &OGame{eventSubscribers: make(map[chan OGameEvent]struct{})}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	b := newEventsTestBot()
+	ch1, unsubscribe1 := b.SubscribeEvents()
+	ch2, unsubscribe2 := b.SubscribeEvents()
+	defer unsubscribe2()
+
+	b.publishChatEvent(ogame.ChatMsg{SenderName: "Bob", Text: "hi"})
+	evt := <-ch1
+	assert.Equal(t, ChatEventType, evt.Type)
+	assert.Equal(t, "Bob", evt.Data.(ogame.ChatMsg).SenderName)
+	evt = <-ch2
+	assert.Equal(t, ChatEventType, evt.Type)
+
+	unsubscribe1()
+	unsubscribe1() // Unsubscribing twice must not panic
+	_, ok := <-ch1
+	assert.False(t, ok)
+
+	b.publishAuctioneerEvent(ogame.AuctioneerNewBid{Sum: 1000})
+	evt = <-ch2
+	assert.Equal(t, AuctioneerEventType, evt.Type)
+	assert.Equal(t, "new bid", evt.Name)
+	assert.Equal(t, 1, len(b.eventSubscribers))
+}
+
+func TestSubscribeEvents_dropWhenBufferFull(t *testing.T) {
+	b := newEventsTestBot()
+	ch, unsubscribe := b.SubscribeEvents()
+	defer unsubscribe()
+	for i := 0; i < cap(ch)+10; i++ {
+		b.publishChatEvent(ogame.ChatMsg{ID: int64(i)})
+	}
+	assert.Equal(t, cap(ch), len(ch))
+	assert.Equal(t, int64(0), (<-ch).Data.(ogame.ChatMsg).ID)
+}
+
+func TestAuctioneerEventName(t *testing.T) {
+	assert.Equal(t, "new bid", auctioneerEventName(ogame.AuctioneerNewBid{}))
+	assert.Equal(t, "new auction", auctioneerEventName(ogame.AuctioneerNewAuction{}))
+	assert.Equal(t, "auction finished", auctioneerEventName(ogame.AuctioneerAuctionFinished{}))
+	assert.Equal(t, "timeLeft", auctioneerEventName(ogame.AuctioneerTimeRemaining{}))
+	assert.Equal(t, "timeLeft", auctioneerEventName(ogame.AuctioneerNextAuction{}))
+	assert.Equal(t, "", auctioneerEventName("unknown"))
+}
+
+func TestReconnectChat(t *testing.T) {
+	b := newEventsTestBot()
+	assert.False(t, b.ReconnectChat())
+
+	msgCh := make(chan string, 10)
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		for {
+			var msg string
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				return
+			}
+			msgCh <- msg
+		}
+	}))
+	defer srv.Close()
+
+	reconnect := func(version string) []string {
+		ws, err := websocket.Dial("ws://"+strings.TrimPrefix(srv.URL, "http://"), "", srv.URL)
+		assert.NoError(t, err)
+		defer ws.Close()
+		b.ws = ws
+		b.serverData.Version = version
+		assert.True(t, b.ReconnectChat())
+		return []string{<-msgCh, <-msgCh}
+	}
+	assert.Equal(t, []string{"1::/chat", "1::/auctioneer"}, reconnect("7.6.0"))
+	assert.Equal(t, []string{"40/chat,", "40/auctioneer,"}, reconnect("8.1.0"))
+	assert.Equal(t, []string{"40/chat,", "40/auctioneer,"}, reconnect("9.0.3"))
+}
+
+func TestPublishWSStateEvent(t *testing.T) {
+	b := newEventsTestBot()
+	ch, unsubscribe := b.SubscribeEvents()
+	defer unsubscribe()
+	b.publishWSStateEvent(false)
+	by, err := json.Marshal(<-ch)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"ws_state","connected":false}`, string(by))
+	b.publishWSStateEvent(true)
+	evt := <-ch
+	assert.Equal(t, WSStateEventType, evt.Type)
+	assert.True(t, *evt.Connected)
+}
@@ -0,0 +1,52 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// expeditionCapTTL how long the points of the top 1 player are cached
+const expeditionCapTTL = 24 * time.Hour
+
+// ExpeditionCap useful cargo capacity of an expedition fleet, derived from the points of the top 1 player
+type ExpeditionCap struct {
+	TopPlayerPoints int64
+	MaxCargo        int64 // Maximum resources an expedition can find, cargo capacity above it is useless
+	FetchedAt       time.Time
+}
+
+// GetExpeditionCap returns the current expedition cap.
+// The points of the top 1 player are fetched from the public highscore api, and cached for a day.
+func (b *OGame) GetExpeditionCap() (ExpeditionCap, error) {
+	b.expeditionCapMu.Lock()
+	defer b.expeditionCapMu.Unlock()
+	now := b.clock.Now()
+	if b.expeditionCap.FetchedAt.IsZero() || now.Sub(b.expeditionCap.FetchedAt) >= expeditionCapTTL {
+		server := b.GetServer()
+		topPoints, err := GetTopPlayerPoints(b.client, b.ctx, server.Number, server.Language)
+		if err != nil {
+			return ExpeditionCap{}, err
+		}
+		b.expeditionCap = ExpeditionCap{TopPlayerPoints: topPoints, FetchedAt: now}
+	}
+	res := b.expeditionCap
+	res.MaxCargo = ogame.ExpeditionMaxCargo(res.TopPlayerPoints, b.CharacterClass())
+	return res, nil
+}
+
+// CheckExpeditionFleet returns either or not the cargo capacity of ships exceeds the expedition cap.
+// If trim is true, the cargo ships that are not needed are removed from the returned fleet.
+func (b *OGame) CheckExpeditionFleet(ships ogame.ShipsInfos, trim bool) (ogame.ShipsInfos, bool, error) {
+	expeditionCap, err := b.GetExpeditionCap()
+	if err != nil {
+		return ships, false, err
+	}
+	techs := b.GetCachedResearch()
+	isCollector := b.CharacterClass() == ogame.Collector
+	exceeds := ships.Cargo(techs, b.IsProbeRaids(), isCollector, b.IsPioneers()) > expeditionCap.MaxCargo
+	if exceeds && trim {
+		ships = ogame.TrimExpeditionCargo(ships, expeditionCap.MaxCargo, techs, b.IsProbeRaids(), isCollector, b.IsPioneers())
+	}
+	return ships, exceeds, nil
+}
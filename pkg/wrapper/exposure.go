@@ -0,0 +1,231 @@
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// ExposurePriority priority of GetExposure, used by the bedtime alert
+const ExposurePriority = taskRunner.Low
+
+// DefaultExposureThreshold resources value above which an unprotected celestial is flagged
+const DefaultExposureThreshold = 1_000_000
+
+// DefaultNightHours length of the night window by default
+const DefaultNightHours = 8
+
+const exposureAlertAdvance = time.Hour
+
+// CelestialExposure resources and protection of a celestial
+type CelestialExposure struct {
+	CelestialID    ogame.CelestialID
+	Coordinate     ogame.Coordinate
+	Resources      ogame.Resources
+	ResourcesValue int64 // Normalized value of the resources on hand (metal units)
+	FleetValue     int64 // Value of the ships stationed on the celestial
+	DefenseValue   int64 // Value of the defenses that can be attacked
+	FleetSaved     bool  // A fleet sent from the celestial is still away at the end of the night window
+	Exposed        bool  // Resources value above the threshold, not fleet saved, and worth more than the fleet and defenses protecting it
+	Error          string
+}
+
+// ExposureReport resources exposure of every celestial
+type ExposureReport struct {
+	Threshold  int64
+	NightStart time.Time
+	NightEnd   time.Time
+	CreatedAt  time.Time
+	Celestials []CelestialExposure
+}
+
+// HasExposed returns either or not at least one celestial is exposed
+func (r ExposureReport) HasExposed() bool {
+	for _, c := range r.Celestials {
+		if c.Exposed {
+			return true
+		}
+	}
+	return false
+}
+
+// ExposureAlert settings of the bedtime alert.
+// When enabled, the exposure report is computed an hour before bedtime (local time),
+// and an exposure event is sent to the events subscribers if a celestial is exposed.
+// The night window, starting at bedtime, is also used by GetExposure when the alert is disabled.
+type ExposureAlert struct {
+	Enabled    bool
+	Hour       int   // Bedtime hour (0-23)
+	Minute     int   // Bedtime minute (0-59)
+	NightHours int64 // Length of the night window, DefaultNightHours if 0
+	Threshold  int64 // Resources value above which a celestial is flagged, DefaultExposureThreshold if 0
+}
+
+func newCelestialExposure(celestial ogame.Celestial, resources ogame.Resources, ships ogame.ShipsInfos, defenses ogame.DefensesInfos, fleetSaved bool, threshold int64) CelestialExposure {
+	res := CelestialExposure{
+		CelestialID:    celestial.GetID(),
+		Coordinate:     celestial.GetCoordinate(),
+		Resources:      resources,
+		ResourcesValue: resources.Value(),
+		FleetValue:     ships.FleetValue(),
+		DefenseValue:   defenses.AttackableValue(),
+		FleetSaved:     fleetSaved,
+	}
+	res.Exposed = res.ResourcesValue >= threshold && !fleetSaved && res.FleetValue+res.DefenseValue < res.ResourcesValue
+	return res
+}
+
+// nightWindow returns the current night window if now is within it, the next one otherwise
+func nightWindow(now time.Time, alert ExposureAlert) (start, end time.Time) {
+	length := time.Duration(alert.NightHours) * time.Hour
+	if length <= 0 {
+		length = DefaultNightHours * time.Hour
+	}
+	start = time.Date(now.Year(), now.Month(), now.Day(), alert.Hour, alert.Minute, 0, 0, now.Location())
+	if now.Before(start.AddDate(0, 0, -1).Add(length)) {
+		start = start.AddDate(0, 0, -1)
+	} else if !now.Before(start.Add(length)) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start, start.Add(length)
+}
+
+// isFleetSaved returns either or not one of the fleets sent from coord is still away at nightEnd
+func isFleetSaved(coord ogame.Coordinate, fleets []ogame.Fleet, nightEnd time.Time) bool {
+	for _, fleet := range fleets {
+		if !fleet.Origin.Equal(coord) {
+			continue
+		}
+		backTime := fleet.BackTime
+		if fleet.ReturnFlight || backTime.IsZero() {
+			backTime = fleet.ArrivalTime
+		}
+		if !backTime.Before(nightEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextExposureAlert returns the next time the bedtime alert must run
+func nextExposureAlert(now time.Time, alert ExposureAlert) time.Time {
+	bedtime := time.Date(now.Year(), now.Month(), now.Day(), alert.Hour, alert.Minute, 0, 0, now.Location())
+	next := bedtime.Add(-exposureAlertAdvance)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// GetExposure reports, for each celestial, the resources on hand and the value of the fleet and defenses protecting them.
+// Celestials with resources above the threshold, that are worth more than what protects them, are flagged as exposed.
+// Fleets in flight are not counted as protection, but a celestial is not flagged if one of the fleets sent
+// from it is still away at the end of the configured night window (fleet save).
+// Each page is fetched in its own task, so higher priority tasks are not held back by the scan.
+func (b *OGame) GetExposure(threshold int64) (ExposureReport, error) {
+	return b.getExposure(ExposurePriority, threshold)
+}
+
+func (b *OGame) getExposure(priority taskRunner.Priority, threshold int64) (ExposureReport, error) {
+	if threshold <= 0 {
+		threshold = DefaultExposureThreshold
+	}
+	now := b.clock.Now()
+	report := ExposureReport{Threshold: threshold, CreatedAt: now}
+	report.NightStart, report.NightEnd = nightWindow(now, b.GetExposureAlert())
+	celestials, err := b.WithPriority(priority).GetCelestials()
+	if err != nil {
+		return report, err
+	}
+	fleets, _ := b.WithPriority(priority).GetFleets()
+	for _, celestial := range celestials {
+		fleetSaved := isFleetSaved(celestial.GetCoordinate(), fleets, report.NightEnd)
+		report.Celestials = append(report.Celestials, b.celestialExposure(priority, celestial, fleetSaved, threshold))
+	}
+	return report, nil
+}
+
+func (b *OGame) celestialExposure(priority taskRunner.Priority, celestial Celestial, fleetSaved bool, threshold int64) CelestialExposure {
+	celestialID := celestial.GetID()
+	errored := func(err error) CelestialExposure {
+		return CelestialExposure{CelestialID: celestialID, Coordinate: celestial.GetCoordinate(), FleetSaved: fleetSaved, Error: err.Error()}
+	}
+	resources, err := b.WithPriority(priority).GetResources(celestialID)
+	if err != nil {
+		return errored(err)
+	}
+	ships, err := b.WithPriority(priority).GetShips(celestialID)
+	if err != nil {
+		return errored(err)
+	}
+	defenses, err := b.WithPriority(priority).GetDefense(celestialID)
+	if err != nil {
+		return errored(err)
+	}
+	return newCelestialExposure(celestial, resources, ships, defenses, fleetSaved, threshold)
+}
+
+// SetExposureAlert sets the bedtime alert settings, and starts/stops the alert accordingly.
+// The alert is bound to the bot context, it stops when the bot is disabled and restarts when it is enabled.
+func (b *OGame) SetExposureAlert(alert ExposureAlert) {
+	b.exposureAlertMu.Lock()
+	defer b.exposureAlertMu.Unlock()
+	if b.exposureAlertCancel != nil {
+		b.exposureAlertCancel()
+		b.exposureAlertCancel = nil
+	}
+	b.exposureAlert = alert
+	if !alert.Enabled {
+		return
+	}
+	b.startExposureAlert(alert)
+}
+
+// startExposureAlert starts the alert loop, exposureAlertMu must be held
+func (b *OGame) startExposureAlert(alert ExposureAlert) {
+	ctx, cancel := context.WithCancel(b.getContext())
+	b.exposureAlertCancel = cancel
+	go b.exposureAlertLoop(ctx, alert)
+}
+
+// restartExposureAlert restarts the alert, if it is running, on the current bot context
+func (b *OGame) restartExposureAlert() {
+	b.exposureAlertMu.Lock()
+	defer b.exposureAlertMu.Unlock()
+	if b.exposureAlertCancel != nil {
+		b.exposureAlertCancel()
+		b.startExposureAlert(b.exposureAlert)
+	}
+}
+
+// GetExposureAlert gets the bedtime alert settings
+func (b *OGame) GetExposureAlert() ExposureAlert {
+	b.exposureAlertMu.Lock()
+	defer b.exposureAlertMu.Unlock()
+	return b.exposureAlert
+}
+
+func (b *OGame) exposureAlertLoop(ctx context.Context, alert ExposureAlert) {
+	for {
+		now := b.clock.Now()
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(nextExposureAlert(now, alert).Sub(now)):
+		}
+		if !b.isEnabled() || !b.IsLoggedIn() || b.IsInMaintenance() {
+			continue
+		}
+		report, err := b.GetExposure(alert.Threshold)
+		if err != nil {
+			b.error("exposure alert", err)
+			continue
+		}
+		if report.HasExposed() {
+			b.info("exposure alert: celestials exposed before bedtime")
+			b.publishExposureEvent(report)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCelestialExposure(t *testing.T) {
+	planet := ogame.Planet{ID: 123, Coordinate: ogame.Coordinate{1, 2, 3, ogame.PlanetType}}
+	resources := ogame.Resources{Metal: 1_000_000, Crystal: 500_000, Deuterium: 100_000}
+	exposure := newCelestialExposure(planet, resources, ogame.ShipsInfos{}, ogame.DefensesInfos{}, false, DefaultExposureThreshold)
+	assert.Equal(t, ogame.CelestialID(123), exposure.CelestialID)
+	assert.Equal(t, int64(2_300_000), exposure.ResourcesValue)
+	assert.True(t, exposure.Exposed)
+
+	exposure = newCelestialExposure(planet, resources, ogame.ShipsInfos{}, ogame.DefensesInfos{PlasmaTurret: 20}, false, DefaultExposureThreshold)
+	assert.Equal(t, int64(2_600_000), exposure.DefenseValue)
+	assert.False(t, exposure.Exposed)
+
+	exposure = newCelestialExposure(planet, resources, ogame.ShipsInfos{}, ogame.DefensesInfos{}, true, DefaultExposureThreshold)
+	assert.True(t, exposure.FleetSaved)
+	assert.False(t, exposure.Exposed)
+
+	exposure = newCelestialExposure(planet, ogame.Resources{Metal: 10_000}, ogame.ShipsInfos{}, ogame.DefensesInfos{}, false, DefaultExposureThreshold)
+	assert.False(t, exposure.Exposed)
+}
+
+func TestNextExposureAlert(t *testing.T) {
+	alert := ExposureAlert{Hour: 23, Minute: 30}
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 5, 10, 22, 30, 0, 0, time.UTC), nextExposureAlert(now, alert))
+	now = time.Date(2022, 5, 10, 22, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 5, 11, 22, 30, 0, 0, time.UTC), nextExposureAlert(now, alert))
+	alert = ExposureAlert{Hour: 0, Minute: 30}
+	now = time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2022, 5, 10, 23, 30, 0, 0, time.UTC), nextExposureAlert(now, alert))
+}
+
+func TestNightWindow(t *testing.T) {
+	alert := ExposureAlert{Hour: 23, Minute: 30}
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	start, end := nightWindow(now, alert)
+	assert.Equal(t, time.Date(2022, 5, 10, 23, 30, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2022, 5, 11, 7, 30, 0, 0, time.UTC), end)
+	// Within the night window started the day before
+	now = time.Date(2022, 5, 11, 2, 0, 0, 0, time.UTC)
+	start, end = nightWindow(now, alert)
+	assert.Equal(t, time.Date(2022, 5, 10, 23, 30, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2022, 5, 11, 7, 30, 0, 0, time.UTC), end)
+	// Night window over, the next one is returned
+	alert = ExposureAlert{Hour: 1, Minute: 0, NightHours: 6}
+	now = time.Date(2022, 5, 10, 7, 0, 0, 0, time.UTC)
+	start, end = nightWindow(now, alert)
+	assert.Equal(t, time.Date(2022, 5, 11, 1, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2022, 5, 11, 7, 0, 0, 0, time.UTC), end)
+}
+
+func TestIsFleetSaved(t *testing.T) {
+	coord := ogame.Coordinate{1, 2, 3, ogame.PlanetType}
+	nightEnd := time.Date(2022, 5, 11, 7, 30, 0, 0, time.UTC)
+	back := func(h int) time.Time { return time.Date(2022, 5, 11, h, 0, 0, 0, time.UTC) }
+	assert.False(t, isFleetSaved(coord, nil, nightEnd))
+	assert.False(t, isFleetSaved(coord, []ogame.Fleet{{Origin: coord, ArrivalTime: back(3), BackTime: back(6)}}, nightEnd))
+	assert.True(t, isFleetSaved(coord, []ogame.Fleet{{Origin: coord, ArrivalTime: back(4), BackTime: back(8)}}, nightEnd))
+	assert.True(t, isFleetSaved(coord, []ogame.Fleet{{Origin: coord, ReturnFlight: true, ArrivalTime: back(9)}}, nightEnd))
+	// Fleet sent from the moon does not cover the planet
+	moon := ogame.Coordinate{1, 2, 3, ogame.MoonType}
+	assert.False(t, isFleetSaved(coord, []ogame.Fleet{{Origin: moon, ArrivalTime: back(4), BackTime: back(8)}}, nightEnd))
+}
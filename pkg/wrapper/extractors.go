@@ -0,0 +1,49 @@
+package wrapper
+
+import (
+	"expvar"
+
+	"github.com/alaingilbert/ogame/pkg/extractor"
+	v7 "github.com/alaingilbert/ogame/pkg/extractor/v7"
+	v71 "github.com/alaingilbert/ogame/pkg/extractor/v71"
+	v8 "github.com/alaingilbert/ogame/pkg/extractor/v8"
+	v874 "github.com/alaingilbert/ogame/pkg/extractor/v874"
+	v9 "github.com/alaingilbert/ogame/pkg/extractor/v9"
+	version "github.com/hashicorp/go-version"
+)
+
+// compatibilityModeTotal number of logins on a server more recent than the newest extractor
+var compatibilityModeTotal = expvar.NewInt("ogame_compatibility_mode_total")
+
+// SupportedExtractor an extractor and the range of game versions it supports
+type SupportedExtractor struct {
+	Name       string
+	MinVersion string // Inclusive
+	MaxVersion string // Exclusive
+	new        func() extractor.Extractor
+}
+
+// SupportedExtractors known extractors, newest first
+var SupportedExtractors = []SupportedExtractor{
+	{Name: "v9", MinVersion: "9.0.0", MaxVersion: "10.0.0-rc0", new: func() extractor.Extractor { return v9.NewExtractor() }},
+	{Name: "v874", MinVersion: "8.7.4-pl3", MaxVersion: "9.0.0", new: func() extractor.Extractor { return v874.NewExtractor() }},
+	{Name: "v8", MinVersion: "8.0.0", MaxVersion: "8.7.4-pl3", new: func() extractor.Extractor { return v8.NewExtractor() }},
+	{Name: "v71", MinVersion: "7.1.0-rc0", MaxVersion: "8.0.0", new: func() extractor.Extractor { return v71.NewExtractor() }},
+	{Name: "v7", MinVersion: "7.0.0-rc0", MaxVersion: "7.1.0-rc0", new: func() extractor.Extractor { return v7.NewExtractor() }},
+}
+
+// selectExtractor returns the extractor to use for a game version, nil if the version is older than all extractors.
+// compatibilityMode is true when the version is more recent than the newest extractor supports,
+// the newest extractor is then used.
+func selectExtractor(ogVersion *version.Version) (e extractor.Extractor, compatibilityMode bool) {
+	newest := SupportedExtractors[0]
+	if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion(newest.MaxVersion))) {
+		return newest.new(), true
+	}
+	for _, supported := range SupportedExtractors {
+		if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion(supported.MinVersion))) {
+			return supported.new(), false
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,32 @@
+package wrapper
+
+import (
+	"testing"
+
+	v7 "github.com/alaingilbert/ogame/pkg/extractor/v7"
+	v874 "github.com/alaingilbert/ogame/pkg/extractor/v874"
+	v9 "github.com/alaingilbert/ogame/pkg/extractor/v9"
+	version "github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectExtractor(t *testing.T) {
+	e, compatibilityMode := selectExtractor(version.Must(version.NewVersion("9.1.2")))
+	assert.IsType(t, &v9.Extractor{}, e)
+	assert.False(t, compatibilityMode)
+
+	e, compatibilityMode = selectExtractor(version.Must(version.NewVersion("8.7.4-pl3")))
+	assert.IsType(t, &v874.Extractor{}, e)
+	assert.False(t, compatibilityMode)
+
+	e, _ = selectExtractor(version.Must(version.NewVersion("7.0.0")))
+	assert.IsType(t, &v7.Extractor{}, e)
+
+	e, compatibilityMode = selectExtractor(version.Must(version.NewVersion("10.0.1")))
+	assert.IsType(t, &v9.Extractor{}, e)
+	assert.True(t, compatibilityMode)
+
+	e, compatibilityMode = selectExtractor(version.Must(version.NewVersion("6.8.0")))
+	assert.Nil(t, e)
+	assert.False(t, compatibilityMode)
+}
@@ -0,0 +1,64 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// friendlyArrivals returns the incoming fleets of other players that are not hostile (alliance transports, deployments...)
+func friendlyArrivals(events []ogame.Event) []ogame.Event {
+	out := make([]ogame.Event, 0)
+	for _, event := range events {
+		if event.Type == ogame.NeutralEvent && !event.ReturnFlight {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// incomingResources sums the announced shipments of the arrivals reaching coord before deadline
+func incomingResources(arrivals []ogame.Event, coord ogame.Coordinate, deadline time.Time) ogame.Resources {
+	var out ogame.Resources
+	for _, arrival := range arrivals {
+		if arrival.Resources != nil && arrival.Destination.Equal(coord) && !arrival.ArrivalTime.After(deadline) {
+			out = out.Add(*arrival.Resources)
+		}
+	}
+	return out
+}
+
+func (b *OGame) getFriendlyArrivals() ([]ogame.Event, error) {
+	events, err := b.getEventList()
+	if err != nil {
+		return []ogame.Event{}, err
+	}
+	arrivals := friendlyArrivals(events)
+	b.notifyFriendlyArrivals(arrivals)
+	return arrivals, nil
+}
+
+// notifyFriendlyArrivals publishes a friendly arrival event for each arrival seen for the first time
+func (b *OGame) notifyFriendlyArrivals(arrivals []ogame.Event) {
+	now := b.clock.Now()
+	announced := make([]ogame.Event, 0)
+	b.friendlyArrivalsMu.Lock()
+	if b.friendlyArrivalsSeen == nil {
+		b.friendlyArrivalsSeen = make(map[int64]time.Time)
+	}
+	for id, arrivalTime := range b.friendlyArrivalsSeen {
+		if arrivalTime.Before(now) {
+			delete(b.friendlyArrivalsSeen, id)
+		}
+	}
+	for _, arrival := range arrivals {
+		if _, ok := b.friendlyArrivalsSeen[arrival.ID]; !ok {
+			b.friendlyArrivalsSeen[arrival.ID] = arrival.ArrivalTime
+			announced = append(announced, arrival)
+		}
+	}
+	b.friendlyArrivalsMu.Unlock()
+	for _, arrival := range announced {
+		b.publishFriendlyArrivalEvent(arrival)
+	}
+}
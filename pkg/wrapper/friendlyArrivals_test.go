@@ -0,0 +1,51 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFriendlyArrivals(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	hub := ogame.Coordinate{Galaxy: 4, System: 116, Position: 12, Type: ogame.PlanetType}
+	events := []ogame.Event{
+		{ID: 1, Type: ogame.HostileEvent, MissionType: ogame.Attack, Destination: hub, ArrivalTime: now},
+		{ID: 2, Type: ogame.OwnEvent, MissionType: ogame.Transport, Destination: hub, ArrivalTime: now},
+		{ID: 3, Type: ogame.NeutralEvent, MissionType: ogame.Transport, Destination: hub, ArrivalTime: now.Add(time.Hour),
+			Resources: &ogame.Resources{Metal: 1000, Crystal: 500}},
+		{ID: 4, Type: ogame.NeutralEvent, MissionType: ogame.Park, Destination: hub, ArrivalTime: now.Add(3 * time.Hour),
+			Resources: &ogame.Resources{Metal: 2000}},
+		{ID: 5, Type: ogame.NeutralEvent, MissionType: ogame.Transport, Destination: hub, ReturnFlight: true, ArrivalTime: now},
+	}
+	arrivals := friendlyArrivals(events)
+	if assert.Len(t, arrivals, 2) {
+		assert.Equal(t, int64(3), arrivals[0].ID)
+		assert.Equal(t, int64(4), arrivals[1].ID)
+	}
+	assert.Equal(t, ogame.Resources{Metal: 1000, Crystal: 500}, incomingResources(arrivals, hub, now.Add(2*time.Hour)))
+	assert.Equal(t, ogame.Resources{}, incomingResources(arrivals, ogame.Coordinate{Galaxy: 1, System: 1, Position: 1}, now.Add(4*time.Hour)))
+}
+
+func TestNotifyFriendlyArrivals(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC))
+	b := newEventsTestBot()
+	b.clock = clock
+	ch, unsubscribe := b.SubscribeEvents()
+	defer unsubscribe()
+
+	arrival := ogame.Event{ID: 3, Type: ogame.NeutralEvent, ArrivalTime: clock.Now().Add(time.Hour)}
+	b.notifyFriendlyArrivals([]ogame.Event{arrival})
+	b.notifyFriendlyArrivals([]ogame.Event{arrival})
+	evt := <-ch
+	assert.Equal(t, FriendlyArrivalEventType, evt.Type)
+	assert.Equal(t, arrival, evt.Data)
+	assert.Equal(t, 0, len(ch))
+
+	clock.Advance(2 * time.Hour)
+	b.notifyFriendlyArrivals(nil)
+	assert.Equal(t, 0, len(b.friendlyArrivalsSeen))
+}
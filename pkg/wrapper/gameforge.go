@@ -11,7 +11,6 @@ import (
 	"github.com/alaingilbert/ogame/pkg/utils"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
-	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -180,7 +179,8 @@ func RedeemCode(client httpclient.IHttpClient, ctx context.Context, lobby, email
 
 // LoginAndAddAccount adds an account to a gameforge lobby
 func LoginAndAddAccount(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, universe, lang string) (*AddAccountRes, error) {
-	postSessionsRes, err := GFLogin(client, ctx, lobby, username, password, otpSecret, "")
+	locale := GetLobbyLocale(lang)
+	postSessionsRes, err := GFLoginWithLocale(client, ctx, lobby, username, password, otpSecret, "", locale)
 	if err != nil {
 		return nil, err
 	}
@@ -190,9 +190,9 @@ func LoginAndAddAccount(client httpclient.IHttpClient, ctx context.Context, lobb
 	}
 	server, found := findServer(universe, lang, servers)
 	if !found {
-		return nil, errors.New("server not found")
+		return nil, fmt.Errorf("%w: universe %s (%s) in %s", ogame.ErrServerNotFound, universe, lang, lobby)
 	}
-	return AddAccount(client, ctx, lobby, server.AccountGroup, postSessionsRes.Token)
+	return AddAccountWithLocale(client, ctx, lobby, server.AccountGroup, postSessionsRes.Token, locale)
 }
 
 // AddAccountRes response from creating a new account
@@ -209,14 +209,20 @@ type AddAccountRes struct {
 
 func (r AddAccountRes) GetBearerToken() string { return r.BearerToken }
 
+// AddAccount same as AddAccountWithLocale, using DefaultLobbyLocale
 func AddAccount(client httpclient.IHttpClient, ctx context.Context, lobby, accountGroup, sessionToken string) (*AddAccountRes, error) {
+	return AddAccountWithLocale(client, ctx, lobby, accountGroup, sessionToken, DefaultLobbyLocale)
+}
+
+// AddAccountWithLocale creates a game account in accountGroup, with the lobby locale
+func AddAccountWithLocale(client httpclient.IHttpClient, ctx context.Context, lobby, accountGroup, sessionToken string, locale LobbyLocale) (*AddAccountRes, error) {
 	var payload struct {
 		AccountGroup string `json:"accountGroup"`
 		Locale       string `json:"locale"`
 		Kid          string `json:"kid"`
 	}
 	payload.AccountGroup = accountGroup // en_181
-	payload.Locale = "en_GB"
+	payload.Locale = locale.Locale
 	jsonPayloadBytes, err := json.Marshal(&payload)
 	if err != nil {
 		return nil, err
@@ -263,13 +269,19 @@ type GFLoginRes struct {
 
 func (r GFLoginRes) GetBearerToken() string { return r.Token }
 
+// GFLogin same as GFLoginWithLocale, using DefaultLobbyLocale
 func GFLogin(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, challengeID string) (out *GFLoginRes, err error) {
+	return GFLoginWithLocale(client, ctx, lobby, username, password, otpSecret, challengeID, DefaultLobbyLocale)
+}
+
+// GFLoginWithLocale logs in the gameforge lobby, with the lobby locale
+func GFLoginWithLocale(client httpclient.IHttpClient, ctx context.Context, lobby, username, password, otpSecret, challengeID string, locale LobbyLocale) (out *GFLoginRes, err error) {
 	gameEnvironmentID, platformGameID, err := getConfiguration(client, ctx, lobby)
 	if err != nil {
 		return out, err
 	}
 
-	req, err := postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID)
+	req, err := postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID, locale)
 	if err != nil {
 		return out, err
 	}
@@ -355,13 +367,13 @@ func getConfiguration(client httpclient.IHttpClient, ctx context.Context, lobby
 	return string(gameEnvironmentID), string(platformGameID), nil
 }
 
-func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID string) (*http.Request, error) {
+func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpSecret, challengeID string, locale LobbyLocale) (*http.Request, error) {
 	payload := url.Values{
 		"autoGameAccountCreation": {"false"},
 		"gameEnvironmentId":       {gameEnvironmentID},
 		"platformGameId":          {platformGameID},
-		"gfLang":                  {"en"},
-		"locale":                  {"en_GB"},
+		"gfLang":                  {locale.GfLang},
+		"locale":                  {locale.Locale},
 		"identity":                {username},
 		"password":                {password},
 	}
@@ -393,7 +405,13 @@ func postSessionsReq(gameEnvironmentID, platformGameID, username, password, otpS
 	return req, nil
 }
 
+// StartCaptchaChallenge same as StartCaptchaChallengeWithLocale, using DefaultLobbyLocale
 func StartCaptchaChallenge(client httpclient.IHttpClient, ctx context.Context, challengeID string) (questionRaw, iconsRaw []byte, err error) {
+	return StartCaptchaChallengeWithLocale(client, ctx, challengeID, DefaultLobbyLocale)
+}
+
+// StartCaptchaChallengeWithLocale starts a captcha challenge, and gets its images in the lobby locale
+func StartCaptchaChallengeWithLocale(client httpclient.IHttpClient, ctx context.Context, challengeID string, locale LobbyLocale) (questionRaw, iconsRaw []byte, err error) {
 	req, err := http.NewRequest(http.MethodGet, "https://challenge.gameforge.com/challenge/"+challengeID, nil)
 	if err != nil {
 		return
@@ -406,7 +424,7 @@ func StartCaptchaChallenge(client httpclient.IHttpClient, ctx context.Context, c
 	defer challengeResp.Body.Close()
 	_, _ = ioutil.ReadAll(challengeResp.Body)
 
-	req, err = http.NewRequest(http.MethodGet, "https://image-drop-challenge.gameforge.com/challenge/"+challengeID+"/en-GB", nil)
+	req, err = http.NewRequest(http.MethodGet, "https://image-drop-challenge.gameforge.com/challenge/"+challengeID+"/"+locale.ChallengeLocale(), nil)
 	if err != nil {
 		return
 	}
@@ -418,50 +436,115 @@ func StartCaptchaChallenge(client httpclient.IHttpClient, ctx context.Context, c
 	defer challengePresentedResp.Body.Close()
 	_, _ = ioutil.ReadAll(challengePresentedResp.Body)
 
-	// Question request
-	req, err = http.NewRequest(http.MethodGet, "https://image-drop-challenge.gameforge.com/challenge/"+challengeID+"/en-GB/text", nil)
+	questionRaw, _, err = GetCaptchaImage(client, ctx, challengeID, locale, CaptchaQuestionImage, "")
 	if err != nil {
 		return
 	}
-	req.WithContext(ctx)
-	questionResp, err := client.Do(req)
+	iconsRaw, _, err = GetCaptchaImage(client, ctx, challengeID, locale, CaptchaIconsImage, "")
+	return
+}
+
+// CaptchaImage kind of image of a captcha challenge
+type CaptchaImage string
+
+// Images of a captcha challenge
+const (
+	CaptchaQuestionImage CaptchaImage = "text"       // Question to answer
+	CaptchaIconsImage    CaptchaImage = "drag-icons" // Icons to pick the answer from
+)
+
+// GetCaptchaImage downloads an image of a captcha challenge, and returns its content type.
+// rawQuery (eg: the timestamp used to bust caches) is passed as is to the upstream url, it can be empty.
+func GetCaptchaImage(client httpclient.IHttpClient, ctx context.Context, challengeID string, locale LobbyLocale, image CaptchaImage, rawQuery string) (raw []byte, contentType string, err error) {
+	imageURL := "https://image-drop-challenge.gameforge.com/challenge/" + challengeID + "/" + locale.ChallengeLocale() + "/" + string(image)
+	if rawQuery != "" {
+		imageURL += "?" + rawQuery
+	}
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
 	if err != nil {
 		return
 	}
-	defer questionResp.Body.Close()
-	questionRaw, _ = ioutil.ReadAll(questionResp.Body)
-
-	// Icons request
-	req, err = http.NewRequest(http.MethodGet, "https://image-drop-challenge.gameforge.com/challenge/"+challengeID+"/en-GB/drag-icons", nil)
+	req.WithContext(ctx)
+	resp, err := client.Do(req)
 	if err != nil {
 		return
 	}
-	req.WithContext(ctx)
-	iconsResp, err := client.Do(req)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.New("failed to get captcha image : " + resp.Status)
+	}
+	raw, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return
 	}
-	defer iconsResp.Body.Close()
-	iconsRaw, _ = ioutil.ReadAll(iconsResp.Body)
-	return
+	// Do not trust the upstream header, it is not always an image type
+	contentType = resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(raw)
+	}
+	return raw, contentType, nil
 }
 
+// CaptchaStatus status of a captcha challenge after an answer was submitted
+type CaptchaStatus string
+
+// Captcha challenge statuses
+const (
+	CaptchaSolved  CaptchaStatus = "solved"
+	CaptchaPending CaptchaStatus = "pending" // Wrong answer, the challenge can be answered again
+	CaptchaFailed  CaptchaStatus = "failed"
+)
+
+// ErrCaptchaExpired returned when answering a captcha challenge that no longer exists
+var ErrCaptchaExpired = errors.New("captcha challenge expired")
+
+// SolveChallenge same as SolveChallengeWithLocale, using DefaultLobbyLocale
 func SolveChallenge(client httpclient.IHttpClient, ctx context.Context, challengeID string, answer int64) error {
-	challengeURL := "https://image-drop-challenge.gameforge.com/challenge/" + challengeID + "/en-GB"
+	return SolveChallengeWithLocale(client, ctx, challengeID, answer, DefaultLobbyLocale)
+}
+
+// SolveChallengeWithLocale submits the answer of a captcha challenge started in the lobby locale
+func SolveChallengeWithLocale(client httpclient.IHttpClient, ctx context.Context, challengeID string, answer int64, locale LobbyLocale) error {
+	_, err := SolveChallengeStatus(client, ctx, challengeID, answer, locale)
+	return err
+}
+
+// SolveChallengeStatus submits the answer of a captcha challenge, and returns the status of the challenge
+func SolveChallengeStatus(client httpclient.IHttpClient, ctx context.Context, challengeID string, answer int64, locale LobbyLocale) (CaptchaStatus, error) {
+	challengeURL := "https://image-drop-challenge.gameforge.com/challenge/" + challengeID + "/" + locale.ChallengeLocale()
 	body := strings.NewReader(`{"answer":` + utils.FI64(answer) + `}`)
 	req, _ := http.NewRequest(http.MethodPost, challengeURL, body)
 	req.Header.Set("Content-Type", "application/json")
 	req.WithContext(ctx)
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return CaptchaFailed, err
 	}
 	defer resp.Body.Close()
-	_, _ = io.Copy(io.Discard, resp.Body)
+	by, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return CaptchaFailed, ErrCaptchaExpired
+	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to solve captcha (%s)", resp.Status)
+		return CaptchaFailed, fmt.Errorf("failed to solve captcha (%s)", resp.Status)
 	}
-	return nil
+	return parseCaptchaStatus(by), nil
+}
+
+func parseCaptchaStatus(by []byte) CaptchaStatus {
+	var res struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(by, &res); err != nil {
+		return CaptchaFailed
+	}
+	switch res.Status {
+	case "solved":
+		return CaptchaSolved
+	case "presented", "pending":
+		return CaptchaPending
+	}
+	return CaptchaFailed
 }
 
 // Server ogame information for their servers
@@ -554,9 +637,32 @@ type ServerData struct {
 	ResearchDurationDivisor       int64   `xml:"researchDurationDivisor"`       // 2
 	DarkMatterNewAcount           int64   `xml:"darkMatterNewAcount"`           // 8000
 	CargoHyperspaceTechMultiplier int64   `xml:"cargoHyperspaceTechMultiplier"` // 5
+	MarketplaceEnabled            bool    `xml:"marketplaceEnabled"`            // 1
+	CharacterClassesEnabled       bool    `xml:"characterClassesEnabled"`       // 1
+	IgnoreEmptySystems            bool    `xml:"ignoreEmptySystems"`            // 1 (galaxy navigation skips empty systems)
+	IgnoreInactiveSystems         bool    `xml:"ignoreInactiveSystems"`         // 1 (galaxy navigation skips inactive systems)
 	SpeedFleet                    int64   `xml:"speedFleet"`                    // 6 // Deprecated in 8.1.0
 }
 
+// ProbeRaids returns either or not espionage probes have cargo and can attack
+func (s ServerData) ProbeRaids() bool {
+	return s.ProbeCargo > 0
+}
+
+// ValidateCoordinate returns an error when the coordinate is outside of the universe, position 16 being the expedition position
+func (s ServerData) ValidateCoordinate(coord ogame.Coordinate) error {
+	if coord.Galaxy < 1 || (s.Galaxies > 0 && coord.Galaxy > s.Galaxies) {
+		return errors.New("galaxy must be within [1, " + utils.FI64(s.Galaxies) + "]")
+	}
+	if coord.System < 1 || (s.Systems > 0 && coord.System > s.Systems) {
+		return errors.New("system must be within [1, " + utils.FI64(s.Systems) + "]")
+	}
+	if coord.Position < 1 || coord.Position > 16 {
+		return errors.New("position must be within [1, 16]")
+	}
+	return nil
+}
+
 // GetServerData gets the server data from xml api
 func GetServerData(client httpclient.IHttpClient, ctx context.Context, serverNumber int64, serverLang string) (ServerData, error) {
 	var serverData ServerData
@@ -582,6 +688,42 @@ func GetServerData(client httpclient.IHttpClient, ctx context.Context, serverNum
 	return serverData, nil
 }
 
+// GetTopPlayerPoints gets the points of the top 1 player from the public highscore xml api
+// https://s157-ru.ogame.gameforge.com/api/highscore.xml?category=1&type=0
+func GetTopPlayerPoints(client httpclient.IHttpClient, ctx context.Context, serverNumber int64, serverLang string) (int64, error) {
+	var highscore struct {
+		Players []struct {
+			Position int64 `xml:"position,attr"`
+			Score    int64 `xml:"score,attr"`
+		} `xml:"player"`
+	}
+	highscoreURL := "https://s" + utils.FI64(serverNumber) + "-" + serverLang + ".ogame.gameforge.com/api/highscore.xml?category=1&type=0"
+	req, err := http.NewRequest(http.MethodGet, highscoreURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Add("Accept-Encoding", "gzip, deflate, br")
+	req = req.WithContext(ctx)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	by, err := utils.ReadBody(resp)
+	if err != nil {
+		return 0, err
+	}
+	if err := xml.Unmarshal(by, &highscore); err != nil {
+		return 0, fmt.Errorf("failed to xml unmarshal %s : %w", highscoreURL, err)
+	}
+	for _, player := range highscore.Players {
+		if player.Position == 1 {
+			return player.Score, nil
+		}
+	}
+	return 0, errors.New("top player not found")
+}
+
 type Account struct {
 	Server struct {
 		Language string
@@ -3,23 +3,33 @@ package wrapper
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/simulator"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
 	"github.com/alaingilbert/ogame/pkg/utils"
 	echo "github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
 )
 
 // APIResp ...
 type APIResp struct {
-	Status  string
-	Code    int
-	Message string
-	Result  any
+	Status   string
+	Code     int
+	Message  string
+	Result   any
+	Priority string               `json:",omitempty"`
+	Warnings []ogame.ParseWarning `json:",omitempty"` // what could not be parsed, the result may be partially wrong
 }
 
 // SuccessResp ...
@@ -27,11 +37,210 @@ func SuccessResp(data any) APIResp {
 	return APIResp{Status: "ok", Code: 200, Result: data}
 }
 
+// SuccessRespWithWarnings same as SuccessResp, with the parse warnings raised while building data
+func SuccessRespWithWarnings(data any, warnings []ogame.ParseWarning) APIResp {
+	resp := SuccessResp(data)
+	resp.Warnings = warnings
+	return resp
+}
+
 // ErrorResp ...
 func ErrorResp(code int, message string) APIResp {
 	return APIResp{Status: "error", Code: code, Message: message}
 }
 
+// TaskPriorityHeader header used by API callers to choose the priority of the task their request enqueue
+const TaskPriorityHeader = "X-Task-Priority"
+
+// ExpeditionWarningHeader header set when an expedition fleet carries more than the expedition cap
+const ExpeditionWarningHeader = "X-Expedition-Warning"
+
+// ParseTaskPriority parses a task priority name (critical|important|normal|low)
+func ParseTaskPriority(name string) (taskRunner.Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "critical":
+		return taskRunner.Critical, nil
+	case "important":
+		return taskRunner.Important, nil
+	case "", "normal":
+		return taskRunner.Normal, nil
+	case "low":
+		return taskRunner.Low, nil
+	}
+	return 0, errors.New("invalid task priority")
+}
+
+// TaskPriorityName returns the name of a task priority
+func TaskPriorityName(priority taskRunner.Priority) string {
+	switch priority {
+	case taskRunner.Critical:
+		return "critical"
+	case taskRunner.Important:
+		return "important"
+	case taskRunner.Low:
+		return "low"
+	}
+	return "normal"
+}
+
+// TaskPriorityMiddleware validates the X-Task-Priority header and stores the effective priority in the context.
+// The critical priority is only allowed for the routes listed in criticalRoutes (eg: "/bot/fleets/:fleetID/cancel").
+// The effective priority is sent back in the response envelope and in the X-Task-Priority response header.
+func TaskPriorityMiddleware(criticalRoutes []string) echo.MiddlewareFunc {
+	allowed := make(map[string]struct{}, len(criticalRoutes))
+	for _, route := range criticalRoutes {
+		allowed[strings.TrimSpace(route)] = struct{}{}
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			priority, err := ParseTaskPriority(c.Request().Header.Get(TaskPriorityHeader))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+			}
+			if _, ok := allowed[c.Path()]; priority == taskRunner.Critical && !ok {
+				return c.JSON(http.StatusForbidden, ErrorResp(403, "critical priority not allowed for this route"))
+			}
+			c.Set("priority", priority)
+			c.Response().Header().Set(TaskPriorityHeader, TaskPriorityName(priority))
+			return next(&priorityContext{Context: c, priority: TaskPriorityName(priority)})
+		}
+	}
+}
+
+// maintenanceExemptRoutes routes that keep working while the game server is in maintenance,
+// either because they do not send any request to the game server, or because they manage the session (login/logout)
+var maintenanceExemptRoutes = map[string]struct{}{
+	"/bot/ws":                               {},
+	"/bot/info":                             {},
+	"/bot/maintenance":                      {},
+	"/bot/health":                           {},
+	"/bot/login":                            {},
+	"/bot/logout":                           {},
+	"/bot/captcha":                          {},
+	"/bot/captcha/solve":                    {},
+	"/bot/captcha/challenge":                {},
+	"/bot/captcha/question/:challengeID":    {},
+	"/bot/captcha/icons/:challengeID":       {},
+	"/bot/ip":                               {},
+	"/bot/server":                           {},
+	"/bot/server-data":                      {},
+	"/bot/server-url":                       {},
+	"/bot/set-user-agent":                   {},
+	"/bot/set-api-new-hostname":             {},
+	"/bot/language":                         {},
+	"/bot/username":                         {},
+	"/bot/universe-name":                    {},
+	"/bot/server/settings":                  {},
+	"/bot/server/speed":                     {},
+	"/bot/server/speed-fleet":               {},
+	"/bot/server/version":                   {},
+	"/bot/server/time/offset":               {},
+	"/bot/simulate-combat":                  {},
+	"/bot/last-activity":                    {},
+	"/bot/extractor/supported":              {},
+	"/bot/self-test/results":                {},
+	"/bot/character-class":                  {},
+	"/bot/fleets/slots/reservations":        {},
+	"/bot/fleets/slots/reservations/:token": {},
+	"/bot/capabilities":                     {},
+	"/bot/crawler-policy":                   {},
+	"/bot/crawler-policy/status":            {},
+	"/bot/auto-sats":                        {},
+	"/bot/recalls":                          {},
+	"/bot/recalls/:jobID":                   {},
+	"/bot/exposure/alert":                   {},
+	"/bot/price/:ogameID/:nbr":              {},
+	"/bot/requirements/:ogameID":            {},
+	"/bot/objects/:ogameID/rapidfire":       {},
+	"/bot/ipm-needed":                       {},
+	"/bot/expedition-odds":                  {},
+	"/bot/resource-profiles":                {},
+	"/bot/resource-profiles/:name":          {},
+	"/bot/resource-profiles/changes":        {},
+}
+
+// MaintenanceMiddleware responds 503 with a Retry-After hint to the bot routes that hit the game server while it is in maintenance.
+// It must be registered after the authentication middleware.
+func MaintenanceMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		bot, ok := c.Get("bot").(*OGame)
+		if !ok || !bot.IsInMaintenance() || !strings.HasPrefix(c.Path(), "/bot/") {
+			return next(c)
+		}
+		if _, ok := maintenanceExemptRoutes[c.Path()]; ok {
+			return next(c)
+		}
+		retryAfter := int64(math.Ceil(bot.maintenanceRetryAfter().Seconds()))
+		c.Response().Header().Set("Retry-After", utils.FI64(retryAfter))
+		return c.JSON(http.StatusServiceUnavailable, ErrorResp(503, ogame.ErrMaintenance.Error()))
+	}
+}
+
+// priorityContext adds the effective task priority to the APIResp sent by the handlers
+type priorityContext struct {
+	echo.Context
+	priority string
+}
+
+// JSON ...
+func (c *priorityContext) JSON(code int, i any) error {
+	if resp, ok := i.(APIResp); ok {
+		resp.Priority = c.priority
+		i = resp
+	}
+	return c.Context.JSON(code, i)
+}
+
+// taskPriority returns the priority to use for the tasks enqueued by the current request
+func taskPriority(c echo.Context) taskRunner.Priority {
+	if priority, ok := c.Get("priority").(taskRunner.Priority); ok {
+		return priority
+	}
+	return taskRunner.Normal
+}
+
+// notImplementedResp response of the handlers of a feature the server does not support (see GetCapabilities)
+func notImplementedResp(c echo.Context, capability ogame.Capability) error {
+	return c.JSON(http.StatusNotImplemented, ErrorResp(501, "unsupported capability: "+string(capability)))
+}
+
+// coordParam returns the coordinate of the coord parameter (eg: 1:234:8, M:1:234:8, [1:234:8]) when given,
+// of the galaxy/system/position path (or form) parameters otherwise, validated against the universe bounds
+func coordParam(c echo.Context, bot *OGame) (ogame.Coordinate, error) {
+	param := func(name string) string {
+		if v := c.Param(name); v != "" {
+			return v
+		}
+		return c.FormValue(name)
+	}
+	coord := ogame.Coordinate{Type: ogame.PlanetType}
+	if s := c.FormValue("coord"); s != "" {
+		var err error
+		if coord, err = ogame.ParseCoord(s); err != nil {
+			return coord, errors.New("invalid coord")
+		}
+	} else {
+		var err error
+		if coord.Galaxy, err = utils.ParseI64(param("galaxy")); err != nil {
+			return coord, errors.New("invalid galaxy")
+		}
+		if coord.System, err = utils.ParseI64(param("system")); err != nil {
+			return coord, errors.New("invalid system")
+		}
+		if coord.Position, err = utils.ParseI64(param("position")); err != nil {
+			return coord, errors.New("invalid position")
+		}
+	}
+	return coord, bot.serverData.ValidateCoordinate(coord)
+}
+
+// GetCapabilitiesHandler returns the features supported by the server and the bot
+// curl 127.0.0.1:1234/bot/capabilities
+func GetCapabilitiesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetCapabilities()))
+}
+
 // HomeHandler ...
 func HomeHandler(c echo.Context) error {
 	version := c.Get("version").(string)
@@ -44,12 +253,35 @@ func HomeHandler(c echo.Context) error {
 	})
 }
 
+// BotInfoHandler returns runtime information about the bot, without sending any game request
+// curl 127.0.0.1:1234/bot/info
+func BotInfoHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.Info()))
+}
+
 // TasksHandler return how many tasks are queued in the heap.
 func TasksHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	return c.JSON(http.StatusOK, SuccessResp(bot.GetTasks()))
 }
 
+// metricsPrefix prefix of the expvar counters published by the bot
+const metricsPrefix = "ogame_"
+
+// DebugVarsHandler publishes the bot counters in the expvar format.
+// The other expvar variables are left out, "cmdline" would leak the credentials given as flags.
+// curl 127.0.0.1:1234/debug/vars
+func DebugVarsHandler(c echo.Context) error {
+	vars := make(map[string]json.RawMessage)
+	expvar.Do(func(kv expvar.KeyValue) {
+		if strings.HasPrefix(kv.Key, metricsPrefix) {
+			vars[kv.Key] = json.RawMessage(kv.Value.String())
+		}
+	})
+	return c.JSON(http.StatusOK, vars)
+}
+
 // GetServerHandler ...
 func GetServerHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -62,6 +294,78 @@ func GetServerDataHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, SuccessResp(bot.serverData))
 }
 
+// GetServerSettingsHandler returns the gameplay settings of the universe
+// curl 127.0.0.1:1234/bot/server/settings
+func GetServerSettingsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetServerSettings()))
+}
+
+// Upper bounds of one SimulateCombatHandler request, the cost of a simulation grows with the units in the battle
+const (
+	maxCombatSimulations     = 1000
+	maxCombatUnits           = 300_000   // Ships and defenses of both sides
+	maxCombatUnitSimulations = 3_000_000 // Units multiplied by the simulations
+)
+
+// combatUnits returns the count of units of both sides of a battle, or false if a count is negative or too large
+func combatUnits(attacker simulator.Attacker, defender simulator.Defender) (int64, bool) {
+	var total int64
+	for _, ship := range ogame.Ships {
+		for _, nbr := range []int64{attacker.ByID(ship.GetID()), defender.ShipsInfos.ByID(ship.GetID())} {
+			if nbr < 0 || nbr > maxCombatUnits {
+				return 0, false
+			}
+			total += nbr
+		}
+	}
+	for _, defense := range ogame.Defenses {
+		nbr := defender.DefensesInfos.ByID(defense.GetID())
+		if nbr < 0 || nbr > maxCombatUnits {
+			return 0, false
+		}
+		total += nbr
+	}
+	return total, total <= maxCombatUnits
+}
+
+// SimulateCombatHandler simulates a battle offline with the universe debris settings, nothing is sent to the server.
+// Seed makes the result reproducible and Logs returns every shot of a single simulation.
+// curl 127.0.0.1:1234/bot/simulate-combat -d '{"Attacker":{"Weapon":10,"Shield":10,"Armour":10,"Cruiser":50},"Defender":{"RocketLauncher":200},"Simulations":100,"Seed":42}'
+func SimulateCombatHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	var req struct {
+		Attacker    simulator.Attacker
+		Defender    simulator.Defender
+		Simulations int
+		Seed        int64
+		Logs        bool
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid json body"))
+	}
+	if req.Simulations == 0 {
+		req.Simulations = 1
+	}
+	if req.Simulations < 0 || req.Simulations > maxCombatSimulations {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid simulations"))
+	}
+	if req.Logs && req.Simulations > 1 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "logs are only available for a single simulation"))
+	}
+	units, ok := combatUnits(req.Attacker, req.Defender)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid units, at most "+utils.FI64(maxCombatUnits)+" in the battle"))
+	}
+	if units*int64(req.Simulations) > maxCombatUnitSimulations {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "too many units for this number of simulations"))
+	}
+	params := bot.GetServerSettings().SimulatorParams(req.Simulations)
+	params.Seed = req.Seed
+	params.IsLogging = req.Logs
+	return c.JSON(http.StatusOK, SuccessResp(simulator.Simulate(req.Attacker, req.Defender, params)))
+}
+
 // SetUserAgentHandler ...
 // curl 127.0.0.1:1234/bot/set-user-agent -d 'userAgent="New user agent"'
 func SetUserAgentHandler(c echo.Context) error {
@@ -71,6 +375,16 @@ func SetUserAgentHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
+// SetAPINewHostnameHandler changes the hostname used to rewrite proxied pages
+// curl 127.0.0.1:1234/bot/set-api-new-hostname -d 'hostname=https://someuniverse.example.com'
+func SetAPINewHostnameHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	if err := bot.SetAPINewHostname(c.Request().PostFormValue("hostname")); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
 // ServerURLHandler ...
 func ServerURLHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -90,14 +404,14 @@ func PageContentHandler(c echo.Context) error {
 	if err := c.Request().ParseForm(); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	pageHTML, _ := bot.GetPageContent(c.Request().Form)
+	pageHTML, _ := bot.WithPriority(taskPriority(c)).GetPageContent(c.Request().Form)
 	return c.JSON(http.StatusOK, SuccessResp(pageHTML))
 }
 
 // LoginHandler ...
 func LoginHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	if _, err := bot.LoginWithExistingCookies(); err != nil {
+	if _, err := bot.WithPriority(taskPriority(c)).LoginWithExistingCookies(); err != nil {
 		if err == ogame.ErrBadCredentials {
 			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 		}
@@ -109,7 +423,7 @@ func LoginHandler(c echo.Context) error {
 // LogoutHandler ...
 func LogoutHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	bot.Logout()
+	bot.WithPriority(taskPriority(c)).Logout()
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
@@ -143,22 +457,136 @@ func ServerVersionHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, SuccessResp(bot.serverData.Version))
 }
 
+// GetMaintenanceHandler returns either or not the game server is in maintenance, and the maintenance periods detected
+// curl 127.0.0.1:1234/bot/maintenance
+func GetMaintenanceHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"InMaintenance": bot.IsInMaintenance(),
+		"Windows":       bot.GetMaintenanceWindows(),
+	}))
+}
+
+// GetSupportedExtractorsHandler returns the game versions supported by each extractor
+// curl 127.0.0.1:1234/bot/extractor/supported
+func GetSupportedExtractorsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"ServerVersion":     bot.serverData.Version,
+		"CompatibilityMode": bot.IsCompatibilityMode(),
+		"Extractors":        SupportedExtractors,
+	}))
+}
+
+// HealthHandler ...
+// curl 127.0.0.1:1234/bot/health
+func HealthHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"Enabled":           bot.IsEnabled(),
+		"LoggedIn":          bot.IsLoggedIn(),
+		"Connected":         bot.IsConnected(),
+		"InMaintenance":     bot.IsInMaintenance(),
+		"CompatibilityMode": bot.IsCompatibilityMode(),
+		"ServerVersion":     bot.serverData.Version,
+	}))
+}
+
 // ServerTimeHandler ...
 func ServerTimeHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	return c.JSON(http.StatusOK, SuccessResp(bot.ServerTime()))
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).ServerTime()))
+}
+
+// GetEffectiveSpeedsHandler returns the speeds currently applied by the universe, including the temporary modifiers
+// curl 127.0.0.1:1234/bot/server/effective-speeds
+func GetEffectiveSpeedsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	speeds, err := bot.WithPriority(taskPriority(c)).GetEffectiveSpeeds()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(speeds))
+}
+
+// ServerTimeOffsetHandler returns the offset (milliseconds) between the server clock and the bot clock,
+// for clients that want to coordinate actions on the server time themselves
+// curl 127.0.0.1:1234/bot/server/time/offset
+func ServerTimeOffsetHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"Offset":     bot.GetServerTimeOffset().Milliseconds(),
+		"ServerTime": bot.serverNow(),
+	}))
+}
+
+// GetLastActivityHandler returns when the account last performed an action visible to other players
+// curl 127.0.0.1:1234/bot/last-activity
+func GetLastActivityHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	lastActivity, err := bot.GetLastActivity()
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"LastActivity": lastActivity,
+		"Elapsed":      int64(bot.clock.Since(lastActivity).Seconds()),
+	}))
 }
 
 // IsUnderAttackHandler ...
 func IsUnderAttackHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	isUnderAttack, err := bot.IsUnderAttack()
+	isUnderAttack, err := bot.WithPriority(taskPriority(c)).IsUnderAttack()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(isUnderAttack))
 }
 
+// GetSpyReportSettingsHandler returns the espionage preferences of the account
+// curl 127.0.0.1:1234/bot/settings/spy-report
+func GetSpyReportSettingsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	settings, err := bot.WithPriority(taskPriority(c)).GetSpyReportSettings()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(settings))
+}
+
+// SetSpyReportSettingsHandler changes the espionage preferences of the account, omitted fields are kept
+// curl 127.0.0.1:1234/bot/settings/spy-report -d 'probesCount=5&showPictures=1'
+func SetSpyReportSettingsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	prefs := bot.GetCachedPreferences()
+	settings := ogame.SpyReportSettings{ProbesCount: prefs.SpioAnz, ShowPictures: prefs.SpioReportPictures}
+	if s := c.Request().PostFormValue("probesCount"); s != "" {
+		probesCount, err := utils.ParseI64(s)
+		if err != nil || probesCount < 1 || probesCount > 99 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid probesCount"))
+		}
+		settings.ProbesCount = probesCount
+	}
+	if s := c.Request().PostFormValue("showPictures"); s != "" {
+		settings.ShowPictures = s == "1" || s == "true"
+	}
+	if err := bot.WithPriority(taskPriority(c)).SetSpyReportSettings(settings); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(settings))
+}
+
+// GetSelfTestResultsHandler returns the latest extraction self-test runs, most recent first
+// curl 127.0.0.1:1234/bot/self-test/results
+func GetSelfTestResultsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"Schedule": bot.GetSelfTestSchedule(),
+		"Results":  bot.GetSelfTestResults(),
+	}))
+}
+
 // IsVacationModeHandler ...
 func IsVacationModeHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -169,7 +597,7 @@ func IsVacationModeHandler(c echo.Context) error {
 // GetUserInfosHandler ...
 func GetUserInfosHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	return c.JSON(http.StatusOK, SuccessResp(bot.GetUserInfos()))
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).GetUserInfos()))
 }
 
 // GetCharacterClassHandler ...
@@ -216,7 +644,7 @@ func HasTechnocratHandler(c echo.Context) error {
 // GetEspionageReportMessagesHandler ...
 func GetEspionageReportMessagesHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	report, err := bot.GetEspionageReportMessages()
+	report, err := bot.WithPriority(taskPriority(c)).GetEspionageReportMessages()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
@@ -230,7 +658,7 @@ func GetEspionageReportHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid msgid id"))
 	}
-	espionageReport, err := bot.GetEspionageReport(msgID)
+	espionageReport, err := bot.WithPriority(taskPriority(c)).GetEspionageReport(msgID)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
@@ -238,7 +666,42 @@ func GetEspionageReportHandler(c echo.Context) error {
 }
 
 // GetEspionageReportForHandler ...
+// curl 127.0.0.1:1234/bot/espionage-report/1/2/3
+// curl '127.0.0.1:1234/bot/espionage-report/by-coord?coord=M:1:2:3'
 func GetEspionageReportForHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	coord, err := coordParam(c, bot)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	planet, err := bot.WithPriority(taskPriority(c)).GetEspionageReportFor(coord)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(planet))
+}
+
+// GetEspionageReportDiffHandler returns what changed at a target between its two most recent espionage reports
+// curl '127.0.0.1:1234/bot/espionage-report/diff?coord=1:2:3'
+func GetEspionageReportDiffHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	coord, err := coordParam(c, bot)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	diff, err := bot.WithPriority(taskPriority(c)).GetEspionageReportDiff(coord)
+	if err != nil {
+		if errors.Is(err, ogame.ErrNotEnoughEspionageReports) {
+			return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(diff))
+}
+
+// AttacksRemainingAgainstHandler returns how many attacks can still be sent to a target before crossing the bashing limit
+// curl '127.0.0.1:1234/bot/bashing/1/2/3?type=3'
+func AttacksRemainingAgainstHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	galaxy, err := utils.ParseI64(c.Param("galaxy"))
 	if err != nil {
@@ -252,11 +715,24 @@ func GetEspionageReportForHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
 	}
-	planet, err := bot.GetEspionageReportFor(ogame.Coordinate{Type: ogame.PlanetType, Galaxy: galaxy, System: system, Position: position})
+	planetType := ogame.PlanetType
+	if typ := c.QueryParam("type"); typ != "" {
+		planetType = ogame.CelestialType(utils.DoParseI64(typ))
+		if planetType != ogame.PlanetType && planetType != ogame.MoonType {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid type"))
+		}
+	}
+	target := ogame.Coordinate{Type: planetType, Galaxy: galaxy, System: system, Position: position}
+	remaining, resetAt, err := bot.WithPriority(taskPriority(c)).AttacksRemainingAgainst(target)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(planet))
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"Target":    target,
+		"Limit":     ogame.BashingLimit,
+		"Remaining": remaining,
+		"ResetAt":   resetAt,
+	}))
 }
 
 // SendMessageHandler ...
@@ -268,142 +744,452 @@ func SendMessageHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	message := c.Request().PostFormValue("message")
-	if err := bot.SendMessage(playerID, message); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).SendMessage(playerID, message); err != nil {
 		if err.Error() == "invalid parameters" {
 			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 		}
+		if err == ogame.ErrIgnoredUser {
+			return c.JSON(http.StatusForbidden, ErrorResp(403, err.Error()))
+		}
+		if err == ogame.ErrSendMessageFailed {
+			return c.JSON(http.StatusBadGateway, ErrorResp(502, err.Error()))
+		}
+		if errors.Is(err, ogame.ErrThrottled) {
+			return c.JSON(http.StatusTooManyRequests, ErrorResp(429, err.Error()))
+		}
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
+// SendMessagesHandler sends the same message to several players, paced to avoid the game anti-spam.
+// Cancelling the request stops the sending, the number of processed recipients is reported.
+// curl 127.0.0.1:1234/bot/send-messages -d 'playerIDs=123,456&message="Sup boi!"'
+func SendMessagesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	playerIDs := make([]int64, 0)
+	for _, s := range strings.Split(c.Request().PostFormValue("playerIDs"), ",") {
+		playerID, err := utils.ParseI64(strings.TrimSpace(s))
+		if err != nil || playerID < 1 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid playerIDs"))
+		}
+		playerIDs = append(playerIDs, playerID)
+	}
+	message := c.Request().PostFormValue("message")
+	if message == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "empty message"))
+	}
+	statuses, err := bot.SendMessages(c.Request().Context(), playerIDs, message)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()+", "+utils.FI64(int64(len(statuses)))+" processed"))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(statuses))
+}
+
 // GetFleetsHandler ...
+// curl '127.0.0.1:1234/bot/fleets?combatReports=1'
 func GetFleetsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	fleets, _ := bot.GetFleets()
+	opts := make([]Option, 0)
+	if c.QueryParam("combatReports") == "1" {
+		opts = append(opts, WithCombatReports)
+	}
+	fleets, _ := bot.WithPriority(taskPriority(c)).GetFleets(opts...)
 	return c.JSON(http.StatusOK, SuccessResp(fleets))
 }
 
-// GetSlotsHandler ...
-func GetSlotsHandler(c echo.Context) error {
+// GetFleetsScheduleHandler returns the active fleets as timeline entries, sorted by departure
+// curl 127.0.0.1:1234/bot/fleets/schedule
+func GetFleetsScheduleHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	slots := bot.GetSlots()
-	return c.JSON(http.StatusOK, SuccessResp(slots))
+	fleets, _ := bot.WithPriority(taskPriority(c)).GetFleets()
+	return c.JSON(http.StatusOK, SuccessResp(ogame.NewFleetSchedule(fleets)))
 }
 
-// CancelFleetHandler ...
-func CancelFleetHandler(c echo.Context) error {
+// GetFriendlyArrivalsHandler returns the incoming fleets of other players that are not hostile
+// curl 127.0.0.1:1234/bot/fleets/incoming-friendly
+func GetFriendlyArrivalsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	fleetID, err := utils.ParseI64(c.Param("fleetID"))
+	arrivals, err := bot.WithPriority(taskPriority(c)).GetFriendlyArrivals()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(bot.CancelFleet(ogame.FleetID(fleetID))))
+	return c.JSON(http.StatusOK, SuccessResp(arrivals))
 }
 
-// GetAttacksHandler ...
-func GetAttacksHandler(c echo.Context) error {
+// GetSpiedEventsHandler returns the espionage actions of other players on our celestials.
+// since is a unix timestamp or a RFC3339 date, all the espionage actions in the messages are returned if omitted.
+// curl 127.0.0.1:1234/bot/espionage/incoming?since=1652184000
+func GetSpiedEventsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	attacks, err := bot.GetAttacks()
+	var since time.Time
+	if sinceStr := c.QueryParam("since"); sinceStr != "" {
+		if ts, err := utils.ParseI64(sinceStr); err == nil {
+			since = time.Unix(ts, 0)
+		} else if since, err = time.Parse(time.RFC3339, sinceStr); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid since"))
+		}
+	}
+	events, err := bot.WithPriority(taskPriority(c)).GetSpiedEvents(since)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(attacks))
+	return c.JSON(http.StatusOK, SuccessResp(events))
 }
 
-// GalaxyInfosHandler ...
-func GalaxyInfosHandler(c echo.Context) error {
+// GetSlotsHandler ...
+func GetSlotsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	galaxy, err := utils.ParseI64(c.Param("galaxy"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	slots := bot.WithPriority(taskPriority(c)).GetSlots()
+	return c.JSON(http.StatusOK, SuccessResp(struct {
+		ogame.Slots
+		Reservations []SlotReservation
+	}{slots, bot.GetSlotReservations()}))
+}
+
+// ReserveSlotsHandler keeps fleet slots free, fleets sent with the returned token (slotToken) can use them.
+// owner is a label shown in the reservations list.
+// curl 127.0.0.1:1234/bot/fleets/slots/reservations -d 'owner=expeditions&slots=2&ttl=3600'
+func ReserveSlotsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	nbr, err := utils.ParseI64(c.FormValue("slots"))
+	if err != nil || nbr < 1 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid slots"))
 	}
-	system, err := utils.ParseI64(c.Param("system"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	ttl, err := utils.ParseI64(c.FormValue("ttl"))
+	if err != nil || ttl < 1 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ttl"))
 	}
-	res, err := bot.GalaxyInfos(galaxy, system)
+	reservation, err := bot.ReserveSlots(c.FormValue("owner"), nbr, time.Duration(ttl)*time.Second)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessResp(reservation))
 }
 
-// GetResearchHandler ...
-func GetResearchHandler(c echo.Context) error {
+// ReleaseSlotsHandler removes a slots reservation, given its token
+// curl -X DELETE 127.0.0.1:1234/bot/fleets/slots/reservations/0123456789abcdef0123456789abcdef
+func ReleaseSlotsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	return c.JSON(http.StatusOK, SuccessResp(bot.GetResearch()))
+	if err := bot.ReleaseSlots(c.Param("token")); err != nil {
+		return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
-// BuyOfferOfTheDayHandler ...
-func BuyOfferOfTheDayHandler(c echo.Context) error {
+// CancelFleetHandler ...
+func CancelFleetHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	if err := bot.BuyOfferOfTheDay(); err != nil {
+	fleetID, err := utils.ParseI64(c.Param("fleetID"))
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).CancelFleet(ogame.FleetID(fleetID))))
 }
 
-// GetMoonsHandler ...
-func GetMoonsHandler(c echo.Context) error {
+// GetAttacksHandler ...
+func GetAttacksHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	return c.JSON(http.StatusOK, SuccessResp(bot.GetMoons()))
+	attacks, err := bot.WithPriority(taskPriority(c)).GetAttacks()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(attacks))
 }
 
-// GetMoonHandler ...
-func GetMoonHandler(c echo.Context) error {
+// GetEventListHandler returns every event of the event list
+// curl 127.0.0.1:1234/bot/events
+func GetEventListHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	moonID, err := utils.ParseI64(c.Param("moonID"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
-	}
-	moon, err := bot.GetMoon(moonID)
+	events, err := bot.WithPriority(taskPriority(c)).GetEventList()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(moon))
+	return c.JSON(http.StatusOK, SuccessResp(events))
 }
 
-// GetMoonByCoordHandler ...
-func GetMoonByCoordHandler(c echo.Context) error {
+// CreateUnionHandler creates a union for one of our attacking fleets, and returns the new union id
+// curl 127.0.0.1:1234/bot/acs/unions -d 'fleetID=123&users=Player1&users=Player2'
+func CreateUnionHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	galaxy, err := utils.ParseI64(c.Param("galaxy"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
-	}
-	system, err := utils.ParseI64(c.Param("system"))
+	fleetID, err := utils.ParseI64(c.Request().PostFormValue("fleetID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid system"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid fleet id"))
 	}
-	position, err := utils.ParseI64(c.Param("position"))
+	users := c.Request().PostForm["users"]
+	fleet, err := bot.WithPriority(taskPriority(c)).GetFleet(ogame.FleetID(fleetID))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+		if err == ogame.ErrFleetNotFound {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	planet, err := bot.GetMoon(ogame.Coordinate{Type: ogame.MoonType, Galaxy: galaxy, System: system, Position: position})
+	unionID, err := bot.WithPriority(taskPriority(c)).CreateUnion(fleet, users)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(planet))
-}
-
-// GetPlanetsHandler ...
-func GetPlanetsHandler(c echo.Context) error {
-	bot := c.Get("bot").(*OGame)
-	return c.JSON(http.StatusOK, SuccessResp(bot.GetPlanets()))
+	return c.JSON(http.StatusOK, SuccessResp(unionID))
 }
 
-// GetCelestialItemsHandler ...
-func GetCelestialItemsHandler(c echo.Context) error {
+// GetACSUnionDetailsHandler ...
+// curl 127.0.0.1:1234/bot/acs/unions/123
+func GetACSUnionDetailsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	celestialID, err := utils.ParseI64(c.Param("celestialID"))
+	unionID, err := utils.ParseI64(c.Param("unionID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid union id"))
 	}
-	items, err := bot.GetItems(ogame.CelestialID(celestialID))
+	details, err := bot.WithPriority(taskPriority(c)).GetACSUnionDetails(unionID)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		if errors.Is(err, ogame.ErrUnionNotFound) {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(items))
+	return c.JSON(http.StatusOK, SuccessResp(details))
+}
+
+// GalaxyInfosHandler ...
+// curl 127.0.0.1:1234/bot/galaxy-infos/1/2
+// curl '127.0.0.1:1234/bot/galaxy-infos?coord=1:2:3' (the position is ignored)
+// relations=1 tags the positions that are mine/allied/enemy. With any of the filters inactive, vacation, moon, debris
+// (1 or 0) and relation (mine, allied, enemy), only the matching planets are returned
+// curl '127.0.0.1:1234/bot/galaxy-infos/1/2?moon=1&inactive=1'
+func GalaxyInfosHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	var galaxy, system int64
+	if c.FormValue("coord") != "" {
+		coord, err := coordParam(c, bot)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		galaxy, system = coord.Galaxy, coord.System
+	} else {
+		var err error
+		if galaxy, err = utils.ParseI64(c.Param("galaxy")); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		if system, err = utils.ParseI64(c.Param("system")); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+	}
+	pred, err := galaxyFilterParam(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	var opts []Option
+	if c.QueryParam("relations") == "1" || c.QueryParam("relation") != "" {
+		opts = append(opts, TagRelations)
+	}
+	res, err := bot.WithPriority(taskPriority(c)).GalaxyInfos(galaxy, system, opts...)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	if pred != nil {
+		return c.JSON(http.StatusOK, SuccessResp(ogame.FilterGalaxy(res, pred)))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(res))
+}
+
+// galaxyFilterParam returns the predicate of the galaxy filters query params, nil when no filter is given
+func galaxyFilterParam(c echo.Context) (func(ogame.PlanetInfos) bool, error) {
+	preds := make([]func(ogame.PlanetInfos) bool, 0)
+	flags := []struct {
+		name string
+		get  func(ogame.PlanetInfos) bool
+	}{
+		{"inactive", func(p ogame.PlanetInfos) bool { return p.Inactive }},
+		{"vacation", func(p ogame.PlanetInfos) bool { return p.Vacation }},
+		{"moon", func(p ogame.PlanetInfos) bool { return p.Moon != nil }},
+		{"debris", func(p ogame.PlanetInfos) bool { return p.Debris.Metal+p.Debris.Crystal > 0 }},
+	}
+	for _, flag := range flags {
+		flag := flag
+		switch c.QueryParam(flag.name) {
+		case "":
+		case "1":
+			preds = append(preds, flag.get)
+		case "0":
+			preds = append(preds, func(p ogame.PlanetInfos) bool { return !flag.get(p) })
+		default:
+			return nil, errors.New("invalid " + flag.name)
+		}
+	}
+	if relation := ogame.GalaxyRelation(c.QueryParam("relation")); relation != "" {
+		if relation != ogame.RelationMine && relation != ogame.RelationAllied && relation != ogame.RelationEnemy {
+			return nil, errors.New("invalid relation")
+		}
+		preds = append(preds, func(p ogame.PlanetInfos) bool { return p.Relation == relation })
+	}
+	if len(preds) == 0 {
+		return nil, nil
+	}
+	return func(p ogame.PlanetInfos) bool {
+		for _, pred := range preds {
+			if !pred(p) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// FindColonizationSlotsHandler ...
+// curl 127.0.0.1:1234/bot/find-colony-slots -d 'galaxy=1&fromSystem=100&toSystem=140&positions=7&positions=8&positions=9'
+func FindColonizationSlotsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	galaxy, err := utils.ParseI64(c.FormValue("galaxy"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
+	}
+	fromSystem, err := utils.ParseI64(c.FormValue("fromSystem"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid fromSystem"))
+	}
+	toSystem, err := utils.ParseI64(c.FormValue("toSystem"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid toSystem"))
+	}
+	payload, _ := c.FormParams()
+	positions := make([]int64, 0)
+	for _, s := range payload["positions"] {
+		position, err := utils.ParseI64(s)
+		if err != nil || position < 1 || position > 15 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position "+s))
+		}
+		positions = append(positions, position)
+	}
+	slots, err := bot.WithPriority(taskPriority(c)).FindColonizationSlots(galaxy, fromSystem, toSystem, positions)
+	if err != nil {
+		if errors.Is(err, ogame.ErrSystemRangeTooLarge) {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(slots))
+}
+
+// GetResearchHandler ...
+func GetResearchHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).GetResearch()))
+}
+
+// GetEffectiveLabLevelHandler returns the summed lab level used by the intergalactic research network
+// curl 127.0.0.1:1234/bot/planets/123/research/effective-lab
+func GetEffectiveLabLevelHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	level, err := bot.WithPriority(taskPriority(c)).GetEffectiveLabLevel(ogame.PlanetID(planetID))
+	if errors.Is(err, ogame.ErrInvalidPlanetID) {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	} else if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(level))
+}
+
+// BuyOfferOfTheDayHandler ...
+func BuyOfferOfTheDayHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	if err := bot.WithPriority(taskPriority(c)).BuyOfferOfTheDay(); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
+// GetMoonsHandler ...
+func GetMoonsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).GetMoons()))
+}
+
+// GetMoonHandler ...
+func GetMoonHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	moonID, err := utils.ParseI64(c.Param("moonID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
+	}
+	moon, err := bot.WithPriority(taskPriority(c)).GetMoon(moonID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(moon))
+}
+
+// GetMoonByCoordHandler ...
+func GetMoonByCoordHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	galaxy, err := utils.ParseI64(c.Param("galaxy"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
+	}
+	system, err := utils.ParseI64(c.Param("system"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid system"))
+	}
+	position, err := utils.ParseI64(c.Param("position"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+	}
+	planet, err := bot.WithPriority(taskPriority(c)).GetMoon(ogame.Coordinate{Type: ogame.MoonType, Galaxy: galaxy, System: system, Position: position})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(planet))
+}
+
+// GetPlanetsHandler ...
+func GetPlanetsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.WithPriority(taskPriority(c)).GetPlanets()))
+}
+
+// GetNotificationsHandler returns the active notifications of the top bar
+// curl 127.0.0.1:1234/bot/notifications
+func GetNotificationsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	notifications, err := bot.WithPriority(taskPriority(c)).GetNotifications()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(notifications))
+}
+
+// GetCelestialItemsHandler ...
+func GetCelestialItemsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	celestialID, err := utils.ParseI64(c.Param("celestialID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
+	}
+	items, err := bot.WithPriority(taskPriority(c)).GetItems(ogame.CelestialID(celestialID))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(items))
+}
+
+// GetCelestialImageHandler returns the image of a celestial, proxied from the game CDN
+// curl 127.0.0.1:1234/bot/celestials/123/image
+func GetCelestialImageHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	celestialID, err := utils.ParseI64(c.Param("celestialID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
+	}
+	img, contentType, err := bot.GetCelestialImage(ogame.CelestialID(celestialID))
+	if errors.Is(err, ogame.ErrInvalidPlanetID) {
+		return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+	} else if err != nil {
+		return c.JSON(http.StatusBadGateway, ErrorResp(502, err.Error()))
+	}
+	c.Response().Header().Set("Cache-Control", "public, max-age=86400")
+	return c.Blob(http.StatusOK, contentType, img)
 }
 
 // ActivateCelestialItemHandler ...
@@ -414,7 +1200,7 @@ func ActivateCelestialItemHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
 	}
 	ref := c.Param("itemRef")
-	if err := bot.ActivateItem(ref, ogame.CelestialID(celestialID)); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).ActivateItem(ref, ogame.CelestialID(celestialID)); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -427,7 +1213,7 @@ func GetPlanetHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	planet, err := bot.GetPlanet(ogame.PlanetID(planetID))
+	planet, err := bot.WithPriority(taskPriority(c)).GetPlanet(ogame.PlanetID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
@@ -435,7 +1221,28 @@ func GetPlanetHandler(c echo.Context) error {
 }
 
 // GetPlanetByCoordHandler ...
+// curl 127.0.0.1:1234/bot/planets/1/2/3
+// curl '127.0.0.1:1234/bot/planets/by-coord?coord=1:2:3'
 func GetPlanetByCoordHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	coord, err := coordParam(c, bot)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	planet, err := bot.WithPriority(taskPriority(c)).GetPlanet(coord.Planet())
+	var ambiguousErr *ogame.ErrAmbiguousCoordinate
+	if errors.As(err, &ambiguousErr) {
+		return c.JSON(http.StatusConflict, ErrorResp(409, err.Error()))
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(planet))
+}
+
+// GetPlanetsByCoordHandler returns all the planets at a coordinate (the old and new planets during a relocation)
+// curl 127.0.0.1:1234/bot/planets/by-coord/1/2/3/all
+func GetPlanetsByCoordHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	galaxy, err := utils.ParseI64(c.Param("galaxy"))
 	if err != nil {
@@ -449,11 +1256,11 @@ func GetPlanetByCoordHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
 	}
-	planet, err := bot.GetPlanet(ogame.Coordinate{Type: ogame.PlanetType, Galaxy: galaxy, System: system, Position: position})
+	celestials, err := bot.WithPriority(taskPriority(c)).GetCelestialsByCoord(ogame.Coordinate{Type: ogame.PlanetType, Galaxy: galaxy, System: system, Position: position})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(planet))
+	return c.JSON(http.StatusOK, SuccessResp(celestials))
 }
 
 // GetResourcesDetailsHandler ...
@@ -463,13 +1270,28 @@ func GetResourcesDetailsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	resources, err := bot.GetResourcesDetails(ogame.CelestialID(planetID))
+	resources, err := bot.WithPriority(taskPriority(c)).GetResourcesDetails(ogame.CelestialID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(resources))
 }
 
+// GetWastedResourcesHandler returns the resources lost to full storages since the previous call
+// curl 127.0.0.1:1234/bot/planets/123/wasted
+func GetWastedResourcesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	wasted, err := bot.WithPriority(taskPriority(c)).GetWastedResources(ogame.CelestialID(planetID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(wasted))
+}
+
 // GetResourceSettingsHandler ...
 func GetResourceSettingsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -477,13 +1299,38 @@ func GetResourceSettingsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetResourceSettings(ogame.PlanetID(planetID))
+	res, err := bot.WithPriority(taskPriority(c)).GetResourceSettings(ogame.PlanetID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(res))
 }
 
+// resourceSettingsParam reads the resource settings (percentages) posted in the form
+func resourceSettingsParam(c echo.Context) (ogame.ResourceSettings, error) {
+	var settings ogame.ResourceSettings
+	fields := []struct {
+		name  string
+		value *int64
+	}{
+		{"metalMine", &settings.MetalMine},
+		{"crystalMine", &settings.CrystalMine},
+		{"deuteriumSynthesizer", &settings.DeuteriumSynthesizer},
+		{"solarPlant", &settings.SolarPlant},
+		{"fusionReactor", &settings.FusionReactor},
+		{"solarSatellite", &settings.SolarSatellite},
+		{"crawler", &settings.Crawler},
+	}
+	for _, field := range fields {
+		value, err := utils.ParseI64(c.Request().PostFormValue(field.name))
+		if err != nil {
+			return settings, errors.New("invalid " + field.name)
+		}
+		*field.value = value
+	}
+	return settings, nil
+}
+
 // SetResourceSettingsHandler ...
 // curl 127.0.0.1:1234/bot/planets/123/resource-settings -d 'metalMine=100&crystalMine=100&deuteriumSynthesizer=100&solarPlant=100&fusionReactor=100&solarSatellite=100'
 func SetResourceSettingsHandler(c echo.Context) error {
@@ -492,60 +1339,108 @@ func SetResourceSettingsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	metalMine, err := utils.ParseI64(c.Request().PostFormValue("metalMine"))
+	settings, err := resourceSettingsParam(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid metalMine"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	crystalMine, err := utils.ParseI64(c.Request().PostFormValue("crystalMine"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid crystalMine"))
+	if err := bot.WithPriority(taskPriority(c)).SetResourceSettings(ogame.PlanetID(planetID), settings); err != nil {
+		if err == ogame.ErrInvalidPlanetID {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	deuteriumSynthesizer, err := utils.ParseI64(c.Request().PostFormValue("deuteriumSynthesizer"))
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
+// SetResourceProfileHandler ...
+// curl -X PUT 127.0.0.1:1234/bot/resource-profiles/night -d 'metalMine=100&crystalMine=100&deuteriumSynthesizer=100&solarPlant=100&fusionReactor=0&solarSatellite=100&crawler=0'
+func SetResourceProfileHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	settings, err := resourceSettingsParam(c)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid deuteriumSynthesizer"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	solarPlant, err := utils.ParseI64(c.Request().PostFormValue("solarPlant"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid solarPlant"))
+	if err := bot.SetResourceProfile(c.Param("name"), settings); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	fusionReactor, err := utils.ParseI64(c.Request().PostFormValue("fusionReactor"))
+	return c.JSON(http.StatusOK, SuccessResp(settings))
+}
+
+// GetResourceProfilesHandler ...
+func GetResourceProfilesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetResourceProfiles()))
+}
+
+// ApplyResourceProfileHandler ...
+// curl -X POST 127.0.0.1:1234/bot/planets/123/apply-profile/night
+func ApplyResourceProfileHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid fusionReactor"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	solarSatellite, err := utils.ParseI64(c.Request().PostFormValue("solarSatellite"))
+	change, err := bot.ApplyResourceProfile(ogame.PlanetID(planetID), c.Param("name"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid solarSatellite"))
+		if err == ogame.ErrResourceProfileNotFound {
+			return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+		} else if err == ogame.ErrInvalidPlanetID {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	crawler, err := utils.ParseI64(c.Request().PostFormValue("crawler"))
+	return c.JSON(http.StatusOK, SuccessResp(change))
+}
+
+// ApplyResourceProfileAllHandler applies a profile to every planet, one planet at a time
+// curl -X POST 127.0.0.1:1234/bot/apply-profile-all/night
+func ApplyResourceProfileAllHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	changes, err := bot.ApplyResourceProfileAll(c.Request().Context(), c.Param("name"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid crawler"))
+		if err == ogame.ErrResourceProfileNotFound {
+			return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	settings := ogame.ResourceSettings{
-		MetalMine:            metalMine,
-		CrystalMine:          crystalMine,
-		DeuteriumSynthesizer: deuteriumSynthesizer,
-		SolarPlant:           solarPlant,
-		FusionReactor:        fusionReactor,
-		SolarSatellite:       solarSatellite,
-		Crawler:              crawler,
+	return c.JSON(http.StatusOK, SuccessResp(changes))
+}
+
+// GetResourceProfileChangesHandler ...
+func GetResourceProfileChangesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetResourceProfileChanges()))
+}
+
+// RevertResourceProfileHandler restores the resource settings a planet had before the last profile applied on it
+// curl -X POST 127.0.0.1:1234/bot/planets/123/resource-settings/revert-last
+func RevertResourceProfileHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	if err := bot.SetResourceSettings(ogame.PlanetID(planetID), settings); err != nil {
-		if err == ogame.ErrInvalidPlanetID {
-			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	change, err := bot.RevertResourceProfile(ogame.PlanetID(planetID))
+	if err != nil {
+		if err == ogame.ErrNoResourceProfileChange {
+			return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
 		}
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(change))
 }
 
 // GetLfBuildingsHandler ...
 func GetLfBuildingsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
+	if !bot.GetCapabilities().Supports(ogame.CapabilityLifeform) {
+		return notImplementedResp(c, ogame.CapabilityLifeform)
+	}
 	planetID, err := utils.ParseI64(c.Param("planetID"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetLfBuildings(ogame.CelestialID(planetID))
+	res, err := bot.WithPriority(taskPriority(c)).GetLfBuildings(ogame.CelestialID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
@@ -555,17 +1450,161 @@ func GetLfBuildingsHandler(c echo.Context) error {
 // GetLfResearchHandler ...
 func GetLfResearchHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
+	if !bot.GetCapabilities().Supports(ogame.CapabilityLifeform) {
+		return notImplementedResp(c, ogame.CapabilityLifeform)
+	}
 	planetID, err := utils.ParseI64(c.Param("planetID"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetLfResearch(ogame.CelestialID(planetID))
+	res, err := bot.WithPriority(taskPriority(c)).GetLfResearch(ogame.CelestialID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(res))
 }
 
+// SetCrawlerPolicyHandler ...
+// curl -X PUT 127.0.0.1:1234/bot/crawler-policy -d 'enabled=true&interval=1800&build=true&adjust=true&planetID=123&planetID=456'
+func SetCrawlerPolicyHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	if err := c.Request().ParseForm(); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid form"))
+	}
+	form := c.Request().Form
+	policy := CrawlerPolicy{
+		Enabled:        form.Get("enabled") == "true",
+		BuildCrawlers:  form.Get("build") == "true",
+		AdjustSettings: form.Get("adjust") == "true",
+	}
+	if interval := form.Get("interval"); interval != "" {
+		seconds, err := utils.ParseI64(interval)
+		if err != nil || seconds < 60 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid interval"))
+		}
+		policy.IntervalSeconds = seconds
+	}
+	for _, planetIDStr := range form["planetID"] {
+		planetID, err := utils.ParseI64(planetIDStr)
+		if err != nil || planetID < 1 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id "+planetIDStr))
+		}
+		policy.PlanetIDs = append(policy.PlanetIDs, ogame.PlanetID(planetID))
+	}
+	bot.SetCrawlerPolicy(policy)
+	return c.JSON(http.StatusOK, SuccessResp(policy))
+}
+
+// GetCrawlerPolicyStatusHandler ...
+func GetCrawlerPolicyStatusHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetCrawlerPolicyStatus()))
+}
+
+// SetAutoSatsPolicyHandler ...
+// curl -X POST 127.0.0.1:1234/bot/auto-sats -d 'enabled=true&interval=1800&margin=10&maxCost=200000&planetID=123&planetID=456'
+func SetAutoSatsPolicyHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	if err := c.Request().ParseForm(); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid form"))
+	}
+	form := c.Request().Form
+	policy := AutoSatsPolicy{Enabled: form.Get("enabled") == "true"}
+	if interval := form.Get("interval"); interval != "" {
+		seconds, err := utils.ParseI64(interval)
+		if err != nil || seconds < 60 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid interval"))
+		}
+		policy.IntervalSeconds = seconds
+	}
+	if margin := form.Get("margin"); margin != "" {
+		energy, err := utils.ParseI64(margin)
+		if err != nil || energy < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid margin"))
+		}
+		policy.Margin = energy
+	}
+	if maxCost := form.Get("maxCost"); maxCost != "" {
+		cost, err := utils.ParseI64(maxCost)
+		if err != nil || cost < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid maxCost"))
+		}
+		policy.MaxCost = cost
+	}
+	for _, planetIDStr := range form["planetID"] {
+		planetID, err := utils.ParseI64(planetIDStr)
+		if err != nil || planetID < 1 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id "+planetIDStr))
+		}
+		policy.PlanetIDs = append(policy.PlanetIDs, ogame.PlanetID(planetID))
+	}
+	bot.SetAutoSatsPolicy(policy)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetAutoSatsStatus().Policy))
+}
+
+// GetAutoSatsStatusHandler ...
+func GetAutoSatsStatusHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetAutoSatsStatus()))
+}
+
+// GetExposureHandler reports the resources exposure of every celestial
+// curl 127.0.0.1:1234/bot/exposure?threshold=1000000
+func GetExposureHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	var threshold int64
+	if thresholdStr := c.QueryParam("threshold"); thresholdStr != "" {
+		var err error
+		threshold, err = utils.ParseI64(thresholdStr)
+		if err != nil || threshold < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid threshold"))
+		}
+	}
+	report, err := bot.getExposure(taskPriority(c), threshold)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(report))
+}
+
+// SetExposureAlertHandler ...
+// curl -X PUT 127.0.0.1:1234/bot/exposure/alert -d 'enabled=true&bedtime=23:30&nightHours=8&threshold=1000000'
+func SetExposureAlertHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	alert := ExposureAlert{Enabled: c.FormValue("enabled") == "true"}
+	if bedtime := c.FormValue("bedtime"); bedtime != "" {
+		t, err := time.Parse("15:04", bedtime)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid bedtime"))
+		}
+		alert.Hour, alert.Minute = t.Hour(), t.Minute()
+	} else if alert.Enabled {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "missing bedtime"))
+	}
+	if nightHoursStr := c.FormValue("nightHours"); nightHoursStr != "" {
+		nightHours, err := utils.ParseI64(nightHoursStr)
+		if err != nil || nightHours < 0 || nightHours > 24 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nightHours"))
+		}
+		alert.NightHours = nightHours
+	}
+	if thresholdStr := c.FormValue("threshold"); thresholdStr != "" {
+		threshold, err := utils.ParseI64(thresholdStr)
+		if err != nil || threshold < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid threshold"))
+		}
+		alert.Threshold = threshold
+	}
+	bot.SetExposureAlert(alert)
+	return c.JSON(http.StatusOK, SuccessResp(alert))
+}
+
+// GetExposureAlertHandler ...
+func GetExposureAlertHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetExposureAlert()))
+}
+
 // GetResourcesBuildingsHandler ...
 func GetResourcesBuildingsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -573,11 +1612,58 @@ func GetResourcesBuildingsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetResourcesBuildings(ogame.CelestialID(planetID))
+	var warnings []ogame.ParseWarning
+	res, err := bot.WithPriority(taskPriority(c)).GetResourcesBuildings(ogame.CelestialID(planetID), CollectWarnings(&warnings))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessRespWithWarnings(res, warnings))
+}
+
+// PlanToMatchHandler returns the upgrades needed to bring the resource buildings of a planet up to
+// the levels of a template planet, and/or to the given levels (buildings=ogameID,level)
+// curl '127.0.0.1:1234/bot/planets/123/plan-to-match?template=456&buildings=1,20&buildings=4,18'
+func PlanToMatchHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	var target ogame.ResourcesBuildings
+	if template := c.QueryParam("template"); template != "" {
+		templateID, err := utils.ParseI64(template)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid template id"))
+		}
+		target, err = bot.WithPriority(taskPriority(c)).GetResourcesBuildings(ogame.CelestialID(templateID))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+		}
+	}
+	for _, building := range c.QueryParams()["buildings"] {
+		a := strings.Split(building, ",")
+		if len(a) != 2 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid building format"))
+		}
+		buildingID, err := utils.ParseI64(a[0])
+		if err != nil || !ogame.ID(buildingID).IsResourceBuilding() {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid building id "+a[0]))
+		}
+		level, err := utils.ParseI64(a[1])
+		if err != nil || level < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid level "+a[1]))
+		}
+		target.Set(ogame.ID(buildingID), level)
+	}
+	steps, price, duration, err := bot.WithPriority(taskPriority(c)).PlanToMatch(ogame.CelestialID(planetID), target)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"Steps":    steps,
+		"Price":    price,
+		"Duration": int64(duration.Seconds()),
+	}))
 }
 
 // GetDefenseHandler ...
@@ -587,11 +1673,32 @@ func GetDefenseHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetDefense(ogame.CelestialID(planetID))
+	var warnings []ogame.ParseWarning
+	res, err := bot.WithPriority(taskPriority(c)).GetDefense(ogame.CelestialID(planetID), CollectWarnings(&warnings))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessRespWithWarnings(res, warnings))
+}
+
+// GetMissilesHandler ...
+// curl 127.0.0.1:1234/bot/planets/123/missiles
+func GetMissilesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	ipm, abm, siloCapacity, err := bot.WithPriority(taskPriority(c)).GetMissiles(ogame.CelestialID(planetID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(map[string]int64{
+		"InterplanetaryMissiles": ipm,
+		"AntiBallisticMissiles":  abm,
+		"SiloCapacity":           siloCapacity,
+		"FreeSlots":              utils.MaxInt(0, siloCapacity-abm-2*ipm),
+	}))
 }
 
 // GetShipsHandler ...
@@ -601,11 +1708,12 @@ func GetShipsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetShips(ogame.CelestialID(planetID))
+	var warnings []ogame.ParseWarning
+	res, err := bot.WithPriority(taskPriority(c)).GetShips(ogame.CelestialID(planetID), CollectWarnings(&warnings))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessRespWithWarnings(res, warnings))
 }
 
 // GetFacilitiesHandler ...
@@ -615,11 +1723,12 @@ func GetFacilitiesHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetFacilities(ogame.CelestialID(planetID))
+	var warnings []ogame.ParseWarning
+	res, err := bot.WithPriority(taskPriority(c)).GetFacilities(ogame.CelestialID(planetID), CollectWarnings(&warnings))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessRespWithWarnings(res, warnings))
 }
 
 // BuildHandler ...
@@ -637,7 +1746,7 @@ func BuildHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
 	}
-	if err := bot.Build(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).Build(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -654,7 +1763,7 @@ func BuildCancelableHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err := bot.BuildCancelable(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildCancelable(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -675,7 +1784,7 @@ func BuildProductionHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
 	}
-	if err := bot.BuildProduction(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildProduction(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -692,7 +1801,7 @@ func BuildBuildingHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err := bot.BuildBuilding(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildBuilding(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -709,7 +1818,7 @@ func BuildTechnologyHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err := bot.BuildTechnology(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildTechnology(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -730,7 +1839,7 @@ func BuildDefenseHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
 	}
-	if err := bot.BuildDefense(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildDefense(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -751,7 +1860,7 @@ func BuildShipsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
 	}
-	if err := bot.BuildShips(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).BuildShips(ogame.CelestialID(planetID), ogame.ID(ogameID), nbr); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -764,13 +1873,28 @@ func GetProductionHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, _, err := bot.GetProduction(ogame.CelestialID(planetID))
+	res, _, err := bot.WithPriority(taskPriority(c)).GetProduction(ogame.CelestialID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(res))
 }
 
+// GetBuildQueueHandler returns the building being built followed by the buildings queued behind it
+// curl 127.0.0.1:1234/bot/planets/123/build-queue
+func GetBuildQueueHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	queue, err := bot.WithPriority(taskPriority(c)).GetBuildQueue(ogame.CelestialID(planetID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(queue))
+}
+
 // ConstructionsBeingBuiltHandler ...
 func ConstructionsBeingBuiltHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -778,7 +1902,7 @@ func ConstructionsBeingBuiltHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	buildingID, buildingCountdown, researchID, researchCountdown, lfBuildingID, lfBuildingCountdown, lfResearchID, lfResearchCountdown := bot.ConstructionsBeingBuilt(ogame.CelestialID(planetID))
+	buildingID, buildingCountdown, researchID, researchCountdown, lfBuildingID, lfBuildingCountdown, lfResearchID, lfResearchCountdown := bot.WithPriority(taskPriority(c)).ConstructionsBeingBuilt(ogame.CelestialID(planetID))
 	return c.JSON(http.StatusOK, SuccessResp(
 		struct {
 			BuildingID          int64
@@ -802,80 +1926,408 @@ func ConstructionsBeingBuiltHandler(c echo.Context) error {
 	))
 }
 
-// CancelBuildingHandler ...
-func CancelBuildingHandler(c echo.Context) error {
+// CancelBuildingHandler ...
+func CancelBuildingHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	if err := bot.WithPriority(taskPriority(c)).CancelBuilding(ogame.CelestialID(planetID)); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
+// CancelQueuedBuildHandler removes a building waiting in the building queue, index being its position in the build-queue list
+// curl -X POST 127.0.0.1:1234/bot/planets/123/cancel-queued/1
+func CancelQueuedBuildHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	index, err := utils.ParseI64(c.Param("index"))
+	if err != nil || index < 1 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid index"))
+	}
+	if err := bot.WithPriority(taskPriority(c)).CancelQueuedBuild(ogame.CelestialID(planetID), index); err != nil {
+		if err == ogame.ErrInvalidQueueIndex {
+			return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
+// CancelResearchHandler ...
+func CancelResearchHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	if err := bot.WithPriority(taskPriority(c)).CancelResearch(ogame.CelestialID(planetID)); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
+// GetResourcesHandler ...
+func GetResourcesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	res, err := bot.WithPriority(taskPriority(c)).GetResources(ogame.CelestialID(planetID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(res))
+}
+
+// GetRequirementsHandler ...
+func GetRequirementsHandler(c echo.Context) error {
+	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	}
+	ogameObj := ogame.Objs.ByID(ogame.ID(ogameID))
+	if ogameObj != nil {
+		requirements := ogameObj.GetRequirements()
+		return c.JSON(http.StatusOK, SuccessResp(requirements))
+	}
+	return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+}
+
+// TechnologyDetailsHandler ...
+// curl 127.0.0.1:1234/bot/planets/123/tech-details/1
+func TechnologyDetailsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+	if err != nil || !ogame.ID(ogameID).IsValid() {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	}
+	res, err := bot.WithPriority(taskPriority(c)).TechnologyDetails(ogame.CelestialID(planetID), ogame.ID(ogameID))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(res))
+}
+
+// GetRapidfireHandler ...
+func GetRapidfireHandler(c echo.Context) error {
+	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	}
+	rapidfire, err := ogame.Objs.GetRapidfire(ogame.ID(ogameID))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(rapidfire))
+}
+
+// GetMyPointsHandler gets the points of the bot player in each highscore type
+// curl 127.0.0.1:1234/bot/my-points
+func GetMyPointsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	points, err := bot.WithPriority(taskPriority(c)).GetMyPoints()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(points))
+}
+
+// GetExpeditionCapHandler returns the points of the top 1 player, and the maximum resources an expedition can find
+// curl 127.0.0.1:1234/bot/expeditions/cap
+func GetExpeditionCapHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	expeditionCap, err := bot.GetExpeditionCap()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(expeditionCap))
+}
+
+// ExpeditionOddsHandler ...
+// curl '127.0.0.1:1234/bot/expedition-odds?fleetPoints=12000&topPoints=30000000&class=3'
+func ExpeditionOddsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	fleetPoints, err := utils.ParseI64(c.QueryParam("fleetPoints"))
+	if err != nil || fleetPoints < 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid fleetPoints"))
+	}
+	topPoints, err := utils.ParseI64(c.QueryParam("topPoints"))
+	if err != nil || topPoints < 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid topPoints"))
+	}
+	class := bot.CharacterClass()
+	if classStr := c.QueryParam("class"); classStr != "" {
+		classInt, err := utils.ParseI64(classStr)
+		if err != nil || classInt < int64(ogame.NoClass) || classInt > int64(ogame.Discoverer) {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid class"))
+		}
+		class = ogame.CharacterClass(classInt)
+	}
+	return c.JSON(http.StatusOK, SuccessResp(ogame.ExpeditionOdds(fleetPoints, topPoints, class)))
+}
+
+// IPMNeededHandler ...
+// curl '127.0.0.1:1234/bot/ipm-needed?defenses=401,10&defenses=502,5&weaponsTech=10&armourTech=8'
+func IPMNeededHandler(c echo.Context) error {
+	var target ogame.DefensesInfos
+	for _, s := range c.QueryParams()["defenses"] {
+		a := strings.Split(s, ",")
+		if len(a) != 2 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid defense "+s))
+		}
+		defenseID, err := utils.ParseI64(a[0])
+		if err != nil || !ogame.ID(defenseID).IsDefense() {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid defense id "+a[0]))
+		}
+		nbr, err := utils.ParseI64(a[1])
+		if err != nil || nbr < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr "+a[1]))
+		}
+		target.Set(ogame.ID(defenseID), nbr)
+	}
+	weaponsTech, err := utils.ParseI64(c.QueryParam("weaponsTech"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid weaponsTech"))
+	}
+	armourTech, err := utils.ParseI64(c.QueryParam("armourTech"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid armourTech"))
+	}
+	nbr, err := ogame.IPMNeeded(target, weaponsTech, armourTech)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nbr))
+}
+
+// MissileDefenseStatusHandler ...
+func MissileDefenseStatusHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	status, err := bot.missileDefenseStatus(taskPriority(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(status))
+}
+
+// GetPriceHandler ...
+func GetPriceHandler(c echo.Context) error {
+	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	}
+	nbr, err := utils.ParseI64(c.Param("nbr"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
+	}
+	ogameObj := ogame.Objs.ByID(ogame.ID(ogameID))
+	if ogameObj != nil {
+		price := ogameObj.GetPrice(nbr)
+		return c.JSON(http.StatusOK, SuccessResp(price))
+	}
+	return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+}
+
+// ShipBuildTimeHandler returns, in seconds, how long the shipyard of a planet takes to build a batch of ships or defenses
+// curl 127.0.0.1:1234/bot/planets/123/ship-build-time/204/100
+func ShipBuildTimeHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	}
+	count, err := utils.ParseI64(c.Param("count"))
+	if err != nil || count < 1 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid count"))
+	}
+	duration, err := bot.WithPriority(taskPriority(c)).ShipBuildTime(ogame.CelestialID(planetID), ogame.ID(ogameID), count)
+	if err != nil {
+		if err == ogame.ErrNotCombatUnit {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(int64(duration.Seconds())))
+}
+
+// ValidateFleetTargetHandler returns the restrictions the game would apply to a mission towards a target, without sending anything
+// curl '127.0.0.1:1234/bot/validate-fleet-target?from=123&coord=M:1:2:3&mission=1'
+func ValidateFleetTargetHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	planetID, err := utils.ParseI64(c.Param("planetID"))
+	celestialID, err := utils.ParseI64(c.FormValue("from"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid from celestial id"))
 	}
-	if err := bot.CancelBuilding(ogame.CelestialID(planetID)); err != nil {
+	coord, err := coordParam(c, bot)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	mission, err := utils.ParseI64(c.FormValue("mission"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid mission"))
+	}
+	restrictions, err := bot.WithPriority(taskPriority(c)).ValidateFleetTarget(ogame.CelestialID(celestialID), coord, ogame.MissionID(mission))
+	if err != nil {
+		if err == ogame.ErrInvalidPlanetID {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(restrictions))
 }
 
-// CancelResearchHandler ...
-func CancelResearchHandler(c echo.Context) error {
+// GetAllowedSpeedsHandler returns the fleet speeds that can be used to send a fleet from a celestial
+// curl '127.0.0.1:1234/bot/allowed-speeds?celestialID=123'
+func GetAllowedSpeedsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	planetID, err := utils.ParseI64(c.Param("planetID"))
+	celestialID, err := utils.ParseI64(c.QueryParam("celestialID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
 	}
-	if err := bot.CancelResearch(ogame.CelestialID(planetID)); err != nil {
+	speeds, err := bot.WithPriority(taskPriority(c)).GetAllowedSpeeds(ogame.CelestialID(celestialID))
+	if err != nil {
+		if err == ogame.ErrInvalidPlanetID {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(nil))
+	return c.JSON(http.StatusOK, SuccessResp(speeds))
 }
 
-// GetResourcesHandler ...
-func GetResourcesHandler(c echo.Context) error {
+// GetTemperatureEffectsHandler returns the energy of one solar satellite and the deuterium production factor of a planet
+// curl 127.0.0.1:1234/bot/planets/123/temperature-effects
+func GetTemperatureEffectsHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	planetID, err := utils.ParseI64(c.Param("planetID"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	res, err := bot.GetResources(ogame.CelestialID(planetID))
+	satelliteEnergy, deutFactor, err := bot.WithPriority(taskPriority(c)).GetTemperatureEffects(ogame.CelestialID(planetID))
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(res))
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{
+		"SatelliteEnergyPerUnit":    satelliteEnergy,
+		"DeuteriumProductionFactor": deutFactor,
+	}))
 }
 
-// GetRequirementsHandler ...
-func GetRequirementsHandler(c echo.Context) error {
+// MineUpgradeROIHandler returns, in seconds, how long the next level of a mine takes to pay back its cost
+// curl 127.0.0.1:1234/bot/planets/123/mine-roi/1
+func MineUpgradeROIHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
 	ogameID, err := utils.ParseI64(c.Param("ogameID"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
 	}
-	ogameObj := ogame.Objs.ByID(ogame.ID(ogameID))
-	if ogameObj != nil {
-		requirements := ogameObj.GetRequirements()
-		return c.JSON(http.StatusOK, SuccessResp(requirements))
+	duration, err := bot.WithPriority(taskPriority(c)).MineUpgradeROI(ogame.CelestialID(planetID), ogame.ID(ogameID))
+	if err != nil {
+		if err == ogame.ErrNotMine || err == ogame.ErrNoProductionGain {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	return c.JSON(http.StatusOK, SuccessResp(int64(duration.Seconds())))
 }
 
-// GetPriceHandler ...
-func GetPriceHandler(c echo.Context) error {
-	ogameID, err := utils.ParseI64(c.Param("ogameID"))
+// FlightTimeHandler estimates the flight duration (secs) and the deuterium consumption of a fleet, without sending it
+// curl '127.0.0.1:1234/bot/planets/123/flight-time?ships=203,10&ships=204,50&speed=10&galaxy=1&system=2&position=3&type=1&mission=3'
+func FlightTimeHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
 	}
-	nbr, err := utils.ParseI64(c.Param("nbr"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr"))
+	origin := bot.GetCachedCelestial(ogame.CelestialID(planetID))
+	if origin == nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, ogame.ErrInvalidPlanetID.Error()))
 	}
-	ogameObj := ogame.Objs.ByID(ogame.ID(ogameID))
-	if ogameObj != nil {
-		price := ogameObj.GetPrice(nbr)
-		return c.JSON(http.StatusOK, SuccessResp(price))
+	where := ogame.Coordinate{Type: ogame.PlanetType}
+	if where.Galaxy, err = utils.ParseI64(c.QueryParam("galaxy")); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
 	}
-	return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogameID"))
+	if where.System, err = utils.ParseI64(c.QueryParam("system")); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid system"))
+	}
+	if where.Position, err = utils.ParseI64(c.QueryParam("position")); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+	}
+	if typ := c.QueryParam("type"); typ != "" {
+		t, err := utils.ParseI64(typ)
+		if err != nil || t < 1 || t > 3 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid type"))
+		}
+		where.Type = ogame.CelestialType(t)
+	}
+	if err := bot.serverData.ValidateCoordinate(where); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	speed := ogame.HundredPercent
+	if s := c.QueryParam("speed"); s != "" {
+		speedFloat, err := strconv.ParseFloat(s, 64)
+		if err != nil || speedFloat <= 0 || speedFloat > 10 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid speed"))
+		}
+		speed = ogame.Speed(speedFloat)
+	}
+	mission := ogame.Transport
+	if m := c.QueryParam("mission"); m != "" {
+		missionInt, err := utils.ParseI64(m)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid mission"))
+		}
+		mission = ogame.MissionID(missionInt)
+	}
+	var ships ogame.ShipsInfos
+	for _, s := range c.QueryParams()["ships"] {
+		a := strings.Split(s, ",")
+		if len(a) != 2 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ships "+s))
+		}
+		shipID, err := utils.ParseI64(a[0])
+		if err != nil || !ogame.ID(shipID).IsShip() {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ship id "+a[0]))
+		}
+		nbr, err := utils.ParseI64(a[1])
+		if err != nil || nbr < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr "+a[1]))
+		}
+		ships.Set(ogame.ID(shipID), ships.ByID(ogame.ID(shipID))+nbr)
+	}
+	if !ships.HasShips() {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "no ships"))
+	}
+	secs, fuel := bot.WithPriority(taskPriority(c)).FlightTime(origin.GetCoordinate(), where, speed, ships, mission)
+	return c.JSON(http.StatusOK, SuccessResp(map[string]int64{"Secs": secs, "Fuel": fuel}))
 }
 
 // SendFleetHandler ...
 // curl 127.0.0.1:1234/bot/planets/123/send-fleet -d 'ships=203,1&ships=204,10&speed=10&galaxy=1&system=1&type=1&position=1&mission=3&metal=1&crystal=2&deuterium=3'
+// Expeditions carrying more than the expedition cap get an X-Expedition-Warning header, or are trimmed with trim=1
+// With fill (eg: fill=deuterium&fill=metal) and/or partial=1, the metal/crystal/deuterium are fixed amounts, the remaining cargo
+// is filled in the fill order, and the resolved payload is sent back
+// The destination can also be given as coord (eg: coord=M:1:2:3) instead of galaxy/system/position/type
+// With slotToken, the fleet can use the slots of that reservation (see ReserveSlotsHandler)
 func SendFleetHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	planetID, err := utils.ParseI64(c.Param("planetID"))
@@ -894,8 +2346,26 @@ func SendFleetHandler(c echo.Context) error {
 	var unionID int64
 	payload := ogame.Resources{}
 	speed := ogame.HundredPercent
+	var slotToken string
+	var trim bool
+	var fill []ogame.PayloadResource
+	var partial bool
 	for key, values := range c.Request().PostForm {
 		switch key {
+		case "fill":
+			for _, v := range values {
+				r := ogame.PayloadResource(v)
+				if !r.IsValid() {
+					return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid fill resource "+v))
+				}
+				fill = append(fill, r)
+			}
+		case "partial":
+			partial = values[0] == "1" || values[0] == "true"
+		case "slotToken":
+			slotToken = values[0]
+		case "trim":
+			trim = values[0] == "1" || values[0] == "true"
 		case "ships":
 			for _, s := range values {
 				a := strings.Split(s, ",")
@@ -910,11 +2380,12 @@ func SendFleetHandler(c echo.Context) error {
 				ships = append(ships, ogame.Quantifiable{ID: ogame.ID(shipID), Nbr: nbr})
 			}
 		case "speed":
-			speedInt, err := utils.ParseI64(values[0])
-			if err != nil || speedInt < 0 || speedInt > 10 {
+			// The allowed speeds depend on the universe and the character class, they are checked when sending
+			speedFloat, err := strconv.ParseFloat(values[0], 64)
+			if err != nil || speedFloat <= 0 {
 				return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid speed"))
 			}
-			speed = ogame.Speed(speedInt)
+			speed = ogame.Speed(speedFloat)
 		case "galaxy":
 			galaxy, err := utils.ParseI64(values[0])
 			if err != nil {
@@ -975,27 +2446,47 @@ func SendFleetHandler(c echo.Context) error {
 			payload.Deuterium = deuterium
 		}
 	}
+	if coordStr := c.Request().PostForm.Get("coord"); coordStr != "" {
+		if where, err = ogame.ParseCoord(coordStr); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid coord"))
+		}
+	}
+	if err := bot.serverData.ValidateCoordinate(where); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+
+	if mission == ogame.Expedition {
+		trimmed, exceeds, err := bot.CheckExpeditionFleet(ogame.ShipsInfos{}.FromQuantifiables(ships), trim)
+		if err != nil {
+			bot.error("expedition cap", err)
+		} else if exceeds && trim {
+			ships = trimmed.ToQuantifiables()
+		} else if exceeds {
+			c.Response().Header().Set(ExpeditionWarningHeader, "fleet cargo capacity exceeds the expedition cap")
+		}
+	}
 
-	fleet, err := bot.SendFleet(ogame.CelestialID(planetID), ships, speed, where, mission, payload, duration, unionID)
-	if err != nil &&
-		(err == ogame.ErrInvalidPlanetID ||
-			err == ogame.ErrNoShipSelected ||
-			err == ogame.ErrUninhabitedPlanet ||
-			err == ogame.ErrNoDebrisField ||
-			err == ogame.ErrPlayerInVacationMode ||
-			err == ogame.ErrAdminOrGM ||
-			err == ogame.ErrNoAstrophysics ||
-			err == ogame.ErrNoobProtection ||
-			err == ogame.ErrPlayerTooStrong ||
-			err == ogame.ErrNoMoonAvailable ||
-			err == ogame.ErrNoRecyclerAvailable ||
-			err == ogame.ErrNoEventsRunning ||
-			err == ogame.ErrPlanetAlreadyReservedForRelocation) {
+	var fleet ogame.Fleet
+	var loaded ogame.Resources
+	withPayloadSpec := len(fill) > 0 || partial
+	if withPayloadSpec {
+		spec := ogame.PayloadSpec{Fixed: payload, Fill: fill, Partial: partial}
+		fleet, loaded, err = bot.WithPriority(taskPriority(c)).SetSlotReservation(slotToken).SendFleetWithPayload(ogame.CelestialID(planetID), ships, speed, where, mission, spec, duration, unionID)
+	} else {
+		fleet, err = bot.WithPriority(taskPriority(c)).SetSlotReservation(slotToken).SendFleet(ogame.CelestialID(planetID), ships, speed, where, mission, payload, duration, unionID)
+	}
+	if _, ok := ogame.IsFleetError(err); ok {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
+	if withPayloadSpec {
+		return c.JSON(http.StatusOK, SuccessResp(struct {
+			ogame.Fleet
+			Payload ogame.Resources
+		}{fleet, loaded}))
+	}
 	return c.JSON(http.StatusOK, SuccessResp(fleet))
 }
 
@@ -1004,13 +2495,13 @@ func GetAlliancePageContentHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	allianceID := c.QueryParam("allianceId")
 	vals := url.Values{"allianceId": {allianceID}}
-	pageHTML, _ := bot.GetPageContent(vals)
+	pageHTML, _ := bot.WithPriority(taskPriority(c)).GetPageContent(vals)
 	return c.HTML(http.StatusOK, string(pageHTML))
 }
 
 func replaceHostname(bot *OGame, html []byte) []byte {
 	serverURLBytes := []byte(bot.serverURL)
-	apiNewHostnameBytes := []byte(bot.apiNewHostname)
+	apiNewHostnameBytes := []byte(bot.getAPINewHostname())
 	escapedServerURL := bytes.Replace(serverURLBytes, []byte("/"), []byte(`\/`), -1)
 	doubleEscapedServerURL := bytes.Replace(serverURLBytes, []byte("/"), []byte("\\\\\\/"), -1)
 	escapedAPINewHostname := bytes.Replace(apiNewHostnameBytes, []byte("/"), []byte(`\/`), -1)
@@ -1041,18 +2532,26 @@ func GetStaticHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 
+	// The hostname of rewritten assets can change at runtime, so their upstream validators must not be reused
+	rewritten := strings.Contains(c.Request().URL.String(), ".xml")
+
 	// Copy the original HTTP headers to our client
 	for k, vv := range resp.Header { // duplicate headers are acceptable in HTTP spec, so add all of them individually: https://stackoverflow.com/questions/4371328/are-duplicate-http-response-headers-acceptable
 		k = http.CanonicalHeaderKey(k)
-		if k != "Content-Length" && k != "Content-Encoding" { // https://github.com/alaingilbert/ogame/pull/80#issuecomment-674559853
-			for _, v := range vv {
-				c.Response().Header().Add(k, v)
-			}
+		if k == "Content-Length" || k == "Content-Encoding" { // https://github.com/alaingilbert/ogame/pull/80#issuecomment-674559853
+			continue
+		}
+		if rewritten && (k == "Etag" || k == "Last-Modified" || k == "Cache-Control" || k == "Expires") {
+			continue
+		}
+		for _, v := range vv {
+			c.Response().Header().Add(k, v)
 		}
 	}
 
-	if strings.Contains(c.Request().URL.String(), ".xml") {
+	if rewritten {
 		body = replaceHostname(bot, body)
+		c.Response().Header().Set("Cache-Control", "no-cache")
 		return c.Blob(http.StatusOK, "application/xml", body)
 	}
 
@@ -1075,7 +2574,7 @@ func GetFromGameHandler(c echo.Context) error {
 	if len(c.QueryParams()) > 0 {
 		vals = c.QueryParams()
 	}
-	pageHTML, _ := bot.GetPageContent(vals)
+	pageHTML, _ := bot.WithPriority(taskPriority(c)).GetPageContent(vals)
 	pageHTML = replaceHostname(bot, pageHTML)
 	return c.HTMLBlob(http.StatusOK, pageHTML)
 }
@@ -1088,7 +2587,7 @@ func PostToGameHandler(c echo.Context) error {
 		vals = c.QueryParams()
 	}
 	payload, _ := c.FormParams()
-	pageHTML, _ := bot.PostPageContent(vals, payload)
+	pageHTML, _ := bot.WithPriority(taskPriority(c)).PostPageContent(vals, payload)
 	pageHTML = replaceHostname(bot, pageHTML)
 	return c.HTMLBlob(http.StatusOK, pageHTML)
 }
@@ -1100,7 +2599,7 @@ func GetStaticHEADHandler(c echo.Context) error {
 	if len(c.QueryString()) > 0 {
 		newURL = newURL + "?" + c.QueryString()
 	}
-	headers, err := bot.HeadersForPage(newURL)
+	headers, err := bot.WithPriority(taskPriority(c)).HeadersForPage(newURL)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
@@ -1124,7 +2623,7 @@ func GetEmpireHandler(c echo.Context) error {
 	if err != nil || nbr > 1 {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid typeID"))
 	}
-	getEmpire, err := bot.GetEmpireJSON(nbr)
+	getEmpire, err := bot.WithPriority(taskPriority(c)).GetEmpireJSON(nbr)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
@@ -1138,7 +2637,7 @@ func DeleteMessageHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid message id"))
 	}
-	if err := bot.DeleteMessage(messageID); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).DeleteMessage(messageID); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -1147,12 +2646,27 @@ func DeleteMessageHandler(c echo.Context) error {
 // DeleteEspionageMessagesHandler ...
 func DeleteEspionageMessagesHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	if err := bot.DeleteAllMessagesFromTab(20); err != nil { // 20 = Espionage Reports
+	if err := bot.WithPriority(taskPriority(c)).DeleteAllMessagesFromTab(20); err != nil { // 20 = Espionage Reports
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "Unable to delete Espionage Reports"))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
+// DeleteEspionageReportsOlderThanHandler deletes the espionage reports older than the given duration
+// curl -X POST '127.0.0.1:1234/bot/espionage-report/prune?olderThan=72h'
+func DeleteEspionageReportsOlderThanHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	olderThan, err := time.ParseDuration(c.QueryParam("olderThan"))
+	if err != nil || olderThan <= 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid olderThan"))
+	}
+	deleted, err := bot.WithPriority(taskPriority(c)).DeleteEspionageReportsOlderThan(olderThan)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{"Deleted": deleted}))
+}
+
 // DeleteMessagesFromTabHandler ...
 func DeleteMessagesFromTabHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -1170,12 +2684,131 @@ func DeleteMessagesFromTabHandler(c echo.Context) error {
 		*/
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid tabIndex provided"))
 	}
-	if err := bot.DeleteAllMessagesFromTab(ogame.MessagesTabID(tabIndex)); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).DeleteAllMessagesFromTab(ogame.MessagesTabID(tabIndex)); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "Unable to delete message from tab "+utils.FI64(tabIndex)))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
+// DeleteMessagesWhereHandler deletes the messages of a tab matching a filter.
+// Supported filters are olderThanHours, coordinate (eg: 1:2:3 or M:1:2:3) and type (action|report|combat|expedition).
+// Cancelling the request stops the deletion, counts are returned either way.
+// curl 127.0.0.1:1234/bot/messages/20/delete-filtered -d 'olderThanHours=24&coordinate=1:2:3&type=report'
+func DeleteMessagesWhereHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	tabIndex, err := utils.ParseI64(c.Param("tabIndex"))
+	if err != nil || tabIndex < 20 || tabIndex > 22 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid tabIndex provided"))
+	}
+	var filter ogame.MessageFilter
+	if s := c.FormValue("olderThanHours"); s != "" {
+		hours, err := utils.ParseI64(s)
+		if err != nil || hours <= 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid olderThanHours"))
+		}
+		filter.OlderThan = time.Duration(hours) * time.Hour
+	}
+	if s := c.FormValue("coordinate"); s != "" {
+		coord, err := ogame.ParseCoord(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid coordinate"))
+		}
+		filter.Coordinate = &coord
+	}
+	filter.Type = c.FormValue("type")
+	now := bot.serverNow()
+	res, err := bot.DeleteMessagesWhere(c.Request().Context(), ogame.MessagesTabID(tabIndex), func(msg ogame.MessageSummary) bool {
+		return filter.Match(msg, now)
+	})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()+", "+utils.FI64(res.Deleted)+" deleted"))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(res))
+}
+
+// SendFleetAndRecallHandler sends a fleet and schedules its recall
+// curl 127.0.0.1:1234/bot/planets/123/send-and-recall -d 'ships=204,10&galaxy=1&system=2&position=3&type=1&mission=1&hold=60'
+func SendFleetAndRecallHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	planetID, err := utils.ParseI64(c.Param("planetID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid planet id"))
+	}
+	if err := c.Request().ParseForm(); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid form"))
+	}
+	var ships []ogame.Quantifiable
+	for _, s := range c.Request().PostForm["ships"] {
+		a := strings.Split(s, ",")
+		if len(a) != 2 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ships "+s))
+		}
+		shipID, err := utils.ParseI64(a[0])
+		if err != nil || !ogame.ID(shipID).IsShip() {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ship id "+a[0]))
+		}
+		nbr, err := utils.ParseI64(a[1])
+		if err != nil || nbr < 0 {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbr "+a[1]))
+		}
+		ships = append(ships, ogame.Quantifiable{ID: ogame.ID(shipID), Nbr: nbr})
+	}
+	galaxy, err := utils.ParseI64(c.FormValue("galaxy"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
+	}
+	system, err := utils.ParseI64(c.FormValue("system"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid system"))
+	}
+	position, err := utils.ParseI64(c.FormValue("position"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+	}
+	where := ogame.Coordinate{Galaxy: galaxy, System: system, Position: position, Type: ogame.PlanetType}
+	if typ := c.FormValue("type"); typ != "" {
+		t, err := utils.ParseI64(typ)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid type"))
+		}
+		where.Type = ogame.CelestialType(t)
+	}
+	missionInt, err := utils.ParseI64(c.FormValue("mission"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid mission"))
+	}
+	hold, err := utils.ParseI64(c.FormValue("hold"))
+	if err != nil || hold <= 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid hold"))
+	}
+	fleet, jobID, err := bot.WithPriority(taskPriority(c)).SendFleetAndRecall(ogame.CelestialID(planetID), ships, where, ogame.MissionID(missionInt), hold)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(map[string]any{"Fleet": fleet, "RecallJobID": jobID}))
+}
+
+// GetRecallJobsHandler returns the scheduled recalls
+// curl 127.0.0.1:1234/bot/recalls
+func GetRecallJobsHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	return c.JSON(http.StatusOK, SuccessResp(bot.GetRecallJobs()))
+}
+
+// CancelRecallJobHandler cancels a scheduled recall
+// curl -X DELETE 127.0.0.1:1234/bot/recalls/1
+func CancelRecallJobHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	jobID, err := utils.ParseI64(c.Param("jobID"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid job id"))
+	}
+	if err := bot.CancelRecallJob(jobID); err != nil {
+		return c.JSON(http.StatusNotFound, ErrorResp(404, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(nil))
+}
+
 // SendIPMHandler ...
 func SendIPMHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -1209,13 +2842,43 @@ func SendIPMHandler(c echo.Context) error {
 	}
 	priority := utils.DoParseI64(c.Request().PostFormValue("priority"))
 	coord := ogame.Coordinate{Type: planetType, Galaxy: galaxy, System: system, Position: position}
-	duration, err := bot.SendIPM(ogame.PlanetID(planetID), coord, ipmAmount, ogame.ID(priority))
+	duration, err := bot.WithPriority(taskPriority(c)).SendIPM(ogame.PlanetID(planetID), coord, ipmAmount, ogame.ID(priority))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(duration))
 }
 
+// QuickSpyHandler sends espionage probes using the galaxy quick action, nbProbes defaults to the preferences
+// With slotToken, the probes can use the slots of that reservation (see ReserveSlotsHandler)
+// curl 127.0.0.1:1234/bot/quick-spy -d 'galaxy=1&system=1&position=1&type=1&nbProbes=2' (or -d 'coord=M:1:1:1&nbProbes=2')
+func QuickSpyHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	coord, err := coordParam(c, bot)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	if coord.Position > 15 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+	}
+	if typ := c.Request().PostFormValue("type"); typ != "" {
+		coord.Type = ogame.CelestialType(utils.DoParseI64(typ))
+	}
+	if coord.Type != ogame.PlanetType && coord.Type != ogame.MoonType { // only accept planet/moon types
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid type"))
+	}
+	nbProbes := utils.DoParseI64(c.Request().PostFormValue("nbProbes"))
+	if nbProbes < 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid nbProbes"))
+	}
+	slotToken := c.Request().PostFormValue("slotToken")
+	fleet, err := bot.WithPriority(taskPriority(c)).SetSlotReservation(slotToken).QuickSpy(coord, nbProbes)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(fleet))
+}
+
 // TeardownHandler ...
 func TeardownHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -1227,7 +2890,7 @@ func TeardownHandler(c echo.Context) error {
 	if err != nil || planetID < 0 {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid ogame id"))
 	}
-	if err = bot.TearDown(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
+	if err = bot.WithPriority(taskPriority(c)).TearDown(ogame.CelestialID(planetID), ogame.ID(ogameID)); err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
@@ -1236,13 +2899,23 @@ func TeardownHandler(c echo.Context) error {
 // GetAuctionHandler ...
 func GetAuctionHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	auction, err := bot.GetAuction()
+	auction, err := bot.WithPriority(taskPriority(c)).GetAuction()
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "could not open auction page"))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(auction))
 }
 
+// GetDarkMatterHandler ...
+func GetDarkMatterHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	darkMatter, err := bot.WithPriority(taskPriority(c)).GetDarkMatter()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResp(darkMatter))
+}
+
 // DoAuctionHandler (`celestialID=metal:crystal:deuterium` eg: `123456=123:456:789`)
 func DoAuctionHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
@@ -1263,37 +2936,45 @@ func DoAuctionHandler(c echo.Context) error {
 			bid[ogame.CelestialID(celestialIDInt)] = ogame.Resources{Metal: metal, Crystal: crystal, Deuterium: deuterium}
 		}
 	}
-	if err := bot.DoAuction(bid); err != nil {
+	if err := bot.WithPriority(taskPriority(c)).DoAuction(bid); err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
 	return c.JSON(http.StatusOK, SuccessResp(nil))
 }
 
 // PhalanxHandler ...
+// curl 127.0.0.1:1234/bot/moons/123/phalanx/1/2/3
+// curl '127.0.0.1:1234/bot/moons/123/phalanx?coord=1:2:3'
 func PhalanxHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 	moonID, err := utils.ParseI64(c.Param("moonID"))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
 	}
-	galaxy, err := utils.ParseI64(c.Param("galaxy"))
+	coord, err := coordParam(c, bot)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid galaxy"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	system, err := utils.ParseI64(c.Param("system"))
+	fleets, err := bot.WithPriority(taskPriority(c)).Phalanx(ogame.MoonID(moonID), coord.Planet())
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid system"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	position, err := utils.ParseI64(c.Param("position"))
+	return c.JSON(http.StatusOK, SuccessResp(fleets))
+}
+
+// GetMoonFacilitiesHandler ...
+// curl 127.0.0.1:1234/bot/moons/123/facilities/detail
+func GetMoonFacilitiesHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	moonID, err := utils.ParseI64(c.Param("moonID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid position"))
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid moon id"))
 	}
-	coord := ogame.Coordinate{Type: ogame.PlanetType, Galaxy: galaxy, System: system, Position: position}
-	fleets, err := bot.Phalanx(ogame.MoonID(moonID), coord)
+	res, err := bot.WithPriority(taskPriority(c)).GetMoonFacilities(ogame.MoonID(moonID))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(fleets))
+	return c.JSON(http.StatusOK, SuccessResp(res))
 }
 
 // JumpGateHandler ...
@@ -1328,7 +3009,7 @@ func JumpGateHandler(c echo.Context) error {
 			}
 		}
 	}
-	success, rechargeCountdown, err := bot.JumpGate(ogame.MoonID(moonOriginID), ogame.MoonID(moonDestinationID), ships)
+	success, rechargeCountdown, err := bot.WithPriority(taskPriority(c)).JumpGate(ogame.MoonID(moonOriginID), ogame.MoonID(moonDestinationID), ships)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
@@ -1345,7 +3026,7 @@ func TechsHandler(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid celestial id"))
 	}
-	supplies, facilities, ships, defenses, researches, lfbuildings, err := bot.GetTechs(ogame.CelestialID(celestialID))
+	supplies, facilities, ships, defenses, researches, lfbuildings, err := bot.WithPriority(taskPriority(c)).GetTechs(ogame.CelestialID(celestialID))
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
 	}
@@ -1363,10 +3044,10 @@ func TechsHandler(c echo.Context) error {
 func GetCaptchaHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
 
-	_, err := GFLogin(bot.client, bot.ctx, bot.lobby, bot.Username, bot.password, bot.otpSecret, "")
+	_, err := GFLoginWithLocale(bot.client, bot.ctx, bot.lobby, bot.Username, bot.password, bot.otpSecret, "", bot.lobbyLocale)
 	var captchaErr *CaptchaRequiredError
 	if errors.As(err, &captchaErr) {
-		questionRaw, iconsRaw, err := StartCaptchaChallenge(bot.GetClient(), bot.ctx, captchaErr.ChallengeID)
+		questionRaw, iconsRaw, err := StartCaptchaChallengeWithLocale(bot.GetClient(), bot.ctx, captchaErr.ChallengeID, bot.lobbyLocale)
 		if err != nil {
 			return c.HTML(http.StatusOK, err.Error())
 		}
@@ -1388,22 +3069,108 @@ func GetCaptchaHandler(c echo.Context) error {
 	return c.HTML(http.StatusOK, "no captcha found")
 }
 
+// CaptchaSolveResult ...
+type CaptchaSolveResult struct {
+	Status             CaptchaStatus
+	NewChallengeIssued bool
+	NewChallenge       *CaptchaChallenge `json:",omitempty"`
+}
+
+// isAPIRequest returns either or not the client expects a JSON response (API client), rather than being a browser form
+func isAPIRequest(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON) ||
+		strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+}
+
 // GetCaptchaSolverHandler ...
+// The form of the captcha page is redirected to the home page, API clients (Accept or Content-Type JSON) get the status.
+// curl 127.0.0.1:1234/bot/captcha/solve -H 'Accept: application/json' -d 'challenge_id=xxx&answer=2'
+// curl 127.0.0.1:1234/bot/captcha/solve -H 'Content-Type: application/json' -d '{"challenge_id":"xxx","answer":2}'
 func GetCaptchaSolverHandler(c echo.Context) error {
 	bot := c.Get("bot").(*OGame)
-	challengeID := c.Request().PostFormValue("challenge_id")
-	answer := utils.DoParseI64(c.Request().PostFormValue("answer"))
+	apiClient := isAPIRequest(c)
+	respond := func(code int, resp APIResp) error {
+		if apiClient {
+			return c.JSON(code, resp)
+		}
+		if code != http.StatusOK {
+			bot.error(resp.Message)
+		}
+		return c.Redirect(http.StatusTemporaryRedirect, "/")
+	}
+
+	var params struct {
+		ChallengeID string `json:"challenge_id"`
+		Answer      *int64 `json:"answer"`
+	}
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := json.NewDecoder(c.Request().Body).Decode(&params); err != nil {
+			return respond(http.StatusBadRequest, ErrorResp(400, "invalid json"))
+		}
+	} else {
+		params.ChallengeID = c.Request().PostFormValue("challenge_id")
+		if answer, err := utils.ParseI64(c.Request().PostFormValue("answer")); err == nil {
+			params.Answer = &answer
+		}
+	}
+	if params.ChallengeID == "" {
+		return respond(http.StatusBadRequest, ErrorResp(400, "invalid challenge_id"))
+	}
+	if params.Answer == nil || *params.Answer < 0 || *params.Answer > 3 {
+		return respond(http.StatusBadRequest, ErrorResp(400, "answer must be 0, 1, 2 or 3"))
+	}
 
-	if err := SolveChallenge(bot.GetClient(), bot.ctx, challengeID, answer); err != nil {
-		bot.error(err)
+	status, err := SolveChallengeStatus(bot.GetClient(), bot.ctx, params.ChallengeID, *params.Answer, bot.lobbyLocale)
+	if errors.Is(err, ErrCaptchaExpired) {
+		res := CaptchaSolveResult{Status: CaptchaFailed}
+		if challenge, err := newCaptchaChallenge(bot); err == nil && challenge.ID != "" {
+			res.NewChallengeIssued = true
+			res.NewChallenge = &challenge
+		}
+		resp := ErrorResp(422, err.Error())
+		resp.Result = res
+		return respond(http.StatusUnprocessableEntity, resp)
+	} else if err != nil {
+		return respond(http.StatusBadGateway, ErrorResp(502, err.Error()))
+	}
+	if status != CaptchaSolved {
+		resp := ErrorResp(422, "captcha not solved")
+		resp.Result = CaptchaSolveResult{Status: status}
+		return respond(http.StatusUnprocessableEntity, resp)
 	}
 
 	if !bot.IsLoggedIn() {
-		if err := bot.Login(); err != nil {
+		if err := bot.WithPriority(taskPriority(c)).Login(); err != nil {
 			bot.error(err)
 		}
 	}
-	return c.Redirect(http.StatusTemporaryRedirect, "/")
+	return respond(http.StatusOK, SuccessResp(CaptchaSolveResult{Status: status}))
+}
+
+// GetCaptchaQuestionHandler serves the question image of a captcha challenge
+// curl '127.0.0.1:1234/bot/captcha/question/<challengeID>?1650000000000'
+func GetCaptchaQuestionHandler(c echo.Context) error {
+	return getCaptchaImage(c, CaptchaQuestionImage)
+}
+
+// GetCaptchaIconsHandler serves the icons image of a captcha challenge
+// curl '127.0.0.1:1234/bot/captcha/icons/<challengeID>?1650000000000'
+func GetCaptchaIconsHandler(c echo.Context) error {
+	return getCaptchaImage(c, CaptchaIconsImage)
+}
+
+func getCaptchaImage(c echo.Context, image CaptchaImage) error {
+	bot := c.Get("bot").(*OGame)
+	challengeID := c.Param("challengeID")
+	if challengeID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResp(400, "invalid challenge id"))
+	}
+	raw, contentType, err := GetCaptchaImage(bot.GetClient(), bot.ctx, challengeID, bot.lobbyLocale, image, c.QueryString())
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, ErrorResp(502, err.Error()))
+	}
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Blob(http.StatusOK, contentType, raw)
 }
 
 // CaptchaChallenge ...
@@ -1413,27 +3180,34 @@ type CaptchaChallenge struct {
 	Icons    string
 }
 
-// GetCaptchaChallengeHandler ...
-func GetCaptchaChallengeHandler(c echo.Context) error {
-	bot := c.Get("bot").(*OGame)
-	_, err := GFLogin(bot.client, bot.ctx, bot.lobby, bot.Username, bot.password, bot.otpSecret, "")
+// newCaptchaChallenge starts the captcha challenge required to login, if any (the ID is empty otherwise)
+func newCaptchaChallenge(bot *OGame) (CaptchaChallenge, error) {
+	_, err := GFLoginWithLocale(bot.client, bot.ctx, bot.lobby, bot.Username, bot.password, bot.otpSecret, "", bot.lobbyLocale)
 	var captchaErr *CaptchaRequiredError
 	if errors.As(err, &captchaErr) {
-		questionRaw, iconsRaw, err := StartCaptchaChallenge(bot.GetClient(), bot.ctx, captchaErr.ChallengeID)
+		questionRaw, iconsRaw, err := StartCaptchaChallengeWithLocale(bot.GetClient(), bot.ctx, captchaErr.ChallengeID, bot.lobbyLocale)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
+			return CaptchaChallenge{}, err
 		}
-		questionB64 := base64.StdEncoding.EncodeToString(questionRaw)
-		iconsB64 := base64.StdEncoding.EncodeToString(iconsRaw)
-		return c.JSON(http.StatusOK, SuccessResp(CaptchaChallenge{
+		return CaptchaChallenge{
 			ID:       captchaErr.ChallengeID,
-			Question: questionB64,
-			Icons:    iconsB64,
-		}))
+			Question: base64.StdEncoding.EncodeToString(questionRaw),
+			Icons:    base64.StdEncoding.EncodeToString(iconsRaw),
+		}, nil
 	} else if err != nil {
+		return CaptchaChallenge{}, err
+	}
+	return CaptchaChallenge{}, nil
+}
+
+// GetCaptchaChallengeHandler ...
+func GetCaptchaChallengeHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	challenge, err := newCaptchaChallenge(bot)
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResp(500, err.Error()))
 	}
-	return c.JSON(http.StatusOK, SuccessResp(CaptchaChallenge{}))
+	return c.JSON(http.StatusOK, SuccessResp(challenge))
 }
 
 // GetPublicIPHandler ...
@@ -1445,3 +3219,39 @@ func GetPublicIPHandler(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, SuccessResp(ip))
 }
+
+// WSHandler stream the game websocket events (auctioneer, chat, ws_state) as json.
+// The bot reconnects to the game websocket on its own, clients stay connected and receive ws_state events.
+func WSHandler(c echo.Context) error {
+	bot := c.Get("bot").(*OGame)
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+		events, unsubscribe := bot.SubscribeEvents()
+		defer unsubscribe()
+		// Detect when the client disconnects
+		closedCh := make(chan struct{})
+		go func() {
+			defer close(closedCh)
+			var msg string
+			for {
+				if err := websocket.Message.Receive(ws, &msg); err != nil {
+					return
+				}
+			}
+		}()
+		for {
+			select {
+			case evt, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(ws, evt); err != nil {
+					return
+				}
+			case <-closedCh:
+				return
+			}
+		}
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
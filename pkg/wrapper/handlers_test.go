@@ -0,0 +1,505 @@
+package wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCriticalRoutes = []string{"/bot/fleets/:fleetID/cancel", "/bot/planets/:planetID/send-fleet"}
+
+func TestParseTaskPriority(t *testing.T) {
+	priority, err := ParseTaskPriority("critical")
+	assert.NoError(t, err)
+	assert.Equal(t, taskRunner.Critical, priority)
+	priority, err = ParseTaskPriority(" Low ")
+	assert.NoError(t, err)
+	assert.Equal(t, taskRunner.Low, priority)
+	priority, err = ParseTaskPriority("")
+	assert.NoError(t, err)
+	assert.Equal(t, taskRunner.Normal, priority)
+	_, err = ParseTaskPriority("urgent")
+	assert.Error(t, err)
+}
+
+func newPriorityTestServer(handler echo.HandlerFunc) *echo.Echo {
+	e := echo.New()
+	e.Use(TaskPriorityMiddleware(testCriticalRoutes))
+	e.GET("/bot/galaxy-infos/:galaxy/:system", handler)
+	e.POST("/bot/fleets/:fleetID/cancel", handler)
+	e.POST("/bot/planets/:planetID/send-fleet", handler)
+	return e
+}
+
+// newBotTestServer returns an echo server whose handlers get bot, the way ogamed injects it
+func newBotTestServer(bot *OGame) *echo.Echo {
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("bot", bot)
+			return next(c)
+		}
+	})
+	return e
+}
+
+func doPriorityRequest(e *echo.Echo, method, target, priority string) (*httptest.ResponseRecorder, APIResp) {
+	req := httptest.NewRequest(method, target, nil)
+	if priority != "" {
+		req.Header.Set(TaskPriorityHeader, priority)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	var resp APIResp
+	_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+	return rec, resp
+}
+
+func TestTaskPriorityMiddleware(t *testing.T) {
+	e := newPriorityTestServer(func(c echo.Context) error {
+		return c.JSON(http.StatusOK, SuccessResp(nil))
+	})
+
+	rec, resp := doPriorityRequest(e, http.MethodGet, "/bot/galaxy-infos/1/2", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "normal", resp.Priority)
+	assert.Equal(t, "normal", rec.Header().Get(TaskPriorityHeader))
+
+	rec, resp = doPriorityRequest(e, http.MethodGet, "/bot/galaxy-infos/1/2", "low")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "low", resp.Priority)
+
+	rec, _ = doPriorityRequest(e, http.MethodGet, "/bot/galaxy-infos/1/2", "urgent")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = doPriorityRequest(e, http.MethodGet, "/bot/galaxy-infos/1/2", "critical")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, resp = doPriorityRequest(e, http.MethodPost, "/bot/fleets/123/cancel", "critical")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "critical", resp.Priority)
+
+	rec, resp = doPriorityRequest(e, http.MethodPost, "/bot/planets/123/send-fleet", "critical")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "critical", resp.Priority)
+}
+
+type priorityTestItem struct {
+	taskDoneCh chan struct{}
+	mu         *sync.Mutex
+	order      *[]string
+}
+
+func (i *priorityTestItem) SetTaskDoneCh(ch chan struct{}) {
+	i.taskDoneCh = ch
+}
+
+func (i *priorityTestItem) Do(name string) {
+	defer close(i.taskDoneCh)
+	i.mu.Lock()
+	*i.order = append(*i.order, name)
+	i.mu.Unlock()
+}
+
+func TestTaskPriorityMiddleware_criticalRecallOvertakesLowScans(t *testing.T) {
+	mu := &sync.Mutex{}
+	order := make([]string, 0)
+	factory := func() *priorityTestItem { return &priorityTestItem{mu: mu, order: &order} }
+	tr := taskRunner.NewTaskRunner[*priorityTestItem](context.Background(), factory)
+	waitTasks := func(nbr int64) {
+		for tr.GetTasks().Total != nbr {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	e := newPriorityTestServer(func(c echo.Context) error {
+		tr.WithPriority(taskPriority(c)).Do(c.Path())
+		return c.JSON(http.StatusOK, SuccessResp(nil))
+	})
+
+	// Hold the runner busy while the requests are queued
+	blocking := tr.WithPriority(taskRunner.Normal)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() { doPriorityRequest(e, http.MethodGet, "/bot/galaxy-infos/1/2", "low"); wg.Done() }()
+	}
+	waitTasks(3)
+	wg.Add(1)
+	go func() { doPriorityRequest(e, http.MethodPost, "/bot/fleets/123/cancel", "critical"); wg.Done() }()
+	waitTasks(4)
+	blocking.Do("blocking")
+	wg.Wait()
+
+	assert.Equal(t, 5, len(order))
+	assert.Equal(t, "blocking", order[0])
+	assert.Equal(t, "/bot/fleets/:fleetID/cancel", order[1])
+}
+
+func TestSetCrawlerPolicyHandler(t *testing.T) {
+	bot := &OGame{}
+	e := newBotTestServer(bot)
+	e.PUT("/bot/crawler-policy", SetCrawlerPolicyHandler)
+	doPut := func(body string) int {
+		req := httptest.NewRequest(http.MethodPut, "/bot/crawler-policy", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, doPut("interval=10"))
+	assert.Equal(t, http.StatusBadRequest, doPut("planetID=abc"))
+	assert.Equal(t, http.StatusOK, doPut("enabled=false&build=true&interval=600&planetID=123&planetID=456"))
+	status := bot.GetCrawlerPolicyStatus()
+	assert.Equal(t, CrawlerPolicy{IntervalSeconds: 600, BuildCrawlers: true, PlanetIDs: []ogame.PlanetID{123, 456}}, status.Policy)
+	assert.Equal(t, 0, len(status.Planets))
+}
+
+func TestCoordParam(t *testing.T) {
+	bot := &OGame{serverData: ServerData{Galaxies: 5, Systems: 499}}
+	e := echo.New()
+	handler := func(c echo.Context) error {
+		coord, err := coordParam(c, bot)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResp(400, err.Error()))
+		}
+		return c.JSON(http.StatusOK, SuccessResp(coord))
+	}
+	e.GET("/bot/planets/:galaxy/:system/:position", handler)
+	e.GET("/bot/planets/by-coord", handler)
+	doGet := func(target string) (int, ogame.Coordinate) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var resp struct{ Result ogame.Coordinate }
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp.Result
+	}
+
+	code, coord := doGet("/bot/planets/1/234/8")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 234, Position: 8, Type: ogame.PlanetType}, coord)
+	code, coord = doGet("/bot/planets/by-coord?coord=M:1:234:8")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 234, Position: 8, Type: ogame.MoonType}, coord)
+	code, _ = doGet("/bot/planets/by-coord?coord=%5B1:234:16%5D")
+	assert.Equal(t, http.StatusOK, code)
+	// Form values are used when the route has no galaxy/system/position parameters
+	code, coord = doGet("/bot/planets/by-coord?galaxy=1&system=234&position=8")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ogame.Coordinate{Galaxy: 1, System: 234, Position: 8, Type: ogame.PlanetType}, coord)
+
+	code, _ = doGet("/bot/planets/by-coord?coord=1:234")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doGet("/bot/planets/by-coord?coord=6:234:8")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doGet("/bot/planets/1/500/8")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doGet("/bot/planets/1/234/17")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doGet("/bot/planets/by-coord")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestUnsupportedCapabilityHandlers(t *testing.T) {
+	bot := &OGame{serverData: ServerData{Version: "6.8.8"}}
+	e := newBotTestServer(bot)
+	e.GET("/bot/planets/:planetID/lifeform-buildings", GetLfBuildingsHandler)
+	e.GET("/bot/capabilities", GetCapabilitiesHandler)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bot/planets/123/lifeform-buildings", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unsupported capability: lifeform")
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bot/capabilities", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp struct{ Result ogame.Capabilities }
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Result.Supports(ogame.CapabilityLifeform))
+	assert.Len(t, resp.Result, 6)
+}
+
+func TestGalaxyFilterParam(t *testing.T) {
+	e := echo.New()
+	filter := func(query string) (func(ogame.PlanetInfos) bool, error) {
+		return galaxyFilterParam(e.NewContext(httptest.NewRequest(http.MethodGet, "/bot/galaxy-infos/1/2?"+query, nil), httptest.NewRecorder()))
+	}
+	pred, err := filter("")
+	assert.NoError(t, err)
+	assert.Nil(t, pred)
+
+	inactiveWithMoon := ogame.PlanetInfos{Inactive: true, Moon: &ogame.MoonInfos{ID: 1}, Relation: ogame.RelationEnemy}
+	inactive := ogame.PlanetInfos{Inactive: true, Relation: ogame.RelationEnemy}
+	pred, err = filter("moon=1&inactive=1")
+	assert.NoError(t, err)
+	assert.True(t, pred(inactiveWithMoon))
+	assert.False(t, pred(inactive))
+	pred, _ = filter("moon=0&relation=enemy")
+	assert.False(t, pred(inactiveWithMoon))
+	assert.True(t, pred(inactive))
+	pred, _ = filter("relation=mine")
+	assert.False(t, pred(inactive))
+
+	_, err = filter("moon=yes")
+	assert.Error(t, err)
+	_, err = filter("relation=friend")
+	assert.Error(t, err)
+}
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	bot := &OGame{clock: clock}
+	e := newBotTestServer(bot)
+	e.Use(MaintenanceMiddleware)
+	ok := func(c echo.Context) error { return c.JSON(http.StatusOK, SuccessResp(nil)) }
+	e.GET("/bot/server/time", ok)
+	e.GET("/bot/maintenance", GetMaintenanceHandler)
+	e.GET("/bot/login", ok)
+	e.POST("/bot/simulate-combat", ok)
+
+	rec, _ := doPriorityRequest(e, http.MethodGet, "/bot/server/time", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	bot.maintenanceAtom = 1
+	bot.maintenanceWindows = []MaintenanceWindow{{Start: clock.Now()}}
+	bot.maintenanceNextProbe = clock.Now().Add(30 * time.Second)
+	rec, resp := doPriorityRequest(e, http.MethodGet, "/bot/server/time", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, 503, resp.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+	// Routes that do not hit the game server keep working
+	rec, _ = doPriorityRequest(e, http.MethodGet, "/bot/login", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec, _ = doPriorityRequest(e, http.MethodPost, "/bot/simulate-combat", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, resp = doPriorityRequest(e, http.MethodGet, "/bot/maintenance", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, true, resp.Result.(map[string]any)["InMaintenance"])
+	assert.Len(t, resp.Result.(map[string]any)["Windows"], 1)
+}
+
+type captchaRoundTripper struct {
+	urls []string
+}
+
+func (rt *captchaRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.urls = append(rt.urls, req.URL.String())
+	resp := &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: make(http.Header), Request: req}
+	switch {
+	case req.Method == http.MethodPost:
+		resp.Body = io.NopCloser(strings.NewReader(`{"id":"abc","status":"presented"}`))
+	case strings.HasSuffix(req.URL.Path, "/text"):
+		resp.Header.Set("Content-Type", "text/plain")
+		resp.Body = io.NopCloser(strings.NewReader("\x89PNG\r\n\x1a\nquestion"))
+	case strings.HasSuffix(req.URL.Path, "/drag-icons"):
+		resp.Header.Set("Content-Type", "image/jpeg")
+		resp.Body = io.NopCloser(strings.NewReader("icons"))
+	default:
+		resp.StatusCode, resp.Status = http.StatusNotFound, "404 Not Found"
+		resp.Body = io.NopCloser(strings.NewReader(""))
+	}
+	return resp, nil
+}
+
+func TestGetCaptchaImageHandlers(t *testing.T) {
+	rt := &captchaRoundTripper{}
+	client := httpclient.NewClient()
+	client.Transport = rt
+	bot := &OGame{client: client, ctx: context.Background(), lobbyLocale: DefaultLobbyLocale}
+	e := newBotTestServer(bot)
+	e.GET("/bot/captcha/question/:challengeID", GetCaptchaQuestionHandler)
+	e.GET("/bot/captcha/icons/:challengeID", GetCaptchaIconsHandler)
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/bot/captcha/question/abc?1650000000000")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "\x89PNG\r\n\x1a\nquestion", rec.Body.String())
+	assert.Equal(t, "https://image-drop-challenge.gameforge.com/challenge/abc/en-GB/text?1650000000000", rt.urls[0])
+
+	rec = get("/bot/captcha/icons/abc")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/jpeg", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "https://image-drop-challenge.gameforge.com/challenge/abc/en-GB/drag-icons", rt.urls[1])
+}
+
+func TestGetCaptchaSolverHandler(t *testing.T) {
+	client := httpclient.NewClient()
+	client.Transport = &captchaRoundTripper{}
+	bot := &OGame{client: client, ctx: context.Background(), lobbyLocale: DefaultLobbyLocale, quiet: true}
+	e := newBotTestServer(bot)
+	e.POST("/bot/captcha/solve", GetCaptchaSolverHandler)
+	post := func(body string) (*httptest.ResponseRecorder, APIResp) {
+		req := httptest.NewRequest(http.MethodPost, "/bot/captcha/solve", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var resp APIResp
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	rec, _ := post("challenge_id=abc")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = post("challenge_id=abc&answer=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = post("answer=1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, resp := post("challenge_id=abc&answer=1")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "pending", resp.Result.(map[string]any)["Status"])
+	assert.Equal(t, false, resp.Result.(map[string]any)["NewChallengeIssued"])
+
+	// JSON body
+	req := httptest.NewRequest(http.MethodPost, "/bot/captcha/solve", strings.NewReader(`{"challenge_id":"abc","answer":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	req = httptest.NewRequest(http.MethodPost, "/bot/captcha/solve", strings.NewReader(`{"challenge_id":"abc"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// The form of the captcha page is redirected to the home page
+	req = httptest.NewRequest(http.MethodPost, "/bot/captcha/solve", strings.NewReader("challenge_id=abc&answer=1"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	assert.Equal(t, "/", rec.Header().Get(echo.HeaderLocation))
+}
+
+func TestParseCaptchaStatus(t *testing.T) {
+	assert.Equal(t, CaptchaSolved, parseCaptchaStatus([]byte(`{"status":"solved"}`)))
+	assert.Equal(t, CaptchaPending, parseCaptchaStatus([]byte(`{"status":"presented"}`)))
+	assert.Equal(t, CaptchaFailed, parseCaptchaStatus([]byte(`{"status":"failed"}`)))
+	assert.Equal(t, CaptchaFailed, parseCaptchaStatus([]byte(`not json`)))
+}
+
+func TestFlightTimeHandler_invalidParams(t *testing.T) {
+	bot := &OGame{serverData: ServerData{Galaxies: 5, Systems: 499}}
+	bot.planets = []Planet{{Planet: ogame.Planet{ID: 123, Coordinate: ogame.Coordinate{Galaxy: 1, System: 2, Position: 3, Type: ogame.PlanetType}}}}
+	e := newBotTestServer(bot)
+	e.GET("/bot/planets/:planetID/flight-time", FlightTimeHandler)
+	doGet := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := doGet("/bot/planets/456/flight-time?ships=203,1&galaxy=1&system=2&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), ogame.ErrInvalidPlanetID.Error())
+	rec = doGet("/bot/planets/123/flight-time?ships=203,1&galaxy=6&system=2&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doGet("/bot/planets/123/flight-time?ships=203,1&galaxy=1&system=2&position=4&speed=11")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid speed")
+	rec = doGet("/bot/planets/123/flight-time?ships=401,1&galaxy=1&system=2&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid ship id 401")
+	rec = doGet("/bot/planets/123/flight-time?galaxy=1&system=2&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no ships")
+}
+
+func TestSimulateCombatHandler(t *testing.T) {
+	bot := &OGame{serverData: ServerData{DebrisFactor: 0.3}}
+	e := newBotTestServer(bot)
+	e.POST("/bot/simulate-combat", SimulateCombatHandler)
+	doPost := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bot/simulate-combat", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := doPost(`{"Attacker":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doPost(`{"Simulations":1001}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid simulations")
+	rec = doPost(`{"Simulations":2,"Logs":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doPost(`{"Attacker":{"Cruiser":200000},"Defender":{"RocketLauncher":200000}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid units")
+	rec = doPost(`{"Attacker":{"Cruiser":-5},"Defender":{"RocketLauncher":100}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doPost(`{"Attacker":{"Cruiser":9223372036854775807},"Defender":{"RocketLauncher":100}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doPost(`{"Attacker":{"Cruiser":20000},"Defender":{"RocketLauncher":20000},"Simulations":100}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too many units")
+
+	body := `{"Attacker":{"Cruiser":20},"Defender":{"RocketLauncher":100},"Simulations":3,"Seed":7}`
+	rec = doPost(body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, rec.Body.String(), doPost(body).Body.String())
+}
+
+func TestDebugVarsHandler(t *testing.T) {
+	e := echo.New()
+	e.GET("/debug/vars", DebugVarsHandler)
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var vars map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	assert.Contains(t, vars, "ogame_throttled_total")
+	assert.Contains(t, vars, "ogame_compatibility_mode_total")
+	assert.NotContains(t, vars, "cmdline")
+	assert.NotContains(t, vars, "memstats")
+}
+
+func TestQuickSpyHandler_invalidParams(t *testing.T) {
+	bot := &OGame{serverData: ServerData{Galaxies: 5, Systems: 499}}
+	e := newBotTestServer(bot)
+	e.POST("/bot/quick-spy", QuickSpyHandler)
+	doPost := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/bot/quick-spy", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := doPost("galaxy=6&system=2&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "galaxy must be within [1, 5]")
+	rec = doPost("galaxy=1&system=x&position=4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid system")
+	rec = doPost("coord=1:2:16")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid position")
+	rec = doPost("coord=DF:1:2:4")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid type")
+	rec = doPost("galaxy=1&system=2&position=4&nbProbes=-1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid nbProbes")
+}
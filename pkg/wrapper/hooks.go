@@ -0,0 +1,116 @@
+package wrapper
+
+import (
+	"sync"
+	"time"
+)
+
+// hooksQueueSize number of callbacks that can wait to be dispatched, callbacks are dropped past it
+const hooksQueueSize = 1024
+
+// RequestInfo request made to the game server, passed to the OnRequest callbacks
+type RequestInfo struct {
+	Method     string
+	URL        string
+	Duration   time.Duration
+	StatusCode int   // 0 if no response was received
+	Err        error // Transport error, if any
+}
+
+// hooks callbacks registered by library users.
+// They are all called, in order, on a single dispatch goroutine, so a slow callback cannot stall the bot.
+type hooks struct {
+	sync.Mutex
+	startOnce      sync.Once
+	queue          chan func()
+	loginSuccess   []func()
+	loginFailure   []func(err error)
+	sessionExpired []func()
+	request        []func(RequestInfo)
+}
+
+// OnLoginSuccess register a callback that is called after each successful login
+func (b *OGame) OnLoginSuccess(clb func()) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	b.hooks.loginSuccess = append(b.hooks.loginSuccess, clb)
+}
+
+// OnLoginFailure register a callback that is called after each failed login
+func (b *OGame) OnLoginFailure(clb func(err error)) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	b.hooks.loginFailure = append(b.hooks.loginFailure, clb)
+}
+
+// OnSessionExpired register a callback that is called when the game reports that we are no longer logged in
+func (b *OGame) OnSessionExpired(clb func()) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	b.hooks.sessionExpired = append(b.hooks.sessionExpired, clb)
+}
+
+// OnRequest register a callback that is called after each request made to the game server
+func (b *OGame) OnRequest(clb func(RequestInfo)) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	b.hooks.request = append(b.hooks.request, clb)
+}
+
+// dispatchHook queues fn to be called on the dispatch goroutine
+func (b *OGame) dispatchHook(fn func()) {
+	b.hooks.startOnce.Do(func() {
+		b.hooks.queue = make(chan func(), hooksQueueSize)
+		go func() {
+			for fn := range b.hooks.queue {
+				b.runHook(fn)
+			}
+		}()
+	})
+	select {
+	case b.hooks.queue <- fn:
+	default:
+		b.error("hooks queue is full, callback dropped")
+	}
+}
+
+func (b *OGame) runHook(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.error("hook callback panicked:", r)
+		}
+	}()
+	fn()
+}
+
+func (b *OGame) loginDone(err error) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	if err == nil {
+		for _, clb := range b.hooks.loginSuccess {
+			b.dispatchHook(clb)
+		}
+		return
+	}
+	for _, clb := range b.hooks.loginFailure {
+		clb := clb
+		b.dispatchHook(func() { clb(err) })
+	}
+}
+
+func (b *OGame) sessionExpired() {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	for _, clb := range b.hooks.sessionExpired {
+		b.dispatchHook(clb)
+	}
+}
+
+func (b *OGame) requestDone(info RequestInfo) {
+	b.hooks.Lock()
+	defer b.hooks.Unlock()
+	for _, clb := range b.hooks.request {
+		clb := clb
+		b.dispatchHook(func() { clb(info) })
+	}
+}
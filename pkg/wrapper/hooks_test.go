@@ -0,0 +1,33 @@
+package wrapper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	b := &OGame{quiet: true}
+	calls := make(chan string, 10)
+	b.OnLoginSuccess(func() { panic("boom") })
+	b.OnLoginSuccess(func() { calls <- "success" })
+	b.OnLoginFailure(func(err error) { calls <- "failure: " + err.Error() })
+	b.OnSessionExpired(func() { calls <- "expired" })
+	b.OnRequest(func(info RequestInfo) { calls <- info.Method + " " + info.URL })
+	b.OnStateChange(func(locked bool, actor string) { calls <- "state: " + actor })
+
+	b.loginDone(nil)
+	b.loginDone(errors.New("bad credentials"))
+	b.sessionExpired()
+	b.requestDone(RequestInfo{Method: "GET", URL: "https://example.com", Duration: time.Second, StatusCode: 200})
+	b.stateChanged(true, "Login")
+
+	// A panicking callback does not prevent the next ones from being called, and the order is kept
+	assert.Equal(t, "success", <-calls)
+	assert.Equal(t, "failure: bad credentials", <-calls)
+	assert.Equal(t, "expired", <-calls)
+	assert.Equal(t, "GET https://example.com", <-calls)
+	assert.Equal(t, "state: Login", <-calls)
+}
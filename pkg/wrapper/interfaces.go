@@ -1,6 +1,7 @@
 package wrapper
 
 import (
+	"context"
 	"crypto/tls"
 	"net/http"
 	"net/url"
@@ -46,6 +47,7 @@ type Celestial interface {
 type Prioritizable interface {
 	Abandon(any) error
 	ActivateItem(string, ogame.CelestialID) error
+	AttacksRemainingAgainst(target ogame.Coordinate) (int64, time.Time, error)
 	Begin() Prioritizable
 	BeginNamed(name string) Prioritizable
 	BuyMarketplace(itemID int64, celestialID ogame.CelestialID) error
@@ -55,37 +57,53 @@ type Prioritizable interface {
 	CollectMarketplaceMessage(ogame.MarketplaceMessage) error
 	CreateUnion(fleet ogame.Fleet, unionUsers []string) (int64, error)
 	DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error
+	DeleteEspionageReportsOlderThan(d time.Duration) (int64, error)
 	DeleteMessage(msgID int64) error
 	DoAuction(bid map[ogame.CelestialID]ogame.Resources) error
 	Done()
+	FindColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error)
 	FlightTime(origin, destination ogame.Coordinate, speed ogame.Speed, ships ogame.ShipsInfos, mission ogame.MissionID) (secs, fuel int64)
 	GalaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemInfos, error)
+	GetACSUnionDetails(unionID int64) (ogame.ACSUnionDetails, error)
 	GetActiveItems(ogame.CelestialID) ([]ogame.ActiveItem, error)
 	GetAllResources() (map[ogame.CelestialID]ogame.Resources, error)
 	GetAttacks(...Option) ([]ogame.AttackEvent, error)
 	GetAuction() (ogame.Auction, error)
 	GetCachedResearch() ogame.Researches
 	GetCelestial(any) (Celestial, error)
+	GetCelestialsByCoord(ogame.Coordinate) ([]Celestial, error)
 	GetCelestials() ([]Celestial, error)
 	GetCombatReportSummaryFor(ogame.Coordinate) (ogame.CombatReportSummary, error)
 	GetDMCosts(ogame.CelestialID) (ogame.DMCosts, error)
+	GetDarkMatter() (int64, error)
+	GetEffectiveLabLevel(planetID ogame.PlanetID) (int64, error)
+	GetEffectiveSpeeds() (ogame.EffectiveSpeeds, error)
 	GetEmpire(ogame.CelestialType) ([]ogame.EmpireCelestial, error)
 	GetEmpireJSON(nbr int64) (any, error)
 	GetEspionageReport(msgID int64) (ogame.EspionageReport, error)
+	GetEspionageReportDiff(ogame.Coordinate) (ogame.EspionageReportDiff, error)
 	GetEspionageReportFor(ogame.Coordinate) (ogame.EspionageReport, error)
 	GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error)
+	GetEventList(...Option) ([]ogame.Event, error)
 	GetExpeditionMessageAt(time.Time) (ogame.ExpeditionMessage, error)
 	GetExpeditionMessages() ([]ogame.ExpeditionMessage, error)
+	GetFleet(fleetID ogame.FleetID) (ogame.Fleet, error)
 	GetFleets(...Option) ([]ogame.Fleet, ogame.Slots)
 	GetFleetsFromEventList() []ogame.Fleet
+	GetFriendlyArrivals() ([]ogame.Event, error)
 	GetItems(ogame.CelestialID) ([]ogame.Item, error)
+	GetMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error)
 	GetMoon(any) (Moon, error)
 	GetMoons() []Moon
+	GetMyPoints() (ogame.Points, error)
+	GetNotifications() ([]ogame.Notification, error)
 	GetPageContent(url.Values) ([]byte, error)
 	GetPlanet(any) (Planet, error)
 	GetPlanets() []Planet
 	GetResearch() ogame.Researches
 	GetSlots() ogame.Slots
+	GetSpiedEvents(since time.Time) ([]ogame.SpiedEvent, error)
+	GetSpyReportSettings() (ogame.SpyReportSettings, error)
 	GetUserInfos() ogame.UserInfos
 	HeadersForPage(url string) (http.Header, error)
 	Highscore(category, typ, page int64) (ogame.Highscore, error)
@@ -96,12 +114,16 @@ type Prioritizable interface {
 	Logout()
 	OfferBuyMarketplace(itemID any, quantity, priceType, price, priceRange int64, celestialID ogame.CelestialID) error
 	OfferSellMarketplace(itemID any, quantity, priceType, price, priceRange int64, celestialID ogame.CelestialID) error
+	PlanToMatch(celestialID ogame.CelestialID, target ogame.ResourcesBuildings) ([]ogame.BuildStep, ogame.Resources, time.Duration, error)
 	PostPageContent(url.Values, url.Values) ([]byte, error)
+	QuickSpy(target ogame.Coordinate, nbProbes int64) (ogame.Fleet, error)
 	RecruitOfficer(typ, days int64) error
 	SendMessage(playerID int64, message string) error
 	SendMessageAlliance(associationID int64, message string) error
 	ServerTime() time.Time
 	SetInitiator(initiator string) Prioritizable
+	SetSlotReservation(token string) Prioritizable
+	SetSpyReportSettings(ogame.SpyReportSettings) error
 	SetVacationMode() error
 	Tx(clb func(tx Prioritizable) error) error
 	UseDM(string, ogame.CelestialID) error
@@ -116,22 +138,33 @@ type Prioritizable interface {
 	BuildTechnology(celestialID ogame.CelestialID, technologyID ogame.ID) error
 	CancelBuilding(ogame.CelestialID) error
 	CancelLfBuilding(ogame.CelestialID) error
+	CancelQueuedBuild(celestialID ogame.CelestialID, index int64) error
 	CancelResearch(ogame.CelestialID) error
 	ConstructionsBeingBuilt(ogame.CelestialID) (buildingID ogame.ID, buildingCountdown int64, researchID ogame.ID, researchCountdown int64, lfBuildingID ogame.ID, lfBuildingCountdown int64, lfResearchID ogame.ID, lfResearchCountdown int64)
 	EnsureFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+	GetAllowedSpeeds(celestialID ogame.CelestialID) ([]ogame.Speed, error)
+	GetBuildQueue(celestialID ogame.CelestialID) ([]ogame.QueuedBuild, error)
 	GetDefense(ogame.CelestialID, ...Option) (ogame.DefensesInfos, error)
 	GetFacilities(ogame.CelestialID, ...Option) (ogame.Facilities, error)
 	GetLfBuildings(ogame.CelestialID, ...Option) (ogame.LfBuildings, error)
 	GetLfResearch(ogame.CelestialID, ...Option) (ogame.LfResearches, error)
+	GetMissiles(celestialID ogame.CelestialID) (ipm, abm, siloCapacity int64, err error)
 	GetProduction(ogame.CelestialID) ([]ogame.Quantifiable, int64, error)
 	GetResources(ogame.CelestialID) (ogame.Resources, error)
 	GetResourcesBuildings(ogame.CelestialID, ...Option) (ogame.ResourcesBuildings, error)
 	GetResourcesDetails(ogame.CelestialID) (ogame.ResourcesDetails, error)
 	GetShips(ogame.CelestialID, ...Option) (ogame.ShipsInfos, error)
+	GetTemperatureEffects(celestialID ogame.CelestialID) (satelliteEnergyPerUnit int64, deutProductionFactor float64, err error)
 	GetTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error)
+	GetWastedResources(ogame.CelestialID) (ogame.Resources, error)
+	MineUpgradeROI(celestialID ogame.CelestialID, mineID ogame.ID) (time.Duration, error)
 	SendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+	SendFleetAndRecall(celestialID ogame.CelestialID, ships []ogame.Quantifiable, where ogame.Coordinate, mission ogame.MissionID, holdSeconds int64) (ogame.Fleet, int64, error)
+	SendFleetWithPayload(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, spec ogame.PayloadSpec, holdingTime, unionID int64) (ogame.Fleet, ogame.Resources, error)
+	ShipBuildTime(celestialID ogame.CelestialID, shipID ogame.ID, count int64) (time.Duration, error)
 	TearDown(celestialID ogame.CelestialID, id ogame.ID) error
 	TechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error)
+	ValidateFleetTarget(celestialID ogame.CelestialID, where ogame.Coordinate, mission ogame.MissionID) ([]ogame.FleetRestriction, error)
 
 	// Planet specific functions
 	DestroyRockets(ogame.PlanetID, int64, int64) error
@@ -142,6 +175,7 @@ type Prioritizable interface {
 	SetResourceSettings(ogame.PlanetID, ogame.ResourceSettings) error
 
 	// Moon specific functions
+	GetMoonFacilities(ogame.MoonID) (ogame.MoonFacilities, error)
 	JumpGate(origin, dest ogame.MoonID, ships ogame.ShipsInfos) (bool, int64, error)
 	JumpGateDestinations(origin ogame.MoonID) ([]ogame.MoonID, int64, error)
 	Phalanx(ogame.MoonID, ogame.Coordinate) ([]ogame.Fleet, error)
@@ -152,62 +186,163 @@ type Prioritizable interface {
 type Wrapper interface {
 	Prioritizable
 	AddAccount(number int, lang string) (*AddAccountRes, error)
+	ApplyResourceProfile(planetID ogame.PlanetID, name string) (ResourceProfileChange, error)
+	ApplyResourceProfileAll(ctx context.Context, name string) ([]ResourceProfileChange, error)
 	BytesDownloaded() int64
 	BytesUploaded() int64
+	CancelRecallJob(jobID int64) error
 	CharacterClass() ogame.CharacterClass
+	CheckExpeditionFleet(ships ogame.ShipsInfos, trim bool) (ogame.ShipsInfos, bool, error)
 	ConstructionTime(id ogame.ID, nbr int64, facilities ogame.Facilities) time.Duration
+	DeleteMessagesWhere(ctx context.Context, tabID ogame.MessagesTabID, predicate func(ogame.MessageSummary) bool) (MessagesDeletion, error)
 	Disable()
 	Distance(origin, destination ogame.Coordinate) int64
 	Enable()
 	FleetDeutSaveFactor() float64
+	GetAutoSatsStatus() AutoSatsStatus
 	GetCachedCelestial(any) Celestial
 	GetCachedCelestials() []Celestial
 	GetCachedMoons() []Moon
 	GetCachedPlanets() []Planet
 	GetCachedPlayer() ogame.UserInfos
 	GetCachedPreferences() ogame.Preferences
+	GetCapabilities() ogame.Capabilities
+	GetCelestialImage(ogame.CelestialID) ([]byte, string, error)
 	GetClient() *httpclient.Client
+	GetCrawlerPolicyStatus() CrawlerPolicyStatus
+	GetExpeditionCap() (ExpeditionCap, error)
+	GetExposure(threshold int64) (ExposureReport, error)
+	GetExposureAlert() ExposureAlert
 	GetExtractor() extractor.Extractor
 	GetLanguage() string
+	GetLastActivity() (time.Time, error)
+	GetMaintenanceWindows() []MaintenanceWindow
 	GetNbSystems() int64
 	GetPublicIP() (string, error)
+	GetRecallJobs() []RecallJob
 	GetResearchSpeed() int64
+	GetResourceProfileChanges() []ResourceProfileChange
+	GetResourceProfiles() map[string]ogame.ResourceSettings
+	GetSelfTestResults() []SelfTestResult
+	GetSelfTestSchedule() string
 	GetServer() Server
 	GetServerData() ServerData
+	GetServerSettings() ServerSettings
+	GetServerTimeOffset() time.Duration
+	GetSlotReservations() []SlotReservation
 	GetSession() string
 	GetState() (bool, string)
+	GetStorageWebhook() StorageWebhook
+	GetThrottleState() ThrottleState
 	GetTasks() taskRunner.TasksOverview
 	GetUniverseName() string
 	GetUniverseSpeed() int64
 	GetUniverseSpeedFleet() int64
 	GetUsername() string
+	IsCompatibilityMode() bool
+	Info() BotInfo
 	IsConnected() bool
 	IsDonutGalaxy() bool
 	IsDonutSystem() bool
 	IsEnabled() bool
+	IsInMaintenance() bool
 	IsLocked() bool
 	IsLoggedIn() bool
 	IsPioneers() bool
+	IsProbeRaids() bool
+	IsThrottled() bool
 	IsV7() bool
 	IsV9() bool
 	IsVacationModeEnabled() bool
 	Location() *time.Location
+	MissileDefenseStatus() ([]ogame.MissileDefense, error)
+	OnLoginFailure(clb func(err error))
+	OnLoginSuccess(clb func())
+	OnRequest(clb func(RequestInfo))
+	OnSessionExpired(clb func())
 	OnStateChange(clb func(locked bool, actor string))
 	Quiet(bool)
 	ReconnectChat() bool
+	ReleaseSlots(token string) error
 	RegisterAuctioneerCallback(func(any))
 	RegisterChatCallback(func(ogame.ChatMsg))
 	RegisterHTMLInterceptor(func(method, url string, params, payload url.Values, pageHTML []byte))
 	RegisterWSCallback(string, func([]byte))
 	RemoveWSCallback(string)
+	ReserveSlots(owner string, n int64, ttl time.Duration) (SlotReservation, error)
+	RevertResourceProfile(planetID ogame.PlanetID) (ResourceProfileChange, error)
+	RunSelfTest() SelfTestResult
+	SendMessages(ctx context.Context, playerIDs []int64, message string) ([]MessageStatus, error)
 	ServerURL() string
 	ServerVersion() string
+	SetAPINewHostname(hostname string) error
+	SetAutoSatsPolicy(AutoSatsPolicy)
 	SetClient(*httpclient.Client)
+	SetCrawlerPolicy(CrawlerPolicy)
+	SetExposureAlert(ExposureAlert)
 	SetGetServerDataWrapper(func(func() (ServerData, error)) (ServerData, error))
 	SetLoginWrapper(func(func() (bool, error)) error)
 	SetOGameCredentials(username, password, otpSecret, bearerToken string)
 	SetProxy(proxyAddress, username, password, proxyType string, loginOnly bool, config *tls.Config) error
+	SetResourceProfile(name string, settings ogame.ResourceSettings) error
+	SetSelfTestSchedule(schedule string) error
+	SetStorageWebhook(StorageWebhook)
 	SetUserAgent(newUserAgent string)
+	SubscribeEvents() (<-chan OGameEvent, func())
 	ValidateAccount(code string) error
+	WaitUntilServerTime(ctx context.Context, t time.Time) error
 	WithPriority(priority taskRunner.Priority) Prioritizable
 }
+
+// The focused interfaces below are subsets of Prioritizable.
+// Strategies can depend on the smallest one they need, and be tested against a fake (see package ogametest).
+
+// FleetSender sends and tracks fleets
+type FleetSender interface {
+	CancelFleet(ogame.FleetID) error
+	EnsureFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+	FlightTime(origin, destination ogame.Coordinate, speed ogame.Speed, ships ogame.ShipsInfos, mission ogame.MissionID) (secs, fuel int64)
+	GetFleets(...Option) ([]ogame.Fleet, ogame.Slots)
+	GetSlots() ogame.Slots
+	SendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate, mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error)
+}
+
+// CelestialReader reads the state of the planets and moons
+type CelestialReader interface {
+	GetCelestial(any) (Celestial, error)
+	GetCelestials() ([]Celestial, error)
+	GetDefense(ogame.CelestialID, ...Option) (ogame.DefensesInfos, error)
+	GetFacilities(ogame.CelestialID, ...Option) (ogame.Facilities, error)
+	GetMoons() []Moon
+	GetPlanets() []Planet
+	GetResearch() ogame.Researches
+	GetResources(ogame.CelestialID) (ogame.Resources, error)
+	GetResourcesBuildings(ogame.CelestialID, ...Option) (ogame.ResourcesBuildings, error)
+	GetShips(ogame.CelestialID, ...Option) (ogame.ShipsInfos, error)
+}
+
+// GalaxyScanner reads the galaxy
+type GalaxyScanner interface {
+	FindColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error)
+	GalaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemInfos, error)
+	GetEspionageReportFor(ogame.Coordinate) (ogame.EspionageReport, error)
+}
+
+// Messenger reads, sends and deletes messages
+type Messenger interface {
+	DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error
+	DeleteMessage(msgID int64) error
+	GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error)
+	GetMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error)
+	SendMessage(playerID int64, message string) error
+	SendMessageAlliance(associationID int64, message string) error
+}
+
+var (
+	_ Wrapper         = (*OGame)(nil)
+	_ Prioritizable   = (*Prioritize)(nil)
+	_ FleetSender     = Prioritizable(nil)
+	_ CelestialReader = Prioritizable(nil)
+	_ GalaxyScanner   = Prioritizable(nil)
+	_ Messenger       = Prioritizable(nil)
+)
@@ -0,0 +1,65 @@
+package wrapper
+
+import "strings"
+
+// LobbyLocale language settings sent to the gameforge lobby (sessions, captcha challenge, accounts)
+type LobbyLocale struct {
+	GfLang string // eg: en
+	Locale string // eg: en_GB
+}
+
+// DefaultLobbyLocale used when the server language is unknown
+var DefaultLobbyLocale = LobbyLocale{GfLang: "en", Locale: "en_GB"}
+
+// lobbyLocales ogame server language -> lobby locale.
+// The server languages are not always iso codes (eg: br, dk, cz, gr, si, ba/yu, tw, jp, us, ar, mx).
+var lobbyLocales = map[string]LobbyLocale{
+	"en": {"en", "en_GB"},
+	"us": {"en", "en_US"},
+	"de": {"de", "de_DE"},
+	"fr": {"fr", "fr_FR"},
+	"es": {"es", "es_ES"},
+	"ar": {"es", "es_AR"},
+	"mx": {"es", "es_MX"},
+	"it": {"it", "it_IT"},
+	"pl": {"pl", "pl_PL"},
+	"pt": {"pt", "pt_PT"},
+	"br": {"pt", "pt_BR"},
+	"ru": {"ru", "ru_RU"},
+	"tr": {"tr", "tr_TR"},
+	"nl": {"nl", "nl_NL"},
+	"dk": {"da", "da_DK"},
+	"se": {"sv", "sv_SE"},
+	"fi": {"fi", "fi_FI"},
+	"cz": {"cs", "cs_CZ"},
+	"sk": {"sk", "sk_SK"},
+	"hu": {"hu", "hu_HU"},
+	"ro": {"ro", "ro_RO"},
+	"gr": {"el", "el_GR"},
+	"hr": {"hr", "hr_HR"},
+	"si": {"sl", "sl_SI"},
+	"ba": {"bs", "bs_BA"},
+	"yu": {"bs", "bs_BA"}, // Lobby language of the ba servers
+	"jp": {"ja", "ja_JP"},
+	"tw": {"zh", "zh_TW"},
+}
+
+// GetLobbyLocale returns the lobby locale of an ogame server language (Params.Lang), DefaultLobbyLocale if unknown
+func GetLobbyLocale(lang string) LobbyLocale {
+	if l, ok := lobbyLocales[strings.ToLower(lang)]; ok {
+		return l
+	}
+	return DefaultLobbyLocale
+}
+
+// ParseLobbyLocale parses a locale (eg: pt_BR or pt-BR), the gfLang being the language part
+func ParseLobbyLocale(locale string) LobbyLocale {
+	locale = strings.Replace(locale, "-", "_", 1)
+	gfLang, _, _ := strings.Cut(locale, "_")
+	return LobbyLocale{GfLang: strings.ToLower(gfLang), Locale: locale}
+}
+
+// ChallengeLocale locale used in the captcha challenge urls (eg: en-GB)
+func (l LobbyLocale) ChallengeLocale() string {
+	return strings.Replace(l.Locale, "_", "-", 1)
+}
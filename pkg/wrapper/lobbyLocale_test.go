@@ -0,0 +1,32 @@
+package wrapper
+
+import (
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLobbyLocale(t *testing.T) {
+	assert.Equal(t, LobbyLocale{GfLang: "en", Locale: "en_GB"}, GetLobbyLocale("en"))
+	assert.Equal(t, LobbyLocale{GfLang: "pt", Locale: "pt_BR"}, GetLobbyLocale("br"))
+	assert.Equal(t, LobbyLocale{GfLang: "pt", Locale: "pt_PT"}, GetLobbyLocale("pt"))
+	assert.Equal(t, LobbyLocale{GfLang: "bs", Locale: "bs_BA"}, GetLobbyLocale("ba"))
+	assert.Equal(t, LobbyLocale{GfLang: "zh", Locale: "zh_TW"}, GetLobbyLocale("TW"))
+	assert.Equal(t, LobbyLocale{GfLang: "da", Locale: "da_DK"}, GetLobbyLocale("dk"))
+	assert.Equal(t, DefaultLobbyLocale, GetLobbyLocale("xx"))
+	assert.Equal(t, LobbyLocale{GfLang: "pt", Locale: "pt_BR"}, ParseLobbyLocale("pt-BR"))
+	assert.Equal(t, "zh-TW", GetLobbyLocale("tw").ChallengeLocale())
+}
+
+func TestPostSessionsReq_locale(t *testing.T) {
+	for lang, expected := range map[string][2]string{"en": {"en", "en_GB"}, "br": {"pt", "pt_BR"}, "ba": {"bs", "bs_BA"}, "tw": {"zh", "zh_TW"}} {
+		req, err := postSessionsReq("envID", "gameID", "user", "pass", "", "", GetLobbyLocale(lang))
+		assert.NoError(t, err)
+		by, _ := io.ReadAll(req.Body)
+		payload, _ := url.ParseQuery(string(by))
+		assert.Equal(t, expected[0], payload.Get("gfLang"), lang)
+		assert.Equal(t, expected[1], payload.Get("locale"), lang)
+	}
+}
@@ -0,0 +1,101 @@
+package wrapper
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// Delays between two probes of the game server while it is in maintenance
+const (
+	maintenanceProbeMinInterval = 10 * time.Second
+	maintenanceProbeMaxInterval = 5 * time.Minute
+)
+
+// MaintenanceWindow period during which the game server was in maintenance
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time // Zero while the maintenance is ongoing
+}
+
+// IsInMaintenance returns either or not the game server is in maintenance.
+// While in maintenance, no request is sent to the game server and the bot watchers are paused.
+func (b *OGame) IsInMaintenance() bool {
+	return atomic.LoadInt32(&b.maintenanceAtom) == 1
+}
+
+// GetMaintenanceWindows returns the maintenance periods detected since the bot started
+func (b *OGame) GetMaintenanceWindows() []MaintenanceWindow {
+	b.maintenanceMu.Lock()
+	defer b.maintenanceMu.Unlock()
+	return append([]MaintenanceWindow{}, b.maintenanceWindows...)
+}
+
+// maintenanceRetryAfter returns the delay before the next probe of the game server
+func (b *OGame) maintenanceRetryAfter() time.Duration {
+	b.maintenanceMu.Lock()
+	defer b.maintenanceMu.Unlock()
+	if d := b.maintenanceNextProbe.Sub(b.clock.Now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// enterMaintenance switches the bot into maintenance state, and probes the game server until it is back
+func (b *OGame) enterMaintenance() {
+	if !atomic.CompareAndSwapInt32(&b.maintenanceAtom, 0, 1) {
+		return
+	}
+	window := MaintenanceWindow{Start: b.clock.Now()}
+	b.maintenanceMu.Lock()
+	b.maintenanceWindows = append(b.maintenanceWindows, window)
+	b.maintenanceNextProbe = window.Start.Add(maintenanceProbeMinInterval)
+	b.maintenanceMu.Unlock()
+	b.error("server maintenance detected, pausing the bot")
+	b.stateChanged(false, "Maintenance")
+	b.publishMaintenanceEvent(window)
+	go b.probeMaintenance()
+}
+
+func (b *OGame) leaveMaintenance() {
+	if !atomic.CompareAndSwapInt32(&b.maintenanceAtom, 1, 0) {
+		return
+	}
+	var window MaintenanceWindow
+	b.maintenanceMu.Lock()
+	if len(b.maintenanceWindows) > 0 {
+		b.maintenanceWindows[len(b.maintenanceWindows)-1].End = b.clock.Now()
+		window = b.maintenanceWindows[len(b.maintenanceWindows)-1]
+	}
+	b.maintenanceMu.Unlock()
+	b.info("server maintenance is over, resuming the bot")
+	b.stateChanged(false, "MaintenanceEnd")
+	b.publishMaintenanceEvent(window)
+}
+
+// probeMaintenance checks the game server with exponential backoff, until the maintenance is over or the bot is disabled.
+// The game server is considered back as soon as it answers a page again.
+func (b *OGame) probeMaintenance() {
+	ctx := b.getContext()
+	interval := maintenanceProbeMinInterval
+	for {
+		select {
+		case <-b.clock.After(interval):
+		case <-ctx.Done():
+			return
+		}
+		finalURL := constructFinalURL(b, url.Values{"page": {"ingame"}, "component": {OverviewPageName}})
+		if _, err := b.execRequest(http.MethodGet, finalURL, nil, url.Values{}); err == nil {
+			b.leaveMaintenance()
+			return
+		}
+		interval *= 2
+		if interval > maintenanceProbeMaxInterval {
+			interval = maintenanceProbeMaxInterval
+		}
+		b.maintenanceMu.Lock()
+		b.maintenanceNextProbe = b.clock.Now().Add(interval)
+		b.maintenanceMu.Unlock()
+	}
+}
@@ -0,0 +1,59 @@
+package wrapper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeMaintenance(t *testing.T) {
+	var inMaintenance int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&inMaintenance) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer srv.Close()
+	clock := clockwork.NewFakeClock()
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clock, quiet: true, serverURL: srv.URL}
+
+	_, err := b.execRequest(http.MethodGet, srv.URL, nil, url.Values{})
+	assert.Equal(t, ogame.ErrMaintenance, err)
+	b.enterMaintenance()
+	assert.True(t, b.IsInMaintenance())
+	assert.Equal(t, maintenanceProbeMinInterval, b.maintenanceRetryAfter())
+
+	// Each failed probe doubles the delay before the next one
+	for _, interval := range []time.Duration{20 * time.Second, 40 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(b.maintenanceRetryAfter())
+		for i := 0; i < 100 && b.maintenanceRetryAfter() != interval; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, interval, b.maintenanceRetryAfter())
+		assert.True(t, b.IsInMaintenance())
+	}
+
+	// The maintenance ends as soon as the game server answers a page again
+	atomic.StoreInt32(&inMaintenance, 0)
+	clock.BlockUntil(1)
+	clock.Advance(40 * time.Second)
+	for i := 0; i < 100 && b.IsInMaintenance(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(t, b.IsInMaintenance())
+	windows := b.GetMaintenanceWindows()
+	assert.Len(t, windows, 1)
+	assert.Equal(t, 70*time.Second, windows[0].End.Sub(windows[0].Start))
+}
@@ -0,0 +1,59 @@
+package wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// Pacing of DeleteMessagesWhere, a pause is made after each batch of deleted messages
+const (
+	deleteMessagesBatchSize  = 10
+	deleteMessagesBatchPause = 2 * time.Second
+)
+
+// MessagesDeletion counts of a filtered messages deletion
+type MessagesDeletion struct {
+	Scanned int64
+	Matched int64
+	Deleted int64
+}
+
+// DeleteMessagesWhere deletes the messages of a tab for which predicate returns true.
+// Messages are deleted one by one at low priority, with a pause after each batch, so other tasks are not starved.
+// The counts are returned even if the context is cancelled midway.
+func (b *OGame) DeleteMessagesWhere(ctx context.Context, tabID ogame.MessagesTabID, predicate func(ogame.MessageSummary) bool) (MessagesDeletion, error) {
+	var res MessagesDeletion
+	msgs, err := b.WithPriority(taskRunner.Low).GetMessageSummaries(tabID)
+	if err != nil {
+		return res, err
+	}
+	res.Scanned = int64(len(msgs))
+	matches := make([]ogame.MessageSummary, 0)
+	for _, msg := range msgs {
+		if predicate(msg) {
+			matches = append(matches, msg)
+		}
+	}
+	res.Matched = int64(len(matches))
+	for i, msg := range matches {
+		if i > 0 && i%deleteMessagesBatchSize == 0 {
+			b.debug("deleted", res.Deleted, "/", res.Matched, "messages from tab", tabID)
+			select {
+			case <-ctx.Done():
+				return res, ctx.Err()
+			case <-b.clock.After(deleteMessagesBatchPause):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		if err := b.WithPriority(taskRunner.Low).DeleteMessage(msg.ID); err != nil {
+			return res, err
+		}
+		res.Deleted++
+	}
+	return res, nil
+}
@@ -124,6 +124,11 @@ func (m Moon) ActivateItem(ref string) error {
 //}
 //
 
+// GetMoonFacilities gets the moon facilities, the phalanx range and the jump gate recharge countdown
+func (m Moon) GetMoonFacilities() (ogame.MoonFacilities, error) {
+	return m.ogame.GetMoonFacilities(m.ID)
+}
+
 // Phalanx uses 5000 deuterium to scan a coordinate
 func (m Moon) Phalanx(coord ogame.Coordinate) ([]ogame.Fleet, error) {
 	return m.ogame.Phalanx(m.ID, coord)
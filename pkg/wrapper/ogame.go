@@ -32,11 +32,7 @@ import (
 	"github.com/alaingilbert/ogame/pkg/exponentialBackoff"
 	"github.com/alaingilbert/ogame/pkg/extractor"
 	v6 "github.com/alaingilbert/ogame/pkg/extractor/v6"
-	v7 "github.com/alaingilbert/ogame/pkg/extractor/v7"
-	v71 "github.com/alaingilbert/ogame/pkg/extractor/v71"
-	v8 "github.com/alaingilbert/ogame/pkg/extractor/v8"
 	v874 "github.com/alaingilbert/ogame/pkg/extractor/v874"
-	v9 "github.com/alaingilbert/ogame/pkg/extractor/v9"
 	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/alaingilbert/ogame/pkg/parser"
@@ -57,12 +53,26 @@ import (
 // multiple goroutines (thread-safe)
 type OGame struct {
 	sync.Mutex
-	isEnabledAtom         int32  // atomic, prevent auto re login if we manually logged out
-	isLoggedInAtom        int32  // atomic, prevent auto re login if we manually logged out
-	isConnectedAtom       int32  // atomic, either or not communication between the bot and OGame is possible
-	lockedAtom            int32  // atomic, bot state locked/unlocked
-	chatConnectedAtom     int32  // atomic, either or not the chat is connected
+	isEnabledAtom         int32 // atomic, prevent auto re login if we manually logged out
+	isLoggedInAtom        int32 // atomic, prevent auto re login if we manually logged out
+	isConnectedAtom       int32 // atomic, either or not communication between the bot and OGame is possible
+	lockedAtom            int32 // atomic, bot state locked/unlocked
+	chatConnectedAtom     int32 // atomic, either or not the chat is connected
+	serverTimeOffsetAtom  int64 // atomic, offset (nanoseconds) between the server clock and the local clock
+	maintenanceAtom       int32 // atomic, either or not the game server is in maintenance
+	throttleAtom          int32 // atomic, either or not the task queue is paused because the game server throttled the bot
+	taskPriorityAtom      int64 // atomic, priority of the task currently holding the bot
+	compatibilityModeAtom int32 // atomic, either or not the game version is more recent than the newest extractor
+	requestsCountAtom     int64 // atomic, number of game requests sent this session
+	lastRequestAtom       int64 // atomic, unix nano of the last successful game request
+	lastActivityAtom      int64 // atomic, unix nano of the last full game page loaded while logged in
+	loginCountAtom        int64 // atomic, number of successful logins this session
+	logoutsDetectedAtom   int64 // atomic, number of responses that triggered a relogin
+	falseLogoutsAtom      int64 // atomic, number of unexpected responses that were not logged out pages
+	parseWarningsAtom     int64 // atomic, number of parse warnings raised this session
+	startedAt             time.Time
 	state                 string // keep name of the function that currently lock the bot
+	ctxMu                 sync.Mutex
 	ctx                   context.Context
 	cancelCtx             context.CancelFunc
 	stateChangeCallbacks  []func(locked bool, actor string)
@@ -74,12 +84,14 @@ type OGame struct {
 	planets               []Planet
 	planetsMu             sync.RWMutex
 	ajaxChatToken         string
+	allianceID            int64 // 0 when not in an alliance
 	Universe              string
 	Username              string
 	password              string
 	otpSecret             string
 	bearerToken           string
 	language              string
+	lobbyLocale           LobbyLocale
 	playerID              int64
 	lobby                 string
 	ogameSession          string
@@ -87,14 +99,18 @@ type OGame struct {
 	server                Server
 	serverData            ServerData
 	location              *time.Location
+	clock                 clockwork.Clock
 	serverURL             string
 	client                *httpclient.Client
 	logger                *log.Logger
 	chatCallbacks         []func(msg ogame.ChatMsg)
 	wsCallbacks           map[string]func(msg []byte)
 	auctioneerCallbacks   []func(any)
+	eventSubscribers      map[chan OGameEvent]struct{}
+	eventSubscribersMu    sync.Mutex
 	interceptorCallbacks  []func(method, url string, params, payload url.Values, pageHTML []byte)
 	closeChatCh           chan struct{}
+	chatMaxBackoff        time.Duration
 	ws                    *websocket.Conn
 	taskRunnerInst        *taskRunner.TaskRunner[*Prioritize]
 	loginWrapper          func(func() (bool, error)) error
@@ -102,6 +118,7 @@ type OGame struct {
 	loginProxyTransport   http.RoundTripper
 	extractor             extractor.Extractor
 	apiNewHostname        string
+	apiNewHostnameMu      sync.RWMutex
 	characterClass        ogame.CharacterClass
 	hasCommander          bool
 	hasAdmiral            bool
@@ -109,6 +126,55 @@ type OGame struct {
 	hasGeologist          bool
 	hasTechnocrat         bool
 	captchaCallback       CaptchaCallback
+	crawlerPolicyMu       sync.Mutex
+	crawlerPolicyCancel   context.CancelFunc
+	crawlerPolicyStatus   CrawlerPolicyStatus
+	autoSatsMu            sync.Mutex
+	autoSatsCancel        context.CancelFunc
+	autoSatsStatus        AutoSatsStatus
+	bashingMu             sync.Mutex
+	bashingAttacks        []bashingAttack
+	maintenanceMu         sync.Mutex
+	maintenanceWindows    []MaintenanceWindow
+	maintenanceNextProbe  time.Time
+	throttleMu            sync.Mutex
+	throttleStart         time.Time
+	throttleUntil         time.Time
+	throttleCooldown      time.Duration
+	throttleCount         int64
+	throttleCriticalWait  time.Duration
+	exposureAlertMu       sync.Mutex
+	exposureAlertCancel   context.CancelFunc
+	exposureAlert         ExposureAlert
+	slotReservationsMu    sync.Mutex
+	slotReservations      map[string]SlotReservation
+	recallJobsMu          sync.Mutex
+	recallJobs            map[int64]*RecallJob
+	recallJobLastID       int64
+	expeditionCapMu       sync.Mutex
+	expeditionCap         ExpeditionCap
+	storageWebhookMu      sync.Mutex
+	storageWebhookCancel  context.CancelFunc
+	storageWebhook        StorageWebhook
+	storageAlertsSent     map[string]time.Time
+	hooks                 hooks
+	celestialImagesMu     sync.Mutex
+	celestialImages       map[string]celestialImage
+	friendlyArrivalsMu    sync.Mutex
+	friendlyArrivalsSeen  map[int64]time.Time
+	resourceProfilesMu    sync.Mutex
+	resourceProfiles      map[string]ogame.ResourceSettings
+	resourceProfileUndo   map[ogame.PlanetID]ResourceProfileChange
+	spiedEventsMu         sync.Mutex
+	spiedEventsSeen       map[int64]time.Time
+	miniFleetToken        string // token of the galaxy quick actions, renewed by every mini fleet response
+	wastedSamplesMu       sync.Mutex
+	wastedSamples         map[ogame.CelestialID]wastedSample
+	selfTestMu            sync.Mutex
+	selfTestCancel        context.CancelFunc
+	selfTestSchedule      string
+	selfTestResults       []SelfTestResult
+	selfTestSnapshot      selfTestSnapshot
 }
 
 // CaptchaCallback ...
@@ -136,11 +202,21 @@ type Params struct {
 	ProxyType       string
 	ProxyLoginOnly  bool
 	TLSConfig       *tls.Config
-	Lobby           string
+	Lobby           string // Lobby of the account (lobby | lobby-pioneers), default lobby
 	APINewHostname  string
 	CookiesFilename string
 	Client          *httpclient.Client
 	CaptchaCallback CaptchaCallback
+	ChatMaxBackoff  time.Duration // Maximum delay between two reconnections to the game websocket, at least 1s, default 60s
+	// StorageWebhookURL url that receives a POST when a planet storage is projected to be full within StorageLeadTime
+	StorageWebhookURL string
+	StorageLeadTime   time.Duration // default 2h
+	LobbyLocale       string        // Locale sent to the lobby (eg: pt_BR), derived from Lang by default
+	MaxResponseBytes  int64         // Responses bigger than this fail with ogame.ErrResponseTooLarge, default 64MB
+	SelfTestSchedule  string        // Time of day ("15:04") of the daily extraction self-test, disabled if empty
+	// ThrottleCriticalWait delay after which critical tasks are let through while the game server throttles the bot,
+	// they wait for the whole cool-down if 0
+	ThrottleCriticalWait time.Duration
 }
 
 // Lobby constants
@@ -185,8 +261,29 @@ func NewWithParams(params Params) (*OGame, error) {
 		return nil, err
 	}
 	b.captchaCallback = params.CaptchaCallback
-	b.setOGameLobby(params.Lobby)
+	if err := b.setOGameLobby(params.Lobby); err != nil {
+		return nil, err
+	}
 	b.apiNewHostname = params.APINewHostname
+	if params.LobbyLocale != "" {
+		b.lobbyLocale = ParseLobbyLocale(params.LobbyLocale)
+	}
+	if params.ChatMaxBackoff > 0 {
+		if params.ChatMaxBackoff < time.Second { // the backoff counts in whole seconds
+			return nil, errors.New("chat max backoff must be at least 1s")
+		}
+		b.chatMaxBackoff = params.ChatMaxBackoff
+	}
+	if params.MaxResponseBytes > 0 {
+		b.client.SetMaxResponseBytes(params.MaxResponseBytes)
+	}
+	if params.StorageWebhookURL != "" {
+		b.SetStorageWebhook(StorageWebhook{URL: params.StorageWebhookURL, LeadTime: params.StorageLeadTime})
+	}
+	b.throttleCriticalWait = params.ThrottleCriticalWait
+	if err := b.SetSelfTestSchedule(params.SelfTestSchedule); err != nil {
+		return nil, err
+	}
 	if params.Proxy != "" {
 		if err := b.SetProxy(params.Proxy, params.ProxyUsername, params.ProxyPassword, params.ProxyType, params.ProxyLoginOnly, params.TLSConfig); err != nil {
 			return nil, err
@@ -217,9 +314,13 @@ func NewNoLogin(username, password, otpSecret, bearerToken, universe, lang, cook
 
 	b.Universe = universe
 	b.SetOGameCredentials(username, password, otpSecret, bearerToken)
-	b.setOGameLobby(Lobby)
+	_ = b.setOGameLobby(Lobby)
 	b.language = lang
+	b.lobbyLocale = GetLobbyLocale(lang)
 	b.playerID = playerID
+	b.chatMaxBackoff = 60 * time.Second
+	b.clock = clockwork.NewRealClock()
+	b.startedAt = b.clock.Now()
 
 	b.extractor = v874.NewExtractor()
 
@@ -249,8 +350,11 @@ func NewNoLogin(username, password, otpSecret, bearerToken, universe, lang, cook
 
 	factory := func() *Prioritize { return &Prioritize{bot: b} }
 	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), factory)
+	b.taskRunnerInst.SetGate(b.throttleGate)
 
 	b.wsCallbacks = make(map[string]func([]byte))
+	b.eventSubscribers = make(map[chan OGameEvent]struct{})
+	b.recallJobs = make(map[int64]*RecallJob)
 
 	return b, nil
 }
@@ -264,14 +368,14 @@ func findServer(universe, lang string, servers []Server) (out Server, found bool
 	return
 }
 
-func findAccount(universe, lang string, playerID int64, accounts []Account, servers []Server) (Account, Server, error) {
+func findAccount(lobby, universe, lang string, playerID int64, accounts []Account, servers []Server) (Account, Server, error) {
 	if lang == "ba" {
 		lang = "yu"
 	}
 	var acc Account
 	server, found := findServer(universe, lang, servers)
 	if !found {
-		return Account{}, Server{}, fmt.Errorf("server %s, %s not found", universe, lang)
+		return Account{}, Server{}, fmt.Errorf("%w: universe %s (%s) in %s", ogame.ErrServerNotFound, universe, lang, lobby)
 	}
 	for _, a := range accounts {
 		if a.Server.Language == server.Language && a.Server.Number == server.Number {
@@ -475,7 +579,7 @@ func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *G
 		var challengeID string
 		tried := false
 		for {
-			out, err = GFLogin(client, b.ctx, lobby, username, password, otpSecret, challengeID)
+			out, err = GFLoginWithLocale(client, b.ctx, lobby, username, password, otpSecret, challengeID, b.lobbyLocale)
 			var captchaErr *CaptchaRequiredError
 			if errors.As(err, &captchaErr) {
 				if tried || b.captchaCallback == nil {
@@ -483,7 +587,7 @@ func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *G
 				}
 				tried = true
 
-				questionRaw, iconsRaw, err := StartCaptchaChallenge(client, b.ctx, captchaErr.ChallengeID)
+				questionRaw, iconsRaw, err := StartCaptchaChallengeWithLocale(client, b.ctx, captchaErr.ChallengeID, b.lobbyLocale)
 				if err != nil {
 					return errors.New("failed to start captcha challenge: " + err.Error())
 				}
@@ -491,7 +595,7 @@ func postSessions(b *OGame, lobby, username, password, otpSecret string) (out *G
 				if err != nil {
 					return errors.New("failed to get answer for captcha challenge: " + err.Error())
 				}
-				if err := SolveChallenge(client, b.ctx, captchaErr.ChallengeID, answer); err != nil {
+				if err := SolveChallengeWithLocale(client, b.ctx, captchaErr.ChallengeID, answer, b.lobbyLocale); err != nil {
 					return errors.New("failed to solve captcha challenge: " + err.Error())
 				}
 				challengeID = captchaErr.ChallengeID
@@ -575,7 +679,7 @@ func (b *OGame) loginPart1(token string) (server Server, userAccount Account, er
 		return
 	}
 	b.debug("find account & server for universe")
-	userAccount, server, err = findAccount(b.Universe, b.language, b.playerID, accounts, servers)
+	userAccount, server, err = findAccount(b.lobby, b.Universe, b.language, b.playerID, accounts, servers)
 	if err != nil {
 		return
 	}
@@ -623,16 +727,17 @@ func (b *OGame) loginPart2(server Server) error {
 
 func (b *OGame) loginPart3(userAccount Account, page parser.OverviewPage) error {
 	if ogVersion, err := version.NewVersion(b.serverData.Version); err == nil {
-		if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("9.0.0"))) {
-			b.extractor = v9.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.7.4-pl3"))) {
-			b.extractor = v874.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("8.0.0"))) {
-			b.extractor = v8.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.1.0-rc0"))) {
-			b.extractor = v71.NewExtractor()
-		} else if ogVersion.GreaterThanOrEqual(version.Must(version.NewVersion("7.0.0-rc0"))) {
-			b.extractor = v7.NewExtractor()
+		e, compatibilityMode := selectExtractor(ogVersion)
+		if e != nil {
+			b.extractor = e
+		}
+		if compatibilityMode {
+			atomic.StoreInt32(&b.compatibilityModeAtom, 1)
+			compatibilityModeTotal.Add(1)
+			b.error("WARNING: ogame version " + b.serverData.Version + " is more recent than the newest supported version (< " +
+				SupportedExtractors[0].MaxVersion + "), using the " + SupportedExtractors[0].Name + " extractor in compatibility mode")
+		} else {
+			atomic.StoreInt32(&b.compatibilityModeAtom, 0)
 		}
 		b.extractor.SetLanguage(b.language)
 		b.extractor.SetLifeformEnabled(page.ExtractLifeformEnabled())
@@ -667,7 +772,7 @@ func (b *OGame) loginPart3(userAccount Account, page parser.OverviewPage) error
 		b.closeChatCh = make(chan struct{})
 		go func(b *OGame) {
 			defer atomic.StoreInt32(&b.chatConnectedAtom, 0)
-			chatRetry := exponentialBackoff.New(context.Background(), clockwork.NewRealClock(), 60)
+			chatRetry := exponentialBackoff.New(context.Background(), clockwork.NewRealClock(), int(b.chatMaxBackoff.Seconds()))
 			chatRetry.LoopForever(func() bool {
 				select {
 				case <-b.closeChatCh:
@@ -756,17 +861,24 @@ func convertCelestial(b *OGame, celestial ogame.Celestial) Celestial {
 }
 
 func (b *OGame) cacheFullPageInfo(page parser.IFullPage) {
-	b.planetsMu.Lock()
-	b.planets = convertPlanets(b, page.ExtractPlanets())
-	b.planetsMu.Unlock()
+	// Keep the cached planets if the planets list could not be parsed, an account always has a planet
+	if planets := page.ExtractPlanets(); len(planets) > 0 {
+		b.planetsMu.Lock()
+		b.planets = convertPlanets(b, planets)
+		b.planetsMu.Unlock()
+	}
 	b.isVacationModeEnabled = page.ExtractIsInVacation()
 	b.ajaxChatToken, _ = page.ExtractAjaxChatToken()
+	b.allianceID = page.ExtractAllianceID()
 	b.characterClass, _ = page.ExtractCharacterClass()
 	b.hasCommander = page.ExtractCommander()
 	b.hasAdmiral = page.ExtractAdmiral()
 	b.hasEngineer = page.ExtractEngineer()
 	b.hasGeologist = page.ExtractGeologist()
 	b.hasTechnocrat = page.ExtractTechnocrat()
+	if serverTime, err := page.ExtractServerTime(); err == nil {
+		b.setServerTimeOffset(serverTime)
+	}
 
 	switch castedPage := page.(type) {
 	case parser.OverviewPage:
@@ -795,7 +907,7 @@ func (b *OGame) wrapLoginWithBearerToken(token string) (useToken bool, err error
 		useToken, err = b.loginWithBearerToken(token)
 		return useToken, err
 	}
-	return useToken, b.loginWrapper(fn)
+	return useToken, b.runLoginWrapper(fn)
 }
 
 func (b *OGame) wrapLoginWithExistingCookies() (useCookies bool, err error) {
@@ -803,11 +915,21 @@ func (b *OGame) wrapLoginWithExistingCookies() (useCookies bool, err error) {
 		useCookies, err = b.loginWithExistingCookies()
 		return useCookies, err
 	}
-	return useCookies, b.loginWrapper(fn)
+	return useCookies, b.runLoginWrapper(fn)
 }
 
 func (b *OGame) wrapLogin() error {
-	return b.loginWrapper(func() (bool, error) { return false, b.login() })
+	return b.runLoginWrapper(func() (bool, error) { return false, b.login() })
+}
+
+// runLoginWrapper runs the login wrapper and notifies the login hooks of the result
+func (b *OGame) runLoginWrapper(fn func() (bool, error)) error {
+	err := b.loginWrapper(fn)
+	if err == nil {
+		atomic.AddInt64(&b.loginCountAtom, 1)
+	}
+	b.loginDone(err)
+	return err
 }
 
 // GetExtractor gets extractor object
@@ -823,11 +945,16 @@ func (b *OGame) SetOGameCredentials(username, password, otpSecret, bearerToken s
 	b.bearerToken = bearerToken
 }
 
-func (b *OGame) setOGameLobby(lobby string) {
-	if lobby != LobbyPioneers {
+func (b *OGame) setOGameLobby(lobby string) error {
+	switch lobby {
+	case "":
 		lobby = Lobby
+	case Lobby, LobbyPioneers:
+	default:
+		return fmt.Errorf("%w %q, must be %s or %s", ogame.ErrInvalidLobby, lobby, Lobby, LobbyPioneers)
 	}
 	b.lobby = lobby
+	return nil
 }
 
 // SetGetServerDataWrapper ...
@@ -956,7 +1083,6 @@ func (b *OGame) connectChatV8(chatRetry *exponentialBackoff.ExponentialBackoff,
 		return
 	}
 	defer resp.Body.Close()
-	chatRetry.Reset()
 	by, _ := ioutil.ReadAll(resp.Body)
 	m := regexp.MustCompile(`"sid":"([^"]+)"`).FindSubmatch(by)
 	if len(m) != 2 {
@@ -972,6 +1098,9 @@ func (b *OGame) connectChatV8(chatRetry *exponentialBackoff.ExponentialBackoff,
 		b.error("failed to dial websocket:", err)
 		return
 	}
+	chatRetry.Reset()
+	b.publishWSStateEvent(true)
+	defer b.publishWSStateEvent(false)
 	_ = websocket.Message.Send(b.ws, "2probe")
 
 	// Recv msgs
@@ -1032,6 +1161,7 @@ LOOP:
 			for _, clb := range b.chatCallbacks {
 				clb(chatMsg)
 			}
+			b.publishChatEvent(chatMsg)
 		} else if regexp.MustCompile(`^\d+/auctioneer`).MatchString(buf) {
 			// 42/auctioneer,["timeLeft","<span style=\"color:#99CC00;\"><b>approx. 30m</b></span> remaining until the auction ends"] // every minute
 			// 42/auctioneer,["timeLeft","Next auction in:<br />\n<span class=\"nextAuction\" id=\"nextAuction\">117</span>"]
@@ -1113,6 +1243,7 @@ LOOP:
 			for _, clb := range b.auctioneerCallbacks {
 				clb(pck)
 			}
+			b.publishAuctioneerEvent(pck)
 		} else {
 			b.error("unknown message received:", buf)
 			time.Sleep(time.Second)
@@ -1133,7 +1264,6 @@ func (b *OGame) connectChatV7(chatRetry *exponentialBackoff.ExponentialBackoff,
 		return
 	}
 	defer resp.Body.Close()
-	chatRetry.Reset()
 	by, _ := ioutil.ReadAll(resp.Body)
 	token := strings.Split(string(by), ":")[0]
 
@@ -1144,6 +1274,9 @@ func (b *OGame) connectChatV7(chatRetry *exponentialBackoff.ExponentialBackoff,
 		b.error("failed to dial websocket:", err)
 		return
 	}
+	chatRetry.Reset()
+	b.publishWSStateEvent(true)
+	defer b.publishWSStateEvent(false)
 
 	// Recv msgs
 LOOP:
@@ -1263,6 +1396,7 @@ LOOP:
 			for _, clb := range b.auctioneerCallbacks {
 				clb(pck)
 			}
+			b.publishAuctioneerEvent(pck)
 		} else if regexp.MustCompile(`6::/chat:\d+\+\[true]`).Match(msg) {
 			b.debug("chat connected")
 		} else if regexp.MustCompile(`6::/chat:\d+\+\[false]`).Match(msg) {
@@ -1278,6 +1412,7 @@ LOOP:
 				for _, clb := range b.chatCallbacks {
 					clb(chatMsg)
 				}
+				b.publishChatEvent(chatMsg)
 			}
 		} else {
 			b.error("unknown message received:", string(buf))
@@ -1286,12 +1421,18 @@ LOOP:
 	}
 }
 
-// ReconnectChat ...
+// ReconnectChat resubscribe to the chat and auctioneer channels
 func (b *OGame) ReconnectChat() bool {
 	if b.ws == nil {
 		return false
 	}
+	if b.IsV8() || b.IsV9() {
+		_ = websocket.Message.Send(b.ws, "40/chat,")
+		_ = websocket.Message.Send(b.ws, "40/auctioneer,")
+		return true
+	}
 	_ = websocket.Message.Send(b.ws, "1::/chat")
+	_ = websocket.Message.Send(b.ws, "1::/auctioneer")
 	return true
 }
 
@@ -1388,6 +1529,12 @@ func (b *OGame) preRequestChecks() error {
 	if !b.IsLoggedIn() {
 		return ogame.ErrBotLoggedOut
 	}
+	if b.IsInMaintenance() {
+		return ogame.ErrMaintenance
+	}
+	if err := b.checkThrottle(); err != nil {
+		return err
+	}
 	if b.serverURL == "" {
 		return errors.New("serverURL is empty")
 	}
@@ -1414,19 +1561,36 @@ func (b *OGame) execRequest(method, finalURL string, payload, vals url.Values) (
 	}
 
 	req = req.WithContext(b.ctx)
+	start := time.Now()
 	resp, err := b.client.Do(req)
+	info := RequestInfo{Method: method, URL: finalURL, Duration: time.Since(start), Err: err}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	b.countRequest(info)
+	b.requestDone(info)
 	if err != nil {
 		return []byte{}, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		by, _ := utils.ReadBodyLimit(resp, b.client.MaxResponseBytes())
+		if isThrottleResponse(resp.StatusCode, by) {
+			return []byte{}, ogame.ErrThrottled
+		}
+		return []byte{}, ogame.ErrMaintenance
+	}
 	if resp.StatusCode >= http.StatusInternalServerError {
 		return []byte{}, err
 	}
-	by, err := utils.ReadBody(resp)
+	by, err := utils.ReadBodyLimit(resp, b.client.MaxResponseBytes())
 	if err != nil {
 		return []byte{}, err
 	}
+	if isThrottleResponse(resp.StatusCode, by) {
+		return []byte{}, ogame.ErrThrottled
+	}
 	return by, nil
 }
 
@@ -1456,20 +1620,33 @@ func setCPParam(b *OGame, vals url.Values, cfg Options) {
 	}
 }
 
-func detectLoggedOut(method, page string, vals url.Values, pageHTML []byte) bool {
+// detectLoggedOut returns either or not the response says the session is gone.
+// A full page is logged out when it carries neither the session token nor the player id.
+// A partial page that is not what was expected is only logged out when it is the lobby page, or empty
+// (the redirect of a post), otherwise it is reported as a false positive and no relogin is done.
+func detectLoggedOut(method, page string, vals url.Values, pageHTML []byte) (loggedOut, falsePositive bool) {
 	if vals.Get("allianceId") != "" {
-		return false
+		return false, false
 	}
+	unexpectedPartial := false
 	switch method {
 	case http.MethodGet:
-		return (page != LogoutPageName && (IsKnowFullPage(vals) || page == "") && !IsAjaxPage(vals) && !v6.IsLogged(pageHTML)) ||
-			(page == EventListAjaxPageName && !bytes.Contains(pageHTML, []byte("eventListWrap"))) ||
+		if page != LogoutPageName && (IsKnowFullPage(vals) || page == "") && !IsAjaxPage(vals) && !v6.IsLogged(pageHTML) {
+			return true, false
+		}
+		unexpectedPartial = (page == EventListAjaxPageName && !bytes.Contains(pageHTML, []byte("eventListWrap"))) ||
 			(page == FetchEventboxAjaxPageName && !canParseEventBox(pageHTML))
 
 	case http.MethodPost:
-		return page == GalaxyContentAjaxPageName && !canParseSystemInfos(pageHTML)
+		unexpectedPartial = page == GalaxyContentAjaxPageName && !canParseSystemInfos(pageHTML)
 	}
-	return false
+	if !unexpectedPartial {
+		return false, false
+	}
+	if v6.IsLobbyPage(pageHTML) || len(bytes.TrimSpace(pageHTML)) == 0 {
+		return true, false
+	}
+	return false, true
 }
 
 func constructFinalURL(b *OGame, vals url.Values) string {
@@ -1523,13 +1700,26 @@ func (b *OGame) pageContent(method string, vals, payload url.Values, opts ...Opt
 		}
 
 		pageHTMLBytes, err = b.execRequest(method, finalURL, payload, vals)
+		if err == ogame.ErrMaintenance {
+			b.enterMaintenance()
+		}
+		if err == ogame.ErrThrottled {
+			b.enterThrottle()
+		}
 		if err != nil {
 			return err
 		}
 
-		if detectLoggedOut(method, page, vals, pageHTMLBytes) {
+		loggedOut, falsePositive := detectLoggedOut(method, page, vals, pageHTMLBytes)
+		if falsePositive {
+			atomic.AddInt64(&b.falseLogoutsAtom, 1)
+			b.debug("unexpected response on page", page, "but not logged out")
+		}
+		if loggedOut {
+			atomic.AddInt64(&b.logoutsDetectedAtom, 1)
 			b.error("Err not logged on page : ", page)
 			atomic.StoreInt32(&b.isConnectedAtom, 0)
+			b.sessionExpired()
 			return ogame.ErrNotLogged
 		}
 
@@ -1572,6 +1762,7 @@ func processResponseHTML(method string, b *OGame, pageHTML []byte, page string,
 		if !IsAjaxPage(vals) && !IsEmpirePage(vals) && v6.IsLogged(pageHTML) {
 			parsedFullPage := parser.AutoParseFullPage(b.extractor, pageHTML)
 			b.cacheFullPageInfo(parsedFullPage)
+			atomic.StoreInt64(&b.lastActivityAtom, b.clock.Now().UnixNano())
 		}
 
 	case http.MethodPost:
@@ -1627,6 +1818,10 @@ func (b *OGame) withRetry(fn func() error) error {
 		if err == nil {
 			break
 		}
+		// Retrying is pointless until the maintenance or the throttle cool-down is over
+		if err == ogame.ErrMaintenance || err == ogame.ErrThrottled {
+			return err
+		}
 		// If we manually logged out, do not try to auto re login.
 		if !b.IsEnabled() {
 			return ogame.ErrBotInactive
@@ -1647,6 +1842,7 @@ func (b *OGame) withRetry(fn func() error) error {
 			if _, loginErr := b.wrapLoginWithExistingCookies(); loginErr != nil {
 				b.error(loginErr.Error()) // log error
 				if loginErr == ogame.ErrAccountNotFound ||
+					errors.Is(loginErr, ogame.ErrServerNotFound) ||
 					loginErr == ogame.ErrAccountBlocked ||
 					loginErr == ogame.ErrBadCredentials ||
 					loginErr == ogame.ErrOTPRequired ||
@@ -1678,18 +1874,51 @@ func (b *OGame) constructionTime(id ogame.ID, nbr int64, facilities ogame.Facili
 	return obj.ConstructionTime(nbr, b.getUniverseSpeed(), facilities, b.hasTechnocrat, b.isDiscoverer())
 }
 
+func (b *OGame) shipBuildTime(celestialID ogame.CelestialID, shipID ogame.ID, count int64) (time.Duration, error) {
+	if !shipID.IsShip() && !shipID.IsDefense() {
+		return 0, ogame.ErrNotCombatUnit
+	}
+	facilities, err := b.getFacilities(celestialID)
+	if err != nil {
+		return 0, err
+	}
+	return b.constructionTime(shipID, count, facilities), nil
+}
+
 func (b *OGame) enable() {
+	b.ctxMu.Lock()
 	b.ctx, b.cancelCtx = context.WithCancel(context.Background())
+	b.ctxMu.Unlock()
 	atomic.StoreInt32(&b.isEnabledAtom, 1)
+	b.restartBackgroundLoops()
 	b.stateChanged(false, "Enable")
 }
 
+// restartBackgroundLoops restarts the running background loops on the new bot context.
+// The loops are bound to the bot context, so they stop when the bot is disabled.
+func (b *OGame) restartBackgroundLoops() {
+	b.restartCrawlerPolicy()
+	b.restartExposureAlert()
+	b.restartStorageWebhook()
+	b.restartSelfTest()
+	b.restartAutoSats()
+}
+
 func (b *OGame) disable() {
 	atomic.StoreInt32(&b.isEnabledAtom, 0)
+	b.ctxMu.Lock()
 	b.cancelCtx()
+	b.ctxMu.Unlock()
 	b.stateChanged(false, "Disable")
 }
 
+// getContext returns the context of the bot, canceled when the bot is disabled
+func (b *OGame) getContext() context.Context {
+	b.ctxMu.Lock()
+	defer b.ctxMu.Unlock()
+	return b.ctx
+}
+
 func (b *OGame) isEnabled() bool {
 	return atomic.LoadInt32(&b.isEnabledAtom) == 1
 }
@@ -1714,6 +1943,10 @@ func (b *OGame) getUniverseSpeedFleet() int64 {
 	return b.serverData.SpeedFleet
 }
 
+func (b *OGame) isProbeRaids() bool {
+	return b.GetServerSettings().ProbeRaids
+}
+
 func (b *OGame) isDonutGalaxy() bool {
 	return b.serverData.DonutGalaxy
 }
@@ -1749,12 +1982,65 @@ func (b *OGame) setVacationMode() error {
 	return err
 }
 
+func (b *OGame) getSpyReportSettings() (ogame.SpyReportSettings, error) {
+	page, err := getPage[parser.PreferencesPage](b)
+	if err != nil {
+		return ogame.SpyReportSettings{}, err
+	}
+	prefs := page.ExtractPreferences()
+	return ogame.SpyReportSettings{ProbesCount: prefs.SpioAnz, ShowPictures: prefs.SpioReportPictures}, nil
+}
+
+// setSpyReportSettings posts the whole preferences form as the game shows it, with only the espionage fields changed
+func (b *OGame) setSpyReportSettings(settings ogame.SpyReportSettings) error {
+	if settings.ProbesCount < 1 || settings.ProbesCount > 99 {
+		return errors.New("invalid probes count")
+	}
+	vals := url.Values{"page": {"ingame"}, "component": {"preferences"}}
+	pageHTML, err := b.getPageContent(vals)
+	if err != nil {
+		return err
+	}
+	payload := b.extractor.ExtractPreferencesForm(pageHTML)
+	if payload.Get("token") == "" {
+		return errors.New("unable to find token")
+	}
+	payload.Set("spio_anz", utils.FI64(settings.ProbesCount))
+	payload.Del("spioReportPictures")
+	if settings.ShowPictures {
+		payload.Set("spioReportPictures", "on")
+	}
+	_, err = b.postPageContent(vals, payload)
+	return err
+}
+
 func (b *OGame) getPlanets() []Planet {
 	page, err := getPage[parser.OverviewPage](b)
 	if err != nil {
 		return []Planet{}
 	}
-	return convertPlanets(b, page.ExtractPlanets())
+	planets := page.ExtractPlanets()
+	if len(planets) == 0 {
+		// An account always has a planet, the planets list markup was not understood
+		planets = b.getPlanetsFromEmpire()
+	}
+	return convertPlanets(b, planets)
+}
+
+// getPlanetsFromEmpire reads the planets from the empire page (commander only) and caches them
+func (b *OGame) getPlanetsFromEmpire() []ogame.Planet {
+	empirePlanets, err := b.getEmpire(ogame.PlanetType)
+	if err != nil || len(empirePlanets) == 0 {
+		return []ogame.Planet{}
+	}
+	empireMoons, _ := b.getEmpire(ogame.MoonType)
+	b.reportParseWarnings([]ogame.ParseWarning{{Page: OverviewPageName, Field: "planets",
+		Message: "planets list not found, read from the empire page"}})
+	planets := ogame.EmpirePlanets(empirePlanets, empireMoons)
+	b.planetsMu.Lock()
+	b.planets = convertPlanets(b, planets)
+	b.planetsMu.Unlock()
+	return planets
 }
 
 func (b *OGame) getPlanet(v any) (Planet, error) {
@@ -1790,6 +2076,36 @@ func (b *OGame) getMoon(v any) (Moon, error) {
 	return *cMoon, nil
 }
 
+func (b *OGame) getNotifications() ([]ogame.Notification, error) {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return nil, err
+	}
+	return page.ExtractNotifications(), nil
+}
+
+func (b *OGame) getEffectiveSpeeds() (ogame.EffectiveSpeeds, error) {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return ogame.EffectiveSpeeds{}, err
+	}
+	speeds := page.ExtractUniverseSpeeds()
+	if speeds.Economy == 0 {
+		speeds.Economy = b.serverData.Speed
+	}
+	if speeds.FleetPeaceful == 0 {
+		speeds.FleetPeaceful = b.serverData.SpeedFleetPeaceful
+	}
+	if speeds.FleetWar == 0 {
+		speeds.FleetWar = b.serverData.SpeedFleetWar
+	}
+	if speeds.FleetHolding == 0 {
+		speeds.FleetHolding = b.serverData.SpeedFleetHolding
+	}
+	speeds.Research = speeds.Economy * utils.MaxInt(b.serverData.ResearchDurationDivisor, 1)
+	return speeds, nil
+}
+
 func (b *OGame) getCelestials() ([]Celestial, error) {
 	page, err := getPage[parser.OverviewPage](b)
 	if err != nil {
@@ -1814,6 +2130,26 @@ func (b *OGame) getCelestial(v any) (Celestial, error) {
 	return convertCelestial(b, celestial), nil
 }
 
+// getCelestialsByCoord returns all the celestials at the coordinate.
+// During a planet relocation, the old and new planets can both be listed.
+func (b *OGame) getCelestialsByCoord(coord ogame.Coordinate) ([]Celestial, error) {
+	page, err := getPage[parser.OverviewPage](b)
+	if err != nil {
+		return nil, err
+	}
+	celestials, err := page.ExtractCelestials()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Celestial, 0)
+	for _, celestial := range celestials {
+		if celestial.GetCoordinate().Equal(coord) {
+			out = append(out, convertCelestial(b, celestial))
+		}
+	}
+	return out, nil
+}
+
 func (b *OGame) recruitOfficer(typ, days int64) error {
 	if typ != 2 && typ != 3 && typ != 4 && typ != 5 && typ != 6 {
 		return errors.New("invalid officer type")
@@ -1913,6 +2249,13 @@ func (b *OGame) sendMessage(id int64, message string, isPlayer bool) error {
 	if strings.Contains(string(bodyBytes), "INVALID_PARAMETERS") {
 		return errors.New("invalid parameters")
 	}
+	// chatLoca "IGNORED_USER": "You have ignored this player."
+	if strings.Contains(string(bodyBytes), "IGNORED_USER") {
+		return ogame.ErrIgnoredUser
+	}
+	if strings.Contains(string(bodyBytes), "SEND_FAILED") {
+		return ogame.ErrSendMessageFailed
+	}
 	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(string(bodyBytes)))
 	if doc.Find("title").Text() == "OGame Lobby" {
 		return ogame.ErrNotLogged
@@ -1927,17 +2270,55 @@ func (b *OGame) sendMessage(id int64, message string, isPlayer bool) error {
 
 func (b *OGame) getFleetsFromEventList() []ogame.Fleet {
 	pageHTML, _ := b.getPageContent(url.Values{"eventList": {"movement"}, "ajax": {"1"}})
-	return b.extractor.ExtractFleetsFromEventList(pageHTML)
+	fleets := b.extractor.ExtractFleetsFromEventList(pageHTML)
+	ogame.SetPhalanxable(fleets)
+	return fleets
 }
 
 func (b *OGame) getFleets(opts ...Option) ([]ogame.Fleet, ogame.Slots) {
-	page, err := getPage[parser.MovementPage](b, opts...)
+	fleets, slots, err := b.fetchFleets(opts...)
 	if err != nil {
 		return []ogame.Fleet{}, ogame.Slots{}
 	}
+	return fleets, slots
+}
+
+// fetchFleets same as getFleets, but the error of the movement page is returned
+func (b *OGame) fetchFleets(opts ...Option) ([]ogame.Fleet, ogame.Slots, error) {
+	page, err := getPage[parser.MovementPage](b, opts...)
+	if err != nil {
+		return []ogame.Fleet{}, ogame.Slots{}, err
+	}
 	fleets := page.ExtractFleets()
 	slots := page.ExtractSlots()
-	return fleets, slots
+	if getOptions(opts...).CombatReports && hasReturningAttack(fleets) {
+		if reports, err := b.getCombatReportMessages(); err == nil {
+			ogame.LinkCombatReports(fleets, reports)
+		}
+	}
+	return fleets, slots, nil
+}
+
+func (b *OGame) getFleet(fleetID ogame.FleetID) (ogame.Fleet, error) {
+	fleets, _, err := b.fetchFleets()
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	for _, fleet := range fleets {
+		if fleet.ID == fleetID {
+			return fleet, nil
+		}
+	}
+	return ogame.Fleet{}, ogame.ErrFleetNotFound
+}
+
+func hasReturningAttack(fleets []ogame.Fleet) bool {
+	for _, fleet := range fleets {
+		if fleet.ReturnFlight && ogame.IsBashingMission(fleet.Mission) {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *OGame) cancelFleet(fleetID ogame.FleetID) error {
@@ -1973,13 +2354,7 @@ func galaxyDistance(galaxy1, galaxy2, universeSize int64, donutGalaxy bool) (dis
 }
 
 func systemDistance(nbSystems, system1, system2 int64, donutSystem bool) (distance int64) {
-	if !donutSystem {
-		return int64(math.Abs(float64(system2 - system1)))
-	}
-	if system1 > system2 {
-		system1, system2 = system2, system1
-	}
-	return int64(math.Min(float64(system2-system1), float64((system1+nbSystems)-system2)))
+	return ogame.SystemDistance(nbSystems, system1, system2, donutSystem)
 }
 
 // Returns the distance between two systems
@@ -2063,6 +2438,13 @@ func (b *OGame) CalcFlightTime(origin, destination ogame.Coordinate, speed float
 		b.GetCachedResearch(), b.characterClass)
 }
 
+// calcFlightTime same as CalcFlightTime, for use from within a task
+func (b *OGame) calcFlightTime(origin, destination ogame.Coordinate, speed float64, ships ogame.ShipsInfos, missionID ogame.MissionID) (secs, fuel int64) {
+	return CalcFlightTime(origin, destination, b.serverData.Galaxies, b.serverData.Systems, b.serverData.DonutGalaxy,
+		b.serverData.DonutSystem, b.serverData.GlobalDeuteriumSaveFactor, speed, GetFleetSpeedForMission(b.serverData, missionID), ships,
+		b.getCachedResearch(), b.characterClass)
+}
+
 // getPhalanx makes 3 calls to ogame server (2 validation, 1 scan)
 func (b *OGame) getPhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([]ogame.Fleet, error) {
 	res := make([]ogame.Fleet, 0)
@@ -2181,7 +2563,53 @@ func (b *OGame) jumpGateDestinations(originMoonID ogame.MoonID) ([]ogame.MoonID,
 	return dests, wait, nil
 }
 
+// getMoonFacilities makes 2 calls to ogame server (facilities, jump gate layer if the moon has a jump gate)
+func (b *OGame) getMoonFacilities(moonID ogame.MoonID) (ogame.MoonFacilities, error) {
+	pageHTML, err := b.getPage(FacilitiesPageName, ChangePlanet(moonID.Celestial()))
+	if err != nil {
+		return ogame.MoonFacilities{}, err
+	}
+	if _, err := b.extractor.ExtractMoon(pageHTML, moonID); err != nil {
+		return ogame.MoonFacilities{}, errors.New("moon not found")
+	}
+	facilities, err := b.extractor.ExtractFacilities(pageHTML)
+	if err != nil {
+		return ogame.MoonFacilities{}, err
+	}
+	res := ogame.MoonFacilities{Facilities: facilities}
+	res.PhalanxRange = ogame.SensorPhalanx.GetRange(facilities.SensorPhalanx, b.isDiscoverer())
+	res.HasJumpGate = facilities.JumpGate > 0
+	if res.HasJumpGate {
+		jumpGateHTML, err := b.getPage(JumpgatelayerPageName, ChangePlanet(moonID.Celestial()))
+		if err != nil {
+			return ogame.MoonFacilities{}, err
+		}
+		_, _, _, res.JumpGateRechargeCountdown = b.extractor.ExtractJumpGate(jumpGateHTML)
+	}
+	return res, nil
+}
+
+// getJumpGateLevel reads the level of the jump gate from the facilities page of the moon
+func (b *OGame) getJumpGateLevel(moonID ogame.MoonID) (int64, error) {
+	pageHTML, err := b.getPage(FacilitiesPageName, ChangePlanet(moonID.Celestial()))
+	if err != nil {
+		return 0, err
+	}
+	facilities, err := b.extractor.ExtractFacilities(pageHTML)
+	if err != nil {
+		return 0, err
+	}
+	return facilities.JumpGate, nil
+}
+
 func (b *OGame) executeJumpGate(originMoonID, destMoonID ogame.MoonID, ships ogame.ShipsInfos) (bool, int64, error) {
+	level, err := b.getJumpGateLevel(originMoonID)
+	if err != nil {
+		return false, 0, err
+	}
+	if level == 0 {
+		return false, 0, ogame.ErrNoJumpGate
+	}
 	pageHTML, _ := b.getPage(JumpgatelayerPageName, ChangePlanet(originMoonID.Celestial()))
 	availShips, token, dests, wait := b.extractor.ExtractJumpGate(pageHTML)
 	if wait > 0 {
@@ -2237,7 +2665,7 @@ func (b *OGame) getEmpireJSON(nbr int64) (any, error) {
 		return nil, err
 	}
 	// Replace the Ogame hostname with our custom hostname
-	pageHTML := strings.Replace(string(pageHTMLBytes), b.serverURL, b.apiNewHostname, -1)
+	pageHTML := strings.Replace(string(pageHTMLBytes), b.serverURL, b.getAPINewHostname(), -1)
 	return b.extractor.ExtractEmpireJSON([]byte(pageHTML))
 }
 
@@ -2279,6 +2707,64 @@ func (b *OGame) createUnion(fleet ogame.Fleet, unionUsers []string) (int64, erro
 	return res.UnionID, nil
 }
 
+func (b *OGame) getACSUnionDetails(unionID int64) (ogame.ACSUnionDetails, error) {
+	out := ogame.ACSUnionDetails{UnionID: unionID, Users: make([]string, 0), Members: make([]ogame.ACSUnionMember, 0)}
+	fleets, _, err := b.fetchFleets()
+	if err != nil {
+		return out, err
+	}
+	var fleet ogame.Fleet
+	for _, f := range fleets {
+		if unionID != 0 && f.UnionID == unionID {
+			fleet = f
+			break
+		}
+	}
+	if fleet.ID == 0 {
+		return out, ogame.ErrUnionNotFound
+	}
+	out.Destination = fleet.Destination
+	out.ArrivalTime = fleet.ArrivalTime
+
+	pageHTML, err := b.getPageContent(url.Values{"page": {FederationlayerAjaxPageName}, "union": {utils.FI64(unionID)}, "fleet": {utils.FI64(fleet.ID)}, "target": {utils.FI64(fleet.TargetPlanetID)}, "ajax": {"1"}})
+	if err != nil {
+		return out, err
+	}
+	payload := b.extractor.ExtractFederation(pageHTML)
+	out.Name = payload.Get("groupname")
+	for _, user := range payload["unionUsers"] {
+		if user = strings.TrimSpace(user); user != "" {
+			out.Users = append(out.Users, user)
+		}
+	}
+
+	// Fleets of the union members are only listed in the event list
+	vals := url.Values{"page": {"componentOnly"}, "component": {EventListAjaxPageName}, "ajax": {"1"}}
+	page, err := getAjaxPage[parser.EventListAjaxPage](b, vals)
+	if err != nil {
+		return out, err
+	}
+	var found *ogame.ACSUnionDetails
+	unions := page.ExtractUnionEvents()
+	for i, union := range unions {
+		for _, member := range union.Members {
+			if member.FleetID == fleet.ID {
+				found = &unions[i]
+			}
+		}
+		if found == nil && union.Destination.Equal(fleet.Destination) {
+			found = &unions[i]
+		}
+	}
+	if found != nil {
+		out.EventID = found.EventID
+		out.ArrivalTime = found.ArrivalTime
+		out.TotalShips = found.TotalShips
+		out.Members = found.Members
+	}
+	return out, nil
+}
+
 func (b *OGame) highscore(category, typ, page int64) (out ogame.Highscore, err error) {
 	if category < 1 || category > 2 {
 		return out, errors.New("category must be in [1, 2] (1:player, 2:alliance)")
@@ -2300,6 +2786,59 @@ func (b *OGame) highscore(category, typ, page int64) (out ogame.Highscore, err e
 	return b.extractor.ExtractHighscore(pageHTML)
 }
 
+// getMyPoints gets the points of the bot player in each highscore type.
+// Makes one call per type, each one opens the highscore page containing the player.
+func (b *OGame) getMyPoints() (out ogame.Points, err error) {
+	for typ := int64(0); typ <= 7; typ++ {
+		vals := url.Values{
+			"page":        {HighscoreContentAjaxPageName},
+			"category":    {"1"},
+			"type":        {utils.FI64(typ)},
+			"searchRelId": {utils.FI64(b.Player.PlayerID)},
+		}
+		pageHTML, err := b.postPageContent(vals, url.Values{})
+		if err != nil {
+			return out, err
+		}
+		highscore, err := b.extractor.ExtractHighscore(pageHTML)
+		if err != nil {
+			return out, err
+		}
+		me, found := ogame.HighscorePlayer{}, false
+		for _, p := range highscore.Players {
+			// The row of the player has no send message link, so the ID might be missing
+			if p.ID == b.Player.PlayerID || p.Name == b.Player.PlayerName {
+				me, found = p, true
+				break
+			}
+		}
+		if !found {
+			return out, errors.New("player not found in highscore type " + utils.FI64(typ))
+		}
+		switch typ {
+		case 0:
+			out.Position = me.Position
+			out.Total = me.Score
+		case 1:
+			out.Economy = me.Score
+		case 2:
+			out.Research = me.Score
+		case 3:
+			out.Military = me.Score
+			out.Ships = me.Ships
+		case 4:
+			out.MilitaryBuilt = me.Score
+		case 5:
+			out.MilitaryDestroyed = me.Score
+		case 6:
+			out.MilitaryLost = me.Score
+		case 7:
+			out.Honor = me.Score
+		}
+	}
+	return out, nil
+}
+
 func (b *OGame) getAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	vals := url.Values{
 		"page":      {"ajax"},
@@ -2786,6 +3325,15 @@ func (b *OGame) getAttacks(opts ...Option) (out []ogame.AttackEvent, err error)
 	return
 }
 
+func (b *OGame) getEventList(opts ...Option) ([]ogame.Event, error) {
+	vals := url.Values{"page": {"componentOnly"}, "component": {EventListAjaxPageName}, "ajax": {"1"}}
+	page, err := getAjaxPage[parser.EventListAjaxPage](b, vals, opts...)
+	if err != nil {
+		return []ogame.Event{}, err
+	}
+	return page.ExtractEvents()
+}
+
 func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemInfos, error) {
 	cfg := getOptions(opts...)
 	var res ogame.SystemInfos
@@ -2800,11 +3348,19 @@ func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemI
 		"system": {utils.FI64(system)},
 	}
 	vals := url.Values{"page": {"ingame"}, "component": {"galaxyContent"}, "ajax": {"1"}}
-	pageHTML, err := b.postPageContent(vals, payload, opts...)
-	if err != nil {
-		return res, err
+	var pageHTML []byte
+	var err error
+	// The galaxy content is sometimes an empty skeleton on the first response, retry once
+	for i := 0; i < 2; i++ {
+		pageHTML, err = b.postPageContent(vals, payload, opts...)
+		if err != nil {
+			return res, err
+		}
+		res, err = b.extractor.ExtractGalaxyInfos(pageHTML, b.Player.PlayerName, b.Player.PlayerID, b.Player.Rank)
+		if err != ogame.ErrGalaxySkeleton {
+			break
+		}
 	}
-	res, err = b.extractor.ExtractGalaxyInfos(pageHTML, b.Player.PlayerName, b.Player.PlayerID, b.Player.Rank)
 	if err != nil {
 		if cfg.DebugGalaxy {
 			fmt.Println(string(pageHTML))
@@ -2814,9 +3370,50 @@ func (b *OGame) galaxyInfos(galaxy, system int64, opts ...Option) (ogame.SystemI
 	if res.Tmpgalaxy != galaxy || res.Tmpsystem != system {
 		return ogame.SystemInfos{}, errors.New("not enough deuterium")
 	}
+	if cfg.TagRelations {
+		res.TagRelations(b.Player.PlayerID, b.allianceID)
+	}
 	return res, err
 }
 
+// colonizationScanMaxSystems maximum number of systems scanned by FindColonizationSlots,
+// the bot is locked for the whole scan
+const colonizationScanMaxSystems = 50
+
+func (b *OGame) findColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error) {
+	if fromSystem > toSystem {
+		fromSystem, toSystem = toSystem, fromSystem
+	}
+	if toSystem-fromSystem+1 > colonizationScanMaxSystems {
+		return nil, fmt.Errorf("%w, at most %d systems", ogame.ErrSystemRangeTooLarge, colonizationScanMaxSystems)
+	}
+	if len(preferredPositions) == 0 {
+		preferredPositions = []int64{4, 5, 6, 7, 8, 9, 10, 11, 12}
+	}
+	for _, position := range preferredPositions {
+		if position < 1 || position > 15 {
+			return nil, errors.New("position must be within [1, 15]")
+		}
+	}
+	out := make([]ogame.Coordinate, 0)
+	for system := fromSystem; system <= toSystem; system++ {
+		systemInfos, err := b.galaxyInfos(galaxy, system)
+		if err != nil {
+			return nil, err
+		}
+		for _, position := range preferredPositions {
+			if systemInfos.Position(position) == nil {
+				out = append(out, ogame.Coordinate{Galaxy: galaxy, System: system, Position: position, Type: ogame.PlanetType})
+			}
+		}
+	}
+	// Bigger expected planets first, keep scanning order for ties
+	sort.SliceStable(out, func(i, j int) bool {
+		return ogame.ExpectedPlanetFields(out[i].Position) > ogame.ExpectedPlanetFields(out[j].Position)
+	})
+	return out, nil
+}
+
 func (b *OGame) getResourceSettings(planetID ogame.PlanetID, options ...Option) (ogame.ResourceSettings, error) {
 	options = append(options, ChangePlanet(planetID.Celestial()))
 	page, err := getPage[parser.ResourcesSettingsPage](b, options...)
@@ -2861,13 +3458,26 @@ func (b *OGame) getCachedResearch() ogame.Researches {
 }
 
 func (b *OGame) getResearch() ogame.Researches {
+	researches, _ := b.fetchResearch()
+	return researches
+}
+
+// getCachedResearchOrFetch same as getCachedResearch, but reports the error if the research page fails to load
+func (b *OGame) getCachedResearchOrFetch() (ogame.Researches, error) {
+	if b.researches == nil {
+		return b.fetchResearch()
+	}
+	return *b.researches, nil
+}
+
+func (b *OGame) fetchResearch() (ogame.Researches, error) {
 	page, err := getPage[parser.ResearchPage](b)
 	if err != nil {
-		return ogame.Researches{}
+		return ogame.Researches{}, err
 	}
 	researches := page.ExtractResearch()
 	b.researches = &researches
-	return researches
+	return researches, nil
 }
 
 func (b *OGame) getResourcesBuildings(celestialID ogame.CelestialID, options ...Option) (ogame.ResourcesBuildings, error) {
@@ -2876,7 +3486,9 @@ func (b *OGame) getResourcesBuildings(celestialID ogame.CelestialID, options ...
 	if err != nil {
 		return ogame.ResourcesBuildings{}, err
 	}
-	return page.ExtractResourcesBuildings()
+	res, warnings, err := page.ExtractResourcesBuildingsWithWarnings()
+	b.reportParseWarnings(warnings, options...)
+	return res, err
 }
 
 func (b *OGame) getLfBuildings(celestialID ogame.CelestialID, options ...Option) (ogame.LfBuildings, error) {
@@ -2903,7 +3515,9 @@ func (b *OGame) getDefense(celestialID ogame.CelestialID, options ...Option) (og
 	if err != nil {
 		return ogame.DefensesInfos{}, err
 	}
-	return page.ExtractDefense()
+	res, warnings, err := page.ExtractDefenseWithWarnings()
+	b.reportParseWarnings(warnings, options...)
+	return res, err
 }
 
 func (b *OGame) getShips(celestialID ogame.CelestialID, options ...Option) (ogame.ShipsInfos, error) {
@@ -2912,19 +3526,80 @@ func (b *OGame) getShips(celestialID ogame.CelestialID, options ...Option) (ogam
 	if err != nil {
 		return ogame.ShipsInfos{}, err
 	}
-	return page.ExtractShips()
+	res, warnings, err := page.ExtractShipsWithWarnings()
+	b.reportParseWarnings(warnings, options...)
+	return res, err
 }
 
-func (b *OGame) getFacilities(celestialID ogame.CelestialID, options ...Option) (ogame.Facilities, error) {
-	options = append(options, ChangePlanet(celestialID))
-	page, err := getPage[parser.FacilitiesPage](b, options...)
+func (b *OGame) getMissiles(celestialID ogame.CelestialID) (ipm, abm, siloCapacity int64, err error) {
+	facilities, err := b.getFacilities(celestialID)
 	if err != nil {
-		return ogame.Facilities{}, err
+		return
 	}
-	return page.ExtractFacilities()
+	defenses, err := b.getDefense(celestialID)
+	if err != nil {
+		return
+	}
+	return defenses.InterplanetaryMissiles, defenses.AntiBallisticMissiles, ogame.MissileSiloCapacity(facilities.MissileSilo), nil
 }
 
-func (b *OGame) getTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
+func (b *OGame) getEffectiveLabLevel(planetID ogame.PlanetID) (int64, error) {
+	researches, err := b.getCachedResearchOrFetch()
+	if err != nil {
+		return 0, err
+	}
+	labs := make(map[ogame.CelestialID]int64)
+	if b.hasCommander {
+		empire, err := b.getEmpire(ogame.PlanetType)
+		if err != nil {
+			return 0, err
+		}
+		for _, planet := range empire {
+			labs[planet.ID] = planet.Facilities.ResearchLab
+		}
+	} else {
+		for _, planet := range b.GetCachedPlanets() {
+			facilities, err := b.getFacilities(planet.GetID())
+			if err != nil {
+				return 0, err
+			}
+			labs[planet.GetID()] = facilities.ResearchLab
+		}
+	}
+	researchingLab, ok := labs[planetID.Celestial()]
+	if !ok {
+		return 0, ogame.ErrInvalidPlanetID
+	}
+	otherLabs := make([]int64, 0, len(labs))
+	for celestialID, lvl := range labs {
+		if celestialID != planetID.Celestial() {
+			otherLabs = append(otherLabs, lvl)
+		}
+	}
+	return ogame.EffectiveLabLevel(researchingLab, otherLabs, researches.IntergalacticResearchNetwork), nil
+}
+
+func (b *OGame) getFacilities(celestialID ogame.CelestialID, options ...Option) (ogame.Facilities, error) {
+	options = append(options, ChangePlanet(celestialID))
+	page, err := getPage[parser.FacilitiesPage](b, options...)
+	if err != nil {
+		return ogame.Facilities{}, err
+	}
+	res, warnings, err := page.ExtractFacilitiesWithWarnings()
+	b.reportParseWarnings(warnings, options...)
+	return res, err
+}
+
+func (b *OGame) planToMatch(celestialID ogame.CelestialID, target ogame.ResourcesBuildings) ([]ogame.BuildStep, ogame.Resources, time.Duration, error) {
+	resourcesBuildings, facilities, _, _, _, _, err := b.getTechs(celestialID)
+	if err != nil {
+		return nil, ogame.Resources{}, 0, err
+	}
+	steps, price, duration := ogame.PlanToMatch(resourcesBuildings, target, facilities, b.getUniverseSpeed())
+	return steps, price, duration, nil
+}
+
+func (b *OGame) getTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
 	vals := url.Values{"page": {FetchTechsName}}
 	page, err := getAjaxPage[parser.FetchTechsAjaxPage](b, vals, ChangePlanet(celestialID))
 	if err != nil {
@@ -2951,13 +3626,29 @@ func (b *OGame) IsV8() bool {
 	return len(b.ServerVersion()) > 0 && b.ServerVersion()[0] == '8'
 }
 
+// GetCapabilities returns the features the server and the bot support, computed from the server data
+// and the lifeform state seen on the game pages
+func (b *OGame) GetCapabilities() ogame.Capabilities {
+	lifeformEnabled := b.extractor != nil && b.extractor.GetLifeformEnabled()
+	return ogame.NewCapabilities(ogame.ServerFeatures{
+		Version:                 b.serverData.Version,
+		LifeformEnabled:         lifeformEnabled,
+		MarketplaceEnabled:      b.serverData.MarketplaceEnabled,
+		CharacterClassesEnabled: b.serverData.CharacterClassesEnabled,
+		SpeedFleetPeaceful:      b.serverData.SpeedFleetPeaceful,
+	})
+}
+
 // IsV9 ...
 func (b *OGame) IsV9() bool {
 	return len(b.ServerVersion()) > 0 && b.ServerVersion()[0] == '9'
 }
 
 func (b *OGame) technologyDetails(celestialID ogame.CelestialID, id ogame.ID) (ogame.TechnologyDetails, error) {
-	pageHTML, _ := b.getPageContent(url.Values{
+	if !b.IsV9() {
+		return ogame.TechnologyDetails{}, errors.New("technology details require a v9 server")
+	}
+	pageHTML, err := b.getPageContent(url.Values{
 		"page":       {"ingame"},
 		"component":  {"technologydetails"},
 		"ajax":       {"1"},
@@ -2965,6 +3656,9 @@ func (b *OGame) technologyDetails(celestialID ogame.CelestialID, id ogame.ID) (o
 		"technology": {utils.FI64(id)},
 		"cp":         {utils.FI64(celestialID)},
 	})
+	if err != nil {
+		return ogame.TechnologyDetails{}, err
+	}
 	return b.extractor.ExtractTechnologyDetails(pageHTML)
 }
 
@@ -3120,6 +3814,14 @@ func (b *OGame) constructionsBeingBuilt(celestialID ogame.CelestialID) (ogame.ID
 	return page.ExtractConstructions()
 }
 
+func (b *OGame) getBuildQueue(celestialID ogame.CelestialID) ([]ogame.QueuedBuild, error) {
+	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
+	if err != nil {
+		return []ogame.QueuedBuild{}, err
+	}
+	return page.ExtractBuildQueue(), nil
+}
+
 func (b *OGame) cancel(token string, techID, listID int64) error {
 	_, _ = b.getPageContent(url.Values{"page": {"ingame"}, "component": {"overview"}, "modus": {"2"}, "token": {token},
 		"type": {utils.FI64(techID)}, "listid": {utils.FI64(listID)}, "action": {"cancel"}})
@@ -3135,6 +3837,32 @@ func (b *OGame) cancelBuilding(celestialID ogame.CelestialID) error {
 	return b.cancel(token, techID, listID)
 }
 
+// cancelQueuedBuild cancels a building waiting in the queue. index is its position in the building queue,
+// 0 being the building in progress, which is cancelled with cancelBuilding.
+func (b *OGame) cancelQueuedBuild(celestialID ogame.CelestialID, index int64) error {
+	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
+	if err != nil {
+		return err
+	}
+	entry, err := queuedBuildToCancel(page.ExtractBuildQueue(), index)
+	if err != nil {
+		return err
+	}
+	token, _, _, err := page.ExtractCancelBuildingInfos()
+	if err != nil {
+		return err
+	}
+	return b.cancel(token, int64(entry.ID), entry.ListID)
+}
+
+// queuedBuildToCancel returns the entry of the building queue at index, the building in progress (0) cannot be chosen
+func queuedBuildToCancel(queue []ogame.QueuedBuild, index int64) (ogame.QueuedBuild, error) {
+	if index < 1 || index >= int64(len(queue)) {
+		return ogame.QueuedBuild{}, ogame.ErrInvalidQueueIndex
+	}
+	return queue[index], nil
+}
+
 func (b *OGame) cancelLfBuilding(celestialID ogame.CelestialID) error {
 	page, err := getPage[parser.OverviewPage](b, ChangePlanet(celestialID))
 	if err != nil {
@@ -3158,7 +3886,13 @@ func (b *OGame) fetchResources(celestialID ogame.CelestialID) (ogame.ResourcesDe
 	if err != nil {
 		return ogame.ResourcesDetails{}, err
 	}
-	return b.extractor.ExtractResourcesDetails(pageJSON)
+	res, err := b.extractor.ExtractResourcesDetails(pageJSON)
+	if err != nil {
+		return res, err
+	}
+	res.SetCapped()
+	res.SetHoursUntilFull()
+	return res, nil
 }
 
 func (b *OGame) getResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
@@ -3181,6 +3915,14 @@ func (b *OGame) getResourcesDetails(celestialID ogame.CelestialID) (ogame.Resour
 	return b.fetchResources(celestialID)
 }
 
+func (b *OGame) getDarkMatter() (int64, error) {
+	res, err := b.fetchResources(ogame.CelestialID(0))
+	if err != nil {
+		return 0, err
+	}
+	return res.Darkmatter.Available, nil
+}
+
 func (b *OGame) destroyRockets(planetID ogame.PlanetID, abm, ipm int64) error {
 	vals := url.Values{
 		"page":      {"ajax"},
@@ -3306,6 +4048,186 @@ func (b *OGame) sendIPM(planetID ogame.PlanetID, coord ogame.Coordinate, nbr int
 	return duration, nil
 }
 
+func (b *OGame) quickSpy(target ogame.Coordinate, nbProbes int64, slotToken string) (ogame.Fleet, error) {
+	if nbProbes <= 0 {
+		nbProbes = b.CachedPreferences.SpioAnz
+	}
+	if nbProbes <= 0 {
+		nbProbes = 1
+	}
+	if target.Type == 0 {
+		target.Type = ogame.PlanetType
+	}
+	_, slots, err := b.fetchFleets()
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	if slots.InUse == slots.Total {
+		return ogame.Fleet{}, ogame.ErrAllSlotsInUse
+	}
+	if err := b.checkSlotReservations(slotToken, slots); err != nil {
+		return ogame.Fleet{}, err
+	}
+	if b.miniFleetToken == "" {
+		vals := url.Values{"page": {"ingame"}, "component": {"galaxy"}, "galaxy": {utils.FI64(target.Galaxy)}, "system": {utils.FI64(target.System)}}
+		pageHTML, err := b.getPageContent(vals)
+		if err != nil {
+			return ogame.Fleet{}, err
+		}
+		if b.miniFleetToken, err = b.extractor.ExtractMiniFleetToken(pageHTML); err != nil {
+			return ogame.Fleet{}, err
+		}
+	}
+	params := url.Values{"page": {"ingame"}, "component": {"fleetdispatch"}, "action": {"miniFleet"}, "ajax": {"1"}, "asJson": {"1"}}
+	if !b.IsV7() && !b.IsV8() && !b.IsV9() {
+		params = url.Values{"page": {"minifleet"}, "ajax": {"1"}}
+	}
+	payload := url.Values{
+		"mission":   {utils.FI64(ogame.Spy)},
+		"galaxy":    {utils.FI64(target.Galaxy)},
+		"system":    {utils.FI64(target.System)},
+		"position":  {utils.FI64(target.Position)},
+		"type":      {utils.FI64(target.Type)},
+		"shipCount": {utils.FI64(nbProbes)},
+		"token":     {b.miniFleetToken},
+	}
+	by, err := b.postPageContent(params, payload)
+	if err != nil {
+		return ogame.Fleet{}, err
+	}
+	// {"response":{"message":"Send espionage probe to:","type":1,"slots":5,"probes":10,"recyclers":0,"explorers":0,"missiles":0,"shipsSent":2,"coordinates":{"galaxy":1,"system":2,"position":3},"planetType":1,"success":true},"newAjaxToken":"..."}
+	var resp struct {
+		Response struct {
+			Message   string
+			ShipsSent int64
+			Success   bool
+		}
+		NewToken     string
+		NewAjaxToken string
+	}
+	if err := json.Unmarshal(by, &resp); err != nil {
+		b.miniFleetToken = ""
+		return ogame.Fleet{}, err
+	}
+	b.miniFleetToken = resp.NewAjaxToken
+	if b.miniFleetToken == "" {
+		b.miniFleetToken = resp.NewToken
+	}
+	if !resp.Response.Success {
+		b.miniFleetToken = ""
+		return ogame.Fleet{}, errors.New(resp.Response.Message)
+	}
+	b.consumeSlotReservation(slotToken)
+	fleet := ogame.Fleet{Mission: ogame.Spy, Destination: target}
+	fleet.Ships.EspionageProbe = resp.Response.ShipsSent
+	return fleet, nil
+}
+
+// hostileMissileThreats returns the interplanetary missiles seen in the latest espionage report of every spied planet,
+// excluding our own planets and the planets of our alliance members.
+// Every page is fetched in its own task so the tasks queue is not held for the whole scan.
+func (b *OGame) hostileMissileThreats(priority taskRunner.Priority) ([]ogame.MissileThreat, error) {
+	summaries, err := b.WithPriority(priority).GetEspionageReportMessages()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[ogame.Coordinate]struct{})
+	threats := make([]ogame.MissileThreat, 0)
+	for _, summary := range summaries {
+		if summary.Type != ogame.Report {
+			continue
+		}
+		if _, ok := seen[summary.Target]; ok {
+			continue
+		}
+		seen[summary.Target] = struct{}{}
+		report, err := b.WithPriority(priority).GetEspionageReport(summary.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get espionage report %d", summary.ID)
+		}
+		if report.Username == b.Player.PlayerName || report.InterplanetaryMissiles == nil || *report.InterplanetaryMissiles == 0 {
+			continue
+		}
+		threats = append(threats, ogame.MissileThreat{
+			Coordinate:             report.Coordinate,
+			Username:               report.Username,
+			InterplanetaryMissiles: *report.InterplanetaryMissiles,
+			ImpulseDrive:           report.ImpulseDrive,
+		})
+	}
+	return b.withoutAllianceMembers(priority, threats)
+}
+
+// withoutAllianceMembers removes the threats located on planets of our alliance members.
+// The alliances are read from the galaxy page of our first planet and of the threats.
+func (b *OGame) withoutAllianceMembers(priority taskRunner.Priority, threats []ogame.MissileThreat) ([]ogame.MissileThreat, error) {
+	planets := b.GetCachedPlanets()
+	if len(threats) == 0 || len(planets) == 0 {
+		return threats, nil
+	}
+	systems := make(map[[2]int64]ogame.SystemInfos)
+	galaxyInfos := func(galaxy, system int64) (ogame.SystemInfos, error) {
+		key := [2]int64{galaxy, system}
+		if infos, ok := systems[key]; ok {
+			return infos, nil
+		}
+		infos, err := b.WithPriority(priority).GalaxyInfos(galaxy, system)
+		if err != nil {
+			return infos, err
+		}
+		systems[key] = infos
+		return infos, nil
+	}
+	ownCoord := planets[0].GetCoordinate()
+	ownInfos, err := galaxyInfos(ownCoord.Galaxy, ownCoord.System)
+	if err != nil {
+		return nil, err
+	}
+	ownPlanet := ownInfos.Position(ownCoord.Position)
+	if ownPlanet == nil || ownPlanet.Alliance == nil {
+		return threats, nil
+	}
+	out := make([]ogame.MissileThreat, 0, len(threats))
+	for _, threat := range threats {
+		infos, err := galaxyInfos(threat.Coordinate.Galaxy, threat.Coordinate.System)
+		if err != nil {
+			return nil, err
+		}
+		if planet := infos.Position(threat.Coordinate.Position); planet != nil && planet.Alliance != nil && planet.Alliance.ID == ownPlanet.Alliance.ID {
+			continue
+		}
+		out = append(out, threat)
+	}
+	return out, nil
+}
+
+func (b *OGame) missileDefenseStatus(priority taskRunner.Priority) ([]ogame.MissileDefense, error) {
+	threats, err := b.hostileMissileThreats(priority)
+	if err != nil {
+		return nil, err
+	}
+	planets := b.WithPriority(priority).GetPlanets()
+	statuses := make([]ogame.MissileDefense, 0, len(planets))
+	for _, planet := range planets {
+		facilities, err := b.WithPriority(priority).GetFacilities(planet.GetID())
+		if err != nil {
+			return nil, err
+		}
+		defenses, err := b.WithPriority(priority).GetDefense(planet.GetID())
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, ogame.MissileDefense{
+			PlanetID:               planet.ID,
+			Coordinate:             planet.Coordinate,
+			MissileSilo:            facilities.MissileSilo,
+			AntiBallisticMissiles:  defenses.AntiBallisticMissiles,
+			InterplanetaryMissiles: defenses.InterplanetaryMissiles,
+		})
+	}
+	return ogame.ComputeMissileDefense(statuses, threats, b.serverData.Systems, b.serverData.DonutSystem), nil
+}
+
 // CheckTargetResponse ...
 type CheckTargetResponse struct {
 	Status string `json:"status"`
@@ -3347,7 +4269,7 @@ type CheckTargetResponse struct {
 }
 
 func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate,
-	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64, ensure bool) (ogame.Fleet, error) {
+	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64, ensure bool, slotToken string, spec *ogame.PayloadSpec) (ogame.Fleet, ogame.Resources, error) {
 
 	// Get existing fleet, so we can ensure new fleet ID is greater
 	initialFleets, slots := b.getFleets()
@@ -3359,19 +4281,22 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	}
 
 	if slots.InUse == slots.Total {
-		return ogame.Fleet{}, ogame.ErrAllSlotsInUse
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAllSlotsInUse
+	}
+	if err := b.checkSlotReservations(slotToken, slots); err != nil {
+		return ogame.Fleet{}, ogame.Resources{}, err
 	}
 
 	if mission == ogame.Expedition {
 		if slots.ExpInUse == slots.ExpTotal {
-			return ogame.Fleet{}, ogame.ErrAllSlotsInUse
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAllSlotsInUse
 		}
 	}
 
 	// Page 1 : get to fleet page
 	pageHTML, err := b.getPage(FleetdispatchPageName, ChangePlanet(celestialID))
 	if err != nil {
-		return ogame.Fleet{}, err
+		return ogame.Fleet{}, ogame.Resources{}, err
 	}
 
 	fleet1Doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
@@ -3379,11 +4304,15 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	if fleet1BodyID != FleetdispatchPageName {
 		now := time.Now().Unix()
 		b.error(ogame.ErrInvalidPlanetID.Error()+", planetID:", celestialID, ", ts: ", now)
-		return ogame.Fleet{}, ogame.ErrInvalidPlanetID
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrInvalidPlanetID
 	}
 
 	if b.extractor.ExtractIsInVacationFromDoc(fleet1Doc) {
-		return ogame.Fleet{}, ogame.ErrAccountInVacationMode
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAccountInVacationMode
+	}
+
+	if !ogame.IsSpeedAllowed(speed, b.extractor.ExtractAllowedSpeedsFromDoc(fleet1Doc)) {
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrInvalidSpeed
 	}
 
 	// Ensure we're not trying to attack/spy ourselves
@@ -3391,7 +4320,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	myCelestials, _ := b.extractor.ExtractCelestialsFromDoc(fleet1Doc)
 	for _, c := range myCelestials {
 		if c.GetCoordinate().Equal(where) && c.GetID() == celestialID {
-			return ogame.Fleet{}, errors.New("origin and destination are the same")
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrSameOriginDestination
 		}
 		if c.GetCoordinate().Equal(where) {
 			destinationIsMyOwnPlanet = true
@@ -3401,9 +4330,9 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	if destinationIsMyOwnPlanet {
 		switch mission {
 		case ogame.Spy:
-			return ogame.Fleet{}, errors.New("you cannot spy yourself")
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrSpyOwnCelestial
 		case ogame.Attack:
-			return ogame.Fleet{}, errors.New("you cannot attack yourself")
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAttackOwnCelestial
 		}
 	}
 
@@ -3421,13 +4350,16 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	} else {
 		for _, ship := range ships {
 			if ship.Nbr > availableShips.ByID(ship.ID) {
-				return ogame.Fleet{}, fmt.Errorf("not enough ships to send, %s", ogame.Objs.ByID(ship.ID).GetName())
+				return ogame.Fleet{}, ogame.Resources{}, fmt.Errorf("%w, %s", ogame.ErrNotEnoughShips, ogame.Objs.ByID(ship.ID).GetName())
 			}
 			atLeastOneShipSelected = true
 		}
 	}
 	if !atLeastOneShipSelected {
-		return ogame.Fleet{}, ogame.ErrNoShipSelected
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrNoShipSelected
+	}
+	if err := ogame.ValidateFleetComposition(ogame.ShipsInfos{}.FromQuantifiables(ships), mission, b.isProbeRaids()); err != nil {
+		return ogame.Fleet{}, ogame.Resources{}, err
 	}
 
 	payload := b.extractor.ExtractHiddenFieldsFromDoc(fleet1Doc)
@@ -3442,7 +4374,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 		tokenM = regexp.MustCompile(`var token = "([^"]+)";`).FindSubmatch(pageHTML)
 	}
 	if len(tokenM) != 2 {
-		return ogame.Fleet{}, errors.New("token not found")
+		return ogame.Fleet{}, ogame.Resources{}, errors.New("token not found")
 	}
 
 	payload.Set("token", string(tokenM[1]))
@@ -3470,7 +4402,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 			}
 		}
 		if !found {
-			return ogame.Fleet{}, ogame.ErrUnionNotFound
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrUnionNotFound
 		}
 	}
 
@@ -3478,24 +4410,33 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	by1, err := b.postPageContent(url.Values{"page": {"ingame"}, "component": {"fleetdispatch"}, "action": {"checkTarget"}, "ajax": {"1"}, "asJson": {"1"}}, payload)
 	if err != nil {
 		b.error(err.Error())
-		return ogame.Fleet{}, err
+		return ogame.Fleet{}, ogame.Resources{}, err
 	}
 	var checkRes CheckTargetResponse
 	if err := json.Unmarshal(by1, &checkRes); err != nil {
 		b.error(err.Error())
-		return ogame.Fleet{}, err
+		return ogame.Fleet{}, ogame.Resources{}, err
 	}
 
 	if !checkRes.TargetOk {
 		if len(checkRes.Errors) > 0 {
-			return ogame.Fleet{}, errors.New(checkRes.Errors[0].Message + " (" + strconv.Itoa(checkRes.Errors[0].Error) + ")")
+			return ogame.Fleet{}, ogame.Resources{}, ogame.NewFleetDispatchError(int64(checkRes.Errors[0].Error), checkRes.Errors[0].Message)
 		}
-		return ogame.Fleet{}, errors.New("target is not ok")
+		return ogame.Fleet{}, ogame.Resources{}, errors.New("target is not ok")
 	}
 
-	cargo := ogame.ShipsInfos{}.FromQuantifiables(ships).Cargo(b.getCachedResearch(), b.server.Settings.EspionageProbeRaids == 1, b.isCollector(), b.IsPioneers())
+	cargo := ogame.ShipsInfos{}.FromQuantifiables(ships).Cargo(b.getCachedResearch(), b.isProbeRaids(), b.isCollector(), b.IsPioneers())
 	newResources := ogame.Resources{}
-	if resources.Total() > cargo {
+	if spec != nil {
+		originCoords, err := b.extractor.ExtractPlanetCoordinate(pageHTML)
+		if err != nil {
+			return ogame.Fleet{}, ogame.Resources{}, err
+		}
+		_, fuel := b.calcFlightTime(originCoords, where, speed.Float64()/10, ogame.ShipsInfos{}.FromQuantifiables(ships), mission)
+		if newResources, err = b.resolvePayloadSpec(celestialID, *spec, b.extractor.ExtractResourcesFromDoc(fleet1Doc), cargo, fuel); err != nil {
+			return ogame.Fleet{}, ogame.Resources{}, err
+		}
+	} else if resources.Total() > cargo {
 		newResources.Deuterium = int64(math.Min(float64(resources.Deuterium), float64(cargo)))
 		cargo -= newResources.Deuterium
 		newResources.Crystal = int64(math.Min(float64(resources.Crystal), float64(cargo)))
@@ -3513,7 +4454,7 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	if b.IsV8() || b.IsV9() {
 		payload.Set("token", checkRes.NewAjaxToken)
 	}
-	payload.Set("speed", strconv.FormatInt(int64(speed), 10))
+	payload.Set("speed", strconv.FormatFloat(speed.Float64(), 'f', -1, 64))
 	payload.Set("crystal", utils.FI64(newResources.Crystal))
 	payload.Set("deuterium", utils.FI64(newResources.Deuterium))
 	payload.Set("metal", utils.FI64(newResources.Metal))
@@ -3556,12 +4497,13 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 		} `json:"errors"`
 	}
 	if err := json.Unmarshal(res, &resStruct); err != nil {
-		return ogame.Fleet{}, errors.New("failed to unmarshal response: " + err.Error())
+		return ogame.Fleet{}, ogame.Resources{}, errors.New("failed to unmarshal response: " + err.Error())
 	}
 
 	if len(resStruct.Errors) > 0 {
-		return ogame.Fleet{}, errors.New(resStruct.Errors[0].Message + " (" + utils.FI64(resStruct.Errors[0].Error) + ")")
+		return ogame.Fleet{}, ogame.Resources{}, ogame.NewFleetDispatchError(resStruct.Errors[0].Error, resStruct.Errors[0].Message)
 	}
+	b.consumeSlotReservation(slotToken)
 
 	// Page 5
 	movementHTML, _ := b.getPage(MovementPageName)
@@ -3580,24 +4522,64 @@ func (b *OGame) sendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 			}
 		}
 		if max.ID > maxInitialFleetID {
-			return max, nil
+			b.recordAttack(max.ID, where, mission)
+			return max, newResources, nil
 		}
 	}
+	b.recordAttack(0, where, mission)
 
 	slots = b.extractor.ExtractSlotsFromDoc(movementDoc)
 	if slots.InUse == slots.Total {
-		return ogame.Fleet{}, ogame.ErrAllSlotsInUse
+		return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAllSlotsInUse
 	}
 
 	if mission == ogame.Expedition {
 		if slots.ExpInUse == slots.ExpTotal {
-			return ogame.Fleet{}, ogame.ErrAllSlotsInUse
+			return ogame.Fleet{}, ogame.Resources{}, ogame.ErrAllSlotsInUse
 		}
 	}
 
 	now := time.Now().Unix()
 	b.error(errors.New("could not find new fleet ID").Error()+", planetID:", celestialID, ", ts: ", now)
-	return ogame.Fleet{}, errors.New("could not find new fleet ID")
+	return ogame.Fleet{}, ogame.Resources{}, errors.New("could not find new fleet ID")
+}
+
+// resolvePayloadSpec resolves the payload against the resources of the fleet dispatch page, minus the fuel of the flight.
+// The resources are read once more right before the dispatch, and the payload resolved again if they changed.
+func (b *OGame) resolvePayloadSpec(celestialID ogame.CelestialID, spec ogame.PayloadSpec, available ogame.Resources, cargo, fuel int64) (ogame.Resources, error) {
+	res, err := spec.Resolve(available, cargo, fuel)
+	if err != nil {
+		return res, err
+	}
+	current, err := b.getResources(celestialID)
+	if err != nil {
+		return res, err
+	}
+	if current.Metal != available.Metal || current.Crystal != available.Crystal || current.Deuterium != available.Deuterium {
+		return spec.Resolve(current, cargo, fuel)
+	}
+	return res, nil
+}
+
+// validateFleetTarget returns the restrictions of a mission from a celestial towards a target, read from the galaxy page
+// of the target system. No fleet is sent.
+func (b *OGame) validateFleetTarget(celestialID ogame.CelestialID, where ogame.Coordinate, mission ogame.MissionID) ([]ogame.FleetRestriction, error) {
+	if b.getCachedCelestial(celestialID) == nil {
+		return nil, ogame.ErrInvalidPlanetID
+	}
+	var targetInfos *ogame.PlanetInfos
+	if mission != ogame.Expedition && where.Position >= 1 && where.Position <= 15 {
+		systemInfos, err := b.galaxyInfos(where.Galaxy, where.System)
+		if err != nil {
+			return nil, err
+		}
+		targetInfos = systemInfos.Position(where.Position)
+	}
+	restrictions := ogame.FleetTargetRestrictions(where, mission, targetInfos, b.Player.PlayerID, b.getCachedResearch())
+	if b.isVacationModeEnabled {
+		restrictions = append(restrictions, ogame.NewFleetRestriction(ogame.RestrictionAccountVacation, ogame.ErrAccountInVacationMode.Error()))
+	}
+	return restrictions, nil
 }
 
 func (b *OGame) getPageMessages(page int64, tabid ogame.MessagesTabID) ([]byte, error) {
@@ -3794,6 +4776,33 @@ func (b *OGame) getEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageRe
 	return ogame.EspionageReport{}, errors.New("espionage report not found for " + coord.String())
 }
 
+// getEspionageReportDiff compares the two most recent espionage reports of coord
+func (b *OGame) getEspionageReportDiff(coord ogame.Coordinate) (ogame.EspionageReportDiff, error) {
+	msgs, err := b.getEspionageReportMessages()
+	if err != nil {
+		return ogame.EspionageReportDiff{}, err
+	}
+	reports := make([]ogame.EspionageReportSummary, 0)
+	for _, msg := range msgs {
+		if msg.Type == ogame.Report && msg.Target.Equal(coord) {
+			reports = append(reports, msg)
+		}
+	}
+	if len(reports) < 2 {
+		return ogame.EspionageReportDiff{}, ogame.ErrNotEnoughEspionageReports
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].CreatedAt.After(reports[j].CreatedAt) })
+	newer, err := b.getEspionageReport(reports[0].ID)
+	if err != nil {
+		return ogame.EspionageReportDiff{}, err
+	}
+	older, err := b.getEspionageReport(reports[1].ID)
+	if err != nil {
+		return ogame.EspionageReportDiff{}, err
+	}
+	return ogame.DiffEspionageReports(older, newer), nil
+}
+
 func (b *OGame) getDeleteMessagesToken() (string, error) {
 	pageHTML, _ := b.getPageContent(url.Values{"page": {"messages"}, "tab": {"20"}, "ajax": {"1"}})
 	tokenM := regexp.MustCompile(`name='token' value='([^']+)'`).FindSubmatch(pageHTML)
@@ -3803,6 +4812,43 @@ func (b *OGame) getDeleteMessagesToken() (string, error) {
 	return string(tokenM[1]), nil
 }
 
+func (b *OGame) getMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error) {
+	out := make([]ogame.MessageSummary, 0)
+	switch tabID {
+	case EspionageMessagesTabID:
+		msgs, err := b.getEspionageReportMessages()
+		if err != nil {
+			return out, err
+		}
+		for _, msg := range msgs {
+			typ := "report"
+			if msg.Type == ogame.Action {
+				typ = "action"
+			}
+			out = append(out, ogame.MessageSummary{ID: msg.ID, TabID: tabID, Type: typ, Coordinate: msg.Target, CreatedAt: msg.CreatedAt})
+		}
+	case CombatReportsMessagesTabID:
+		msgs, err := b.getCombatReportMessages()
+		if err != nil {
+			return out, err
+		}
+		for _, msg := range msgs {
+			out = append(out, ogame.MessageSummary{ID: msg.ID, TabID: tabID, Type: "combat", Coordinate: msg.Destination, CreatedAt: msg.CreatedAt})
+		}
+	case ExpeditionsMessagesTabID:
+		msgs, err := b.getExpeditionMessages()
+		if err != nil {
+			return out, err
+		}
+		for _, msg := range msgs {
+			out = append(out, ogame.MessageSummary{ID: msg.ID, TabID: tabID, Type: "expedition", Coordinate: msg.Coordinate, CreatedAt: msg.CreatedAt})
+		}
+	default:
+		return out, errors.New("unsupported messages tab " + utils.FI64(tabID))
+	}
+	return out, nil
+}
+
 func (b *OGame) deleteMessage(msgID int64) error {
 	token, err := b.getDeleteMessagesToken()
 	if err != nil {
@@ -3833,6 +4879,25 @@ func (b *OGame) deleteMessage(msgID int64) error {
 	return nil
 }
 
+func (b *OGame) deleteEspionageReportsOlderThan(d time.Duration) (int64, error) {
+	msgs, err := b.getEspionageReportMessages()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := b.serverNow().Add(-d)
+	var deleted int64
+	for _, msg := range msgs {
+		if msg.CreatedAt.IsZero() || !msg.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := b.deleteMessage(msg.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 const (
 	EspionageMessagesTabID            ogame.MessagesTabID = 20
 	CombatReportsMessagesTabID        ogame.MessagesTabID = 21
@@ -3935,6 +5000,74 @@ func getResourcesProductionsLight(resBuildings ogame.ResourcesBuildings, researc
 	return productions
 }
 
+// mineUpgradeROI returns how long the extra production of the next mine level takes to pay back its cost.
+// Both are valued at the standard 3:2:1 merchant ratio, and the energy the new level consumes is accounted for.
+func mineUpgradeROI(resBuildings ogame.ResourcesBuildings, researches ogame.Researches, resSettings ogame.ResourceSettings,
+	temp ogame.Temperature, universeSpeed int64, mineID ogame.ID) (time.Duration, error) {
+	upgraded := resBuildings
+	var cost ogame.Resources
+	switch mineID {
+	case ogame.MetalMineID:
+		upgraded.MetalMine++
+		cost = ogame.MetalMine.GetPrice(upgraded.MetalMine)
+	case ogame.CrystalMineID:
+		upgraded.CrystalMine++
+		cost = ogame.CrystalMine.GetPrice(upgraded.CrystalMine)
+	case ogame.DeuteriumSynthesizerID:
+		upgraded.DeuteriumSynthesizer++
+		cost = ogame.DeuteriumSynthesizer.GetPrice(upgraded.DeuteriumSynthesizer)
+	default:
+		return 0, ogame.ErrNotMine
+	}
+	before := getResourcesProductionsLight(resBuildings, researches, resSettings, temp, universeSpeed)
+	after := getResourcesProductionsLight(upgraded, researches, resSettings, temp, universeSpeed)
+	gainPerHour := after.Value() - before.Value()
+	if gainPerHour <= 0 {
+		return 0, ogame.ErrNoProductionGain
+	}
+	return time.Duration(float64(cost.Value()) / float64(gainPerHour) * float64(time.Hour)), nil
+}
+
+func (b *OGame) getAllowedSpeeds(celestialID ogame.CelestialID) ([]ogame.Speed, error) {
+	pageHTML, err := b.getPage(FleetdispatchPageName, ChangePlanet(celestialID))
+	if err != nil {
+		return []ogame.Speed{}, err
+	}
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTML))
+	if b.extractor.ExtractBodyIDFromDoc(doc) != FleetdispatchPageName {
+		return []ogame.Speed{}, ogame.ErrInvalidPlanetID
+	}
+	return b.extractor.ExtractAllowedSpeedsFromDoc(doc), nil
+}
+
+func (b *OGame) getTemperatureEffects(celestialID ogame.CelestialID) (int64, float64, error) {
+	planet, err := b.getPlanet(celestialID)
+	if err != nil {
+		return 0, 0, err
+	}
+	satelliteEnergyPerUnit := ogame.SolarSatellite.Production(planet.Temperature, 1, b.isCollector())
+	return satelliteEnergyPerUnit, ogame.DeuteriumSynthesizer.TemperatureFactor(planet.Temperature.Mean()), nil
+}
+
+func (b *OGame) mineUpgradeROI(celestialID ogame.CelestialID, mineID ogame.ID) (time.Duration, error) {
+	if mineID != ogame.MetalMineID && mineID != ogame.CrystalMineID && mineID != ogame.DeuteriumSynthesizerID {
+		return 0, ogame.ErrNotMine
+	}
+	planet, err := b.getPlanet(celestialID)
+	if err != nil {
+		return 0, err
+	}
+	resBuildings, err := b.getResourcesBuildings(celestialID)
+	if err != nil {
+		return 0, err
+	}
+	resSettings, err := b.getResourceSettings(planet.ID)
+	if err != nil {
+		return 0, err
+	}
+	return mineUpgradeROI(resBuildings, b.getResearch(), resSettings, planet.Temperature, b.serverData.Speed, mineID)
+}
+
 func (b *OGame) getPublicIP() (string, error) {
 	var res struct {
 		IP string `json:"ip"`
@@ -3961,7 +5094,8 @@ func (b *OGame) getPublicIP() (string, error) {
 
 func (b *OGame) stateChanged(locked bool, actor string) {
 	for _, clb := range b.stateChangeCallbacks {
-		clb(locked, actor)
+		clb := clb
+		b.dispatchHook(func() { clb(locked, actor) })
 	}
 }
 
@@ -3983,7 +5117,7 @@ func (b *OGame) botUnlock(unlockedBy string) {
 
 func (b *OGame) addAccount(number int, lang string) (*AddAccountRes, error) {
 	accountGroup := fmt.Sprintf("%s_%d", lang, number)
-	return AddAccount(b.client, b.ctx, b.lobby, accountGroup, b.bearerToken)
+	return AddAccountWithLocale(b.client, b.ctx, b.lobby, accountGroup, b.bearerToken, GetLobbyLocale(lang))
 }
 
 func (b *OGame) getCachedCelestial(v any) Celestial {
@@ -4037,17 +5171,17 @@ func (b *OGame) GetCachedCelestialByID(celestialID ogame.CelestialID) Celestial
 	return nil
 }
 
-// GetCachedCelestialByCoord return celestial from cached value
+// GetCachedCelestialByCoord return celestial from cached value.
+// If several celestials are at the coordinate (planet relocation), the one with the lowest id is returned,
+// so the result does not depend on the order of the cached planets.
 func (b *OGame) GetCachedCelestialByCoord(coord ogame.Coordinate) Celestial {
-	for _, p := range b.GetCachedPlanets() {
-		if p.GetCoordinate().Equal(coord) {
-			return p
-		}
-		if p.Moon != nil && p.Moon.GetCoordinate().Equal(coord) {
-			return *p.Moon
+	var out Celestial
+	for _, c := range b.getCachedCelestials() {
+		if c.GetCoordinate().Equal(coord) && (out == nil || c.GetID() < out.GetID()) {
+			out = c
 		}
 	}
-	return nil
+	return out
 }
 
 func (b *OGame) getCachedMoons() []Moon {
@@ -4127,14 +5261,25 @@ func (b *OGame) ValidateAccount(code string) error {
 	return b.validateAccount(code)
 }
 
-// OnStateChange register a callback that is notified when the bot state changes
+// OnStateChange register a callback that is notified when the bot state changes.
+// Like the other hooks, it is called asynchronously on the hooks dispatch goroutine.
 func (b *OGame) OnStateChange(clb func(locked bool, actor string)) {
 	b.stateChangeCallbacks = append(b.stateChangeCallbacks, clb)
 }
 
 // GetState returns the current bot state
 func (b *OGame) GetState() (bool, string) {
-	return atomic.LoadInt32(&b.lockedAtom) == 1, b.state
+	locked := atomic.LoadInt32(&b.lockedAtom) == 1
+	if !locked && b.IsThrottled() {
+		return false, "Throttled"
+	}
+	return locked, b.state
+}
+
+// IsCompatibilityMode returns either or not the game version is more recent than the newest extractor supports,
+// in which case the newest extractor is used and some features might not work
+func (b *OGame) IsCompatibilityMode() bool {
+	return atomic.LoadInt32(&b.compatibilityModeAtom) == 1
 }
 
 // IsLocked returns either or not the bot is currently locked
@@ -4154,7 +5299,9 @@ func (b *OGame) AddAccount(number int, lang string) (*AddAccountRes, error) {
 
 // WithPriority ...
 func (b *OGame) WithPriority(priority taskRunner.Priority) Prioritizable {
-	return b.taskRunnerInst.WithPriority(priority)
+	task := b.taskRunnerInst.WithPriority(priority)
+	task.priority = priority
+	return task
 }
 
 // Begin start a transaction. Once this function is called, "Done" must be called to release the lock.
@@ -4172,6 +5319,11 @@ func (b *OGame) SetInitiator(initiator string) Prioritizable {
 	return nil
 }
 
+// SetSlotReservation sets the token of the slots reservation (see ReserveSlots) the fleets sent by the task can use
+func (b *OGame) SetSlotReservation(token string) Prioritizable {
+	return b.WithPriority(taskRunner.Normal).SetSlotReservation(token)
+}
+
 // Done ...
 func (b *OGame) Done() {}
 
@@ -4200,6 +5352,26 @@ func (b *OGame) GetLanguage() string {
 	return b.language
 }
 
+// SetAPINewHostname change the hostname that replaces the game hostname in proxied pages.
+// The hostname must be of the form scheme://host[:port], eg: https://someuniverse.example.com
+func (b *OGame) SetAPINewHostname(hostname string) error {
+	u, err := url.Parse(strings.TrimSuffix(hostname, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return ogame.ErrInvalidAPINewHostname
+	}
+	b.apiNewHostnameMu.Lock()
+	defer b.apiNewHostnameMu.Unlock()
+	b.apiNewHostname = u.Scheme + "://" + u.Host
+	return nil
+}
+
+func (b *OGame) getAPINewHostname() string {
+	b.apiNewHostnameMu.RLock()
+	defer b.apiNewHostnameMu.RUnlock()
+	return b.apiNewHostname
+}
+
 // SetUserAgent change the user-agent used by the http client
 func (b *OGame) SetUserAgent(newUserAgent string) {
 	b.client.SetUserAgent(newUserAgent)
@@ -4269,6 +5441,11 @@ func (b *OGame) IsPioneers() bool {
 	return b.lobby == LobbyPioneers
 }
 
+// IsProbeRaids either or not the server allows espionage probes to carry resources and attack
+func (b *OGame) IsProbeRaids() bool {
+	return b.isProbeRaids()
+}
+
 // IsDonutGalaxy shortcut to get ogame galaxy donut config
 func (b *OGame) IsDonutGalaxy() bool {
 	return b.isDonutGalaxy()
@@ -4315,6 +5492,16 @@ func (b *OGame) GetCachedPreferences() ogame.Preferences {
 	return b.CachedPreferences
 }
 
+// GetSpyReportSettings returns the espionage preferences of the account
+func (b *OGame) GetSpyReportSettings() (ogame.SpyReportSettings, error) {
+	return b.WithPriority(taskRunner.Normal).GetSpyReportSettings()
+}
+
+// SetSpyReportSettings changes the espionage preferences of the account, the other preferences are kept
+func (b *OGame) SetSpyReportSettings(settings ogame.SpyReportSettings) error {
+	return b.WithPriority(taskRunner.Normal).SetSpyReportSettings(settings)
+}
+
 // SetVacationMode puts account in vacation mode
 func (b *OGame) SetVacationMode() error {
 	return b.WithPriority(taskRunner.Normal).SetVacationMode()
@@ -4363,6 +5550,16 @@ func (b *OGame) GetMoons() []Moon {
 	return b.WithPriority(taskRunner.Normal).GetMoons()
 }
 
+// GetEffectiveSpeeds gets the speeds currently applied by the universe, including the temporary modifiers (events)
+func (b *OGame) GetEffectiveSpeeds() (ogame.EffectiveSpeeds, error) {
+	return b.WithPriority(taskRunner.Normal).GetEffectiveSpeeds()
+}
+
+// GetNotifications gets the active notifications of the top bar (unread messages, incoming attack, wreck field)
+func (b *OGame) GetNotifications() ([]ogame.Notification, error) {
+	return b.WithPriority(taskRunner.Normal).GetNotifications()
+}
+
 // GetMoon gets infos for moonID
 func (b *OGame) GetMoon(v any) (Moon, error) {
 	return b.WithPriority(taskRunner.Normal).GetMoon(v)
@@ -4390,6 +5587,11 @@ func (b *OGame) GetCelestial(v any) (Celestial, error) {
 	return b.WithPriority(taskRunner.Normal).GetCelestial(v)
 }
 
+// GetCelestialsByCoord get all the player's planets/moons at the coordinate
+func (b *OGame) GetCelestialsByCoord(coord ogame.Coordinate) ([]Celestial, error) {
+	return b.WithPriority(taskRunner.Normal).GetCelestialsByCoord(coord)
+}
+
 // ServerVersion returns OGame version
 func (b *OGame) ServerVersion() string {
 	return b.serverData.Version
@@ -4421,11 +5623,22 @@ func (b *OGame) SendMessageAlliance(associationID int64, message string) error {
 	return b.WithPriority(taskRunner.Normal).SendMessageAlliance(associationID, message)
 }
 
+// MissileDefenseStatus gets, for each planet, the missile silo level, anti-ballistic missiles count and the
+// estimated interplanetary missiles of the spied hostile players within missile range.
+func (b *OGame) MissileDefenseStatus() ([]ogame.MissileDefense, error) {
+	return b.missileDefenseStatus(taskRunner.Normal)
+}
+
 // GetFleets get the player's own fleets activities
 func (b *OGame) GetFleets(opts ...Option) ([]ogame.Fleet, ogame.Slots) {
 	return b.WithPriority(taskRunner.Normal).GetFleets(opts...)
 }
 
+// GetFleet get one of the player's own fleets in flight, by id
+func (b *OGame) GetFleet(fleetID ogame.FleetID) (ogame.Fleet, error) {
+	return b.WithPriority(taskRunner.Normal).GetFleet(fleetID)
+}
+
 // GetFleetsFromEventList get the player's own fleets activities
 func (b *OGame) GetFleetsFromEventList() []ogame.Fleet {
 	return b.WithPriority(taskRunner.Normal).GetFleetsFromEventList()
@@ -4441,6 +5654,34 @@ func (b *OGame) GetAttacks(opts ...Option) ([]ogame.AttackEvent, error) {
 	return b.WithPriority(taskRunner.Normal).GetAttacks(opts...)
 }
 
+// GetEventList get every fleet of the event list, hostile, own and neutral
+func (b *OGame) GetEventList(opts ...Option) ([]ogame.Event, error) {
+	return b.WithPriority(taskRunner.Normal).GetEventList(opts...)
+}
+
+// GetEffectiveLabLevel get the summed lab level used for research times when researching on planetID.
+// Uses the empire page with a commander, otherwise visits the facilities of every planet.
+func (b *OGame) GetEffectiveLabLevel(planetID ogame.PlanetID) (int64, error) {
+	return b.WithPriority(taskRunner.Normal).GetEffectiveLabLevel(planetID)
+}
+
+// GetFriendlyArrivals get the incoming fleets of other players that are not hostile (alliance transports, deployments...).
+// A friendly arrival event is published the first time an arrival is seen.
+func (b *OGame) GetFriendlyArrivals() ([]ogame.Event, error) {
+	return b.WithPriority(taskRunner.Normal).GetFriendlyArrivals()
+}
+
+// GetSpiedEvents get the espionage actions of other players on our celestials since the given time.
+// A spied event is published the first time an espionage action is seen.
+func (b *OGame) GetSpiedEvents(since time.Time) ([]ogame.SpiedEvent, error) {
+	return b.WithPriority(taskRunner.Normal).GetSpiedEvents(since)
+}
+
+// FindColonizationSlots scans a range of systems (50 at most) for empty positions, sorted by expected planet size
+func (b *OGame) FindColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error) {
+	return b.WithPriority(taskRunner.Low).FindColonizationSlots(galaxy, fromSystem, toSystem, preferredPositions)
+}
+
 // GalaxyInfos get information of all planets and moons of a solar system
 func (b *OGame) GalaxyInfos(galaxy, system int64, options ...Option) (ogame.SystemInfos, error) {
 	return b.WithPriority(taskRunner.Normal).GalaxyInfos(galaxy, system, options...)
@@ -4472,6 +5713,12 @@ func (b *OGame) GetShips(celestialID ogame.CelestialID, options ...Option) (ogam
 	return b.WithPriority(taskRunner.Normal).GetShips(celestialID, options...)
 }
 
+// GetMissiles gets the interplanetary and anti-ballistic missiles of a planet, and the number of slots of its
+// missile silo (an anti-ballistic missile takes one slot, an interplanetary missile takes two)
+func (b *OGame) GetMissiles(celestialID ogame.CelestialID) (ipm, abm, siloCapacity int64, err error) {
+	return b.WithPriority(taskRunner.Normal).GetMissiles(celestialID)
+}
+
 // GetFacilities gets all facilities information of a planet
 func (b *OGame) GetFacilities(celestialID ogame.CelestialID, options ...Option) (ogame.Facilities, error) {
 	return b.WithPriority(taskRunner.Normal).GetFacilities(celestialID, options...)
@@ -4508,6 +5755,34 @@ func (b *OGame) TechnologyDetails(celestialID ogame.CelestialID, id ogame.ID) (o
 	return b.WithPriority(taskRunner.Normal).TechnologyDetails(celestialID, id)
 }
 
+// ShipBuildTime returns how long the shipyard of a celestial takes to build count ships (or defenses),
+// given its shipyard/nanite levels and the class/officer bonuses
+func (b *OGame) ShipBuildTime(celestialID ogame.CelestialID, shipID ogame.ID, count int64) (time.Duration, error) {
+	return b.WithPriority(taskRunner.Normal).ShipBuildTime(celestialID, shipID, count)
+}
+
+// ValidateFleetTarget returns the restrictions the game would apply to a mission from a celestial towards a target,
+// without sending anything. An empty list does not guarantee the fleet can be sent (ships, fuel, slots are not checked).
+func (b *OGame) ValidateFleetTarget(celestialID ogame.CelestialID, where ogame.Coordinate, mission ogame.MissionID) ([]ogame.FleetRestriction, error) {
+	return b.WithPriority(taskRunner.Normal).ValidateFleetTarget(celestialID, where, mission)
+}
+
+// GetAllowedSpeeds returns the fleet speeds the fleet dispatch page of a celestial offers
+func (b *OGame) GetAllowedSpeeds(celestialID ogame.CelestialID) ([]ogame.Speed, error) {
+	return b.WithPriority(taskRunner.Normal).GetAllowedSpeeds(celestialID)
+}
+
+// GetTemperatureEffects returns the energy one solar satellite produces on a planet, and the factor its temperature
+// applies to the deuterium synthesizer production
+func (b *OGame) GetTemperatureEffects(celestialID ogame.CelestialID) (satelliteEnergyPerUnit int64, deutProductionFactor float64, err error) {
+	return b.WithPriority(taskRunner.Normal).GetTemperatureEffects(celestialID)
+}
+
+// MineUpgradeROI returns how long the extra production of the next level of a mine takes to pay back its cost
+func (b *OGame) MineUpgradeROI(celestialID ogame.CelestialID, mineID ogame.ID) (time.Duration, error) {
+	return b.WithPriority(taskRunner.Normal).MineUpgradeROI(celestialID, mineID)
+}
+
 // TearDown tears down any ogame building
 func (b *OGame) TearDown(celestialID ogame.CelestialID, id ogame.ID) error {
 	return b.WithPriority(taskRunner.Normal).TearDown(celestialID, id)
@@ -4538,6 +5813,11 @@ func (b *OGame) BuildShips(celestialID ogame.CelestialID, shipID ogame.ID, nbr i
 	return b.WithPriority(taskRunner.Normal).BuildShips(celestialID, shipID, nbr)
 }
 
+// GetBuildQueue returns the building being built followed by the buildings queued behind it
+func (b *OGame) GetBuildQueue(celestialID ogame.CelestialID) ([]ogame.QueuedBuild, error) {
+	return b.WithPriority(taskRunner.Normal).GetBuildQueue(celestialID)
+}
+
 // ConstructionsBeingBuilt returns the building & research being built, and the time remaining (secs)
 func (b *OGame) ConstructionsBeingBuilt(celestialID ogame.CelestialID) (ogame.ID, int64, ogame.ID, int64, ogame.ID, int64, ogame.ID, int64) {
 	return b.WithPriority(taskRunner.Normal).ConstructionsBeingBuilt(celestialID)
@@ -4548,6 +5828,12 @@ func (b *OGame) CancelBuilding(celestialID ogame.CelestialID) error {
 	return b.WithPriority(taskRunner.Normal).CancelBuilding(celestialID)
 }
 
+// CancelQueuedBuild removes a building waiting in the building queue, index being its position in GetBuildQueue.
+// The building in progress (index 0) is cancelled with CancelBuilding.
+func (b *OGame) CancelQueuedBuild(celestialID ogame.CelestialID, index int64) error {
+	return b.WithPriority(taskRunner.Normal).CancelQueuedBuild(celestialID, index)
+}
+
 // CancelLfBuilding cancel the construction of a lifeform building on a specified planet
 func (b *OGame) CancelLfBuilding(celestialID ogame.CelestialID) error {
 	return b.WithPriority(taskRunner.Normal).CancelLfBuilding(celestialID)
@@ -4568,11 +5854,28 @@ func (b *OGame) GetResources(celestialID ogame.CelestialID) (ogame.Resources, er
 	return b.WithPriority(taskRunner.Normal).GetResources(celestialID)
 }
 
+// GetWastedResources returns the resources lost to full storages since the previous call for this celestial.
+// The first call only records a sample and returns nothing.
+func (b *OGame) GetWastedResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
+	return b.WithPriority(taskRunner.Normal).GetWastedResources(celestialID)
+}
+
 // GetResourcesDetails gets user resources
 func (b *OGame) GetResourcesDetails(celestialID ogame.CelestialID) (ogame.ResourcesDetails, error) {
 	return b.WithPriority(taskRunner.Normal).GetResourcesDetails(celestialID)
 }
 
+// PlanToMatch returns the upgrades, total price and total time needed to bring
+// the resource buildings of a planet up to the target levels
+func (b *OGame) PlanToMatch(celestialID ogame.CelestialID, target ogame.ResourcesBuildings) ([]ogame.BuildStep, ogame.Resources, time.Duration, error) {
+	return b.WithPriority(taskRunner.Normal).PlanToMatch(celestialID, target)
+}
+
+// GetDarkMatter gets the current dark matter balance of the account
+func (b *OGame) GetDarkMatter() (int64, error) {
+	return b.WithPriority(taskRunner.Normal).GetDarkMatter()
+}
+
 // GetTechs gets a celestial supplies/facilities/ships/researches
 func (b *OGame) GetTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
 	return b.WithPriority(taskRunner.Normal).GetTechs(celestialID)
@@ -4584,6 +5887,13 @@ func (b *OGame) SendFleet(celestialID ogame.CelestialID, ships []ogame.Quantifia
 	return b.WithPriority(taskRunner.Normal).SendFleet(celestialID, ships, speed, where, mission, resources, holdingTime, unionID)
 }
 
+// SendFleetWithPayload sends a fleet, the payload is resolved at dispatch time against the resources of the origin.
+// Returns the resources loaded.
+func (b *OGame) SendFleetWithPayload(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate,
+	mission ogame.MissionID, spec ogame.PayloadSpec, holdingTime, unionID int64) (ogame.Fleet, ogame.Resources, error) {
+	return b.WithPriority(taskRunner.Normal).SendFleetWithPayload(celestialID, ships, speed, where, mission, spec, holdingTime, unionID)
+}
+
 // EnsureFleet either sends all the requested ships or fail
 func (b *OGame) EnsureFleet(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate,
 	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
@@ -4595,6 +5905,12 @@ func (b *OGame) DestroyRockets(planetID ogame.PlanetID, abm, ipm int64) error {
 	return b.WithPriority(taskRunner.Normal).DestroyRockets(planetID, abm, ipm)
 }
 
+// QuickSpy sends espionage probes using the galaxy view quick action, from the celestial currently selected in the game.
+// nbProbes <= 0 uses the probes count of the preferences. The returned fleet has no ID.
+func (b *OGame) QuickSpy(target ogame.Coordinate, nbProbes int64) (ogame.Fleet, error) {
+	return b.WithPriority(taskRunner.Normal).QuickSpy(target, nbProbes)
+}
+
 // SendIPM sends IPM
 func (b *OGame) SendIPM(planetID ogame.PlanetID, coord ogame.Coordinate, nbr int64, priority ogame.ID) (int64, error) {
 	return b.WithPriority(taskRunner.Normal).SendIPM(planetID, coord, nbr, priority)
@@ -4610,6 +5926,11 @@ func (b *OGame) GetEspionageReportFor(coord ogame.Coordinate) (ogame.EspionageRe
 	return b.WithPriority(taskRunner.Normal).GetEspionageReportFor(coord)
 }
 
+// GetEspionageReportDiff returns what changed at a target between its two most recent espionage reports
+func (b *OGame) GetEspionageReportDiff(coord ogame.Coordinate) (ogame.EspionageReportDiff, error) {
+	return b.WithPriority(taskRunner.Normal).GetEspionageReportDiff(coord)
+}
+
 // GetExpeditionMessages gets the expedition messages
 func (b *OGame) GetExpeditionMessages() ([]ogame.ExpeditionMessage, error) {
 	return b.WithPriority(taskRunner.Normal).GetExpeditionMessages()
@@ -4635,6 +5956,11 @@ func (b *OGame) GetEspionageReportMessages() ([]ogame.EspionageReportSummary, er
 	return b.WithPriority(taskRunner.Normal).GetEspionageReportMessages()
 }
 
+// GetMessageSummaries gets the summary of each message of a tab (espionage, combat reports or expeditions)
+func (b *OGame) GetMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error) {
+	return b.WithPriority(taskRunner.Normal).GetMessageSummaries(tabID)
+}
+
 // GetEspionageReport gets a detailed espionage report
 func (b *OGame) GetEspionageReport(msgID int64) (ogame.EspionageReport, error) {
 	return b.WithPriority(taskRunner.Normal).GetEspionageReport(msgID)
@@ -4650,6 +5976,11 @@ func (b *OGame) DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error {
 	return b.WithPriority(taskRunner.Normal).DeleteAllMessagesFromTab(tabID)
 }
 
+// DeleteEspionageReportsOlderThan deletes the espionage reports older than d, and returns how many were deleted
+func (b *OGame) DeleteEspionageReportsOlderThan(d time.Duration) (int64, error) {
+	return b.WithPriority(taskRunner.Normal).DeleteEspionageReportsOlderThan(d)
+}
+
 // GetResourcesProductions gets the planet resources production
 func (b *OGame) GetResourcesProductions(planetID ogame.PlanetID) (ogame.Resources, error) {
 	return b.WithPriority(taskRunner.Normal).GetResourcesProductions(planetID)
@@ -4714,6 +6045,11 @@ func (b *OGame) UnsafePhalanx(moonID ogame.MoonID, coord ogame.Coordinate) ([]og
 	return b.WithPriority(taskRunner.Normal).UnsafePhalanx(moonID, coord)
 }
 
+// GetMoonFacilities gets the moon facilities, the phalanx range and the jump gate recharge countdown
+func (b *OGame) GetMoonFacilities(moonID ogame.MoonID) (ogame.MoonFacilities, error) {
+	return b.WithPriority(taskRunner.Normal).GetMoonFacilities(moonID)
+}
+
 // JumpGateDestinations returns available destinations for jump gate.
 func (b *OGame) JumpGateDestinations(origin ogame.MoonID) (moonIDs []ogame.MoonID, rechargeCountdown int64, err error) {
 	return b.WithPriority(taskRunner.Normal).JumpGateDestinations(origin)
@@ -4734,6 +6070,17 @@ func (b *OGame) CreateUnion(fleet ogame.Fleet, users []string) (int64, error) {
 	return b.WithPriority(taskRunner.Normal).CreateUnion(fleet, users)
 }
 
+// GetACSUnionDetails gets the members of an ACS union we take part in, and their fleets
+func (b *OGame) GetACSUnionDetails(unionID int64) (ogame.ACSUnionDetails, error) {
+	return b.WithPriority(taskRunner.Normal).GetACSUnionDetails(unionID)
+}
+
+// AttacksRemainingAgainst returns how many attacks can still be sent to a target without crossing the bashing limit,
+// and when the oldest attack counted expires
+func (b *OGame) AttacksRemainingAgainst(target ogame.Coordinate) (int64, time.Time, error) {
+	return b.WithPriority(taskRunner.Normal).AttacksRemainingAgainst(target)
+}
+
 // HeadersForPage gets the headers for a specific ogame page
 func (b *OGame) HeadersForPage(url string) (http.Header, error) {
 	return b.WithPriority(taskRunner.Normal).HeadersForPage(url)
@@ -4769,6 +6116,11 @@ func (b *OGame) Highscore(category, typ, page int64) (ogame.Highscore, error) {
 	return b.WithPriority(taskRunner.Normal).Highscore(category, typ, page)
 }
 
+// GetMyPoints gets the points of the bot player in each highscore type
+func (b *OGame) GetMyPoints() (ogame.Points, error) {
+	return b.WithPriority(taskRunner.Normal).GetMyPoints()
+}
+
 // GetAllResources gets the resources of all planets and moons
 func (b *OGame) GetAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	return b.WithPriority(taskRunner.Normal).GetAllResources()
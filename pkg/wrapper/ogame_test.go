@@ -2,14 +2,23 @@ package wrapper
 
 import (
 	"bytes"
+	"context"
 	"github.com/PuerkitoBio/goquery"
+	"github.com/alaingilbert/clockwork"
+	v6 "github.com/alaingilbert/ogame/pkg/extractor/v6"
+	v7 "github.com/alaingilbert/ogame/pkg/extractor/v7"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
 	"github.com/alaingilbert/ogame/pkg/ogame"
 	"github.com/alaingilbert/ogame/pkg/utils"
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func BenchmarkUserInfoRegex(b *testing.B) {
@@ -98,6 +107,24 @@ func TestEnergyProduced(t *testing.T) {
 	assert.Equal(t, int64(9200+3002+1326), produced)
 }
 
+func TestMineUpgradeROI(t *testing.T) {
+	resBuildings := ogame.ResourcesBuildings{MetalMine: 20, CrystalMine: 15, DeuteriumSynthesizer: 10, SolarPlant: 22}
+	resSettings := ogame.ResourceSettings{MetalMine: 100, CrystalMine: 100, DeuteriumSynthesizer: 100, SolarPlant: 100}
+	temp := ogame.Temperature{Min: -23, Max: 17}
+	roi, err := mineUpgradeROI(resBuildings, ogame.Researches{}, resSettings, temp, 1, ogame.MetalMineID)
+	assert.NoError(t, err)
+	assert.True(t, roi > 24*time.Hour)
+	fasterUni, _ := mineUpgradeROI(resBuildings, ogame.Researches{}, resSettings, temp, 4, ogame.MetalMineID)
+	assert.True(t, fasterUni < roi)
+
+	_, err = mineUpgradeROI(resBuildings, ogame.Researches{}, resSettings, temp, 1, ogame.SolarPlantID)
+	assert.Equal(t, ogame.ErrNotMine, err)
+
+	// Without energy, the extra level only makes the planet consume more
+	_, err = mineUpgradeROI(ogame.ResourcesBuildings{MetalMine: 20}, ogame.Researches{}, resSettings, temp, 1, ogame.MetalMineID)
+	assert.Equal(t, ogame.ErrNoProductionGain, err)
+}
+
 func TestExtractCargoCapacity(t *testing.T) {
 	pageHTMLBytes, _ := ioutil.ReadFile("../../samples/unversioned/sendfleet3.htm")
 	fleet3Doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(pageHTMLBytes))
@@ -243,3 +270,212 @@ func TestVersion(t *testing.T) {
 func TestFindSlowestSpeed(t *testing.T) {
 	assert.Equal(t, int64(8000), findSlowestSpeed(ogame.ShipsInfos{SmallCargo: 1, LargeCargo: 1}, ogame.Researches{CombustionDrive: 6}, false, false))
 }
+
+func TestFindAccount(t *testing.T) {
+	servers := []Server{{Name: "Bellatrix", Language: "en", Number: 1}, {Name: "Bellatrix", Language: "fr", Number: 1}}
+	accounts := []Account{{ID: 123}}
+	accounts[0].Server.Language = "fr"
+	accounts[0].Server.Number = 1
+
+	acc, server, err := findAccount(Lobby, "Bellatrix", "fr", 0, accounts, servers)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123), acc.ID)
+	assert.Equal(t, "fr", server.Language)
+
+	_, _, err = findAccount(Lobby, "Bellatrix", "en", 0, accounts, servers)
+	assert.Equal(t, ogame.ErrAccountNotFound, err)
+
+	_, _, err = findAccount(LobbyPioneers, "Andromeda", "en", 0, accounts, servers)
+	assert.ErrorIs(t, err, ogame.ErrServerNotFound)
+	assert.Contains(t, err.Error(), "lobby-pioneers")
+}
+
+func TestSetOGameLobby(t *testing.T) {
+	b := &OGame{}
+	assert.NoError(t, b.setOGameLobby(""))
+	assert.Equal(t, Lobby, b.lobby)
+	assert.NoError(t, b.setOGameLobby(LobbyPioneers))
+	assert.Equal(t, LobbyPioneers, b.lobby)
+	assert.ErrorIs(t, b.setOGameLobby("lobby-steam"), ogame.ErrInvalidLobby)
+	assert.Equal(t, LobbyPioneers, b.lobby)
+}
+
+func TestSetAPINewHostname(t *testing.T) {
+	b := &OGame{}
+	assert.NoError(t, b.SetAPINewHostname("https://someuniverse.example.com/"))
+	assert.Equal(t, "https://someuniverse.example.com", b.getAPINewHostname())
+	assert.NoError(t, b.SetAPINewHostname("http://127.0.0.1:8080"))
+	assert.Equal(t, "http://127.0.0.1:8080", b.getAPINewHostname())
+	assert.ErrorIs(t, b.SetAPINewHostname("someuniverse.example.com"), ogame.ErrInvalidAPINewHostname)
+	assert.ErrorIs(t, b.SetAPINewHostname("ftp://someuniverse.example.com"), ogame.ErrInvalidAPINewHostname)
+	assert.ErrorIs(t, b.SetAPINewHostname("https://someuniverse.example.com/game?x=1"), ogame.ErrInvalidAPINewHostname)
+	assert.Equal(t, "http://127.0.0.1:8080", b.getAPINewHostname())
+}
+
+func TestDetectLoggedOut(t *testing.T) {
+	eventList := url.Values{"page": {"componentOnly"}, "component": {EventListAjaxPageName}}
+	overview := url.Values{"page": {"ingame"}, "component": {OverviewPageName}}
+	galaxy := url.Values{"page": {GalaxyContentAjaxPageName}, "ajax": {"1"}}
+
+	pageHTMLBytes, _ := ioutil.ReadFile("../../samples/unversioned/eventList.html")
+	loggedOut, falsePositive := detectLoggedOut(http.MethodGet, getPageName(eventList), eventList, pageHTMLBytes)
+	assert.False(t, loggedOut)
+	assert.False(t, falsePositive)
+
+	pageHTMLBytes, _ = ioutil.ReadFile("../../samples/unversioned/eventlist_loggedout.html")
+	loggedOut, falsePositive = detectLoggedOut(http.MethodGet, getPageName(eventList), eventList, pageHTMLBytes)
+	assert.True(t, loggedOut)
+	assert.False(t, falsePositive)
+
+	// Another payload served in place of the event list is unexpected, but we are still logged in
+	pageHTMLBytes, _ = ioutil.ReadFile("../../samples/v7/fetchResources.html")
+	loggedOut, falsePositive = detectLoggedOut(http.MethodGet, getPageName(eventList), eventList, pageHTMLBytes)
+	assert.False(t, loggedOut)
+	assert.True(t, falsePositive)
+
+	// Mobile pages have no session meta, only the session variable
+	pageHTMLBytes, _ = ioutil.ReadFile("../../samples/v7/overview_mobile.html")
+	loggedOut, _ = detectLoggedOut(http.MethodGet, getPageName(overview), overview, pageHTMLBytes)
+	assert.False(t, loggedOut)
+
+	// The redirect of a post has no body
+	loggedOut, falsePositive = detectLoggedOut(http.MethodPost, getPageName(galaxy), galaxy, []byte{})
+	assert.True(t, loggedOut)
+	assert.False(t, falsePositive)
+}
+
+func TestFindColonizationSlots_errors(t *testing.T) {
+	b := &OGame{serverData: ServerData{Systems: 499}}
+	b.server.Settings.UniverseSize = 5
+
+	slots, err := b.findColonizationSlots(1, 100, 150, nil)
+	assert.ErrorIs(t, err, ogame.ErrSystemRangeTooLarge)
+	assert.Nil(t, slots)
+	slots, err = b.findColonizationSlots(1, 150, 100, nil)
+	assert.ErrorIs(t, err, ogame.ErrSystemRangeTooLarge)
+	assert.Nil(t, slots)
+
+	_, err = b.findColonizationSlots(1, 100, 110, []int64{16})
+	assert.EqualError(t, err, "position must be within [1, 15]")
+
+	// No partial result when a system cannot be scanned
+	slots, err = b.findColonizationSlots(6, 100, 110, nil)
+	assert.EqualError(t, err, "galaxy must be within [1, 5]")
+	assert.Nil(t, slots)
+}
+
+func TestGetFleet_movementPageError(t *testing.T) {
+	b := &OGame{}
+	_, err := b.getFleet(ogame.FleetID(1))
+	assert.ErrorIs(t, err, ogame.ErrBotInactive)
+	_, err = b.getACSUnionDetails(1)
+	assert.ErrorIs(t, err, ogame.ErrBotInactive)
+}
+
+func TestQueuedBuildToCancel(t *testing.T) {
+	pageHTMLBytes, _ := ioutil.ReadFile("../../samples/unversioned/overview_queue_buildings.html")
+	queue := v6.NewExtractor().ExtractBuildQueue(pageHTMLBytes)
+
+	// Solar Plant 4 is in progress, Metal Mine 4 and 5 are waiting
+	entry, err := queuedBuildToCancel(queue, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.MetalMineID, entry.ID)
+	assert.Equal(t, int64(637337), entry.ListID)
+	entry, err = queuedBuildToCancel(queue, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, ogame.MetalMineID, entry.ID)
+	assert.Equal(t, int64(637338), entry.ListID)
+
+	for _, index := range []int64{-1, 0, 3} {
+		_, err = queuedBuildToCancel(queue, index)
+		assert.Equal(t, ogame.ErrInvalidQueueIndex, err, index)
+	}
+}
+
+func TestGetCachedResearchOrFetch(t *testing.T) {
+	researchesHTML, _ := ioutil.ReadFile("../../samples/v7.1/en/research_notEnoughDMToHalve.html")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(researchesHTML)
+	}))
+	defer srv.Close()
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clockwork.NewFakeClock(), quiet: true,
+		serverURL: srv.URL, extractor: v7.NewExtractor()}
+	b.isEnabledAtom = 1
+	b.isLoggedInAtom = 1
+
+	// Research cache is empty, the research page is fetched
+	researches, err := b.getCachedResearchOrFetch()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), researches.IntergalacticResearchNetwork)
+	assert.NotNil(t, b.researches)
+
+	b.researches = &ogame.Researches{IntergalacticResearchNetwork: 1}
+	researches, err = b.getCachedResearchOrFetch()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), researches.IntergalacticResearchNetwork)
+}
+
+func TestGetEffectiveLabLevel(t *testing.T) {
+	// Labs of the planets 1, 2 and 3
+	empireHTML := []byte(`createImperiumHtml("#mainWrapper", "#loading", {"planets":[` +
+		`{"id":1,"type":1,"diameter":"12,800km","31":3},{"id":2,"type":1,"diameter":"12,800km","31":12},` +
+		`{"id":3,"type":1,"diameter":"12,800km","31":10}]}, 0 );`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(empireHTML)
+	}))
+	defer srv.Close()
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clockwork.NewFakeClock(), quiet: true,
+		serverURL: srv.URL, extractor: v7.NewExtractor()}
+	b.isEnabledAtom = 1
+	b.isLoggedInAtom = 1
+	b.hasCommander = true
+
+	b.researches = &ogame.Researches{IntergalacticResearchNetwork: 17}
+	level, err := b.getEffectiveLabLevel(ogame.PlanetID(1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), level)
+
+	// The researching lab is counted even if it is the lowest one
+	b.researches = &ogame.Researches{IntergalacticResearchNetwork: 1}
+	level, err = b.getEffectiveLabLevel(ogame.PlanetID(1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), level)
+
+	_, err = b.getEffectiveLabLevel(ogame.PlanetID(4))
+	assert.Equal(t, ogame.ErrInvalidPlanetID, err)
+}
+
+// newLoopTestBot returns an enabled and logged in bot with a fake clock, for the tests of the background loops
+func newLoopTestBot() (*OGame, clockwork.FakeClock) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock}
+	b.enable()
+	b.isLoggedInAtom = 1
+	return b, clock
+}
+
+// assertRunsEveryMinute checks that a background loop started with a 60 seconds interval runs once when started,
+// then after every interval, and that it stops when the bot is disabled and restarts when it is enabled again.
+func assertRunsEveryMinute(t *testing.T, b *OGame, clock clockwork.FakeClock, lastRun func() time.Time) {
+	t.Helper()
+	waitRun := func() {
+		for i := 0; i < 100 && !lastRun().Equal(clock.Now()); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, clock.Now(), lastRun())
+	}
+	clock.BlockUntil(1)
+	assert.Equal(t, clock.Now(), lastRun())
+	clock.Advance(59 * time.Second)
+	assert.Equal(t, clock.Now().Add(-59*time.Second), lastRun())
+	clock.Advance(time.Second)
+	waitRun()
+
+	clock.BlockUntil(1)
+	b.disable()
+	clock.Advance(time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, clock.Now().Add(-time.Minute), lastRun())
+	b.enable()
+	waitRun()
+}
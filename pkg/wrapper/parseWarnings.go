@@ -0,0 +1,21 @@
+package wrapper
+
+import (
+	"sync/atomic"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// reportParseWarnings logs and counts the warnings of a page, and hands them to the CollectWarnings option if any
+func (b *OGame) reportParseWarnings(warnings []ogame.ParseWarning, options ...Option) {
+	if len(warnings) == 0 {
+		return
+	}
+	atomic.AddInt64(&b.parseWarningsAtom, int64(len(warnings)))
+	for _, w := range warnings {
+		b.warn("parse warning on", w.Page, "page:", w.Field, w.Message)
+	}
+	if cfg := getOptions(options...); cfg.Warnings != nil {
+		*cfg.Warnings = append(*cfg.Warnings, warnings...)
+	}
+}
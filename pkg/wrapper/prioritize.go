@@ -7,15 +7,18 @@ import (
 	"time"
 
 	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
 )
 
 // Prioritize ...
 type Prioritize struct {
 	bot          *OGame
 	initiator    string
+	slotToken    string
 	name         string
 	taskIsDoneCh chan struct{}
 	isTx         int32
+	priority     taskRunner.Priority
 }
 
 func (b *Prioritize) SetTaskDoneCh(ch chan struct{}) {
@@ -28,6 +31,12 @@ func (b *Prioritize) SetInitiator(initiator string) Prioritizable {
 	return b
 }
 
+// SetSlotReservation sets the token of the slots reservation (see ReserveSlots) the fleets sent by the task can use
+func (b *Prioritize) SetSlotReservation(token string) Prioritizable {
+	b.slotToken = token
+	return b
+}
+
 // Begin a new transaction. "Done" must be called to release the lock.
 func (b *Prioritize) Begin() Prioritizable {
 	return b.BeginNamed("Tx")
@@ -53,6 +62,7 @@ func (b *Prioritize) begin(name string) *Prioritize {
 		}
 		b.name += name
 		b.bot.botLock(b.name)
+		atomic.StoreInt64(&b.bot.taskPriorityAtom, int64(b.priority))
 	}
 	return b
 }
@@ -60,6 +70,7 @@ func (b *Prioritize) begin(name string) *Prioritize {
 func (b *Prioritize) done() {
 	if atomic.AddInt32(&b.isTx, -1) == 0 {
 		defer close(b.taskIsDoneCh)
+		atomic.StoreInt64(&b.bot.taskPriorityAtom, 0)
 		b.bot.botUnlock(b.name)
 	}
 }
@@ -125,6 +136,20 @@ func (b *Prioritize) IsUnderAttack() (bool, error) {
 	return b.bot.isUnderAttack()
 }
 
+// GetSpyReportSettings returns the espionage preferences of the account
+func (b *Prioritize) GetSpyReportSettings() (ogame.SpyReportSettings, error) {
+	b.begin("GetSpyReportSettings")
+	defer b.done()
+	return b.bot.getSpyReportSettings()
+}
+
+// SetSpyReportSettings changes the espionage preferences of the account, the other preferences are kept
+func (b *Prioritize) SetSpyReportSettings(settings ogame.SpyReportSettings) error {
+	b.begin("SetSpyReportSettings")
+	defer b.done()
+	return b.bot.setSpyReportSettings(settings)
+}
+
 // SetVacationMode puts account in vacation mode
 func (b *Prioritize) SetVacationMode() error {
 	b.begin("SetVacationMode")
@@ -154,6 +179,20 @@ func (b *Prioritize) GetMoons() []Moon {
 	return b.bot.getMoons()
 }
 
+// GetEffectiveSpeeds gets the speeds currently applied by the universe, including the temporary modifiers (events)
+func (b *Prioritize) GetEffectiveSpeeds() (ogame.EffectiveSpeeds, error) {
+	b.begin("GetEffectiveSpeeds")
+	defer b.done()
+	return b.bot.getEffectiveSpeeds()
+}
+
+// GetNotifications gets the active notifications of the top bar (unread messages, incoming attack, wreck field)
+func (b *Prioritize) GetNotifications() ([]ogame.Notification, error) {
+	b.begin("GetNotifications")
+	defer b.done()
+	return b.bot.getNotifications()
+}
+
 // GetMoon gets infos for moonID
 func (b *Prioritize) GetMoon(v any) (Moon, error) {
 	b.begin("GetMoon")
@@ -191,6 +230,13 @@ func (b *Prioritize) GetCelestial(v any) (Celestial, error) {
 	return b.bot.getCelestial(v)
 }
 
+// GetCelestialsByCoord get all the player's planets/moons at the coordinate
+func (b *Prioritize) GetCelestialsByCoord(coord ogame.Coordinate) ([]Celestial, error) {
+	b.begin("GetCelestialsByCoord")
+	defer b.done()
+	return b.bot.getCelestialsByCoord(coord)
+}
+
 // ServerTime returns server time
 // Timezone is OGT (OGame Time zone)
 func (b *Prioritize) ServerTime() time.Time {
@@ -227,6 +273,13 @@ func (b *Prioritize) GetFleets(opts ...Option) ([]ogame.Fleet, ogame.Slots) {
 	return b.bot.getFleets(opts...)
 }
 
+// GetFleet get one of the player's own fleets in flight, by id
+func (b *Prioritize) GetFleet(fleetID ogame.FleetID) (ogame.Fleet, error) {
+	b.begin("GetFleet")
+	defer b.done()
+	return b.bot.getFleet(fleetID)
+}
+
 // GetFleetsFromEventList get the player's own fleets activities
 func (b *Prioritize) GetFleetsFromEventList() []ogame.Fleet {
 	b.begin("GetFleets")
@@ -248,6 +301,44 @@ func (b *Prioritize) GetAttacks(opts ...Option) ([]ogame.AttackEvent, error) {
 	return b.bot.getAttacks(opts...)
 }
 
+// GetEventList get every fleet of the event list, hostile, own and neutral
+func (b *Prioritize) GetEventList(opts ...Option) ([]ogame.Event, error) {
+	b.begin("GetEventList")
+	defer b.done()
+	return b.bot.getEventList(opts...)
+}
+
+// GetEffectiveLabLevel get the summed lab level used for research times when researching on planetID.
+// Uses the empire page with a commander, otherwise visits the facilities of every planet.
+func (b *Prioritize) GetEffectiveLabLevel(planetID ogame.PlanetID) (int64, error) {
+	b.begin("GetEffectiveLabLevel")
+	defer b.done()
+	return b.bot.getEffectiveLabLevel(planetID)
+}
+
+// GetFriendlyArrivals get the incoming fleets of other players that are not hostile (alliance transports, deployments...).
+// A friendly arrival event is published the first time an arrival is seen.
+func (b *Prioritize) GetFriendlyArrivals() ([]ogame.Event, error) {
+	b.begin("GetFriendlyArrivals")
+	defer b.done()
+	return b.bot.getFriendlyArrivals()
+}
+
+// GetSpiedEvents get the espionage actions of other players on our celestials since the given time.
+// A spied event is published the first time an espionage action is seen.
+func (b *Prioritize) GetSpiedEvents(since time.Time) ([]ogame.SpiedEvent, error) {
+	b.begin("GetSpiedEvents")
+	defer b.done()
+	return b.bot.getSpiedEvents(since)
+}
+
+// FindColonizationSlots scans a range of systems (50 at most) for empty positions, sorted by expected planet size
+func (b *Prioritize) FindColonizationSlots(galaxy, fromSystem, toSystem int64, preferredPositions []int64) ([]ogame.Coordinate, error) {
+	b.begin("FindColonizationSlots")
+	defer b.done()
+	return b.bot.findColonizationSlots(galaxy, fromSystem, toSystem, preferredPositions)
+}
+
 // GalaxyInfos get information of all planets and moons of a solar system
 func (b *Prioritize) GalaxyInfos(galaxy, system int64, options ...Option) (ogame.SystemInfos, error) {
 	b.begin("GalaxyInfos")
@@ -298,6 +389,14 @@ func (b *Prioritize) GetFacilities(celestialID ogame.CelestialID, options ...Opt
 	return b.bot.getFacilities(celestialID, options...)
 }
 
+// GetMissiles gets the interplanetary and anti-ballistic missiles of a planet, and the number of slots of its
+// missile silo (an anti-ballistic missile takes one slot, an interplanetary missile takes two)
+func (b *Prioritize) GetMissiles(celestialID ogame.CelestialID) (ipm, abm, siloCapacity int64, err error) {
+	b.begin("GetMissiles")
+	defer b.done()
+	return b.bot.getMissiles(celestialID)
+}
+
 // GetProduction get what is in the production queue.
 // (ships & defense being built)
 func (b *Prioritize) GetProduction(celestialID ogame.CelestialID) ([]ogame.Quantifiable, int64, error) {
@@ -341,6 +440,44 @@ func (b *Prioritize) TechnologyDetails(celestialID ogame.CelestialID, id ogame.I
 	return b.bot.technologyDetails(celestialID, id)
 }
 
+// ShipBuildTime returns how long the shipyard of a celestial takes to build count ships (or defenses),
+// given its shipyard/nanite levels and the class/officer bonuses
+func (b *Prioritize) ShipBuildTime(celestialID ogame.CelestialID, shipID ogame.ID, count int64) (time.Duration, error) {
+	b.begin("ShipBuildTime")
+	defer b.done()
+	return b.bot.shipBuildTime(celestialID, shipID, count)
+}
+
+// ValidateFleetTarget returns the restrictions the game would apply to a mission from a celestial towards a target,
+// without sending anything. An empty list does not guarantee the fleet can be sent (ships, fuel, slots are not checked).
+func (b *Prioritize) ValidateFleetTarget(celestialID ogame.CelestialID, where ogame.Coordinate, mission ogame.MissionID) ([]ogame.FleetRestriction, error) {
+	b.begin("ValidateFleetTarget")
+	defer b.done()
+	return b.bot.validateFleetTarget(celestialID, where, mission)
+}
+
+// GetAllowedSpeeds returns the fleet speeds the fleet dispatch page of a celestial offers
+func (b *Prioritize) GetAllowedSpeeds(celestialID ogame.CelestialID) ([]ogame.Speed, error) {
+	b.begin("GetAllowedSpeeds")
+	defer b.done()
+	return b.bot.getAllowedSpeeds(celestialID)
+}
+
+// GetTemperatureEffects returns the energy one solar satellite produces on a planet, and the factor its temperature
+// applies to the deuterium synthesizer production
+func (b *Prioritize) GetTemperatureEffects(celestialID ogame.CelestialID) (satelliteEnergyPerUnit int64, deutProductionFactor float64, err error) {
+	b.begin("GetTemperatureEffects")
+	defer b.done()
+	return b.bot.getTemperatureEffects(celestialID)
+}
+
+// MineUpgradeROI returns how long the extra production of the next level of a mine takes to pay back its cost
+func (b *Prioritize) MineUpgradeROI(celestialID ogame.CelestialID, mineID ogame.ID) (time.Duration, error) {
+	b.begin("MineUpgradeROI")
+	defer b.done()
+	return b.bot.mineUpgradeROI(celestialID, mineID)
+}
+
 // TearDown tears down any ogame building
 func (b *Prioritize) TearDown(celestialID ogame.CelestialID, id ogame.ID) error {
 	b.begin("TearDown")
@@ -383,6 +520,13 @@ func (b *Prioritize) BuildShips(celestialID ogame.CelestialID, shipID ogame.ID,
 	return b.bot.buildShips(celestialID, shipID, nbr)
 }
 
+// GetBuildQueue returns the building being built followed by the buildings queued behind it
+func (b *Prioritize) GetBuildQueue(celestialID ogame.CelestialID) ([]ogame.QueuedBuild, error) {
+	b.begin("GetBuildQueue")
+	defer b.done()
+	return b.bot.getBuildQueue(celestialID)
+}
+
 // ConstructionsBeingBuilt returns the building & research being built, and the time remaining (secs)
 func (b *Prioritize) ConstructionsBeingBuilt(celestialID ogame.CelestialID) (ogame.ID, int64, ogame.ID, int64, ogame.ID, int64, ogame.ID, int64) {
 	b.begin("ConstructionsBeingBuilt")
@@ -397,6 +541,14 @@ func (b *Prioritize) CancelBuilding(celestialID ogame.CelestialID) error {
 	return b.bot.cancelBuilding(celestialID)
 }
 
+// CancelQueuedBuild removes a building waiting in the building queue, index being its position in GetBuildQueue.
+// The building in progress (index 0) is cancelled with CancelBuilding.
+func (b *Prioritize) CancelQueuedBuild(celestialID ogame.CelestialID, index int64) error {
+	b.begin("CancelQueuedBuild")
+	defer b.done()
+	return b.bot.cancelQueuedBuild(celestialID, index)
+}
+
 // CancelLfBuilding cancel the construction of a lifeform building on a specified planet
 func (b *Prioritize) CancelLfBuilding(celestialID ogame.CelestialID) error {
 	b.begin("CancelLfBuilding")
@@ -432,6 +584,29 @@ func (b *Prioritize) GetResourcesDetails(celestialID ogame.CelestialID) (ogame.R
 	return b.bot.getResourcesDetails(celestialID)
 }
 
+// GetWastedResources returns the resources lost to full storages since the previous call for this celestial.
+// The first call only records a sample and returns nothing.
+func (b *Prioritize) GetWastedResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
+	b.begin("GetWastedResources")
+	defer b.done()
+	return b.bot.getWastedResources(celestialID)
+}
+
+// PlanToMatch returns the upgrades, total price and total time needed to bring
+// the resource buildings of a planet up to the target levels
+func (b *Prioritize) PlanToMatch(celestialID ogame.CelestialID, target ogame.ResourcesBuildings) ([]ogame.BuildStep, ogame.Resources, time.Duration, error) {
+	b.begin("PlanToMatch")
+	defer b.done()
+	return b.bot.planToMatch(celestialID, target)
+}
+
+// GetDarkMatter gets the current dark matter balance of the account
+func (b *Prioritize) GetDarkMatter() (int64, error) {
+	b.begin("GetDarkMatter")
+	defer b.done()
+	return b.bot.getDarkMatter()
+}
+
 // GetTechs gets a celestial supplies/facilities/ships/researches
 func (b *Prioritize) GetTechs(celestialID ogame.CelestialID) (ogame.ResourcesBuildings, ogame.Facilities, ogame.ShipsInfos, ogame.DefensesInfos, ogame.Researches, ogame.LfBuildings, error) {
 	b.begin("GetTechs")
@@ -444,7 +619,27 @@ func (b *Prioritize) SendFleet(celestialID ogame.CelestialID, ships []ogame.Quan
 	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
 	b.begin("SendFleet")
 	defer b.done()
-	return b.bot.sendFleet(celestialID, ships, speed, where, mission, resources, holdingTime, unionID, false)
+	fleet, _, err := b.bot.sendFleet(celestialID, ships, speed, where, mission, resources, holdingTime, unionID, false, b.slotToken, nil)
+	return fleet, err
+}
+
+// SendFleetWithPayload sends a fleet, the payload is resolved at dispatch time against the resources of the origin.
+// Returns the resources loaded.
+func (b *Prioritize) SendFleetWithPayload(celestialID ogame.CelestialID, ships []ogame.Quantifiable, speed ogame.Speed, where ogame.Coordinate,
+	mission ogame.MissionID, spec ogame.PayloadSpec, holdingTime, unionID int64) (ogame.Fleet, ogame.Resources, error) {
+	b.begin("SendFleetWithPayload")
+	defer b.done()
+	return b.bot.sendFleet(celestialID, ships, speed, where, mission, ogame.Resources{}, holdingTime, unionID, false, b.slotToken, &spec)
+}
+
+// SendFleetAndRecall sends a fleet, and schedules its recall holdSeconds after departure (fake attacks, deploy bounces...).
+// Returns the fleet, and the id of the recall job that can be cancelled with CancelRecallJob.
+// The recall runs with the Important priority.
+func (b *Prioritize) SendFleetAndRecall(celestialID ogame.CelestialID, ships []ogame.Quantifiable, where ogame.Coordinate,
+	mission ogame.MissionID, holdSeconds int64) (ogame.Fleet, int64, error) {
+	b.begin("SendFleetAndRecall")
+	defer b.done()
+	return b.bot.sendFleetAndRecall(celestialID, ships, where, mission, holdSeconds, b.slotToken)
 }
 
 // EnsureFleet either sends all the requested ships or fail
@@ -452,7 +647,8 @@ func (b *Prioritize) EnsureFleet(celestialID ogame.CelestialID, ships []ogame.Qu
 	mission ogame.MissionID, resources ogame.Resources, holdingTime, unionID int64) (ogame.Fleet, error) {
 	b.begin("EnsureFleet")
 	defer b.done()
-	return b.bot.sendFleet(celestialID, ships, speed, where, mission, resources, holdingTime, unionID, true)
+	fleet, _, err := b.bot.sendFleet(celestialID, ships, speed, where, mission, resources, holdingTime, unionID, true, b.slotToken, nil)
+	return fleet, err
 }
 
 // DestroyRockets destroys anti-ballistic & inter-planetary missiles
@@ -462,6 +658,14 @@ func (b *Prioritize) DestroyRockets(planetID ogame.PlanetID, abm, ipm int64) err
 	return b.bot.destroyRockets(planetID, abm, ipm)
 }
 
+// QuickSpy sends espionage probes using the galaxy view quick action, from the celestial currently selected in the game.
+// nbProbes <= 0 uses the probes count of the preferences. The returned fleet has no ID.
+func (b *Prioritize) QuickSpy(target ogame.Coordinate, nbProbes int64) (ogame.Fleet, error) {
+	b.begin("QuickSpy")
+	defer b.done()
+	return b.bot.quickSpy(target, nbProbes, b.slotToken)
+}
+
 // SendIPM sends IPM
 func (b *Prioritize) SendIPM(planetID ogame.PlanetID, coord ogame.Coordinate, nbr int64, priority ogame.ID) (int64, error) {
 	b.begin("SendIPM")
@@ -483,6 +687,13 @@ func (b *Prioritize) GetEspionageReportFor(coord ogame.Coordinate) (ogame.Espion
 	return b.bot.getEspionageReportFor(coord)
 }
 
+// GetEspionageReportDiff returns what changed at a target between its two most recent espionage reports
+func (b *Prioritize) GetEspionageReportDiff(coord ogame.Coordinate) (ogame.EspionageReportDiff, error) {
+	b.begin("GetEspionageReportDiff")
+	defer b.done()
+	return b.bot.getEspionageReportDiff(coord)
+}
+
 // GetEspionageReportMessages gets the summary of each espionage reports
 func (b *Prioritize) GetEspionageReportMessages() ([]ogame.EspionageReportSummary, error) {
 	b.begin("GetEspionageReportMessages")
@@ -490,6 +701,13 @@ func (b *Prioritize) GetEspionageReportMessages() ([]ogame.EspionageReportSummar
 	return b.bot.getEspionageReportMessages()
 }
 
+// GetMessageSummaries gets the summary of each message of a tab (espionage, combat reports or expeditions)
+func (b *Prioritize) GetMessageSummaries(tabID ogame.MessagesTabID) ([]ogame.MessageSummary, error) {
+	b.begin("GetMessageSummaries")
+	defer b.done()
+	return b.bot.getMessageSummaries(tabID)
+}
+
 // CollectAllMarketplaceMessages collect all marketplace messages
 func (b *Prioritize) CollectAllMarketplaceMessages() error {
 	b.begin("CollectAllMarketplaceMessages")
@@ -540,6 +758,13 @@ func (b *Prioritize) DeleteAllMessagesFromTab(tabID ogame.MessagesTabID) error {
 	return b.bot.deleteAllMessagesFromTab(tabID)
 }
 
+// DeleteEspionageReportsOlderThan deletes the espionage reports older than d, and returns how many were deleted
+func (b *Prioritize) DeleteEspionageReportsOlderThan(d time.Duration) (int64, error) {
+	b.begin("DeleteEspionageReportsOlderThan")
+	defer b.done()
+	return b.bot.deleteEspionageReportsOlderThan(d)
+}
+
 // GetResourcesProductions gets the planet resources production
 func (b *Prioritize) GetResourcesProductions(planetID ogame.PlanetID) (ogame.Resources, error) {
 	b.begin("GetResourcesProductions")
@@ -589,6 +814,13 @@ func (b *Prioritize) JumpGate(origin, dest ogame.MoonID, ships ogame.ShipsInfos)
 	return b.bot.executeJumpGate(origin, dest, ships)
 }
 
+// GetMoonFacilities gets the moon facilities, the phalanx range and the jump gate recharge countdown
+func (b *Prioritize) GetMoonFacilities(moonID ogame.MoonID) (ogame.MoonFacilities, error) {
+	b.begin("GetMoonFacilities")
+	defer b.done()
+	return b.bot.getMoonFacilities(moonID)
+}
+
 // JumpGateDestinations returns available destinations for jump gate.
 func (b *Prioritize) JumpGateDestinations(origin ogame.MoonID) ([]ogame.MoonID, int64, error) {
 	b.begin("JumpGateDestinations")
@@ -610,6 +842,21 @@ func (b *Prioritize) CreateUnion(fleet ogame.Fleet, users []string) (int64, erro
 	return b.bot.createUnion(fleet, users)
 }
 
+// GetACSUnionDetails gets the members of an ACS union we take part in, and their fleets
+func (b *Prioritize) GetACSUnionDetails(unionID int64) (ogame.ACSUnionDetails, error) {
+	b.begin("GetACSUnionDetails")
+	defer b.done()
+	return b.bot.getACSUnionDetails(unionID)
+}
+
+// AttacksRemainingAgainst returns how many attacks can still be sent to a target without crossing the bashing limit,
+// and when the oldest attack counted expires
+func (b *Prioritize) AttacksRemainingAgainst(target ogame.Coordinate) (int64, time.Time, error) {
+	b.begin("AttacksRemainingAgainst")
+	defer b.done()
+	return b.bot.attacksRemainingAgainst(target)
+}
+
 // HeadersForPage gets the headers for a specific ogame page
 func (b *Prioritize) HeadersForPage(url string) (http.Header, error) {
 	b.begin("HeadersForPage")
@@ -652,6 +899,13 @@ func (b *Prioritize) Highscore(category, typ, page int64) (ogame.Highscore, erro
 	return b.bot.highscore(category, typ, page)
 }
 
+// GetMyPoints gets the points of the bot player in each highscore type
+func (b *Prioritize) GetMyPoints() (ogame.Points, error) {
+	b.begin("GetMyPoints")
+	defer b.done()
+	return b.bot.getMyPoints()
+}
+
 // GetAllResources ...
 func (b *Prioritize) GetAllResources() (map[ogame.CelestialID]ogame.Resources, error) {
 	b.begin("GetAllResources")
@@ -0,0 +1,169 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/alaingilbert/ogame/pkg/utils"
+)
+
+// Retry of a recall that could not be sent because the bot was logged out, disabled or the server in maintenance
+const (
+	recallRetryInterval = 5 * time.Second
+	recallMaxDelay      = 5 * time.Minute // Used as deadline when the arrival time of the fleet is unknown
+)
+
+// RecallJob scheduled recall of a fleet
+type RecallJob struct {
+	ID        int64
+	FleetID   ogame.FleetID
+	RecallAt  time.Time // Server time at which the fleet is recalled
+	ExpiresAt time.Time // Server time after which the recall is given up (arrival of the fleet)
+	cancel    context.CancelFunc
+}
+
+// SendFleetAndRecall sends a fleet, and schedules its recall holdSeconds after departure (fake attacks, deploy bounces...).
+// Returns the fleet, and the id of the recall job that can be cancelled with CancelRecallJob.
+// The recall runs with the Important priority.
+// ErrRecallAfterArrival is returned, and nothing is sent, if the fleet would arrive before holdSeconds.
+// If the fleet sent arrives sooner than estimated, it is recalled right away and ErrRecallAfterArrival is returned.
+func (b *OGame) SendFleetAndRecall(celestialID ogame.CelestialID, ships []ogame.Quantifiable, where ogame.Coordinate,
+	mission ogame.MissionID, holdSeconds int64) (ogame.Fleet, int64, error) {
+	return b.WithPriority(taskRunner.Normal).SendFleetAndRecall(celestialID, ships, where, mission, holdSeconds)
+}
+
+func (b *OGame) sendFleetAndRecall(celestialID ogame.CelestialID, ships []ogame.Quantifiable, where ogame.Coordinate,
+	mission ogame.MissionID, holdSeconds int64, slotToken string) (ogame.Fleet, int64, error) {
+	if holdSeconds <= 0 {
+		return ogame.Fleet{}, 0, errors.New("invalid hold duration")
+	}
+	flightSecs, err := b.estimateFlightSecs(celestialID, ships, where, mission)
+	if err != nil {
+		return ogame.Fleet{}, 0, err
+	}
+	if flightSecs > 0 && holdSeconds >= flightSecs {
+		return ogame.Fleet{}, 0, ogame.ErrRecallAfterArrival
+	}
+	fleet, _, err := b.sendFleet(celestialID, ships, ogame.HundredPercent, where, mission, ogame.Resources{}, 0, 0, false, slotToken, nil)
+	if err != nil {
+		return fleet, 0, err
+	}
+	if fleet.ArriveIn > 0 && holdSeconds >= fleet.ArriveIn {
+		if err := b.cancelFleet(fleet.ID); err != nil {
+			return fleet, 0, err
+		}
+		return fleet, 0, ogame.ErrRecallAfterArrival
+	}
+	recallAt := b.serverNow().Add(time.Duration(holdSeconds) * time.Second)
+	expiresAt := fleet.ArrivalTime
+	if expiresAt.IsZero() {
+		expiresAt = recallAt.Add(recallMaxDelay)
+	}
+	jobID := b.scheduleRecall(fleet.ID, recallAt, expiresAt)
+	return fleet, jobID, nil
+}
+
+// estimateFlightSecs estimates the flight duration at full speed of the ships sent from a celestial,
+// limited to the ships available on its fleetdispatch page
+func (b *OGame) estimateFlightSecs(celestialID ogame.CelestialID, ships []ogame.Quantifiable, where ogame.Coordinate, mission ogame.MissionID) (int64, error) {
+	pageHTML, err := b.getPage(FleetdispatchPageName, ChangePlanet(celestialID))
+	if err != nil {
+		return 0, err
+	}
+	origin, err := b.extractor.ExtractPlanetCoordinate(pageHTML)
+	if err != nil {
+		return 0, err
+	}
+	availableShips := b.extractor.ExtractFleet1Ships(pageHTML)
+	var sent ogame.ShipsInfos
+	for _, s := range ships {
+		sent.Set(s.ID, sent.ByID(s.ID)+utils.MinInt(s.Nbr, availableShips.ByID(s.ID)))
+	}
+	secs, _ := b.calcFlightTime(origin, where, ogame.HundredPercent.Float64()/10, sent, mission)
+	return secs, nil
+}
+
+func (b *OGame) scheduleRecall(fleetID ogame.FleetID, recallAt, expiresAt time.Time) int64 {
+	ctx, cancel := context.WithCancel(context.Background())
+	b.recallJobsMu.Lock()
+	b.recallJobLastID++
+	job := &RecallJob{ID: b.recallJobLastID, FleetID: fleetID, RecallAt: recallAt, ExpiresAt: expiresAt, cancel: cancel}
+	b.recallJobs[job.ID] = job
+	b.recallJobsMu.Unlock()
+	go b.runRecallJob(ctx, job)
+	return job.ID
+}
+
+// isRecallRetryable returns either or not the recall failed because of a state of the bot that does not last (relogin...)
+func isRecallRetryable(err error) bool {
+	return errors.Is(err, ogame.ErrBotLoggedOut) ||
+		errors.Is(err, ogame.ErrBotInactive) ||
+		errors.Is(err, ogame.ErrMaintenance)
+}
+
+// runRecallJob recalls the fleet at the scheduled time.
+// If the bot is logged out, disabled or the server in maintenance, the recall is retried until the bot is back,
+// the job is cancelled or the job expires.
+func (b *OGame) runRecallJob(ctx context.Context, job *RecallJob) {
+	defer b.removeRecallJob(job.ID)
+	for {
+		err := b.WaitUntilServerTime(ctx, job.RecallAt)
+		if err == nil {
+			if err = b.WithPriority(taskRunner.Important).CancelFleet(job.FleetID); err == nil {
+				b.info("recall job", job.ID, "recalled fleet", job.FleetID)
+				return
+			}
+		}
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if !isRecallRetryable(err) {
+			b.error("recall job", job.ID, "failed to recall fleet", job.FleetID, err)
+			return
+		}
+		if !b.serverNow().Before(job.ExpiresAt) {
+			b.error("recall job", job.ID, "expired before fleet", job.FleetID, "could be recalled", err)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(recallRetryInterval):
+		}
+	}
+}
+
+func (b *OGame) removeRecallJob(jobID int64) {
+	b.recallJobsMu.Lock()
+	defer b.recallJobsMu.Unlock()
+	delete(b.recallJobs, jobID)
+}
+
+// CancelRecallJob cancels a scheduled recall, the fleet keeps flying
+func (b *OGame) CancelRecallJob(jobID int64) error {
+	b.recallJobsMu.Lock()
+	defer b.recallJobsMu.Unlock()
+	job, ok := b.recallJobs[jobID]
+	if !ok {
+		return ogame.ErrRecallJobNotFound
+	}
+	job.cancel()
+	delete(b.recallJobs, jobID)
+	return nil
+}
+
+// GetRecallJobs returns the scheduled recalls
+func (b *OGame) GetRecallJobs() []RecallJob {
+	b.recallJobsMu.Lock()
+	defer b.recallJobsMu.Unlock()
+	jobs := make([]RecallJob, 0, len(b.recallJobs))
+	for _, job := range b.recallJobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
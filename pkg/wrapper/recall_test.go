@@ -0,0 +1,70 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitRecallJobs(b *OGame, n int) {
+	for i := 0; i < 100 && len(b.GetRecallJobs()) != n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRecallJob_retriesWhileLoggedOut(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock, quiet: true, recallJobs: make(map[int64]*RecallJob)}
+	b.isEnabledAtom = 1
+	now := clock.Now()
+	jobID := b.scheduleRecall(ogame.FleetID(1), now.Add(time.Minute), now.Add(2*time.Minute))
+
+	// The bot is logged out (relogin in progress) when the recall is due, the job is kept and retried
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	clock.Advance(recallRetryInterval)
+	clock.BlockUntil(1)
+	assert.Len(t, b.GetRecallJobs(), 1)
+
+	// The job is given up once the fleet arrived
+	clock.Advance(time.Minute)
+	waitRecallJobs(b, 0)
+	assert.Len(t, b.GetRecallJobs(), 0)
+	assert.Equal(t, ogame.ErrRecallJobNotFound, b.CancelRecallJob(jobID))
+}
+
+func TestRecallJob_cancelWhileRetrying(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{clock: clock, quiet: true, recallJobs: make(map[int64]*RecallJob)}
+	now := clock.Now()
+	jobID := b.scheduleRecall(ogame.FleetID(1), now.Add(time.Minute), now.Add(2*time.Minute))
+
+	// The bot is disabled, the job waits for it to be enabled again
+	clock.BlockUntil(1)
+	assert.Len(t, b.GetRecallJobs(), 1)
+	assert.NoError(t, b.CancelRecallJob(jobID))
+	waitRecallJobs(b, 0)
+	assert.Len(t, b.GetRecallJobs(), 0)
+}
+
+func TestSendFleetAndRecall_refusesHoldAfterArrival(t *testing.T) {
+	b := newFleetdispatchTestBot(t)
+	b.serverData = ServerData{Galaxies: 9, Systems: 499, SpeedFleetWar: 1, SpeedFleetPeaceful: 1, GlobalDeuteriumSaveFactor: 1}
+	b.researches = &ogame.Researches{}
+	b.recallJobs = make(map[int64]*RecallJob)
+	ships := []ogame.Quantifiable{{ID: ogame.SmallCargoID, Nbr: 6}}
+	where := ogame.Coordinate{Galaxy: 9, System: 297, Position: 9, Type: ogame.PlanetType}
+
+	// 6 small cargo fly [9:297:12] -> [9:297:9] in 3536 seconds, the recall would happen after the arrival
+	secs, err := b.estimateFlightSecs(ogame.CelestialID(33795776), ships, where, ogame.Attack)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3536), secs)
+	_, jobID, err := b.sendFleetAndRecall(ogame.CelestialID(33795776), ships, where, ogame.Attack, secs, "")
+	assert.Equal(t, ogame.ErrRecallAfterArrival, err)
+	assert.Equal(t, int64(0), jobID)
+	assert.Len(t, b.GetRecallJobs(), 0)
+}
@@ -0,0 +1,144 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// resourceProfilePause pause between two planets when a profile is applied to every planet
+const resourceProfilePause = 2 * time.Second
+
+// ResourceProfileChange resource settings of a planet before and after a profile was applied
+type ResourceProfileChange struct {
+	PlanetID  ogame.PlanetID
+	Profile   string
+	Before    ogame.ResourceSettings
+	After     ogame.ResourceSettings
+	AppliedAt time.Time
+	Error     string `json:",omitempty"` // Set when applying the profile to every planet failed on this one
+}
+
+// SetResourceProfile stores a named resource settings profile, replacing any profile with the same name.
+// Profiles live in memory, they are lost when the bot is restarted.
+func (b *OGame) SetResourceProfile(name string, settings ogame.ResourceSettings) error {
+	if name == "" {
+		return errors.New("profile name is required")
+	}
+	b.resourceProfilesMu.Lock()
+	defer b.resourceProfilesMu.Unlock()
+	if b.resourceProfiles == nil {
+		b.resourceProfiles = make(map[string]ogame.ResourceSettings)
+	}
+	b.resourceProfiles[name] = settings
+	return nil
+}
+
+// GetResourceProfiles returns the stored resource settings profiles
+func (b *OGame) GetResourceProfiles() map[string]ogame.ResourceSettings {
+	b.resourceProfilesMu.Lock()
+	defer b.resourceProfilesMu.Unlock()
+	out := make(map[string]ogame.ResourceSettings, len(b.resourceProfiles))
+	for name, settings := range b.resourceProfiles {
+		out[name] = settings
+	}
+	return out
+}
+
+// ApplyResourceProfile sets the resource settings of a planet to a stored profile.
+// The settings it replaces are kept, so the change can be undone with RevertResourceProfile.
+func (b *OGame) ApplyResourceProfile(planetID ogame.PlanetID, name string) (ResourceProfileChange, error) {
+	b.resourceProfilesMu.Lock()
+	settings, ok := b.resourceProfiles[name]
+	b.resourceProfilesMu.Unlock()
+	if !ok {
+		return ResourceProfileChange{}, ogame.ErrResourceProfileNotFound
+	}
+	return b.changeResourceSettings(planetID, name, settings)
+}
+
+// ApplyResourceProfileAll applies a stored profile to every planet, pausing between two planets.
+// A planet that fails does not stop the others, its error is reported in its change.
+func (b *OGame) ApplyResourceProfileAll(ctx context.Context, name string) ([]ResourceProfileChange, error) {
+	changes := make([]ResourceProfileChange, 0)
+	for i, planet := range b.GetCachedPlanets() {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return changes, ctx.Err()
+			case <-b.clock.After(resourceProfilePause):
+			}
+		}
+		change, err := b.ApplyResourceProfile(planet.ID, name)
+		if err == ogame.ErrResourceProfileNotFound {
+			return changes, err
+		}
+		if err != nil {
+			change = ResourceProfileChange{PlanetID: planet.ID, Profile: name, Error: err.Error()}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// RevertResourceProfile restores the resource settings a planet had before the last profile applied on it
+func (b *OGame) RevertResourceProfile(planetID ogame.PlanetID) (ResourceProfileChange, error) {
+	b.resourceProfilesMu.Lock()
+	last, ok := b.resourceProfileUndo[planetID]
+	b.resourceProfilesMu.Unlock()
+	if !ok {
+		return ResourceProfileChange{}, ogame.ErrNoResourceProfileChange
+	}
+	change, err := b.changeResourceSettings(planetID, "", last.Before)
+	if err != nil {
+		return change, err
+	}
+	b.resourceProfilesMu.Lock()
+	delete(b.resourceProfileUndo, planetID)
+	b.resourceProfilesMu.Unlock()
+	return change, nil
+}
+
+// GetResourceProfileChanges returns the last profile applied on each planet, that can still be reverted
+func (b *OGame) GetResourceProfileChanges() []ResourceProfileChange {
+	b.resourceProfilesMu.Lock()
+	defer b.resourceProfilesMu.Unlock()
+	out := make([]ResourceProfileChange, 0, len(b.resourceProfileUndo))
+	for _, change := range b.resourceProfileUndo {
+		out = append(out, change)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AppliedAt.Before(out[j].AppliedAt) })
+	return out
+}
+
+// changeResourceSettings reads then sets the resource settings of a planet in one transaction.
+// profile is empty for a revert, in which case the change is not recorded.
+func (b *OGame) changeResourceSettings(planetID ogame.PlanetID, profile string, settings ogame.ResourceSettings) (ResourceProfileChange, error) {
+	change := ResourceProfileChange{PlanetID: planetID, Profile: profile, After: settings}
+	err := b.WithPriority(taskRunner.Normal).Tx(func(tx Prioritizable) error {
+		before, err := tx.GetResourceSettings(planetID)
+		if err != nil {
+			return err
+		}
+		change.Before = before
+		return tx.SetResourceSettings(planetID, settings)
+	})
+	if err != nil {
+		return ResourceProfileChange{}, err
+	}
+	change.AppliedAt = b.clock.Now()
+	if profile != "" {
+		b.info("resource settings profile", profile, "applied on", planetID)
+		b.resourceProfilesMu.Lock()
+		if b.resourceProfileUndo == nil {
+			b.resourceProfileUndo = make(map[ogame.PlanetID]ResourceProfileChange)
+		}
+		b.resourceProfileUndo[planetID] = change
+		b.resourceProfilesMu.Unlock()
+	}
+	return change, nil
+}
@@ -0,0 +1,28 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceProfiles(t *testing.T) {
+	b := &OGame{}
+	night := ogame.ResourceSettings{MetalMine: 100, CrystalMine: 100, DeuteriumSynthesizer: 100, SolarPlant: 100, SolarSatellite: 100}
+	assert.Error(t, b.SetResourceProfile("", night))
+	assert.NoError(t, b.SetResourceProfile("night", night))
+	profiles := b.GetResourceProfiles()
+	assert.Equal(t, 1, len(profiles))
+	assert.Equal(t, night, profiles["night"])
+
+	// The returned map is a copy
+	delete(profiles, "night")
+	assert.Equal(t, 1, len(b.GetResourceProfiles()))
+
+	_, err := b.ApplyResourceProfile(123, "day")
+	assert.Equal(t, ogame.ErrResourceProfileNotFound, err)
+	_, err = b.RevertResourceProfile(123)
+	assert.Equal(t, ogame.ErrNoResourceProfileChange, err)
+	assert.Equal(t, 0, len(b.GetResourceProfileChanges()))
+}
@@ -0,0 +1,277 @@
+package wrapper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// SelfTestPriority priority of the tasks used by the extraction self-test
+const SelfTestPriority = taskRunner.Low
+
+// selfTestResultsKept number of self-test runs kept in memory
+const selfTestResultsKept = 30
+
+var selfTestBuildingsIDs = []ogame.ID{ogame.MetalMineID, ogame.CrystalMineID, ogame.DeuteriumSynthesizerID, ogame.SolarPlantID,
+	ogame.FusionReactorID, ogame.MetalStorageID, ogame.CrystalStorageID, ogame.DeuteriumTankID}
+
+// SelfTestCheck outcome of one extraction invariant
+type SelfTestCheck struct {
+	Name    string
+	Page    string
+	Passed  bool
+	Message string `json:",omitempty"`
+}
+
+// SelfTestResult one run of the extraction self-test
+type SelfTestResult struct {
+	StartedAt time.Time
+	Duration  int64 // Milliseconds
+	Passed    bool
+	Checks    []SelfTestCheck
+}
+
+// selfTestSnapshot levels seen by the previous run, levels are not expected to go down from one day to the next
+type selfTestSnapshot struct {
+	buildings map[ogame.CelestialID]ogame.ResourcesBuildings
+	research  *ogame.Researches
+}
+
+func selfTestCheck(name, page string, err error) SelfTestCheck {
+	check := SelfTestCheck{Name: name, Page: page, Passed: err == nil}
+	if err != nil {
+		check.Message = err.Error()
+	}
+	return check
+}
+
+// checkResourcesDetails resources must be readable and non-negative, and the storages must have a capacity.
+// The available amounts are not compared to the capacity, fleets can deliver more than the storage holds.
+func checkResourcesDetails(details ogame.ResourcesDetails) error {
+	for _, r := range []struct {
+		name                string
+		available, capacity int64
+	}{
+		{"metal", details.Metal.Available, details.Metal.StorageCapacity},
+		{"crystal", details.Crystal.Available, details.Crystal.StorageCapacity},
+		{"deuterium", details.Deuterium.Available, details.Deuterium.StorageCapacity},
+	} {
+		if r.available < 0 {
+			return fmt.Errorf("negative %s: %d", r.name, r.available)
+		}
+		if r.capacity <= 0 {
+			return fmt.Errorf("no %s storage capacity", r.name)
+		}
+	}
+	return nil
+}
+
+// checkLevelsMonotonic levels must not go down.
+// A building torn down between two runs fails the check too, it cannot be told apart from an extraction error.
+func checkLevelsMonotonic(ids []ogame.ID, previous, current func(ogame.ID) int64) error {
+	for _, id := range ids {
+		if prev, cur := previous(id), current(id); cur < prev {
+			return fmt.Errorf("%s went down from %d to %d", id, prev, cur)
+		}
+	}
+	return nil
+}
+
+// checkOwnPlanetInSystem the planet must appear as ours in its galaxy system
+func checkOwnPlanetInSystem(coord ogame.Coordinate, systemInfos ogame.SystemInfos, playerID int64) error {
+	planetInfos := systemInfos.Position(coord.Position)
+	if planetInfos == nil {
+		return fmt.Errorf("no planet at %s", coord)
+	}
+	if planetInfos.Player.ID != playerID {
+		return fmt.Errorf("planet at %s belongs to player %d", coord, planetInfos.Player.ID)
+	}
+	return nil
+}
+
+// checkSlots the slots in use must match the fleets listed on the movement page
+func checkSlots(fleets []ogame.Fleet, slots ogame.Slots) error {
+	var expeditions int64
+	for _, fleet := range fleets {
+		if fleet.Mission == ogame.Expedition {
+			expeditions++
+		}
+	}
+	if slots.InUse > slots.Total || slots.ExpInUse > slots.ExpTotal {
+		return fmt.Errorf("more slots in use than available: %d/%d, expeditions %d/%d", slots.InUse, slots.Total, slots.ExpInUse, slots.ExpTotal)
+	}
+	if slots.InUse != int64(len(fleets)) {
+		return fmt.Errorf("%d slots in use for %d fleets", slots.InUse, len(fleets))
+	}
+	if slots.ExpInUse != expeditions {
+		return fmt.Errorf("%d expedition slots in use for %d expeditions", slots.ExpInUse, expeditions)
+	}
+	return nil
+}
+
+// SetSelfTestSchedule sets the time of day ("15:04", bot local time) of the daily extraction self-test,
+// and starts/stops it accordingly (empty schedule to stop).
+// The self-test is bound to the bot context, it stops when the bot is disabled and restarts when it is enabled.
+func (b *OGame) SetSelfTestSchedule(schedule string) error {
+	var at time.Time
+	if schedule != "" {
+		var err error
+		if at, err = time.Parse("15:04", schedule); err != nil {
+			return err
+		}
+	}
+	b.selfTestMu.Lock()
+	defer b.selfTestMu.Unlock()
+	if b.selfTestCancel != nil {
+		b.selfTestCancel()
+		b.selfTestCancel = nil
+	}
+	b.selfTestSchedule = schedule
+	if schedule == "" {
+		return nil
+	}
+	b.startSelfTest(at)
+	return nil
+}
+
+// startSelfTest starts the self-test loop, selfTestMu must be held
+func (b *OGame) startSelfTest(at time.Time) {
+	ctx, cancel := context.WithCancel(b.getContext())
+	b.selfTestCancel = cancel
+	go b.selfTestLoop(ctx, at.Hour(), at.Minute())
+}
+
+// restartSelfTest restarts the self-test, if it is scheduled, on the current bot context
+func (b *OGame) restartSelfTest() {
+	b.selfTestMu.Lock()
+	defer b.selfTestMu.Unlock()
+	if b.selfTestCancel != nil {
+		b.selfTestCancel()
+		at, _ := time.Parse("15:04", b.selfTestSchedule) // validated by SetSelfTestSchedule
+		b.startSelfTest(at)
+	}
+}
+
+// GetSelfTestSchedule gets the time of day of the daily extraction self-test, empty if disabled
+func (b *OGame) GetSelfTestSchedule() string {
+	b.selfTestMu.Lock()
+	defer b.selfTestMu.Unlock()
+	return b.selfTestSchedule
+}
+
+// GetSelfTestResults returns the latest self-test runs, most recent first
+func (b *OGame) GetSelfTestResults() []SelfTestResult {
+	b.selfTestMu.Lock()
+	defer b.selfTestMu.Unlock()
+	out := make([]SelfTestResult, len(b.selfTestResults))
+	for i, res := range b.selfTestResults {
+		out[len(out)-1-i] = res
+	}
+	return out
+}
+
+func (b *OGame) selfTestLoop(ctx context.Context, hour, minute int) {
+	for {
+		now := b.clock.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(next.Sub(now)):
+		}
+		if !b.isEnabled() || !b.IsLoggedIn() || b.IsInMaintenance() {
+			continue
+		}
+		b.RunSelfTest()
+	}
+}
+
+// RunSelfTest loads the overview, supplies, research, galaxy and movement pages and checks extraction invariants.
+// The result is kept for GetSelfTestResults, and a self_test event is published when a check fails.
+func (b *OGame) RunSelfTest() SelfTestResult {
+	res := SelfTestResult{StartedAt: b.clock.Now(), Checks: make([]SelfTestCheck, 0)}
+	add := func(check SelfTestCheck) { res.Checks = append(res.Checks, check) }
+
+	b.selfTestMu.Lock()
+	prev := b.selfTestSnapshot
+	b.selfTestMu.Unlock()
+	snapshot := selfTestSnapshot{buildings: make(map[ogame.CelestialID]ogame.ResourcesBuildings)}
+
+	planets := b.GetCachedPlanets()
+	if len(planets) == 0 {
+		add(selfTestCheck("planets", OverviewPageName, fmt.Errorf("no planet extracted")))
+	}
+	for _, planet := range planets {
+		coord := planet.GetCoordinate()
+		name := "planet " + coord.String()
+		details, err := b.WithPriority(SelfTestPriority).GetResourcesDetails(planet.GetID())
+		if err == nil {
+			err = checkResourcesDetails(details)
+		}
+		add(selfTestCheck(name+" resources", OverviewPageName, err))
+
+		buildings, err := b.WithPriority(SelfTestPriority).GetResourcesBuildings(planet.GetID())
+		prevBuildings, hasPrev := prev.buildings[planet.GetID()]
+		if err == nil {
+			snapshot.buildings[planet.GetID()] = buildings
+			if hasPrev {
+				err = checkLevelsMonotonic(selfTestBuildingsIDs, prevBuildings.ByID, buildings.ByID)
+			}
+		} else if hasPrev {
+			// Keep the previous levels, so the next run still has something to compare with
+			snapshot.buildings[planet.GetID()] = prevBuildings
+		}
+		add(selfTestCheck(name+" buildings levels", SuppliesPageName, err))
+
+		systemInfos, err := b.WithPriority(SelfTestPriority).GalaxyInfos(coord.Galaxy, coord.System)
+		if err == nil {
+			err = checkOwnPlanetInSystem(coord, systemInfos, b.Player.PlayerID)
+		}
+		add(selfTestCheck(name+" in galaxy", GalaxyPageName, err))
+	}
+
+	research := b.WithPriority(SelfTestPriority).GetResearch()
+	snapshot.research = &research
+	var researchErr error
+	if prev.research != nil {
+		ids := make([]ogame.ID, 0, len(ogame.Technologies))
+		for _, technology := range ogame.Technologies {
+			ids = append(ids, technology.GetID())
+		}
+		researchErr = checkLevelsMonotonic(ids, prev.research.ByID, research.ByID)
+	}
+	add(selfTestCheck("research levels", ResearchPageName, researchErr))
+
+	fleets, slots := b.WithPriority(SelfTestPriority).GetFleets()
+	add(selfTestCheck("fleet slots", MovementPageName, checkSlots(fleets, slots)))
+
+	res.Passed = true
+	failed := make([]SelfTestCheck, 0)
+	for _, check := range res.Checks {
+		if !check.Passed {
+			res.Passed = false
+			failed = append(failed, check)
+		}
+	}
+	res.Duration = b.clock.Since(res.StartedAt).Milliseconds()
+
+	b.selfTestMu.Lock()
+	b.selfTestSnapshot = snapshot
+	b.selfTestResults = append(b.selfTestResults, res)
+	if len(b.selfTestResults) > selfTestResultsKept {
+		b.selfTestResults = b.selfTestResults[len(b.selfTestResults)-selfTestResultsKept:]
+	}
+	b.selfTestMu.Unlock()
+
+	if !res.Passed {
+		b.warn("self-test failed", failed)
+		b.publishEvent(OGameEvent{Type: SelfTestEventType, Data: failed})
+	}
+	return res
+}
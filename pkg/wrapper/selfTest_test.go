@@ -0,0 +1,35 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckResourcesDetails(t *testing.T) {
+	var details ogame.ResourcesDetails
+	details.Metal.StorageCapacity = 10_000
+	details.Crystal.StorageCapacity = 10_000
+	details.Deuterium.StorageCapacity = 10_000
+	details.Metal.Available = 20_000 // delivered by a fleet
+	assert.NoError(t, checkResourcesDetails(details))
+	details.Crystal.Available = -1
+	assert.EqualError(t, checkResourcesDetails(details), "negative crystal: -1")
+	details.Crystal.Available = 0
+	details.Deuterium.StorageCapacity = 0
+	assert.EqualError(t, checkResourcesDetails(details), "no deuterium storage capacity")
+}
+
+func TestCheckLevelsMonotonic(t *testing.T) {
+	prev := ogame.ResourcesBuildings{MetalMine: 10, CrystalMine: 8}
+	assert.NoError(t, checkLevelsMonotonic(selfTestBuildingsIDs, prev.ByID, ogame.ResourcesBuildings{MetalMine: 11, CrystalMine: 8}.ByID))
+	assert.Error(t, checkLevelsMonotonic(selfTestBuildingsIDs, prev.ByID, ogame.ResourcesBuildings{MetalMine: 10}.ByID))
+}
+
+func TestCheckSlots(t *testing.T) {
+	fleets := []ogame.Fleet{{Mission: ogame.Expedition}, {Mission: ogame.Transport}}
+	assert.NoError(t, checkSlots(fleets, ogame.Slots{InUse: 2, Total: 5, ExpInUse: 1, ExpTotal: 2}))
+	assert.EqualError(t, checkSlots(fleets, ogame.Slots{InUse: 3, Total: 5, ExpInUse: 1, ExpTotal: 2}), "3 slots in use for 2 fleets")
+	assert.EqualError(t, checkSlots(fleets, ogame.Slots{InUse: 2, Total: 5, ExpInUse: 0, ExpTotal: 2}), "0 expedition slots in use for 1 expeditions")
+}
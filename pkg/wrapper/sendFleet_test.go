@@ -0,0 +1,88 @@
+package wrapper
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/extractor/v7"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFleetdispatchTestBot returns a logged in bot whose game server serves the captured v7 movement and fleetdispatch pages.
+// The fleetdispatch page is the one of the planet 33795776 [9:297:12], with the planet 33796125 [9:297:9] and 6 small cargo.
+func newFleetdispatchTestBot(t *testing.T) *OGame {
+	movementHTML, _ := ioutil.ReadFile("../../samples/v7/movement.html")
+	fleetdispatchHTML, _ := ioutil.ReadFile("../../samples/v7/fleetdispatch.html")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("component") {
+		case MovementPageName:
+			_, _ = w.Write(movementHTML)
+		case FleetdispatchPageName:
+			_, _ = w.Write(fleetdispatchHTML)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	ext := v7.NewExtractor()
+	ext.SetLocation(time.UTC)
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clockwork.NewFakeClock(), quiet: true,
+		serverURL: srv.URL, extractor: ext, slotReservations: make(map[string]SlotReservation)}
+	b.isEnabledAtom = 1
+	b.isLoggedInAtom = 1
+	return b
+}
+
+func TestSendFleet_ownCelestialErrors(t *testing.T) {
+	b := newFleetdispatchTestBot(t)
+	ships := func() []ogame.Quantifiable {
+		return []ogame.Quantifiable{{ID: ogame.SmallCargoID, Nbr: 1}}
+	}
+	origin := ogame.CelestialID(33795776)
+
+	_, _, err := b.sendFleet(origin, ships(), ogame.HundredPercent, ogame.Coordinate{Galaxy: 9, System: 297, Position: 12, Type: ogame.PlanetType},
+		ogame.Transport, ogame.Resources{}, 0, 0, false, "", nil)
+	assert.Equal(t, ogame.ErrSameOriginDestination, err)
+
+	other := ogame.Coordinate{Galaxy: 9, System: 297, Position: 9, Type: ogame.PlanetType}
+	_, _, err = b.sendFleet(origin, ships(), ogame.HundredPercent, other, ogame.Spy, ogame.Resources{}, 0, 0, false, "", nil)
+	assert.Equal(t, ogame.ErrSpyOwnCelestial, err)
+	_, _, err = b.sendFleet(origin, ships(), ogame.HundredPercent, other, ogame.Attack, ogame.Resources{}, 0, 0, false, "", nil)
+	assert.Equal(t, ogame.ErrAttackOwnCelestial, err)
+
+	for _, err := range []error{ogame.ErrSameOriginDestination, ogame.ErrSpyOwnCelestial, ogame.ErrAttackOwnCelestial} {
+		_, ok := ogame.IsFleetError(err)
+		assert.True(t, ok, err.Error())
+	}
+}
+
+func TestSendFleet_ensureNotEnoughShips(t *testing.T) {
+	b := newFleetdispatchTestBot(t)
+	ships := []ogame.Quantifiable{{ID: ogame.SmallCargoID, Nbr: 7}}
+	_, _, err := b.sendFleet(ogame.CelestialID(33795776), ships, ogame.HundredPercent, ogame.Coordinate{Galaxy: 9, System: 297, Position: 9, Type: ogame.PlanetType},
+		ogame.Transport, ogame.Resources{}, 0, 0, true, "", nil)
+	assert.ErrorIs(t, err, ogame.ErrNotEnoughShips)
+	assert.Equal(t, "not enough ships to send, small cargo", err.Error())
+	code, ok := ogame.IsFleetError(err)
+	assert.True(t, ok)
+	assert.Equal(t, "not_enough_ships", code)
+}
+
+func TestQuickSpy_slotsReserved(t *testing.T) {
+	b := newFleetdispatchTestBot(t)
+	_, slots := b.getFleets()
+	_, err := b.ReserveSlots("expeditions", slots.Total-slots.InUse, time.Hour)
+	assert.NoError(t, err)
+	b.miniFleetToken = "token"
+	_, err = b.quickSpy(ogame.Coordinate{Galaxy: 9, System: 297, Position: 4, Type: ogame.PlanetType}, 1, "")
+	assert.Equal(t, ogame.ErrSlotsReserved, err)
+	// The probes are not dispatched, a refused dispatch would reset the token
+	assert.Equal(t, "token", b.miniFleetToken)
+}
@@ -0,0 +1,73 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// Pacing of SendMessages, the backoff doubles each time the game refuses a message, up to the max
+const (
+	sendMessagesPause      = 3 * time.Second
+	sendMessagesBackoff    = 30 * time.Second
+	sendMessagesMaxBackoff = 5 * time.Minute
+	sendMessagesRetries    = 3
+)
+
+// MessageStatus outcome of a message sent to one recipient of SendMessages
+type MessageStatus struct {
+	PlayerID int64
+	Sent     bool
+	Error    string `json:",omitempty"`
+}
+
+// SendMessages sends the same message to each player, one at a time with a pause between recipients.
+// When the game refuses a message with the generic SEND_FAILED status,
+// it backs off and retries the same recipient a few times before giving up on it.
+// The statuses of the recipients processed so far are returned even if the context is cancelled midway.
+func (b *OGame) SendMessages(ctx context.Context, playerIDs []int64, message string) ([]MessageStatus, error) {
+	statuses := make([]MessageStatus, 0, len(playerIDs))
+	backoff := sendMessagesBackoff
+	for i, playerID := range playerIDs {
+		if i > 0 {
+			if err := b.sendMessagesWait(ctx, sendMessagesPause); err != nil {
+				return statuses, err
+			}
+		}
+		status := MessageStatus{PlayerID: playerID}
+		for attempt := 0; ; attempt++ {
+			err := b.WithPriority(taskRunner.Low).SendMessage(playerID, message)
+			if err == nil {
+				status.Sent = true
+				status.Error = ""
+				backoff = sendMessagesBackoff
+				break
+			}
+			status.Error = err.Error()
+			if !errors.Is(err, ogame.ErrSendMessageFailed) || attempt+1 >= sendMessagesRetries {
+				break
+			}
+			b.debug("message to", playerID, "refused, retrying in", backoff)
+			if err := b.sendMessagesWait(ctx, backoff); err != nil {
+				return append(statuses, status), err
+			}
+			if backoff *= 2; backoff > sendMessagesMaxBackoff {
+				backoff = sendMessagesMaxBackoff
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (b *OGame) sendMessagesWait(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.clock.After(d):
+		return nil
+	}
+}
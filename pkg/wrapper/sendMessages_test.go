@@ -0,0 +1,93 @@
+package wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessages_backoff(t *testing.T) {
+	// The refusal status is a key of the chatLoca of the captured pages, the chat answers it as its status
+	pageHTMLBytes, _ := ioutil.ReadFile("../../samples/v9.0.2/en/lifeform/overview_all_queues.html")
+	m := regexp.MustCompile(`chatLoca = ({[^;]+});`).FindSubmatch(pageHTMLBytes)
+	assert.Len(t, m, 2)
+	var chatLoca map[string]string
+	assert.NoError(t, json.Unmarshal(m[1], &chatLoca))
+	assert.Contains(t, chatLoca, "SEND_FAILED")
+	refused, _ := json.Marshal(ChatPostResp{Status: "SEND_FAILED", NewToken: "token"})
+	sent, _ := json.Marshal(ChatPostResp{ID: 1, SenderID: 100, TargetID: 2, Text: "hello\n", NewToken: "token"})
+
+	// Player 1 always refuses the message, player 2 accepts it on the second attempt
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mu.Lock()
+		defer mu.Unlock()
+		playerID := r.PostForm.Get("playerId")
+		attempts[playerID]++
+		if playerID == "1" || attempts[playerID] == 1 {
+			_, _ = w.Write(refused)
+			return
+		}
+		_, _ = w.Write(sent)
+	}))
+	defer srv.Close()
+	getAttempts := func(playerID string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts[playerID]
+	}
+
+	clock := clockwork.NewFakeClock()
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clock, quiet: true, serverURL: srv.URL}
+	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), func() *Prioritize { return &Prioritize{bot: b} })
+	b.isEnabledAtom = 1
+	b.isLoggedInAtom = 1
+
+	var statuses []MessageStatus
+	var err error
+	done := make(chan struct{})
+	go func() {
+		statuses, err = b.SendMessages(context.Background(), []int64{1, 2}, "hello")
+		close(done)
+	}()
+
+	// wait advances the clock by d once SendMessages waits, the next attempt is not made any sooner
+	wait := func(d time.Duration, playerID string, attempt int) {
+		clock.BlockUntil(1)
+		clock.Advance(d - time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, attempt-1, getAttempts(playerID))
+		clock.Advance(time.Millisecond)
+		for i := 0; i < 100 && getAttempts(playerID) != attempt; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, attempt, getAttempts(playerID))
+	}
+	wait(sendMessagesBackoff, "1", 2)
+	wait(2*sendMessagesBackoff, "1", 3)
+	// Player 1 is given up after the last retry, the backoff keeps growing for the next recipient
+	wait(sendMessagesPause, "2", 1)
+	wait(4*sendMessagesBackoff, "2", 2)
+	<-done
+
+	assert.NoError(t, err)
+	assert.Equal(t, []MessageStatus{
+		{PlayerID: 1, Sent: false, Error: ogame.ErrSendMessageFailed.Error()},
+		{PlayerID: 2, Sent: true},
+	}, statuses)
+	assert.Equal(t, sendMessagesRetries, getAttempts("1"))
+}
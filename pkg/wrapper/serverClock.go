@@ -0,0 +1,54 @@
+package wrapper
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// waitUntilServerTimeRecheck maximum duration WaitUntilServerTime sleeps before re-checking the server time offset
+const waitUntilServerTimeRecheck = 30 * time.Second
+
+// setServerTimeOffset stores the offset between the server clock and the local clock
+func (b *OGame) setServerTimeOffset(serverTime time.Time) {
+	atomic.StoreInt64(&b.serverTimeOffsetAtom, int64(serverTime.Sub(b.clock.Now())))
+}
+
+// GetServerTimeOffset returns the offset between the server clock and the local clock,
+// measured (with second precision) on the last full page loaded. Positive if the server clock is ahead.
+func (b *OGame) GetServerTimeOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&b.serverTimeOffsetAtom))
+}
+
+// serverNow returns the current server time, estimated from the local clock and the server time offset
+func (b *OGame) serverNow() time.Time {
+	return b.clock.Now().Add(b.GetServerTimeOffset())
+}
+
+// WaitUntilServerTime blocks until the server clock reaches t.
+// The offset is re-checked at least every 30 seconds, so a drift measured while waiting is taken into account.
+// Returns an error if the context is cancelled, or if the bot gets disabled or logged out while waiting.
+func (b *OGame) WaitUntilServerTime(ctx context.Context, t time.Time) error {
+	for {
+		if !b.isEnabled() {
+			return ogame.ErrBotInactive
+		}
+		if !b.IsLoggedIn() {
+			return ogame.ErrBotLoggedOut
+		}
+		remaining := t.Sub(b.serverNow())
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining > waitUntilServerTimeRecheck {
+			remaining = waitUntilServerTimeRecheck
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.clock.After(remaining):
+		}
+	}
+}
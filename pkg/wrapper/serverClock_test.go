@@ -0,0 +1,59 @@
+package wrapper
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitUntilServerTime(t *testing.T) {
+	now := time.Date(2022, 8, 21, 10, 0, 0, 0, time.UTC)
+	clock := clockwork.NewFakeClockAt(now)
+	b := &OGame{isEnabledAtom: 1, isLoggedInAtom: 1, clock: clock}
+	b.setServerTimeOffset(now.Add(2 * time.Second))
+	assert.Equal(t, 2*time.Second, b.GetServerTimeOffset())
+
+	done := make(chan error)
+	go func() { done <- b.WaitUntilServerTime(context.Background(), now.Add(62*time.Second)) }()
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+	// The server clock drifted one second behind while waiting
+	b.setServerTimeOffset(clock.Now().Add(time.Second))
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatal("returned before the server time")
+	default:
+	}
+	clock.Advance(time.Second)
+	assert.NoError(t, <-done)
+
+	// Target already reached
+	assert.NoError(t, b.WaitUntilServerTime(context.Background(), now))
+}
+
+func TestWaitUntilServerTime_abort(t *testing.T) {
+	now := time.Date(2022, 8, 21, 10, 0, 0, 0, time.UTC)
+	clock := clockwork.NewFakeClockAt(now)
+	b := &OGame{isEnabledAtom: 1, isLoggedInAtom: 1, clock: clock}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.WaitUntilServerTime(ctx, now.Add(time.Hour)) }()
+	clock.BlockUntil(1)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	go func() { done <- b.WaitUntilServerTime(context.Background(), now.Add(time.Hour)) }()
+	clock.BlockUntil(1)
+	atomic.StoreInt32(&b.isEnabledAtom, 0)
+	clock.Advance(30 * time.Second)
+	assert.ErrorIs(t, <-done, ogame.ErrBotInactive)
+}
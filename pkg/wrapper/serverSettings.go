@@ -0,0 +1,90 @@
+package wrapper
+
+import (
+	"github.com/alaingilbert/ogame/pkg/simulator"
+)
+
+// ServerSettings gameplay settings of the universe, parsed from the serverData.xml api and the lobby servers list
+type ServerSettings struct {
+	Speed                         int64
+	SpeedFleetPeaceful            int64
+	SpeedFleetWar                 int64
+	SpeedFleetHolding             int64
+	Galaxies                      int64
+	Systems                       int64
+	DonutGalaxy                   bool
+	DonutSystem                   bool
+	ACS                           bool
+	RapidFire                     bool
+	DefToTF                       bool
+	DebrisFactor                  float64 // Part of the destroyed ships that goes to the debris field
+	DebrisFactorDef               float64 // Part of the destroyed defences that goes to the debris field
+	RepairFactor                  float64
+	WreckField                    bool
+	WfMinimumRessLost             int64
+	WfMinimumLossPercentage       int64
+	WfBasicPercentageRepairable   int64
+	GlobalDeuteriumSaveFactor     float64
+	BonusFields                   int64
+	ProbeRaids                    bool
+	ProbeCargo                    int64
+	Bashlimit                     int64
+	NewbieProtectionLimit         int64
+	NewbieProtectionHigh          int64
+	ResearchDurationDivisor       int64
+	CargoHyperspaceTechMultiplier int64
+	MarketplaceEnabled            bool
+	CharacterClassesEnabled       bool
+	IgnoreEmptySystems            bool
+	IgnoreInactiveSystems         bool
+}
+
+func newServerSettings(serverData ServerData, server Server) ServerSettings {
+	return ServerSettings{
+		Speed:                         serverData.Speed,
+		SpeedFleetPeaceful:            serverData.SpeedFleetPeaceful,
+		SpeedFleetWar:                 serverData.SpeedFleetWar,
+		SpeedFleetHolding:             serverData.SpeedFleetHolding,
+		Galaxies:                      serverData.Galaxies,
+		Systems:                       serverData.Systems,
+		DonutGalaxy:                   serverData.DonutGalaxy,
+		DonutSystem:                   serverData.DonutSystem,
+		ACS:                           serverData.ACS,
+		RapidFire:                     serverData.RapidFire,
+		DefToTF:                       serverData.DefToTF,
+		DebrisFactor:                  serverData.DebrisFactor,
+		DebrisFactorDef:               serverData.DebrisFactorDef,
+		RepairFactor:                  serverData.RepairFactor,
+		WreckField:                    serverData.WfEnabled,
+		WfMinimumRessLost:             serverData.WfMinimumRessLost,
+		WfMinimumLossPercentage:       serverData.WfMinimumLossPercentage,
+		WfBasicPercentageRepairable:   serverData.WfBasicPercentageRepairable,
+		GlobalDeuteriumSaveFactor:     serverData.GlobalDeuteriumSaveFactor,
+		BonusFields:                   serverData.BonusFields,
+		ProbeRaids:                    serverData.ProbeRaids() || server.Settings.EspionageProbeRaids == 1,
+		ProbeCargo:                    serverData.ProbeCargo,
+		Bashlimit:                     serverData.Bashlimit,
+		NewbieProtectionLimit:         serverData.NewbieProtectionLimit,
+		NewbieProtectionHigh:          serverData.NewbieProtectionHigh,
+		ResearchDurationDivisor:       serverData.ResearchDurationDivisor,
+		CargoHyperspaceTechMultiplier: serverData.CargoHyperspaceTechMultiplier,
+		MarketplaceEnabled:            serverData.MarketplaceEnabled,
+		CharacterClassesEnabled:       serverData.CharacterClassesEnabled,
+		IgnoreEmptySystems:            serverData.IgnoreEmptySystems,
+		IgnoreInactiveSystems:         serverData.IgnoreInactiveSystems,
+	}
+}
+
+// SimulatorParams returns the combat simulator parameters matching the server debris settings
+func (s ServerSettings) SimulatorParams(simulations int) simulator.SimulatorParams {
+	params := simulator.SimulatorParams{Simulations: simulations, FleetToDebris: s.DebrisFactor}
+	if s.DefToTF {
+		params.DefenceToDebris = s.DebrisFactorDef
+	}
+	return params
+}
+
+// GetServerSettings returns the gameplay settings of the universe
+func (b *OGame) GetServerSettings() ServerSettings {
+	return newServerSettings(b.serverData, b.server)
+}
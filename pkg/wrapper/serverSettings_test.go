@@ -0,0 +1,20 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerSettings_SimulatorParams(t *testing.T) {
+	settings := newServerSettings(ServerData{DebrisFactor: 0.7, DebrisFactorDef: 0.3, ProbeCargo: 5}, Server{})
+	assert.True(t, settings.ProbeRaids)
+	params := settings.SimulatorParams(10)
+	assert.Equal(t, 10, params.Simulations)
+	assert.Equal(t, 0.7, params.FleetToDebris)
+	assert.Equal(t, 0.0, params.DefenceToDebris)
+
+	settings = newServerSettings(ServerData{DebrisFactor: 0.7, DebrisFactorDef: 0.3, DefToTF: true}, Server{})
+	assert.False(t, settings.ProbeRaids)
+	assert.Equal(t, 0.3, settings.SimulatorParams(10).DefenceToDebris)
+}
@@ -0,0 +1,124 @@
+package wrapper
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// SlotReservation fleet slots kept free until ExpiresAt.
+// Only the fleets sent with the reservation token (see SetSlotReservation) can use the slots,
+// each of them consumes one reserved slot. Owner is a label for display.
+type SlotReservation struct {
+	Token     string `json:",omitempty"` // Only returned by ReserveSlots
+	Owner     string
+	Slots     int64
+	ExpiresAt time.Time
+}
+
+// newSlotReservationToken returns a random token that cannot be guessed by the other clients of the bot
+func newSlotReservationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// purgeSlotReservations removes the expired and consumed reservations
+func purgeSlotReservations(reservations map[string]SlotReservation, now time.Time) {
+	for token, r := range reservations {
+		if r.Slots <= 0 || !now.Before(r.ExpiresAt) {
+			delete(reservations, token)
+		}
+	}
+}
+
+// slotsReservedByOthers returns the number of slots reserved by other reservations than token
+func slotsReservedByOthers(reservations map[string]SlotReservation, token string, now time.Time) (out int64) {
+	for t, r := range reservations {
+		if t != token && now.Before(r.ExpiresAt) {
+			out += r.Slots
+		}
+	}
+	return
+}
+
+// ReserveSlots keeps n fleet slots free during ttl. Returns the reservation and its token,
+// fleets sent without the token cannot use the reserved slots.
+func (b *OGame) ReserveSlots(owner string, n int64, ttl time.Duration) (SlotReservation, error) {
+	if owner == "" {
+		return SlotReservation{}, errors.New("owner is required")
+	}
+	if n <= 0 || ttl <= 0 {
+		return SlotReservation{}, errors.New("invalid reservation")
+	}
+	token, err := newSlotReservationToken()
+	if err != nil {
+		return SlotReservation{}, err
+	}
+	b.slotReservationsMu.Lock()
+	defer b.slotReservationsMu.Unlock()
+	if b.slotReservations == nil {
+		b.slotReservations = make(map[string]SlotReservation)
+	}
+	r := SlotReservation{Token: token, Owner: owner, Slots: n, ExpiresAt: b.clock.Now().Add(ttl)}
+	b.slotReservations[token] = r
+	return r, nil
+}
+
+// ReleaseSlots removes the slots reservation of token
+func (b *OGame) ReleaseSlots(token string) error {
+	b.slotReservationsMu.Lock()
+	defer b.slotReservationsMu.Unlock()
+	if _, ok := b.slotReservations[token]; !ok {
+		return ogame.ErrSlotReservationNotFound
+	}
+	delete(b.slotReservations, token)
+	return nil
+}
+
+// GetSlotReservations returns the active slots reservations, without their token
+func (b *OGame) GetSlotReservations() []SlotReservation {
+	b.slotReservationsMu.Lock()
+	defer b.slotReservationsMu.Unlock()
+	purgeSlotReservations(b.slotReservations, b.clock.Now())
+	out := make([]SlotReservation, 0, len(b.slotReservations))
+	for _, r := range b.slotReservations {
+		r.Token = ""
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Owner != out[j].Owner {
+			return out[i].Owner < out[j].Owner
+		}
+		return out[i].ExpiresAt.Before(out[j].ExpiresAt)
+	})
+	return out
+}
+
+// checkSlotReservations ensure the fleet sent with token is allowed to use one of the free slots.
+// Called by sendFleet, while holding the bot lock.
+func (b *OGame) checkSlotReservations(token string, slots ogame.Slots) error {
+	b.slotReservationsMu.Lock()
+	defer b.slotReservationsMu.Unlock()
+	if slots.Total-slots.InUse <= slotsReservedByOthers(b.slotReservations, token, b.clock.Now()) {
+		return ogame.ErrSlotsReserved
+	}
+	return nil
+}
+
+// consumeSlotReservation consumes one of the slots reserved with token, once its fleet is sent
+func (b *OGame) consumeSlotReservation(token string) {
+	b.slotReservationsMu.Lock()
+	defer b.slotReservationsMu.Unlock()
+	if r, ok := b.slotReservations[token]; ok {
+		r.Slots--
+		b.slotReservations[token] = r
+		purgeSlotReservations(b.slotReservations, b.clock.Now())
+	}
+}
@@ -0,0 +1,65 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlotsReservedByOthers(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	reservations := map[string]SlotReservation{
+		"token1": {Token: "token1", Owner: "expeditions", Slots: 2, ExpiresAt: now.Add(time.Hour)},
+		"token2": {Token: "token2", Owner: "fleetsave", Slots: 1, ExpiresAt: now.Add(time.Minute)},
+		"token3": {Token: "token3", Owner: "expired", Slots: 3, ExpiresAt: now},
+	}
+	assert.Equal(t, int64(3), slotsReservedByOthers(reservations, "", now))
+	assert.Equal(t, int64(1), slotsReservedByOthers(reservations, "token1", now))
+	assert.Equal(t, int64(2), slotsReservedByOthers(reservations, "token2", now))
+	// The owner label does not give access to the slots
+	assert.Equal(t, int64(3), slotsReservedByOthers(reservations, "expeditions", now))
+}
+
+func TestPurgeSlotReservations(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	reservations := map[string]SlotReservation{
+		"token1": {Token: "token1", Owner: "expeditions", Slots: 2, ExpiresAt: now.Add(time.Hour)},
+		"token2": {Token: "token2", Owner: "consumed", Slots: 0, ExpiresAt: now.Add(time.Hour)},
+		"token3": {Token: "token3", Owner: "expired", Slots: 3, ExpiresAt: now},
+	}
+	purgeSlotReservations(reservations, now)
+	assert.Len(t, reservations, 1)
+	_, ok := reservations["token1"]
+	assert.True(t, ok)
+}
+
+func TestReserveSlots(t *testing.T) {
+	b := &OGame{clock: clockwork.NewFakeClock()}
+	r1, err := b.ReserveSlots("expeditions", 2, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, r1.Token, 32)
+	r2, err := b.ReserveSlots("expeditions", 1, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, r1.Token, r2.Token)
+
+	// The tokens are not listed
+	reservations := b.GetSlotReservations()
+	assert.Len(t, reservations, 2)
+	for _, r := range reservations {
+		assert.Equal(t, "", r.Token)
+	}
+
+	slots := ogame.Slots{InUse: 1, Total: 4}
+	assert.Equal(t, ogame.ErrSlotsReserved, b.checkSlotReservations("", slots))
+	assert.Equal(t, ogame.ErrSlotsReserved, b.checkSlotReservations("expeditions", slots))
+	assert.NoError(t, b.checkSlotReservations(r1.Token, slots))
+
+	b.consumeSlotReservation(r2.Token)
+	assert.Len(t, b.GetSlotReservations(), 1)
+	assert.NoError(t, b.ReleaseSlots(r1.Token))
+	assert.Equal(t, ogame.ErrSlotReservationNotFound, b.ReleaseSlots(r1.Token))
+	assert.NoError(t, b.checkSlotReservations("", slots))
+}
@@ -0,0 +1,67 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// spiedEventsRetention how long the already announced espionage actions are remembered
+const spiedEventsRetention = 7 * 24 * time.Hour
+
+// spiedEvents returns the espionage actions of other players on our celestials that happened at or after since
+func spiedEvents(msgs []ogame.EspionageReportSummary, since time.Time) []ogame.SpiedEvent {
+	out := make([]ogame.SpiedEvent, 0)
+	for _, msg := range msgs {
+		if msg.Type != ogame.Action || msg.CreatedAt.Before(since) {
+			continue
+		}
+		out = append(out, ogame.SpiedEvent{
+			MessageID:        msg.ID,
+			Attacker:         msg.Attacker,
+			AttackerOrigin:   msg.AttackerOrigin,
+			Target:           msg.Target,
+			Date:             msg.CreatedAt,
+			CounterEspionage: msg.CounterEspionage,
+		})
+	}
+	return out
+}
+
+func (b *OGame) getSpiedEvents(since time.Time) ([]ogame.SpiedEvent, error) {
+	msgs, err := b.getEspionageReportMessages()
+	if err != nil {
+		return []ogame.SpiedEvent{}, err
+	}
+	events := spiedEvents(msgs, since)
+	b.notifySpiedEvents(events)
+	return events, nil
+}
+
+// notifySpiedEvents publishes a spied event for each espionage action seen for the first time
+func (b *OGame) notifySpiedEvents(events []ogame.SpiedEvent) {
+	now := b.clock.Now()
+	announced := make([]ogame.SpiedEvent, 0)
+	b.spiedEventsMu.Lock()
+	if b.spiedEventsSeen == nil {
+		b.spiedEventsSeen = make(map[int64]time.Time)
+	}
+	for id, date := range b.spiedEventsSeen {
+		if now.Sub(date) > spiedEventsRetention {
+			delete(b.spiedEventsSeen, id)
+		}
+	}
+	for _, event := range events {
+		if now.Sub(event.Date) > spiedEventsRetention {
+			continue
+		}
+		if _, ok := b.spiedEventsSeen[event.MessageID]; !ok {
+			b.spiedEventsSeen[event.MessageID] = event.Date
+			announced = append(announced, event)
+		}
+	}
+	b.spiedEventsMu.Unlock()
+	for _, event := range announced {
+		b.publishSpiedEvent(event)
+	}
+}
@@ -0,0 +1,47 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpiedEvents(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	origin := ogame.Coordinate{Galaxy: 4, System: 116, Position: 8, Type: ogame.PlanetType}
+	target := ogame.Coordinate{Galaxy: 4, System: 117, Position: 9, Type: ogame.PlanetType}
+	msgs := []ogame.EspionageReportSummary{
+		{ID: 1, Type: ogame.Report, Target: origin, CreatedAt: now},
+		{ID: 2, Type: ogame.Action, Target: target, CreatedAt: now, Attacker: "Origin", AttackerOrigin: origin, CounterEspionage: 10},
+		{ID: 3, Type: ogame.Action, Target: target, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+	events := spiedEvents(msgs, now.Add(-time.Hour))
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, ogame.SpiedEvent{MessageID: 2, Attacker: "Origin", AttackerOrigin: origin, Target: target, Date: now, CounterEspionage: 10}, events[0])
+	}
+	assert.Len(t, spiedEvents(msgs, time.Time{}), 2)
+}
+
+func TestNotifySpiedEvents(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC))
+	b := newEventsTestBot()
+	b.clock = clock
+	ch, unsubscribe := b.SubscribeEvents()
+	defer unsubscribe()
+
+	event := ogame.SpiedEvent{MessageID: 2, Attacker: "Origin", Date: clock.Now()}
+	old := ogame.SpiedEvent{MessageID: 3, Date: clock.Now().Add(-8 * 24 * time.Hour)}
+	b.notifySpiedEvents([]ogame.SpiedEvent{event, old})
+	b.notifySpiedEvents([]ogame.SpiedEvent{event})
+	evt := <-ch
+	assert.Equal(t, SpiedEventType, evt.Type)
+	assert.Equal(t, event, evt.Data)
+	assert.Equal(t, 0, len(ch))
+
+	clock.Advance(spiedEventsRetention + time.Hour)
+	b.notifySpiedEvents(nil)
+	assert.Equal(t, 0, len(b.spiedEventsSeen))
+}
@@ -0,0 +1,191 @@
+package wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// DefaultStorageLeadTime alert when a storage is projected to be full within this delay
+const DefaultStorageLeadTime = 2 * time.Hour
+
+// DefaultStorageAlertCooldown minimum delay between two alerts for the same planet and resource
+const DefaultStorageAlertCooldown = 6 * time.Hour
+
+const storageWebhookInterval = 15 * time.Minute
+
+// StorageWebhook settings of the storage full webhook.
+// When set, the planets storages are checked every 15 minutes, and an alert is posted (json) to URL
+// for each storage projected to be full within LeadTime.
+// Resources announced by friendly fleets arriving within LeadTime are counted as already available.
+type StorageWebhook struct {
+	URL      string
+	LeadTime time.Duration // DefaultStorageLeadTime if 0
+	Cooldown time.Duration // DefaultStorageAlertCooldown if 0
+}
+
+// StorageAlert payload posted to the storage webhook
+type StorageAlert struct {
+	CelestialID       ogame.CelestialID
+	Coordinate        ogame.Coordinate
+	Resource          string // metal | crystal | deuterium
+	Available         int64
+	StorageCapacity   int64
+	CurrentProduction int64 // Per hour
+	FullIn            int64 // Seconds before the storage is full, 0 if already full
+	FullAt            time.Time
+}
+
+// storageAlerts returns an alert for each storage of the celestial projected to be full within leadTime
+func storageAlerts(celestial ogame.Celestial, details ogame.ResourcesDetails, leadTime time.Duration, now time.Time) (out []StorageAlert) {
+	metalFullIn, crystalFullIn, deuteriumFullIn := details.StorageFullIn()
+	add := func(resource string, available, capacity, production int64, fullIn time.Duration) {
+		if fullIn < 0 || fullIn > leadTime {
+			return
+		}
+		out = append(out, StorageAlert{
+			CelestialID:       celestial.GetID(),
+			Coordinate:        celestial.GetCoordinate(),
+			Resource:          resource,
+			Available:         available,
+			StorageCapacity:   capacity,
+			CurrentProduction: production,
+			FullIn:            int64(fullIn.Seconds()),
+			FullAt:            now.Add(fullIn),
+		})
+	}
+	add("metal", details.Metal.Available, details.Metal.StorageCapacity, details.Metal.CurrentProduction, metalFullIn)
+	add("crystal", details.Crystal.Available, details.Crystal.StorageCapacity, details.Crystal.CurrentProduction, crystalFullIn)
+	add("deuterium", details.Deuterium.Available, details.Deuterium.StorageCapacity, details.Deuterium.CurrentProduction, deuteriumFullIn)
+	return
+}
+
+// withIncoming counts the resources announced by incoming fleets as already available
+func withIncoming(details ogame.ResourcesDetails, incoming ogame.Resources) ogame.ResourcesDetails {
+	details.Metal.Available += incoming.Metal
+	details.Crystal.Available += incoming.Crystal
+	details.Deuterium.Available += incoming.Deuterium
+	return details
+}
+
+// SetStorageWebhook sets the storage full webhook, and starts/stops the monitor accordingly (empty URL to stop).
+// The monitor is bound to the bot context, it stops when the bot is disabled and restarts when it is enabled.
+func (b *OGame) SetStorageWebhook(hook StorageWebhook) {
+	if hook.LeadTime <= 0 {
+		hook.LeadTime = DefaultStorageLeadTime
+	}
+	if hook.Cooldown <= 0 {
+		hook.Cooldown = DefaultStorageAlertCooldown
+	}
+	b.storageWebhookMu.Lock()
+	defer b.storageWebhookMu.Unlock()
+	if b.storageWebhookCancel != nil {
+		b.storageWebhookCancel()
+		b.storageWebhookCancel = nil
+	}
+	b.storageWebhook = hook
+	b.storageAlertsSent = make(map[string]time.Time)
+	if hook.URL == "" {
+		return
+	}
+	b.startStorageWebhook(hook)
+}
+
+// startStorageWebhook starts the monitor loop, storageWebhookMu must be held
+func (b *OGame) startStorageWebhook(hook StorageWebhook) {
+	ctx, cancel := context.WithCancel(b.getContext())
+	b.storageWebhookCancel = cancel
+	go b.storageWebhookLoop(ctx, hook)
+}
+
+// restartStorageWebhook restarts the monitor, if it is running, on the current bot context.
+// The alerts cooldowns are kept.
+func (b *OGame) restartStorageWebhook() {
+	b.storageWebhookMu.Lock()
+	defer b.storageWebhookMu.Unlock()
+	if b.storageWebhookCancel != nil {
+		b.storageWebhookCancel()
+		b.startStorageWebhook(b.storageWebhook)
+	}
+}
+
+// GetStorageWebhook gets the storage full webhook settings
+func (b *OGame) GetStorageWebhook() StorageWebhook {
+	b.storageWebhookMu.Lock()
+	defer b.storageWebhookMu.Unlock()
+	return b.storageWebhook
+}
+
+// shouldSendStorageAlert returns either or not the alert is not in cooldown, and records it if so
+func (b *OGame) shouldSendStorageAlert(alert StorageAlert, cooldown time.Duration, now time.Time) bool {
+	b.storageWebhookMu.Lock()
+	defer b.storageWebhookMu.Unlock()
+	key := fmt.Sprintf("%d:%s", alert.CelestialID, alert.Resource)
+	if sentAt, ok := b.storageAlertsSent[key]; ok && now.Sub(sentAt) < cooldown {
+		return false
+	}
+	b.storageAlertsSent[key] = now
+	return true
+}
+
+func (b *OGame) storageWebhookLoop(ctx context.Context, hook StorageWebhook) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.clock.After(storageWebhookInterval):
+		}
+		if !b.isEnabled() || !b.IsLoggedIn() || b.IsInMaintenance() {
+			continue
+		}
+		arrivals, err := b.WithPriority(taskRunner.Low).GetFriendlyArrivals()
+		if err != nil {
+			b.error("storage webhook", err)
+		}
+		for _, planet := range b.GetCachedPlanets() {
+			details, err := b.WithPriority(taskRunner.Low).GetResourcesDetails(planet.GetID())
+			if err != nil {
+				b.error("storage webhook", err)
+				continue
+			}
+			now := b.clock.Now()
+			details = withIncoming(details, incomingResources(arrivals, planet.GetCoordinate(), now.Add(hook.LeadTime)))
+			for _, alert := range storageAlerts(planet, details, hook.LeadTime, now) {
+				if !b.shouldSendStorageAlert(alert, hook.Cooldown, now) {
+					continue
+				}
+				if err := postStorageAlert(ctx, hook.URL, alert); err != nil {
+					b.error("storage webhook", err)
+				}
+			}
+		}
+	}
+}
+
+func postStorageAlert(ctx context.Context, url string, alert StorageAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
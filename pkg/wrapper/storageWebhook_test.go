@@ -0,0 +1,42 @@
+package wrapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageAlerts(t *testing.T) {
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	planet := ogame.Planet{ID: 123, Coordinate: ogame.Coordinate{1, 2, 3, ogame.PlanetType}}
+	var details ogame.ResourcesDetails
+	details.Metal.Available = 100_000
+	details.Metal.StorageCapacity = 100_000
+	details.Crystal.Available = 90_000
+	details.Crystal.StorageCapacity = 100_000
+	details.Crystal.CurrentProduction = 5_000
+	details.Deuterium.Available = 10_000
+	details.Deuterium.StorageCapacity = 100_000
+	details.Deuterium.CurrentProduction = 1_000
+	alerts := storageAlerts(planet, details, DefaultStorageLeadTime, now)
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, "metal", alerts[0].Resource)
+		assert.Equal(t, int64(0), alerts[0].FullIn)
+		assert.Equal(t, "crystal", alerts[1].Resource)
+		assert.Equal(t, int64(7200), alerts[1].FullIn)
+		assert.Equal(t, now.Add(2*time.Hour), alerts[1].FullAt)
+		assert.Equal(t, ogame.CelestialID(123), alerts[1].CelestialID)
+	}
+}
+
+func TestShouldSendStorageAlert(t *testing.T) {
+	b := &OGame{storageAlertsSent: make(map[string]time.Time)}
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	alert := StorageAlert{CelestialID: 123, Resource: "metal"}
+	assert.True(t, b.shouldSendStorageAlert(alert, time.Hour, now))
+	assert.False(t, b.shouldSendStorageAlert(alert, time.Hour, now.Add(30*time.Minute)))
+	assert.True(t, b.shouldSendStorageAlert(StorageAlert{CelestialID: 123, Resource: "crystal"}, time.Hour, now))
+	assert.True(t, b.shouldSendStorageAlert(alert, time.Hour, now.Add(time.Hour)))
+}
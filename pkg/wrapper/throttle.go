@@ -0,0 +1,151 @@
+package wrapper
+
+import (
+	"bytes"
+	"expvar"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+)
+
+// Cool-down of the task queue when the game server throttles the bot, doubled on every consecutive throttle
+const (
+	throttleMinCooldown = 30 * time.Second
+	throttleMaxCooldown = 10 * time.Minute
+	// A throttle happening less than this after the previous cool-down ended is considered consecutive
+	throttleBackoffReset = 5 * time.Minute
+	// Only short pages are checked for the throttle message, so a game page mentioning it is not mistaken for it
+	throttleMaxBodyLen = 512
+)
+
+// throttledTotal number of times the game server throttled the bot
+var throttledTotal = expvar.NewInt("ogame_throttled_total")
+
+var throttleMessage = []byte("too many requests")
+
+// ThrottleState throttling status of the bot
+type ThrottleState struct {
+	Throttled bool
+	Start     time.Time     // start of the current (or last) cool-down, zero if never throttled
+	RetryAt   time.Time     // time at which the task queue resumes
+	Cooldown  time.Duration // duration of the current (or last) cool-down
+	Count     int64         // throttles detected since the bot started
+}
+
+// isThrottleResponse returns either or not a game server response means that the bot sends too many requests
+func isThrottleResponse(statusCode int, body []byte) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode != http.StatusServiceUnavailable && len(body) > throttleMaxBodyLen {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(body), throttleMessage)
+}
+
+// IsThrottled returns either or not the task queue is paused because the game server throttled the bot
+func (b *OGame) IsThrottled() bool {
+	return atomic.LoadInt32(&b.throttleAtom) == 1
+}
+
+// GetThrottleState returns the throttling status of the bot and the time at which the task queue resumes
+func (b *OGame) GetThrottleState() ThrottleState {
+	b.throttleMu.Lock()
+	defer b.throttleMu.Unlock()
+	return ThrottleState{
+		Throttled: b.IsThrottled(),
+		Start:     b.throttleStart,
+		RetryAt:   b.throttleUntil,
+		Cooldown:  b.throttleCooldown,
+		Count:     b.throttleCount,
+	}
+}
+
+// throttleRemaining returns how long a task of the given priority still has to wait before sending requests.
+// Critical tasks are let through once throttleCriticalWait is elapsed, if it is set.
+func (b *OGame) throttleRemaining(priority taskRunner.Priority) time.Duration {
+	b.throttleMu.Lock()
+	until := b.throttleUntil
+	if priority == taskRunner.Critical && b.throttleCriticalWait > 0 {
+		if criticalUntil := b.throttleStart.Add(b.throttleCriticalWait); criticalUntil.Before(until) {
+			until = criticalUntil
+		}
+	}
+	b.throttleMu.Unlock()
+	if d := until.Sub(b.clock.Now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// enterThrottle pauses the task queue for a cool-down that doubles on consecutive throttles
+func (b *OGame) enterThrottle() {
+	now := b.clock.Now()
+	b.throttleMu.Lock()
+	if b.throttleCooldown > 0 && now.Before(b.throttleUntil.Add(throttleBackoffReset)) {
+		b.throttleCooldown *= 2
+		if b.throttleCooldown > throttleMaxCooldown {
+			b.throttleCooldown = throttleMaxCooldown
+		}
+	} else {
+		b.throttleCooldown = throttleMinCooldown
+	}
+	b.throttleStart = now
+	b.throttleUntil = now.Add(b.throttleCooldown)
+	b.throttleCount++
+	cooldown := b.throttleCooldown
+	b.throttleMu.Unlock()
+	throttledTotal.Add(1)
+	b.error("game server throttled the bot, pausing the task queue for", cooldown)
+	if atomic.CompareAndSwapInt32(&b.throttleAtom, 0, 1) {
+		b.stateChanged(false, "Throttled")
+		go b.resumeAfterThrottle()
+	}
+}
+
+// resumeAfterThrottle waits for the end of the cool-down, which can be extended meanwhile, then resumes the task queue
+func (b *OGame) resumeAfterThrottle() {
+	var done <-chan struct{}
+	if ctx := b.getContext(); ctx != nil {
+		done = ctx.Done()
+	}
+	for {
+		wait := b.throttleRemaining(taskRunner.Normal)
+		if wait <= 0 {
+			break
+		}
+		select {
+		case <-b.clock.After(wait):
+		case <-done:
+			atomic.StoreInt32(&b.throttleAtom, 0)
+			return
+		}
+	}
+	if atomic.CompareAndSwapInt32(&b.throttleAtom, 1, 0) {
+		b.info("throttle cool-down is over, resuming the task queue")
+		b.stateChanged(false, "ThrottleEnd")
+	}
+}
+
+// throttleGate holds the queued tasks until the throttle cool-down is over for their priority
+func (b *OGame) throttleGate(priority taskRunner.Priority) <-chan time.Time {
+	if !b.IsThrottled() {
+		return nil
+	}
+	wait := b.throttleRemaining(priority)
+	if wait <= 0 {
+		return nil
+	}
+	return b.clock.After(wait)
+}
+
+// checkThrottle returns ogame.ErrThrottled if the task currently holding the bot must not send requests yet
+func (b *OGame) checkThrottle() error {
+	if b.IsThrottled() && b.throttleRemaining(taskRunner.Priority(atomic.LoadInt64(&b.taskPriorityAtom))) > 0 {
+		return ogame.ErrThrottled
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package wrapper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alaingilbert/clockwork"
+	"github.com/alaingilbert/ogame/pkg/httpclient"
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/alaingilbert/ogame/pkg/taskRunner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsThrottleResponse(t *testing.T) {
+	assert.True(t, isThrottleResponse(http.StatusTooManyRequests, nil))
+	assert.True(t, isThrottleResponse(http.StatusServiceUnavailable, []byte("<h1>Too Many Requests</h1>")))
+	assert.False(t, isThrottleResponse(http.StatusServiceUnavailable, []byte("<h1>Maintenance</h1>")))
+	assert.True(t, isThrottleResponse(http.StatusOK, []byte(`{"error":"too many requests"}`)))
+	assert.False(t, isThrottleResponse(http.StatusOK, []byte("<html>"+strings.Repeat(" ", throttleMaxBodyLen)+"too many requests</html>")))
+	assert.False(t, isThrottleResponse(http.StatusOK, []byte("<html></html>")))
+}
+
+func TestThrottleBurst(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	clock := clockwork.NewFakeClock()
+	b := &OGame{client: httpclient.NewClient(), ctx: context.Background(), clock: clock, quiet: true, throttleCriticalWait: 10 * time.Second}
+
+	// Three throttled responses in a row, each one doubles the cool-down
+	for _, cooldown := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute} {
+		_, err := b.execRequest(http.MethodGet, srv.URL, nil, url.Values{})
+		assert.Equal(t, ogame.ErrThrottled, err)
+		b.enterThrottle()
+		state := b.GetThrottleState()
+		assert.True(t, state.Throttled)
+		assert.Equal(t, cooldown, state.Cooldown)
+		assert.Equal(t, clock.Now().Add(cooldown), state.RetryAt)
+	}
+	assert.Equal(t, int64(3), b.GetThrottleState().Count)
+	_, state := b.GetState()
+	assert.Equal(t, "Throttled", state)
+	assert.True(t, b.Info().Throttled)
+
+	// Critical tasks are let through after a shorter wait
+	b.taskPriorityAtom = int64(taskRunner.Normal)
+	assert.Equal(t, ogame.ErrThrottled, b.checkThrottle())
+	b.taskPriorityAtom = int64(taskRunner.Critical)
+	assert.Equal(t, ogame.ErrThrottled, b.checkThrottle())
+	clock.Advance(10 * time.Second)
+	assert.NoError(t, b.checkThrottle())
+	b.taskPriorityAtom = int64(taskRunner.Normal)
+	assert.Equal(t, ogame.ErrThrottled, b.checkThrottle())
+
+	// The queue resumes by itself at the end of the cool-down
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Minute)
+	for i := 0; i < 100 && b.IsThrottled(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(t, b.IsThrottled())
+	assert.NoError(t, b.checkThrottle())
+
+	// A throttle long after the previous cool-down starts over from the minimum
+	clock.Advance(throttleBackoffReset + time.Second)
+	b.enterThrottle()
+	assert.Equal(t, throttleMinCooldown, b.GetThrottleState().Cooldown)
+}
+
+func TestThrottleHoldsQueuedTasks(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	b := &OGame{ctx: context.Background(), clock: clock, quiet: true, throttleCriticalWait: 10 * time.Second}
+	b.taskRunnerInst = taskRunner.NewTaskRunner(context.Background(), func() *Prioritize { return &Prioritize{bot: b} })
+	b.taskRunnerInst.SetGate(b.throttleGate)
+	b.enterThrottle()
+
+	processed := make(chan taskRunner.Priority, 2)
+	queue := func(priority taskRunner.Priority) {
+		task := b.taskRunnerInst.WithPriority(priority)
+		processed <- priority
+		close(task.taskIsDoneCh)
+	}
+	go queue(taskRunner.Normal)
+	go queue(taskRunner.Critical)
+	for b.taskRunnerInst.GetTasks().Total != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queued tasks wait instead of failing, critical tasks are let through first
+	clock.BlockUntil(2)
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, taskRunner.Critical, <-processed)
+	assert.Equal(t, int64(1), b.taskRunnerInst.GetTasks().Total)
+
+	clock.BlockUntil(2)
+	clock.Advance(throttleMinCooldown)
+	assert.Equal(t, taskRunner.Normal, <-processed)
+}
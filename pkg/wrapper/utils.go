@@ -4,8 +4,11 @@ import (
 	"github.com/alaingilbert/ogame/pkg/ogame"
 )
 
-// GetFleetSpeedForMission ...
+// GetFleetSpeedForMission returns the universe fleet speed that applies to the mission (war, holding or peaceful)
 func GetFleetSpeedForMission(serverData ServerData, missionID ogame.MissionID) int64 {
+	if missionID == ogame.ParkInThatAlly {
+		return serverData.SpeedFleetHolding
+	}
 	if missionID == ogame.Attack ||
 		missionID == ogame.GroupedAttack ||
 		missionID == ogame.Destroy ||
@@ -0,0 +1,16 @@
+package wrapper
+
+import (
+	"testing"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFleetSpeedForMission(t *testing.T) {
+	serverData := ServerData{SpeedFleetPeaceful: 2, SpeedFleetWar: 3, SpeedFleetHolding: 4}
+	assert.Equal(t, int64(2), GetFleetSpeedForMission(serverData, ogame.Transport))
+	assert.Equal(t, int64(2), GetFleetSpeedForMission(serverData, ogame.Expedition))
+	assert.Equal(t, int64(3), GetFleetSpeedForMission(serverData, ogame.Attack))
+	assert.Equal(t, int64(4), GetFleetSpeedForMission(serverData, ogame.ParkInThatAlly))
+}
@@ -0,0 +1,36 @@
+package wrapper
+
+import (
+	"time"
+
+	"github.com/alaingilbert/ogame/pkg/ogame"
+)
+
+// wastedSample resources details of a celestial and when they were fetched
+type wastedSample struct {
+	at      time.Time
+	details ogame.ResourcesDetails
+}
+
+// recordWastedSample stores the new sample of a celestial and returns the resources wasted since the previous one
+func (b *OGame) recordWastedSample(celestialID ogame.CelestialID, sample wastedSample) ogame.Resources {
+	b.wastedSamplesMu.Lock()
+	defer b.wastedSamplesMu.Unlock()
+	if b.wastedSamples == nil {
+		b.wastedSamples = make(map[ogame.CelestialID]wastedSample)
+	}
+	prev, ok := b.wastedSamples[celestialID]
+	b.wastedSamples[celestialID] = sample
+	if !ok || !sample.at.After(prev.at) {
+		return ogame.Resources{}
+	}
+	return prev.details.WastedIn(sample.at.Sub(prev.at))
+}
+
+func (b *OGame) getWastedResources(celestialID ogame.CelestialID) (ogame.Resources, error) {
+	details, err := b.getResourcesDetails(celestialID)
+	if err != nil {
+		return ogame.Resources{}, err
+	}
+	return b.recordWastedSample(celestialID, wastedSample{at: b.clock.Now(), details: details}), nil
+}
@@ -9,6 +9,9 @@ type Options struct {
 	SkipInterceptor bool
 	SkipRetry       bool
 	ChangePlanet    ogame.CelestialID // cp parameter
+	CombatReports   bool
+	Warnings        *[]ogame.ParseWarning // collects the parse warnings, see CollectWarnings
+	TagRelations    bool
 }
 
 // Option functions to be passed to public interface to change behaviors
@@ -24,6 +27,17 @@ func SkipInterceptor(opt *Options) {
 	opt.SkipInterceptor = true
 }
 
+// TagRelations option to tag the galaxy positions that are mine/allied/enemy (see ogame.PlanetInfos.Relation)
+func TagRelations(opt *Options) {
+	opt.TagRelations = true
+}
+
+// WithCombatReports option to link the returning attack fleets to their combat report (see ogame.Fleet.CombatReportID).
+// This fetches the combat reports, so it is only worth it when such fleets are expected.
+func WithCombatReports(opt *Options) {
+	opt.CombatReports = true
+}
+
 // SkipRetry option to skip retry
 func SkipRetry(opt *Options) {
 	opt.SkipRetry = true
@@ -35,3 +49,11 @@ func ChangePlanet(celestialID ogame.CelestialID) Option {
 		opt.ChangePlanet = celestialID
 	}
 }
+
+// CollectWarnings option to receive what could not be parsed on the pages of the request.
+// Supported by GetResourcesBuildings, GetFacilities, GetShips and GetDefense.
+func CollectWarnings(warnings *[]ogame.ParseWarning) Option {
+	return func(opt *Options) {
+		opt.Warnings = warnings
+	}
+}